@@ -2,17 +2,39 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
 	"flag"
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"time"
 
 	"github.com/ooqls/go-app/app"
 	"github.com/ooqls/go-auth/api/v1/gen/gen_authentication"
+	"github.com/ooqls/go-auth/api/v1/gen/gen_oauth"
+	"github.com/ooqls/go-auth/domain/v1/accesstokens"
 	"github.com/ooqls/go-auth/domain/v1/authentication"
 	"github.com/ooqls/go-auth/domain/v1/authorization"
+	"github.com/ooqls/go-auth/domain/v1/directory"
+	"github.com/ooqls/go-auth/domain/v1/federation"
+	"github.com/ooqls/go-auth/domain/v1/introspection"
+	"github.com/ooqls/go-auth/domain/v1/keyring"
+	"github.com/ooqls/go-auth/domain/v1/mail"
+	"github.com/ooqls/go-auth/domain/v1/oauth"
 	usersvc "github.com/ooqls/go-auth/domain/v1/serivce/users"
+	"github.com/ooqls/go-auth/domain/v1/serviceaccounts"
+	accesstokenrecords "github.com/ooqls/go-auth/records/v1/accesstokens"
+	"github.com/ooqls/go-auth/records/v1/challengeattempts"
+	"github.com/ooqls/go-auth/records/v1/federatedidentities"
+	authgen "github.com/ooqls/go-auth/records/v1/gen"
+	"github.com/ooqls/go-auth/records/v1/mfa"
+	"github.com/ooqls/go-auth/records/v1/oauthclients"
+	"github.com/ooqls/go-auth/records/v1/passkeys"
+	"github.com/ooqls/go-auth/records/v1/roles"
+	serviceaccountrecords "github.com/ooqls/go-auth/records/v1/serviceaccounts"
+	"github.com/ooqls/go-auth/records/v1/sessions"
+	"github.com/ooqls/go-auth/records/v1/signingkeys"
 	"github.com/ooqls/go-auth/records/v1/users"
 	"github.com/ooqls/go-cache/cache"
 	"github.com/ooqls/go-cache/factory"
@@ -26,11 +48,55 @@ import (
 )
 
 var (
-	appConfigPath string
+	appConfigPath  string
+	webAuthnRPID   string
+	webAuthnOrigin string
+	resetURL       string
+	verifyURL      string
+	emailPolicy    string
+	smtpHost       string
+	smtpPort       int
+	smtpUsername   string
+	smtpPassword   string
+	mailFrom       string
+	loginURL       string
+	consentURL     string
+	deviceURL      string
+	issuerURL      string
+	keyringSecret  string
+	keyRotation    time.Duration
+	federationPath string
+	directoryPath  string
+	magicLinkURL   string
+	magicPolicy    string
+	tokenClaims    string
+	tokenDomain    string
 )
 
 func init() {
 	flag.StringVar(&appConfigPath, "app-config", "", "path to app config")
+	flag.StringVar(&webAuthnRPID, "webauthn-rp-id", "localhost", "relying party id passkeys are bound to")
+	flag.StringVar(&webAuthnOrigin, "webauthn-origin", "http://localhost:8080", "origin passkey ceremonies are accepted from")
+	flag.StringVar(&resetURL, "reset-url", "http://localhost:8080/reset", "page recovery emails link to, the token is added as a query parameter")
+	flag.StringVar(&verifyURL, "verify-url", "http://localhost:8080/auth/email/verify", "link verification emails point to, the token is added as a query parameter")
+	flag.StringVar(&emailPolicy, "email-verification-policy", string(authentication.UnverifiedLoginAllowed), "whether users with an unverified email can log in, allow_login or deny_login")
+	flag.StringVar(&smtpHost, "smtp-host", "", "smtp server to send emails with, emails are written to stdout when empty")
+	flag.IntVar(&smtpPort, "smtp-port", 587, "smtp server port")
+	flag.StringVar(&smtpUsername, "smtp-username", "", "smtp username")
+	flag.StringVar(&smtpPassword, "smtp-password", os.Getenv("SMTP_PASSWORD"), "smtp password, defaults to $SMTP_PASSWORD")
+	flag.StringVar(&mailFrom, "mail-from", "noreply@localhost", "address emails are sent from")
+	flag.StringVar(&loginURL, "login-url", "http://localhost:8080/login", "page oauth users are sent to log in, the authorization request is added as the return_to query parameter")
+	flag.StringVar(&consentURL, "consent-url", "http://localhost:8080/consent", "page oauth users are sent to consent, the consent_id is added as a query parameter")
+	flag.StringVar(&deviceURL, "device-url", "http://localhost:8080/device", "page users enter the user code of a device on to approve it, the user_code is added as a query parameter in the complete verification uri")
+	flag.StringVar(&issuerURL, "issuer-url", "http://localhost:8080", "url the service is reachable at, it is the issuer of oauth and id tokens and OpenID Connect clients discover the provider from it")
+	flag.StringVar(&keyringSecret, "keyring-secret", os.Getenv("KEYRING_SECRET"), "base64 encoded 32 byte key the signing keys are encrypted with in the database, defaults to $KEYRING_SECRET. Signing keys are not persisted when empty")
+	flag.DurationVar(&keyRotation, "key-rotation-period", 30*24*time.Hour, "how often the token signing key is rotated")
+	flag.StringVar(&federationPath, "federation-config", "", "path to a JSON array of upstream OpenID Connect identity providers users can log in with, federated login is disabled when empty")
+	flag.StringVar(&directoryPath, "directory-config", "", "path to the JSON configuration of an LDAP or Active Directory server users can log in with their directory password, directory login is disabled when empty")
+	flag.StringVar(&magicLinkURL, "magic-link-url", "http://localhost:8080/auth/magic/verify", "link magic link emails point to, the token is added as a query parameter")
+	flag.StringVar(&magicPolicy, "magic-link-policy", string(authentication.MagicLinkOptIn), "which users can log in with magic links, opt_in or all")
+	flag.StringVar(&tokenClaims, "token-claims", "", "comma separated claims to embed in auth tokens so services can authorize without loading roles: roles, amr and auth_time")
+	flag.StringVar(&tokenDomain, "token-domain", "", "domain to embed in auth tokens, it is the domain of the authorization context services reconstruct from them")
 }
 
 func main() {
@@ -83,6 +149,7 @@ func main() {
 	authApp.OnStartup(func(ctx *app.AppContext) error {
 		db := sqlx.GetSQLX()
 		store.Register(authentication.Challenge{})
+		var err error
 
 		authCfg, ok := ctx.AuthIssuerConfig()
 		if !ok {
@@ -95,20 +162,151 @@ func main() {
 		}
 		userR := users.NewSQLUserReader(cache.New[[]users.User](cache.NewMemCache()), db)
 		userW := users.NewSQLWriter(db)
+		sessionR := sessions.NewSQLReader(db)
+		sessionW := sessions.NewSQLWriter(db)
+		mfaR := mfa.NewSQLReader(db)
+		mfaW := mfa.NewSQLWriter(db)
+		passkeyR := passkeys.NewSQLReader(db)
+		passkeyW := passkeys.NewSQLWriter(db)
+		clientR := oauthclients.NewSQLReader(db)
+		clientW := oauthclients.NewSQLWriter(db)
+		serviceAccountR := serviceaccountrecords.NewSQLReader(db)
+		serviceAccountW := serviceaccountrecords.NewSQLWriter(db)
+		accessTokenR := accesstokenrecords.NewSQLReader(db)
+		accessTokenW := accesstokenrecords.NewSQLWriter(db)
+		identityR := federatedidentities.NewSQLReader(db)
+		identityW := federatedidentities.NewSQLWriter(db)
+
+		verificationCfg := &jwt.TokenConfiguration{
+			Audience:                []string{"email_verification"},
+			Issuer:                  app.AuthIssuer,
+			ValidityDurationSeconds: authentication.EmailVerificationTTL.Seconds(),
+		}
+		accessCfg := &jwt.TokenConfiguration{
+			Audience:                []string{"oauth"},
+			Issuer:                  issuerURL,
+			ValidityDurationSeconds: 3600,
+		}
+
+		// tokens signed before the key ring have no kid, keys.JWT() verifies them for the longest token validity after start
+		var signingKeyR signingkeys.Reader
+		var signingKeyW signingkeys.Writer
+		var sealKey []byte
+		if keyringSecret != "" {
+			sealKey, err = base64.StdEncoding.DecodeString(keyringSecret)
+			if err != nil {
+				return fmt.Errorf("failed to decode keyring secret: %v", err)
+			}
+			signingKeyR = signingkeys.NewSQLReader(db)
+			signingKeyW = signingkeys.NewSQLWriter(db)
+		} else {
+			ctx.L().Warn("no keyring secret, signing keys are not persisted and sessions end on restart")
+		}
+		ring, err := keyring.NewKeyRingV1(signingKeyR, signingKeyW, sealKey, maxValidity(authCfg, refreshCfg, accessCfg, verificationCfg), keys.JWT())
+		if err != nil {
+			return fmt.Errorf("failed to create key ring: %v", err)
+		}
+		err = ring.Reload(context.Background())
+		if err != nil {
+			return fmt.Errorf("failed to load key ring: %v", err)
+		}
+		go keyring.NewRotator(ring, keyRotation).Run(context.Background())
 
 		ua := authorization.NewUserAuthorizerImpl(userR)
 		chalStore := store.NewRedisStore("challenges", *redis.GetConnection(), time.Minute*15)
-		authIssuer := jwt.NewJwtTokenIssuer[authentication.UserClaims](authCfg, keys.JWT())
-		refreshIssuer := jwt.NewJwtTokenIssuer[authentication.UserClaims](refreshCfg, keys.JWT())
+		authIssuer := keyring.NewTokenIssuer[authentication.UserClaims](authCfg, ring)
+		refreshIssuer := keyring.NewTokenIssuer[authentication.UserClaims](refreshCfg, ring)
 
-		challenger := authentication.NewChallengerV1(chalStore)
+		attemptR := challengeattempts.NewSQLReader(authgen.New(db), nil)
+		attemptW := challengeattempts.NewSQLWriter(authgen.New(db))
+		challenger := authentication.NewChallengerV1(chalStore, attemptR, attemptW, authentication.DefaultLockoutPolicy())
 		cacheFactory := factory.NewRedisCacheFactory(*redis.GetConnection())
-		authenticator := authentication.NewAuthenticatorV1(authIssuer, refreshIssuer, cacheFactory, challenger, []string{"auth"})
+		policy, err := authentication.ParseEmailVerificationPolicy(emailPolicy)
+		if err != nil {
+			return err
+		}
+		aggRoleR := roles.NewAggRoleReaderImpl(ctx.L(), authgen.New(db))
+		accessTokens := accesstokens.NewAccessTokenServiceV1(accessTokenR, accessTokenW, userR, aggRoleR)
+
+		mapping, err := authentication.ParseClaimsMapping(tokenClaims, tokenDomain)
+		if err != nil {
+			return err
+		}
+		// optional dependencies stay nil interfaces when they are not configured so their consumers can tell they are disabled
+		var claimsMapper authentication.ClaimsMapper
+		if mapping.Enabled() {
+			claimsMapper = authentication.NewClaimsMapperV1(mapping, userR, aggRoleR, serviceAccountR)
+		}
+		authenticator := authentication.NewAuthenticatorV1(authIssuer, refreshIssuer, cacheFactory, challenger, sessionR, sessionW, mfaR, mfaW, policy, accessTokens, claimsMapper, []string{"auth"})
+
+		webAuthnStore := store.NewRedisStore("webauthn", *redis.GetConnection(), time.Minute*5)
+		webAuthnChallenger, err := authentication.NewWebAuthnChallenger(authentication.WebAuthnConfig{
+			RPID:          webAuthnRPID,
+			RPDisplayName: "authentication",
+			RPOrigins:     []string{webAuthnOrigin},
+		}, webAuthnStore, passkeyR, passkeyW, attemptR, attemptW, authentication.DefaultLockoutPolicy())
+		if err != nil {
+			return fmt.Errorf("failed to create webauthn challenger: %v", err)
+		}
+		passkeyAuthenticator := authentication.NewAuthenticatorV1(authIssuer, refreshIssuer, cacheFactory, webAuthnChallenger, sessionR, sessionW, mfaR, mfaW, policy, accessTokens, claimsMapper, []string{"auth"})
+
+		var mailer mail.Mailer = mail.NewFileMailer(os.Stdout)
+		if smtpHost != "" {
+			mailer = mail.NewSMTPMailer(mail.SMTPConfig{
+				Host:     smtpHost,
+				Port:     smtpPort,
+				Username: smtpUsername,
+				Password: smtpPassword,
+				From:     mailFrom,
+			})
+		}
+		recoverer := authentication.NewRecovererV1(cacheFactory, challenger, mailer, resetURL)
+		verificationIssuer := keyring.NewTokenIssuer[authentication.EmailVerificationClaims](verificationCfg, ring)
+		emailVerifier := authentication.NewEmailVerifier(verificationIssuer, mailer, verifyURL)
+
+		linkPolicy, err := authentication.ParseMagicLinkPolicy(magicPolicy)
+		if err != nil {
+			return err
+		}
+		magicLinker := authentication.NewMagicLinkerV1(cacheFactory, userR, authenticator, mailer, magicLinkURL, linkPolicy)
+
+		var federator federation.Federator
+		if federationPath != "" {
+			providers, err := federation.LoadProvidersFile(federationPath)
+			if err != nil {
+				return fmt.Errorf("failed to load identity providers: %v", err)
+			}
+			federator, err = federation.NewFederatorV1(providers, strings.TrimSuffix(issuerURL, "/")+"/auth/federation", identityR, identityW, userR, userW, authenticator, cacheFactory, nil)
+			if err != nil {
+				return fmt.Errorf("failed to create federator: %v", err)
+			}
+		}
+
+		var directoryAuthenticator directory.Authenticator
+		if directoryPath != "" {
+			config, err := directory.LoadConfigFile(directoryPath)
+			if err != nil {
+				return fmt.Errorf("failed to load directory configuration: %v", err)
+			}
+			directoryAuthenticator, err = directory.NewAuthenticatorV1(*config, identityR, identityW, userR, userW, roles.NewSQLRoleReader(nil, ctx.L(), authgen.New(db)), roles.NewSQLRoleWriter(authgen.New(db)), authenticator)
+			if err != nil {
+				return fmt.Errorf("failed to create directory authenticator: %v", err)
+			}
+		}
+
 		userService := usersvc.NewUserServiceImpl(ua, userR, userW)
-		server := NewAuthenticationServer(ctx.L(), authenticator, userService)
+		server := NewAuthenticationServer(ctx.L(), authenticator, passkeyAuthenticator, webAuthnChallenger, recoverer, emailVerifier, userService, accessTokens, federator, directoryAuthenticator, magicLinker)
+
+		accessIssuer := keyring.NewTokenIssuer[oauth.AccessClaims](accessCfg, ring)
+		roleR := roles.NewSQLRoleReader(nil, ctx.L(), authgen.New(db))
+		serviceAccounts := serviceaccounts.NewServiceAccountServiceV1(serviceAccountR, serviceAccountW, roleR, authenticator, cacheFactory, strings.TrimSuffix(issuerURL, "/")+"/oauth/token")
+		authorizationServer := oauth.NewAuthorizationServerV1(clientR, clientW, userService, cacheFactory, accessIssuer, ring, serviceAccounts)
+		introspector := introspection.NewIntrospectorV1(authenticator, accessTokens, serviceAccounts)
+		oauthServer := NewOAuthServer(ctx.L(), authenticator, authorizationServer, serviceAccounts, introspector, loginURL, consentURL, deviceURL)
 
 		e := authApp.Features().Gin.Engine
 		gen_authentication.RegisterHandlers(e, server)
+		gen_oauth.RegisterHandlers(e, oauthServer)
 
 		return nil
 	})
@@ -121,3 +319,16 @@ func main() {
 	<-signalChan
 	cancel()
 }
+
+// maxValidity returns the validity of the longest lived token, retired signing keys verify for that long
+func maxValidity(cfgs ...*jwt.TokenConfiguration) time.Duration {
+	var longest time.Duration
+	for _, cfg := range cfgs {
+		validity := time.Duration(cfg.ValidityDurationSeconds * float64(time.Second))
+		if validity > longest {
+			longest = validity
+		}
+	}
+
+	return longest
+}
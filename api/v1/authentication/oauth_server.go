@@ -0,0 +1,927 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	gen "github.com/ooqls/go-auth/api/v1/gen/gen_oauth"
+	"github.com/ooqls/go-auth/domain/v1/authentication"
+	"github.com/ooqls/go-auth/domain/v1/introspection"
+	"github.com/ooqls/go-auth/domain/v1/oauth"
+	"github.com/ooqls/go-auth/domain/v1/serviceaccounts"
+	"github.com/ooqls/go-auth/records/v1/oauthclients"
+	serviceaccountrecords "github.com/ooqls/go-auth/records/v1/serviceaccounts"
+	"go.uber.org/zap"
+)
+
+var _ gen.ServerInterface = &OAuthServerImpl{}
+
+// NewOAuthServer creates the OAuth 2.0 endpoints. Users who are not logged in are sent to loginURL with the
+// authorization request in the return_to parameter, users who have to consent are sent to consentURL with the consent_id parameter.
+// deviceURL is the verification uri where users enter the user codes of devices. Token introspection is not served if introspector is nil
+func NewOAuthServer(
+	l *zap.Logger,
+	authenticator authentication.Authenticator,
+	authorizationServer oauth.AuthorizationServer,
+	serviceAccounts serviceaccounts.ServiceAccountService,
+	introspector introspection.Introspector,
+	loginURL string,
+	consentURL string,
+	deviceURL string) *OAuthServerImpl {
+
+	return &OAuthServerImpl{
+		l:                   l,
+		authenticator:       authenticator,
+		authorizationServer: authorizationServer,
+		serviceAccounts:     serviceAccounts,
+		introspector:        introspector,
+		loginURL:            loginURL,
+		consentURL:          consentURL,
+		deviceURL:           deviceURL,
+	}
+}
+
+type OAuthServerImpl struct {
+	l                   *zap.Logger
+	authenticator       authentication.Authenticator
+	authorizationServer oauth.AuthorizationServer
+	serviceAccounts     serviceaccounts.ServiceAccountService
+	introspector        introspection.Introspector
+	loginURL            string
+	consentURL          string
+	deviceURL           string
+}
+
+// authenticate returns the claims of the request's auth token, responding with 401 if it is not authenticated
+func (o *OAuthServerImpl) authenticate(ctx *gin.Context) (*authentication.UserClaims, bool) {
+	claims, err := authTokenClaims(ctx, o.authenticator)
+	if err != nil {
+		ctx.JSON(401, gin.H{"error": "Authentication failed"})
+		return nil, false
+	}
+
+	if refuseAccessToken(ctx, claims) {
+		return nil, false
+	}
+
+	return claims, true
+}
+
+// refuseAccessToken responds with 403 if the claims are of a personal access token, a personal access token is
+// limited to its scope, it must not authorize clients or manage service accounts
+func refuseAccessToken(ctx *gin.Context, claims *authentication.UserClaims) bool {
+	if claims.AccessTokenID != uuid.Nil {
+		ctx.JSON(403, gin.H{"error": "Personal access tokens can not be used here"})
+		return true
+	}
+
+	return false
+}
+
+// authenticateOwner returns the claims of the request's auth token like authenticate, service accounts are
+// refused with 403 since only users own service accounts
+func (o *OAuthServerImpl) authenticateOwner(ctx *gin.Context) (*authentication.UserClaims, bool) {
+	claims, ok := o.authenticate(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	if claims.ServiceAccount {
+		ctx.JSON(403, gin.H{"error": "Service accounts can not manage service accounts"})
+		return nil, false
+	}
+
+	return claims, true
+}
+
+// withQuery returns the url with the query parameter set
+func withQuery(rawURL string, key string, value string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	query.Set(key, value)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// authTime returns when the user logged in to the session of the auth token, or the zero time if the session is gone
+func (o *OAuthServerImpl) authTime(ctx *gin.Context, claims *authentication.UserClaims) time.Time {
+	userSessions, err := o.authenticator.ListSessions(ctx, claims.UserID)
+	if err != nil {
+		o.l.Error("failed to list sessions", zap.String("user_id", claims.UserID.String()), zap.Error(err))
+		return time.Time{}
+	}
+
+	for _, session := range userSessions {
+		if session.ID == claims.SessionID {
+			return session.CreatedAt
+		}
+	}
+
+	return time.Time{}
+}
+
+func toOAuthClient(client oauthclients.Client) gen.OAuthClient {
+	return gen.OAuthClient{
+		ClientId:     client.ID,
+		Confidential: client.SecretHash != nil,
+		CreatedAt:    client.CreatedAt,
+		Name:         client.Name,
+		RedirectUris: client.RedirectUris,
+		Scopes:       client.Scopes,
+	}
+}
+
+func toServiceAccount(account serviceaccountrecords.ServiceAccount) gen.ServiceAccount {
+	return gen.ServiceAccount{
+		ClientId:  account.ID,
+		CreatedAt: account.CreatedAt,
+		Name:      account.Name,
+		PublicKey: account.PublicKey != nil,
+	}
+}
+
+func (o *OAuthServerImpl) Authorize(ctx *gin.Context, params gen.AuthorizeParams) {
+	// errors about the client or redirect uri must not be sent to the redirect uri
+	_, err := o.authorizationServer.ValidateClient(ctx, params.ClientId, params.RedirectUri)
+	if err != nil {
+		if errors.Is(err, oauth.ErrServerError) {
+			ctx.JSON(500, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		ctx.JSON(400, gin.H{"error": "Invalid client or redirect uri"})
+		return
+	}
+
+	claims, err := authTokenClaims(ctx, o.authenticator)
+	if err != nil {
+		login, err := withQuery(o.loginURL, "return_to", ctx.Request.URL.RequestURI())
+		if err != nil {
+			o.l.Error("failed to build login url", zap.Error(err))
+			ctx.JSON(500, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		ctx.Redirect(http.StatusFound, login)
+		return
+	}
+
+	if refuseAccessToken(ctx, claims) {
+		return
+	}
+
+	if claims.ServiceAccount {
+		ctx.JSON(403, gin.H{"error": "Service accounts can not authorize clients"})
+		return
+	}
+
+	req := oauth.AuthorizeRequest{
+		ResponseType:        params.ResponseType,
+		ClientID:            params.ClientId,
+		RedirectURI:         params.RedirectUri,
+		CodeChallenge:       params.CodeChallenge,
+		CodeChallengeMethod: params.CodeChallengeMethod,
+		AuthTime:            o.authTime(ctx, claims),
+	}
+	if params.Scope != nil {
+		req.Scope = *params.Scope
+	}
+	if params.State != nil {
+		req.State = *params.State
+	}
+	if params.Nonce != nil {
+		req.Nonce = *params.Nonce
+	}
+
+	authorization, err := o.authorizationServer.Authorize(ctx, claims.UserID, req)
+	if err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid client or redirect uri"})
+		return
+	}
+
+	if authorization.ConsentID != "" {
+		consent, err := withQuery(o.consentURL, "consent_id", authorization.ConsentID)
+		if err != nil {
+			o.l.Error("failed to build consent url", zap.Error(err))
+			ctx.JSON(500, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		ctx.Redirect(http.StatusFound, consent)
+		return
+	}
+
+	ctx.Redirect(http.StatusFound, authorization.RedirectURI)
+}
+
+func (o *OAuthServerImpl) GetConsentRequest(ctx *gin.Context, params gen.GetConsentRequestParams) {
+	claims, ok := o.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	consentRequest, err := o.authorizationServer.PendingConsent(ctx, claims.UserID, params.ConsentId)
+	if err != nil {
+		if errors.Is(err, oauth.ErrConsentNotFound) {
+			ctx.JSON(404, gin.H{"error": "Consent request not found"})
+			return
+		}
+
+		ctx.JSON(500, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	ctx.JSON(200, gen.ConsentRequestResponse{
+		ClientId:   consentRequest.ClientID,
+		ClientName: consentRequest.ClientName,
+		Scopes:     consentRequest.Scopes,
+	})
+}
+
+func (o *OAuthServerImpl) Consent(ctx *gin.Context) {
+	claims, ok := o.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	var request gen.ConsentJSONRequestBody
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	authorization, err := o.authorizationServer.Consent(ctx, claims.UserID, request.ConsentId, request.Approved)
+	if err != nil {
+		if errors.Is(err, oauth.ErrConsentNotFound) {
+			ctx.JSON(404, gin.H{"error": "Consent request not found"})
+			return
+		}
+
+		ctx.JSON(500, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	ctx.JSON(200, gen.ConsentDecisionResponse{
+		RedirectUri: authorization.RedirectURI,
+	})
+}
+
+func (o *OAuthServerImpl) ListConsents(ctx *gin.Context) {
+	claims, ok := o.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	consents, err := o.authorizationServer.ListConsents(ctx, claims.UserID)
+	if err != nil {
+		ctx.JSON(500, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	response := gen.ConsentList{Consents: []gen.Consent{}}
+	for _, consent := range consents {
+		response.Consents = append(response.Consents, gen.Consent{
+			ClientId:  consent.ClientID,
+			Scopes:    consent.Scopes,
+			UpdatedAt: consent.UpdatedAt,
+		})
+	}
+
+	ctx.JSON(200, response)
+}
+
+func (o *OAuthServerImpl) RevokeConsent(ctx *gin.Context, clientId openapi_types.UUID) {
+	claims, ok := o.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	err := o.authorizationServer.RevokeConsent(ctx, claims.UserID, clientId)
+	if err != nil {
+		if errors.Is(err, oauth.ErrConsentNotFound) {
+			ctx.JSON(404, gin.H{"error": "Consent not found"})
+			return
+		}
+
+		ctx.JSON(500, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	ctx.JSON(200, gin.H{"message": "Consent revoked"})
+}
+
+// clientAuthentication returns the client id and secret of a token or device authorization request. Clients send
+// them in the form or with HTTP basic auth, ok is false if both are used and don't match
+func clientAuthentication(ctx *gin.Context, formClientID string, formClientSecret string) (clientID string, clientSecret string, ok bool) {
+	username, password, basic := ctx.Request.BasicAuth()
+	if !basic {
+		return formClientID, formClientSecret, true
+	}
+
+	// RFC 6749 2.3.1, the client id and secret are form encoded before they are put in the header
+	clientID, idErr := url.QueryUnescape(username)
+	clientSecret, secretErr := url.QueryUnescape(password)
+	if idErr != nil || secretErr != nil || (formClientSecret != "" && formClientSecret != clientSecret) {
+		return "", "", false
+	}
+
+	if formClientID != "" && formClientID != clientID {
+		return "", "", false
+	}
+
+	return clientID, clientSecret, true
+}
+
+// respondOAuthError responds with the RFC 6749 5.2 error of a token or device authorization request
+func respondOAuthError(ctx *gin.Context, err error) {
+	code := oauth.ErrorCode(err)
+	switch code {
+	case oauth.ErrInvalidClient.Error():
+		ctx.Header("WWW-Authenticate", `Basic realm="oauth"`)
+		ctx.JSON(401, gen.OAuthErrorResponse{Error: code})
+	case oauth.ErrServerError.Error():
+		ctx.JSON(500, gen.OAuthErrorResponse{Error: code})
+	default:
+		ctx.JSON(400, gen.OAuthErrorResponse{Error: code})
+	}
+}
+
+// AuthorizeDevice implements the device authorization endpoint, clients authenticate like at the token endpoint
+func (o *OAuthServerImpl) AuthorizeDevice(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Pragma", "no-cache")
+
+	clientID, clientSecret, ok := clientAuthentication(ctx, ctx.PostForm("client_id"), ctx.PostForm("client_secret"))
+	if !ok {
+		ctx.JSON(400, gen.OAuthErrorResponse{Error: oauth.ErrInvalidRequest.Error()})
+		return
+	}
+
+	device, err := o.authorizationServer.AuthorizeDevice(ctx, oauth.DeviceAuthorizationRequest{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scope:        ctx.PostForm("scope"),
+	})
+	if err != nil {
+		respondOAuthError(ctx, err)
+		return
+	}
+
+	verificationURIComplete, err := withQuery(o.deviceURL, "user_code", device.UserCode)
+	if err != nil {
+		o.l.Error("invalid device url", zap.Error(err))
+		ctx.JSON(500, gen.OAuthErrorResponse{Error: oauth.ErrServerError.Error()})
+		return
+	}
+
+	ctx.JSON(200, gen.DeviceAuthorizationResponse{
+		DeviceCode:              device.DeviceCode,
+		UserCode:                device.UserCode,
+		VerificationUri:         o.deviceURL,
+		VerificationUriComplete: verificationURIComplete,
+		ExpiresIn:               device.ExpiresIn,
+		Interval:                device.Interval,
+	})
+}
+
+func (o *OAuthServerImpl) GetDeviceRequest(ctx *gin.Context, params gen.GetDeviceRequestParams) {
+	_, ok := o.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	deviceRequest, err := o.authorizationServer.PendingDevice(ctx, params.UserCode)
+	if err != nil {
+		if errors.Is(err, oauth.ErrDeviceNotFound) {
+			ctx.JSON(404, gin.H{"error": "Device authorization not found"})
+			return
+		}
+
+		ctx.JSON(500, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	ctx.JSON(200, gen.ConsentRequestResponse{
+		ClientId:   deviceRequest.ClientID,
+		ClientName: deviceRequest.ClientName,
+		Scopes:     deviceRequest.Scopes,
+	})
+}
+
+func (o *OAuthServerImpl) ApproveDevice(ctx *gin.Context) {
+	claims, ok := o.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	var request gen.ApproveDeviceJSONRequestBody
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	err := o.authorizationServer.ApproveDevice(ctx, claims.UserID, request.UserCode, request.Approved, o.authTime(ctx, claims))
+	if err != nil {
+		if errors.Is(err, oauth.ErrDeviceNotFound) {
+			ctx.JSON(404, gin.H{"error": "Device authorization not found"})
+			return
+		}
+
+		ctx.JSON(500, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	ctx.JSON(200, gin.H{})
+}
+
+// Token implements the token endpoint, clients authenticate with client_secret_basic or client_secret_post
+func (o *OAuthServerImpl) Token(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Pragma", "no-cache")
+
+	req := oauth.TokenRequest{
+		GrantType:           ctx.PostForm("grant_type"),
+		Code:                ctx.PostForm("code"),
+		RedirectURI:         ctx.PostForm("redirect_uri"),
+		CodeVerifier:        ctx.PostForm("code_verifier"),
+		RefreshToken:        ctx.PostForm("refresh_token"),
+		DeviceCode:          ctx.PostForm("device_code"),
+		Scope:               ctx.PostForm("scope"),
+		ClientID:            ctx.PostForm("client_id"),
+		ClientSecret:        ctx.PostForm("client_secret"),
+		ClientAssertionType: ctx.PostForm("client_assertion_type"),
+		ClientAssertion:     ctx.PostForm("client_assertion"),
+	}
+
+	var ok bool
+	req.ClientID, req.ClientSecret, ok = clientAuthentication(ctx, req.ClientID, req.ClientSecret)
+	if !ok {
+		ctx.JSON(400, gen.OAuthErrorResponse{Error: oauth.ErrInvalidRequest.Error()})
+		return
+	}
+
+	response, err := o.authorizationServer.Token(ctx, req)
+	if err != nil {
+		respondOAuthError(ctx, err)
+		return
+	}
+
+	tokenResponse := gen.OAuthTokenResponse{
+		AccessToken: response.AccessToken,
+		TokenType:   response.TokenType,
+		ExpiresIn:   response.ExpiresIn,
+	}
+	if response.Scope != "" {
+		tokenResponse.Scope = &response.Scope
+	}
+	if response.RefreshToken != "" {
+		tokenResponse.RefreshToken = &response.RefreshToken
+	}
+	if response.IDToken != "" {
+		tokenResponse.IdToken = &response.IDToken
+	}
+
+	ctx.JSON(200, tokenResponse)
+}
+
+// Introspect implements the token introspection endpoint, RFC 7662. Service accounts authenticate like at the token endpoint
+func (o *OAuthServerImpl) Introspect(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Pragma", "no-cache")
+
+	if o.introspector == nil {
+		ctx.JSON(404, gen.OAuthErrorResponse{Error: oauth.ErrInvalidRequest.Error()})
+		return
+	}
+
+	clientID, clientSecret, ok := clientAuthentication(ctx, ctx.PostForm("client_id"), ctx.PostForm("client_secret"))
+	if !ok {
+		ctx.JSON(400, gen.OAuthErrorResponse{Error: oauth.ErrInvalidRequest.Error()})
+		return
+	}
+
+	token := ctx.PostForm("token")
+	if token == "" {
+		ctx.JSON(400, gen.OAuthErrorResponse{Error: oauth.ErrInvalidRequest.Error()})
+		return
+	}
+
+	result, err := o.introspector.Introspect(ctx, serviceaccounts.ClientCredentials{
+		ClientID:      clientID,
+		ClientSecret:  clientSecret,
+		AssertionType: ctx.PostForm("client_assertion_type"),
+		Assertion:     ctx.PostForm("client_assertion"),
+	}, token)
+	if err != nil {
+		if errors.Is(err, introspection.ErrInvalidCredentials) {
+			respondOAuthError(ctx, oauth.ErrInvalidClient)
+		} else {
+			o.l.Error("failed to introspect token", zap.Error(err))
+			respondOAuthError(ctx, oauth.ErrServerError)
+		}
+		return
+	}
+
+	if !result.Active {
+		ctx.JSON(200, gen.IntrospectionResponse{Active: false})
+		return
+	}
+
+	exp := result.ExpiresAt.Unix()
+	response := gen.IntrospectionResponse{
+		Active:         true,
+		Sub:            &result.Subject,
+		Exp:            &exp,
+		Roles:          &result.Roles,
+		ServiceAccount: &result.ServiceAccount,
+	}
+	permissions := []gen.IntrospectionPermission{}
+	for _, role := range result.RoleAggs {
+		for _, permission := range role.Permissions {
+			permissions = append(permissions, gen.IntrospectionPermission{
+				Id:            permission.ID,
+				RoleId:        role.RoleId,
+				ResourceGroup: permission.ResourceGroup,
+				ResourceKind:  permission.ResourceKind,
+				ResourceName:  permission.ResourceName,
+				Actions:       permission.Actions,
+			})
+		}
+	}
+	response.Permissions = &permissions
+	if !result.IssuedAt.IsZero() {
+		iat := result.IssuedAt.Unix()
+		response.Iat = &iat
+	}
+	if result.SessionID != uuid.Nil {
+		response.Sid = &result.SessionID
+	}
+	if len(result.Scopes) > 0 {
+		scopes := make([]string, 0, len(result.Scopes))
+		for _, id := range result.Scopes {
+			scopes = append(scopes, id.String())
+		}
+		scope := oauth.FormatScope(scopes)
+		response.Scope = &scope
+	}
+
+	ctx.JSON(200, response)
+}
+
+func (o *OAuthServerImpl) ListClients(ctx *gin.Context) {
+	claims, ok := o.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	clients, err := o.authorizationServer.ListClients(ctx, claims.UserID)
+	if err != nil {
+		ctx.JSON(500, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	response := gen.ClientList{Clients: []gen.OAuthClient{}}
+	for _, client := range clients {
+		response.Clients = append(response.Clients, toOAuthClient(client))
+	}
+
+	ctx.JSON(200, response)
+}
+
+func (o *OAuthServerImpl) RegisterClient(ctx *gin.Context) {
+	claims, ok := o.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	var request gen.RegisterClientJSONRequestBody
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	scopes := []string{}
+	for _, scope := range request.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	confidential := request.Confidential != nil && *request.Confidential
+	client, secret, err := o.authorizationServer.RegisterClient(ctx, claims.UserID, request.Name, request.RedirectUris, scopes, confidential)
+	if err != nil {
+		switch {
+		case errors.Is(err, oauth.ErrInvalidRedirectURI):
+			ctx.JSON(400, gin.H{"error": "Invalid redirect uri"})
+		case errors.Is(err, oauth.ErrInvalidScope):
+			ctx.JSON(400, gin.H{"error": "Invalid scope"})
+		case errors.Is(err, oauth.ErrInvalidRequest):
+			ctx.JSON(400, gin.H{"error": "Invalid request"})
+		default:
+			ctx.JSON(500, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	response := gen.ClientRegistrationResponse{
+		Client: toOAuthClient(*client),
+	}
+	if secret != "" {
+		response.ClientSecret = &secret
+	}
+
+	ctx.JSON(200, response)
+}
+
+func (o *OAuthServerImpl) DeleteClient(ctx *gin.Context, clientId openapi_types.UUID) {
+	claims, ok := o.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	err := o.authorizationServer.DeleteClient(ctx, claims.UserID, clientId)
+	if err != nil {
+		if errors.Is(err, oauth.ErrClientNotFound) {
+			ctx.JSON(404, gin.H{"error": "Client not found"})
+			return
+		}
+
+		ctx.JSON(500, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	ctx.JSON(200, gin.H{"message": "Client deleted"})
+}
+
+func (o *OAuthServerImpl) ListServiceAccounts(ctx *gin.Context) {
+	claims, ok := o.authenticateOwner(ctx)
+	if !ok {
+		return
+	}
+
+	accounts, err := o.serviceAccounts.ListServiceAccounts(ctx, claims.UserID)
+	if err != nil {
+		ctx.JSON(500, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	response := gen.ServiceAccountList{ServiceAccounts: []gen.ServiceAccount{}}
+	for _, account := range accounts {
+		response.ServiceAccounts = append(response.ServiceAccounts, toServiceAccount(account))
+	}
+
+	ctx.JSON(200, response)
+}
+
+func (o *OAuthServerImpl) CreateServiceAccount(ctx *gin.Context) {
+	claims, ok := o.authenticateOwner(ctx)
+	if !ok {
+		return
+	}
+
+	var request gen.CreateServiceAccountJSONRequestBody
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	var publicKey []byte
+	if request.PublicKey != nil {
+		publicKey = []byte(*request.PublicKey)
+	}
+
+	account, secret, err := o.serviceAccounts.CreateServiceAccount(ctx, claims.UserID, request.Name, publicKey)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceaccounts.ErrInvalidName):
+			ctx.JSON(400, gin.H{"error": "Invalid name"})
+		case errors.Is(err, serviceaccounts.ErrInvalidPublicKey):
+			ctx.JSON(400, gin.H{"error": "Invalid public key"})
+		default:
+			ctx.JSON(500, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	response := gen.ServiceAccountResponse{
+		ServiceAccount: toServiceAccount(*account),
+	}
+	if secret != "" {
+		response.ClientSecret = &secret
+	}
+
+	ctx.JSON(200, response)
+}
+
+func (o *OAuthServerImpl) DeleteServiceAccount(ctx *gin.Context, clientId openapi_types.UUID) {
+	claims, ok := o.authenticateOwner(ctx)
+	if !ok {
+		return
+	}
+
+	err := o.serviceAccounts.DeleteServiceAccount(ctx, claims.UserID, clientId)
+	if err != nil {
+		if errors.Is(err, serviceaccounts.ErrServiceAccountNotFound) {
+			ctx.JSON(404, gin.H{"error": "Service account not found"})
+			return
+		}
+
+		ctx.JSON(500, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	ctx.JSON(200, gin.H{"message": "Service account deleted"})
+}
+
+func (o *OAuthServerImpl) RotateServiceAccountCredentials(ctx *gin.Context, clientId openapi_types.UUID) {
+	claims, ok := o.authenticateOwner(ctx)
+	if !ok {
+		return
+	}
+
+	var request gen.RotateServiceAccountCredentialsJSONRequestBody
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	var publicKey []byte
+	if request.PublicKey != nil {
+		publicKey = []byte(*request.PublicKey)
+	}
+
+	secret, err := o.serviceAccounts.RotateCredentials(ctx, claims.UserID, clientId, publicKey)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceaccounts.ErrInvalidPublicKey):
+			ctx.JSON(400, gin.H{"error": "Invalid public key"})
+		case errors.Is(err, serviceaccounts.ErrServiceAccountNotFound):
+			ctx.JSON(404, gin.H{"error": "Service account not found"})
+		default:
+			ctx.JSON(500, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	response := gen.ServiceAccountCredentialsResponse{}
+	if secret != "" {
+		response.ClientSecret = &secret
+	}
+
+	ctx.JSON(200, response)
+}
+
+func (o *OAuthServerImpl) ListServiceAccountRoles(ctx *gin.Context, clientId openapi_types.UUID) {
+	claims, ok := o.authenticateOwner(ctx)
+	if !ok {
+		return
+	}
+
+	roles, err := o.serviceAccounts.ListRoles(ctx, claims.UserID, clientId)
+	if err != nil {
+		if errors.Is(err, serviceaccounts.ErrServiceAccountNotFound) {
+			ctx.JSON(404, gin.H{"error": "Service account not found"})
+			return
+		}
+
+		ctx.JSON(500, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	response := gen.ServiceAccountRoleList{Roles: []gen.ServiceAccountRole{}}
+	for _, role := range roles {
+		response.Roles = append(response.Roles, gen.ServiceAccountRole{
+			Id:       role.ID,
+			RoleName: role.RoleName,
+		})
+	}
+
+	ctx.JSON(200, response)
+}
+
+func (o *OAuthServerImpl) GrantServiceAccountRole(ctx *gin.Context, clientId openapi_types.UUID, roleId openapi_types.UUID) {
+	claims, ok := o.authenticateOwner(ctx)
+	if !ok {
+		return
+	}
+
+	err := o.serviceAccounts.GrantRole(ctx, claims.UserID, clientId, roleId)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceaccounts.ErrRoleNotHeld):
+			ctx.JSON(403, gin.H{"error": "Only roles you hold can be granted"})
+		case errors.Is(err, serviceaccounts.ErrServiceAccountNotFound):
+			ctx.JSON(404, gin.H{"error": "Service account not found"})
+		default:
+			ctx.JSON(500, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	ctx.JSON(200, gin.H{"message": "Role granted"})
+}
+
+func (o *OAuthServerImpl) RevokeServiceAccountRole(ctx *gin.Context, clientId openapi_types.UUID, roleId openapi_types.UUID) {
+	claims, ok := o.authenticateOwner(ctx)
+	if !ok {
+		return
+	}
+
+	err := o.serviceAccounts.RevokeRole(ctx, claims.UserID, clientId, roleId)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceaccounts.ErrServiceAccountNotFound):
+			ctx.JSON(404, gin.H{"error": "Service account not found"})
+		case errors.Is(err, serviceaccounts.ErrRoleNotGranted):
+			ctx.JSON(404, gin.H{"error": "Role not granted"})
+		default:
+			ctx.JSON(500, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	ctx.JSON(200, gin.H{"message": "Role revoked"})
+}
+
+func (o *OAuthServerImpl) OpenIDConfiguration(ctx *gin.Context) {
+	metadata := o.authorizationServer.Discovery()
+	response := gen.ProviderMetadata{
+		Issuer:                            metadata.Issuer,
+		AuthorizationEndpoint:             metadata.AuthorizationEndpoint,
+		TokenEndpoint:                     metadata.TokenEndpoint,
+		DeviceAuthorizationEndpoint:       metadata.DeviceAuthorizationEndpoint,
+		UserinfoEndpoint:                  metadata.UserInfoEndpoint,
+		JwksUri:                           metadata.JWKSURI,
+		ScopesSupported:                   metadata.ScopesSupported,
+		ResponseTypesSupported:            metadata.ResponseTypesSupported,
+		GrantTypesSupported:               metadata.GrantTypesSupported,
+		SubjectTypesSupported:             metadata.SubjectTypesSupported,
+		IdTokenSigningAlgValuesSupported:  metadata.IDTokenSigningAlgValuesSupported,
+		TokenEndpointAuthMethodsSupported: metadata.TokenEndpointAuthMethodsSupported,
+		CodeChallengeMethodsSupported:     metadata.CodeChallengeMethodsSupported,
+		ClaimsSupported:                   metadata.ClaimsSupported,
+	}
+	if o.introspector != nil {
+		introspectionEndpoint := strings.TrimSuffix(metadata.Issuer, "/") + "/oauth/introspect"
+		response.IntrospectionEndpoint = &introspectionEndpoint
+	}
+
+	ctx.JSON(200, response)
+}
+
+func (o *OAuthServerImpl) Jwks(ctx *gin.Context) {
+	response := gen.JWKS{Keys: []gen.JWK{}}
+	for _, jwk := range o.authorizationServer.JWKS() {
+		response.Keys = append(response.Keys, gen.JWK{
+			Kty: jwk.KeyType,
+			Use: jwk.Use,
+			Alg: jwk.Algorithm,
+			Kid: jwk.KeyID,
+			N:   jwk.Modulus,
+			E:   jwk.Exponent,
+		})
+	}
+
+	ctx.JSON(200, response)
+}
+
+// UserInfo returns the claims about the user of the bearer access token, errors are reported as in RFC 6750 3
+func (o *OAuthServerImpl) UserInfo(ctx *gin.Context) {
+	accessToken, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+	if !ok || accessToken == "" {
+		ctx.Header("WWW-Authenticate", `Bearer realm="oauth"`)
+		ctx.JSON(401, gen.OAuthErrorResponse{Error: oauth.ErrInvalidToken.Error()})
+		return
+	}
+
+	info, err := o.authorizationServer.UserInfo(ctx, accessToken)
+	if err != nil {
+		code := oauth.ErrorCode(err)
+		switch code {
+		case oauth.ErrInvalidToken.Error():
+			ctx.Header("WWW-Authenticate", `Bearer realm="oauth", error="invalid_token"`)
+			ctx.JSON(401, gen.OAuthErrorResponse{Error: code})
+		case oauth.ErrInsufficientScope.Error():
+			ctx.Header("WWW-Authenticate", `Bearer realm="oauth", error="insufficient_scope", scope="openid"`)
+			ctx.JSON(403, gen.OAuthErrorResponse{Error: code})
+		default:
+			ctx.JSON(500, gen.OAuthErrorResponse{Error: code})
+		}
+		return
+	}
+
+	ctx.JSON(200, gen.UserInfo{
+		Sub:               info.Subject,
+		Email:             info.Email,
+		EmailVerified:     info.EmailVerified,
+		PreferredUsername: info.PreferredUsername,
+	})
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	gen "github.com/ooqls/go-auth/api/v1/gen/gen_oauth"
+	"github.com/ooqls/go-auth/domain/v1/authentication"
+	authmocks "github.com/ooqls/go-auth/domain/v1/authentication/mocks"
+	oauthmocks "github.com/ooqls/go-auth/domain/v1/oauth/mocks"
+	"github.com/ooqls/go-auth/records/v1/oauthclients"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestAuthorizeRefusesPersonalAccessTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	gin.SetMode(gin.TestMode)
+
+	userID := uuid.New()
+	accessTokens := authmocks.NewMockAccessTokenVerifier(ctrl)
+	accessTokens.EXPECT().VerifyAccessToken(gomock.Any(), gomock.Any()).AnyTimes().Return(&authentication.UserClaims{UserID: userID, AccessTokenID: uuid.New()}, nil)
+
+	// the authorization server must not issue a code, Authorize is not expected
+	authorizationServer := oauthmocks.NewMockAuthorizationServer(ctrl)
+	authorizationServer.EXPECT().ValidateClient(gomock.Any(), "client", "https://client.example.com/callback").AnyTimes().Return(&oauthclients.Client{}, nil)
+
+	server := NewOAuthServer(zap.NewNop(), authmocks.NewTestAuthenticator(ctrl, accessTokens, nil), authorizationServer, nil, nil,
+		"https://auth.example.com/login", "https://auth.example.com/consent", "https://auth.example.com/device")
+
+	do := func(cookie *http.Cookie) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = httptest.NewRequest("GET", "/oauth/authorize?client_id=client", nil)
+		if cookie != nil {
+			ctx.Request.AddCookie(cookie)
+		}
+
+		server.Authorize(ctx, gen.AuthorizeParams{
+			ResponseType:        "code",
+			ClientId:            "client",
+			RedirectUri:         "https://client.example.com/callback",
+			CodeChallenge:       "challenge",
+			CodeChallengeMethod: "S256",
+		})
+		return w
+	}
+
+	w := do(&http.Cookie{Name: "OKEY", Value: authentication.AccessTokenPrefix + "test"})
+	assert.Equalf(t, 403, w.Code, "personal access tokens should not authorize clients")
+	assert.Emptyf(t, w.Header().Get("Location"), "personal access tokens should not be redirected with a code")
+
+	w = do(nil)
+	assert.Equalf(t, http.StatusFound, w.Code, "requests without a token should be redirected to login")
+	assert.Truef(t, strings.HasPrefix(w.Header().Get("Location"), "https://auth.example.com/login"), "should redirect to the login page: %s", w.Header().Get("Location"))
+}
@@ -1,30 +1,114 @@
 package main
 
 import (
+	"context"
+	"crypto/subtle"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
 	gen "github.com/ooqls/go-auth/api/v1/gen/gen_authentication"
+	"github.com/ooqls/go-auth/domain/v1/accesstokens"
 	"github.com/ooqls/go-auth/domain/v1/authentication"
 	"github.com/ooqls/go-auth/domain/v1/authorization"
+	"github.com/ooqls/go-auth/domain/v1/directory"
+	"github.com/ooqls/go-auth/domain/v1/federation"
 	"github.com/ooqls/go-auth/domain/v1/serivce/users"
 	"go.uber.org/zap"
 )
 
 var _ gen.ServerInterface = &AuthenticationServerImpl{}
 
-func NewAuthenticationServer(l *zap.Logger, authenticator authentication.Authenticator, userService users.UserService) *AuthenticationServerImpl {
+func NewAuthenticationServer(
+	l *zap.Logger,
+	authenticator authentication.Authenticator,
+	passkeyAuthenticator authentication.Authenticator,
+	passkeyRegistrar authentication.PasskeyRegistrar,
+	recoverer authentication.Recoverer,
+	emailVerifier *authentication.EmailVerifier,
+	userService users.UserService,
+	accessTokens accesstokens.AccessTokenService,
+	federator federation.Federator,
+	directoryAuthenticator directory.Authenticator,
+	magicLinker authentication.MagicLinker) *AuthenticationServerImpl {
+
 	return &AuthenticationServerImpl{
-		l:             l,
-		Authenticator: authenticator,
-		userService:   userService,
+		l:                      l,
+		Authenticator:          authenticator,
+		passkeyAuthenticator:   passkeyAuthenticator,
+		passkeyRegistrar:       passkeyRegistrar,
+		recoverer:              recoverer,
+		emailVerifier:          emailVerifier,
+		userService:            userService,
+		accessTokens:           accessTokens,
+		federator:              federator,
+		directoryAuthenticator: directoryAuthenticator,
+		magicLinker:            magicLinker,
 	}
 }
 
 type AuthenticationServerImpl struct {
 	l             *zap.Logger
 	Authenticator authentication.Authenticator
-	userService   users.UserService
+	// passkeyAuthenticator logs users in with the WebAuthn challenger, it shares its token and session state with Authenticator
+	passkeyAuthenticator authentication.Authenticator
+	passkeyRegistrar     authentication.PasskeyRegistrar
+	recoverer            authentication.Recoverer
+	emailVerifier        *authentication.EmailVerifier
+	userService          users.UserService
+	accessTokens         accesstokens.AccessTokenService
+	// federator logs users in with upstream identity providers, it is nil when none are configured
+	federator federation.Federator
+	// directoryAuthenticator logs users in with their LDAP password, it is nil when no directory is configured
+	directoryAuthenticator directory.Authenticator
+	magicLinker            authentication.MagicLinker
+}
+
+// clientContext returns the request context carrying the client info recorded on new sessions
+func clientContext(ctx *gin.Context) context.Context {
+	return authentication.WithClientInfo(ctx.Request.Context(), authentication.ClientInfo{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	})
+}
+
+// authTokenClaims returns the claims of the request's auth token
+func authTokenClaims(ctx *gin.Context, authenticator authentication.Authenticator) (*authentication.UserClaims, error) {
+	okey, err := ctx.Cookie("OKEY")
+	if err != nil {
+		return nil, err
+	}
+
+	return authenticator.IsAuthenticated(ctx, okey)
+}
+
+// authenticate returns the claims of the request's auth token, responding with 401 if it is not authenticated.
+// Personal access tokens and service accounts are refused with 403, the account is only managed from an
+// interactive session
+func (a *AuthenticationServerImpl) authenticate(ctx *gin.Context) (*authentication.UserClaims, bool) {
+	claims, err := authTokenClaims(ctx, a.Authenticator)
+	if err != nil {
+		ctx.JSON(401, gin.H{"error": "Authentication failed"})
+		return nil, false
+	}
+
+	if claims.AccessTokenID != uuid.Nil {
+		ctx.JSON(403, gin.H{"error": "Personal access tokens can not manage the account"})
+		return nil, false
+	}
+
+	if claims.ServiceAccount {
+		ctx.JSON(403, gin.H{"error": "Service accounts can not manage the account"})
+		return nil, false
+	}
+
+	return claims, true
 }
 
 func (a *AuthenticationServerImpl) LoginChallenge(ctx *gin.Context) {
@@ -49,15 +133,31 @@ func (a *AuthenticationServerImpl) LoginChallenge(ctx *gin.Context) {
 		return
 	}
 	a.l.Sugar().Infof("Issued challenge for user %s, salt: %s", user.Username, user.Salt)
-	serverResponse := gen.ChallengeServerResponse{
-		Id:              challenge.ID,
-		Base64Challenge: base64.StdEncoding.EncodeToString(challenge.Challenge),
-		Base64Salt:      base64.StdEncoding.EncodeToString(challenge.User.Salt),
+	serverResponse, err := challengeServerResponse(challenge)
+	if err != nil {
+		a.l.Error("user has an unsupported key algorithm", zap.Error(err))
+		ctx.JSON(500, gin.H{"error": "failed to issue challenge"})
+		return
 	}
 
 	ctx.JSON(200, serverResponse)
 }
 
+// challengeServerResponse returns the challenge with what the user needs to answer it with their key
+func challengeServerResponse(challenge *authentication.Challenge) (*gen.ChallengeServerResponse, error) {
+	algorithm, err := authentication.ParseAlgorithm(challenge.User.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gen.ChallengeServerResponse{
+		Id:              challenge.ID,
+		Base64Challenge: base64.StdEncoding.EncodeToString(challenge.Challenge),
+		Base64Salt:      base64.StdEncoding.EncodeToString(challenge.User.Salt),
+		Algorithm:       gen.KeyAlgorithm(algorithm),
+	}, nil
+}
+
 func (a *AuthenticationServerImpl) LoginChallengeResponse(ctx *gin.Context) {
 	var request gen.ChallengeClientResponse
 	if err := ctx.ShouldBindJSON(&request); err != nil {
@@ -71,15 +171,502 @@ func (a *AuthenticationServerImpl) LoginChallengeResponse(ctx *gin.Context) {
 		return
 	}
 
-	okey, rkey, userID, err := a.Authenticator.ChallengeResponse(ctx, request.Id, challengeStr)
+	tokens, err := a.Authenticator.ChallengeResponse(clientContext(ctx), request.Id, challengeStr)
+	respondLogin(ctx, tokens, err)
+}
+
+func (a *AuthenticationServerImpl) LdapLogin(ctx *gin.Context) {
+	if a.directoryAuthenticator == nil {
+		ctx.JSON(404, gin.H{"error": "no directory is configured"})
+		return
+	}
+
+	var request gen.LdapLoginRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	tokens, err := a.directoryAuthenticator.Login(clientContext(ctx), request.Username, request.Password)
+	switch {
+	case errors.Is(err, directory.ErrUsernameTaken):
+		ctx.JSON(409, gin.H{"error": "username belongs to an account that is not in the directory"})
+		return
+	case errors.Is(err, directory.ErrDirectoryUnavailable):
+		ctx.JSON(502, gin.H{"error": "directory unavailable"})
+		return
+	}
+
+	respondLogin(ctx, tokens, err)
+}
+
+// respondLogin answers the response to a login challenge, setting the login cookies unless MFA is still required
+func respondLogin(ctx *gin.Context, tokens *authentication.TokenResponse, err error) {
 	if err != nil {
+		if respondAccountLocked(ctx, err) {
+			return
+		}
+
+		if errors.Is(err, authentication.ErrEmailNotVerified) {
+			ctx.JSON(403, gin.H{"error": "email not verified"})
+			return
+		}
+
 		ctx.JSON(401, gin.H{"error": "Authentication failed"})
 		return
 	}
 
-	ctx.SetCookie("OKEY", okey, 0, "/", "", true, true)
-	ctx.SetCookie("RKEY", rkey, 0, "/", "", true, true)
-	ctx.SetCookie("UID", userID, 0, "/", "", true, true)
+	resp := gen.LoginResponse{MfaRequired: tokens.MFARequired}
+	if tokens.ServerProof != nil {
+		serverProof := base64.StdEncoding.EncodeToString(tokens.ServerProof)
+		resp.Base64ServerProof = &serverProof
+	}
+
+	if tokens.MFARequired {
+		resp.MfaToken = &tokens.MFAToken
+		ctx.JSON(200, resp)
+		return
+	}
+
+	setLoginCookies(ctx, tokens)
+	ctx.JSON(200, resp)
+}
+
+// respondAccountLocked responds with 429 and when to retry if the error is an AccountLockedError
+func respondAccountLocked(ctx *gin.Context, err error) bool {
+	var lockedErr *authentication.AccountLockedError
+	if !errors.As(err, &lockedErr) {
+		return false
+	}
+
+	ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(lockedErr.RetryAfter.Seconds()))))
+	ctx.JSON(429, gin.H{"error": "Too many failed attempts"})
+	return true
+}
+
+// setLoginCookies sets the auth token, refresh token and user id cookies of a finished login
+func setLoginCookies(ctx *gin.Context, tokens *authentication.TokenResponse) {
+	ctx.SetCookie("OKEY", tokens.AuthToken, 0, "/", "", true, true)
+	ctx.SetCookie("RKEY", tokens.RefreshToken, 0, "/", "", true, true)
+	ctx.SetCookie("UID", tokens.UserId.String(), 0, "/", "", true, true)
+}
+
+func (a *AuthenticationServerImpl) EnrollMFA(ctx *gin.Context) {
+	claims, ok := a.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	user, err := a.userService.GetUser(authorization.NewInternalOperationContext(ctx), claims.UserID)
+	if err != nil || user == nil {
+		a.l.Error("failed to get user", zap.Error(err))
+		ctx.JSON(500, gin.H{"error": "failed to enroll mfa"})
+		return
+	}
+
+	enrollment, err := a.Authenticator.EnrollMFA(ctx, claims.UserID, user.Username)
+	if err != nil {
+		if errors.Is(err, authentication.ErrMFAAlreadyEnabled) {
+			ctx.JSON(409, gin.H{"error": "mfa already enabled"})
+			return
+		}
+
+		ctx.JSON(500, gin.H{"error": "failed to enroll mfa"})
+		return
+	}
+
+	ctx.JSON(200, gen.MFAEnrollResponse{
+		Secret:     enrollment.Secret,
+		OtpauthUri: enrollment.URI,
+	})
+}
+
+func (a *AuthenticationServerImpl) ConfirmMFA(ctx *gin.Context) {
+	var request gen.MFAConfirmRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	claims, ok := a.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	codes, err := a.Authenticator.ConfirmMFA(ctx, claims.UserID, request.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, authentication.ErrMFAFailed):
+			ctx.JSON(400, gin.H{"error": "invalid code"})
+		case errors.Is(err, authentication.ErrMFANotEnrolled):
+			ctx.JSON(404, gin.H{"error": "mfa not enrolled"})
+		case errors.Is(err, authentication.ErrMFAAlreadyEnabled):
+			ctx.JSON(409, gin.H{"error": "mfa already enabled"})
+		default:
+			ctx.JSON(500, gin.H{"error": "failed to confirm mfa"})
+		}
+		return
+	}
+
+	a.l.Info("user enabled mfa", zap.String("user_id", claims.UserID.String()))
+	ctx.JSON(200, gen.MFAConfirmResponse{RecoveryCodes: codes})
+}
+
+func (a *AuthenticationServerImpl) VerifyMFA(ctx *gin.Context) {
+	var request gen.MFAVerifyRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	tokens, err := a.Authenticator.VerifyMFA(clientContext(ctx), request.MfaToken, request.Code)
+	if err != nil {
+		if respondAccountLocked(ctx, err) {
+			return
+		}
+
+		ctx.JSON(401, gin.H{"error": "Authentication failed"})
+		return
+	}
+
+	setLoginCookies(ctx, tokens)
+	ctx.JSON(200, gin.H{})
+}
+
+// passkeyOptions returns the ceremony id and the WebAuthn options held by the challenge
+func passkeyOptions(challenge *authentication.Challenge) (*gen.PasskeyOptionsResponse, error) {
+	var options map[string]interface{}
+	err := json.Unmarshal(challenge.Challenge, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gen.PasskeyOptionsResponse{
+		Id:      challenge.ID,
+		Options: options,
+	}, nil
+}
+
+func (a *AuthenticationServerImpl) BeginPasskeyRegistration(ctx *gin.Context) {
+	claims, ok := a.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	user, err := a.userService.GetUser(authorization.NewInternalOperationContext(ctx), claims.UserID)
+	if err != nil || user == nil {
+		a.l.Error("failed to get user", zap.Error(err))
+		ctx.JSON(500, gin.H{"error": "failed to begin passkey registration"})
+		return
+	}
+
+	challenge, err := a.passkeyRegistrar.BeginPasskeyRegistration(ctx, user)
+	if err != nil {
+		ctx.JSON(500, gin.H{"error": "failed to begin passkey registration"})
+		return
+	}
+
+	resp, err := passkeyOptions(challenge)
+	if err != nil {
+		a.l.Error("failed to decode passkey options", zap.Error(err))
+		ctx.JSON(500, gin.H{"error": "failed to begin passkey registration"})
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+func (a *AuthenticationServerImpl) FinishPasskeyRegistration(ctx *gin.Context) {
+	var request gen.PasskeyFinishRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	claims, ok := a.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	credential, err := json.Marshal(request.Credential)
+	if err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	user, err := a.userService.GetUser(authorization.NewInternalOperationContext(ctx), claims.UserID)
+	if err != nil || user == nil {
+		a.l.Error("failed to get user", zap.Error(err))
+		ctx.JSON(500, gin.H{"error": "failed to register passkey"})
+		return
+	}
+
+	passkey, err := a.passkeyRegistrar.FinishPasskeyRegistration(ctx, user, request.Id, credential)
+	if err != nil {
+		if errors.Is(err, authentication.ErrInternal) {
+			ctx.JSON(500, gin.H{"error": "failed to register passkey"})
+			return
+		}
+
+		ctx.JSON(400, gin.H{"error": "invalid passkey registration"})
+		return
+	}
+
+	a.l.Info("user registered a passkey", zap.String("user_id", claims.UserID.String()))
+	ctx.JSON(200, gen.PasskeyRegistrationResponse{
+		Id:        passkey.ID,
+		CreatedAt: passkey.CreatedAt,
+	})
+}
+
+func (a *AuthenticationServerImpl) BeginPasskeyLogin(ctx *gin.Context) {
+	var request gen.LoginChallengeRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	authCtx := authorization.NewInternalOperationContext(ctx)
+
+	user, err := a.userService.GetUserByUsername(authCtx, request.Username)
+	if err != nil {
+		ctx.JSON(401, gin.H{"error": "Authentication failed"})
+		return
+	}
+
+	challenge, err := a.passkeyAuthenticator.ChallengeRequest(authCtx, user)
+	if err != nil {
+		if errors.Is(err, authentication.ErrNoPasskeys) {
+			ctx.JSON(401, gin.H{"error": "Authentication failed"})
+			return
+		}
+
+		a.l.Error("failed to issue passkey challenge", zap.Error(err))
+		ctx.JSON(500, gin.H{"error": "failed to issue challenge"})
+		return
+	}
+
+	resp, err := passkeyOptions(challenge)
+	if err != nil {
+		a.l.Error("failed to decode passkey options", zap.Error(err))
+		ctx.JSON(500, gin.H{"error": "failed to issue challenge"})
+		return
+	}
+
+	ctx.JSON(200, resp)
+}
+
+func (a *AuthenticationServerImpl) FinishPasskeyLogin(ctx *gin.Context) {
+	var request gen.PasskeyFinishRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	credential, err := json.Marshal(request.Credential)
+	if err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	tokens, err := a.passkeyAuthenticator.ChallengeResponse(clientContext(ctx), request.Id, credential)
+	if errors.Is(err, authentication.ErrSignCountRegression) {
+		a.l.Warn("passkey sign count regressed, the passkey may be cloned", zap.String("challenge_id", request.Id.String()))
+	}
+
+	respondLogin(ctx, tokens, err)
+}
+
+func (a *AuthenticationServerImpl) CredentialChangeChallenge(ctx *gin.Context) {
+	claims, ok := a.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	authCtx := authorization.NewInternalOperationContext(ctx)
+	user, err := a.userService.GetUser(authCtx, claims.UserID)
+	if err != nil || user == nil {
+		a.l.Error("failed to get user", zap.Error(err))
+		ctx.JSON(500, gin.H{"error": "failed to issue challenge"})
+		return
+	}
+
+	challenge, err := a.Authenticator.ChallengeRequest(authCtx, user)
+	if err != nil {
+		a.l.Error("failed to issue challenge", zap.Error(err))
+		ctx.JSON(500, gin.H{"error": "failed to issue challenge"})
+		return
+	}
+
+	serverResponse, err := challengeServerResponse(challenge)
+	if err != nil {
+		a.l.Error("user has an unsupported key algorithm", zap.Error(err))
+		ctx.JSON(500, gin.H{"error": "failed to issue challenge"})
+		return
+	}
+
+	ctx.JSON(200, serverResponse)
+}
+
+func (a *AuthenticationServerImpl) ChangeCredentials(ctx *gin.Context) {
+	var request gen.CredentialChangeRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	claims, ok := a.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	proof, err := base64.StdEncoding.DecodeString(request.Base64Challenge)
+	if err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	change, err := base64.StdEncoding.DecodeString(request.Base64Change)
+	if err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	credentials, err := a.Authenticator.ChangeCredentials(ctx, claims.UserID, request.Id, proof, change)
+	if err != nil {
+		if respondAccountLocked(ctx, err) {
+			return
+		}
+
+		if errors.Is(err, authentication.ErrInvalidRegistration) {
+			ctx.JSON(400, gin.H{"error": "invalid credentials"})
+			return
+		}
+
+		ctx.JSON(401, gin.H{"error": "Authentication failed"})
+		return
+	}
+
+	err = a.userService.ChangeCredentials(authorization.NewInternalOperationContext(ctx), claims.UserID,
+		string(credentials.OldKey), string(credentials.Key), string(credentials.Salt), credentials.Algorithm)
+	if err != nil {
+		if errors.Is(err, users.ErrCredentialsChanged) {
+			ctx.JSON(409, gin.H{"error": "credentials changed concurrently"})
+			return
+		}
+
+		a.l.Error("failed to change credentials", zap.Error(err))
+		ctx.JSON(500, gin.H{"error": "failed to change credentials"})
+		return
+	}
+
+	a.l.Info("user changed credentials", zap.String("user_id", claims.UserID.String()), zap.String("algorithm", string(credentials.Algorithm)))
+
+	err = a.Authenticator.TerminateOtherSessions(ctx, claims.UserID, claims.SessionID)
+	if err != nil {
+		a.l.Error("failed to terminate other sessions after a credential change", zap.String("user_id", claims.UserID.String()), zap.Error(err))
+		ctx.JSON(500, gin.H{"error": "failed to terminate other sessions"})
+		return
+	}
+
+	ctx.JSON(200, gin.H{})
+}
+
+// RequestRecovery responds the same whether or not the user exists so it can't be used to find usernames
+func (a *AuthenticationServerImpl) RequestRecovery(ctx *gin.Context) {
+	var request gen.RecoveryRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	user, err := a.userService.GetUserByUsername(authorization.NewInternalOperationContext(ctx), request.Username)
+	if err != nil {
+		a.l.Error("failed to get user", zap.Error(err))
+	} else if user != nil {
+		err = a.recoverer.RequestRecovery(ctx, user)
+		if err != nil {
+			a.l.Warn("failed to request recovery", zap.String("user_id", user.ID.String()), zap.Error(err))
+		}
+	}
+
+	ctx.JSON(202, gin.H{})
+}
+
+func (a *AuthenticationServerImpl) CompleteRecovery(ctx *gin.Context) {
+	var request gen.RecoveryCompleteRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	key, err := base64.StdEncoding.DecodeString(request.Base64Key)
+	if err != nil {
+		ctx.JSON(400, gin.H{"error": "invalid key"})
+		return
+	}
+
+	secret, err := base64.StdEncoding.DecodeString(request.EncryptedSecret)
+	if err != nil {
+		ctx.JSON(400, gin.H{"error": "invalid secret"})
+		return
+	}
+
+	var algorithmName string
+	if request.Algorithm != nil {
+		algorithmName = string(*request.Algorithm)
+	}
+
+	algorithm, err := authentication.ParseAlgorithm(algorithmName)
+	if err != nil {
+		ctx.JSON(400, gin.H{"error": "invalid algorithm"})
+		return
+	}
+
+	userId, credentials, err := a.recoverer.CompleteRecovery(ctx, request.Token, authentication.Registration{
+		Username:  request.Username,
+		Key:       key,
+		Secret:    secret,
+		Algorithm: algorithm,
+	})
+	if err != nil {
+		if errors.Is(err, authentication.ErrRecoveryFailed) {
+			ctx.JSON(401, gin.H{"error": "invalid recovery token"})
+			return
+		}
+
+		ctx.JSON(400, gin.H{"error": "invalid credentials"})
+		return
+	}
+
+	authCtx := authorization.NewInternalOperationContext(ctx)
+	user, err := a.userService.GetUser(authCtx, userId)
+	if err != nil || user == nil {
+		a.l.Error("failed to get recovered user", zap.String("user_id", userId.String()), zap.Error(err))
+		ctx.JSON(500, gin.H{"error": "failed to reset credentials"})
+		return
+	}
+
+	err = a.userService.ChangeCredentials(authCtx, userId, string(user.Key), string(credentials.Key), string(credentials.Salt), credentials.Algorithm)
+	if err != nil {
+		if errors.Is(err, users.ErrCredentialsChanged) {
+			ctx.JSON(409, gin.H{"error": "credentials changed concurrently"})
+			return
+		}
+
+		a.l.Error("failed to reset credentials", zap.Error(err))
+		ctx.JSON(500, gin.H{"error": "failed to reset credentials"})
+		return
+	}
+
+	a.l.Info("user reset credentials", zap.String("user_id", userId.String()), zap.String("algorithm", string(credentials.Algorithm)))
+
+	err = a.Authenticator.RevokeAllForUser(ctx, userId)
+	if err != nil {
+		a.l.Error("failed to revoke sessions after a recovery", zap.String("user_id", userId.String()), zap.Error(err))
+		ctx.JSON(500, gin.H{"error": "failed to revoke sessions"})
+		return
+	}
 
 	ctx.JSON(200, gin.H{})
 }
@@ -91,19 +678,307 @@ func (a *AuthenticationServerImpl) RefreshToken(ctx *gin.Context) {
 		return
 	}
 
-	resp, err := a.Authenticator.AuthenticateWithToken(ctx, request.RefreshToken)
+	resp, err := a.Authenticator.Refresh(ctx, request.RefreshToken)
 	if err != nil {
+		if errors.Is(err, authentication.ErrRefreshTokenReused) {
+			a.l.Warn("refresh token reuse detected")
+		}
+
 		ctx.JSON(401, gin.H{"error": "Authentication failed"})
 		return
 	}
 
-	a.l.Sugar().Infow("User %s refreshed token successfully", request.RefreshToken)
+	a.l.Info("user refreshed token successfully", zap.String("user_id", resp.UserId.String()))
 
 	ctx.SetCookie("OKEY", resp.AuthToken, 0, "/", "", true, true)
+	ctx.SetCookie("RKEY", resp.RefreshToken, 0, "/", "", true, true)
+
+	ctx.JSON(200, gin.H{})
+}
+
+func (a *AuthenticationServerImpl) Logout(ctx *gin.Context, params gen.LogoutParams) {
+	claims, ok := a.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	okey, _ := ctx.Cookie("OKEY")
+	if err := a.Authenticator.Revoke(ctx, okey); err != nil {
+		a.l.Error("failed to revoke authentication token", zap.Error(err))
+		ctx.JSON(500, gin.H{"error": "failed to logout"})
+		return
+	}
+
+	if rkey, err := ctx.Cookie("RKEY"); err == nil && rkey != "" {
+		if err := a.Authenticator.Revoke(ctx, rkey); err != nil && !errors.Is(err, authentication.ErrInvalidToken) {
+			a.l.Error("failed to revoke refresh token", zap.Error(err))
+			ctx.JSON(500, gin.H{"error": "failed to logout"})
+			return
+		}
+	}
+
+	if params.All != nil && *params.All {
+		if err := a.Authenticator.RevokeAllForUser(ctx, claims.UserID); err != nil {
+			a.l.Error("failed to revoke all tokens for user", zap.Error(err))
+			ctx.JSON(500, gin.H{"error": "failed to logout"})
+			return
+		}
+	}
+
+	a.l.Info("user logged out", zap.String("user_id", claims.UserID.String()))
+
+	ctx.SetCookie("OKEY", "", -1, "/", "", true, true)
+	ctx.SetCookie("RKEY", "", -1, "/", "", true, true)
+	ctx.SetCookie("UID", "", -1, "/", "", true, true)
+
+	ctx.JSON(200, gin.H{})
+}
+
+func (a *AuthenticationServerImpl) ListSessions(ctx *gin.Context) {
+	claims, ok := a.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	userSessions, err := a.Authenticator.ListSessions(ctx, claims.UserID)
+	if err != nil {
+		a.l.Error("failed to list sessions", zap.Error(err))
+		ctx.JSON(500, gin.H{"error": "failed to list sessions"})
+		return
+	}
+
+	resp := gen.SessionList{Sessions: make([]gen.Session, 0, len(userSessions))}
+	for _, session := range userSessions {
+		resp.Sessions = append(resp.Sessions, gen.Session{
+			Id:         session.ID,
+			UserAgent:  session.UserAgent,
+			IpAddress:  session.IpAddress,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID == claims.SessionID,
+		})
+	}
 
+	ctx.JSON(200, resp)
+}
+
+func (a *AuthenticationServerImpl) TerminateSession(ctx *gin.Context, id openapi_types.UUID) {
+	claims, ok := a.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	err := a.Authenticator.TerminateSession(ctx, claims.UserID, id)
+	if err != nil {
+		if errors.Is(err, authentication.ErrSessionNotFound) {
+			ctx.JSON(404, gin.H{"error": "session not found"})
+			return
+		}
+
+		a.l.Error("failed to terminate session", zap.Error(err))
+		ctx.JSON(500, gin.H{"error": "failed to terminate session"})
+		return
+	}
+
+	ctx.JSON(200, gin.H{})
+}
+
+func (a *AuthenticationServerImpl) TerminateOtherSessions(ctx *gin.Context) {
+	claims, ok := a.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	err := a.Authenticator.TerminateOtherSessions(ctx, claims.UserID, claims.SessionID)
+	if err != nil {
+		a.l.Error("failed to terminate other sessions", zap.Error(err))
+		ctx.JSON(500, gin.H{"error": "failed to terminate sessions"})
+		return
+	}
+
+	a.l.Info("user signed out other devices", zap.String("user_id", claims.UserID.String()))
 	ctx.JSON(200, gin.H{})
 }
 
+// toAccessToken returns the API representation of a personal access token
+func toAccessToken(token accesstokens.AccessToken) gen.AccessToken {
+	resp := gen.AccessToken{
+		Id:            token.ID,
+		Name:          token.Name,
+		PermissionIds: token.PermissionIDs,
+		CreatedAt:     token.CreatedAt,
+		ExpiresAt:     token.ExpiresAt,
+	}
+
+	if token.LastUsedAt.Valid {
+		resp.LastUsedAt = &token.LastUsedAt.Time
+	}
+
+	return resp
+}
+
+func (a *AuthenticationServerImpl) ListAccessTokens(ctx *gin.Context) {
+	claims, ok := a.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	tokens, err := a.accessTokens.ListAccessTokens(ctx, claims.UserID)
+	if err != nil {
+		ctx.JSON(500, gin.H{"error": "failed to list access tokens"})
+		return
+	}
+
+	resp := gen.AccessTokenList{AccessTokens: make([]gen.AccessToken, 0, len(tokens))}
+	for _, token := range tokens {
+		resp.AccessTokens = append(resp.AccessTokens, toAccessToken(token))
+	}
+
+	ctx.JSON(200, resp)
+}
+
+func (a *AuthenticationServerImpl) CreateAccessToken(ctx *gin.Context) {
+	claims, ok := a.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	var req gen.CreateAccessTokenJSONRequestBody
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	created, token, err := a.accessTokens.CreateAccessToken(ctx, claims.UserID, req.Name, req.ExpiresAt, req.PermissionIds)
+	if err != nil {
+		switch {
+		case errors.Is(err, accesstokens.ErrInvalidName), errors.Is(err, accesstokens.ErrInvalidExpiry), errors.Is(err, accesstokens.ErrInvalidScope):
+			ctx.JSON(400, gin.H{"error": err.Error()})
+		case errors.Is(err, accesstokens.ErrPermissionNotHeld):
+			ctx.JSON(403, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(500, gin.H{"error": "failed to create access token"})
+		}
+		return
+	}
+
+	ctx.JSON(201, gen.CreatedAccessTokenResponse{
+		AccessToken: toAccessToken(*created),
+		Token:       token,
+	})
+}
+
+func (a *AuthenticationServerImpl) RevokeAccessToken(ctx *gin.Context, id openapi_types.UUID) {
+	claims, ok := a.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	err := a.accessTokens.RevokeAccessToken(ctx, claims.UserID, id)
+	if err != nil {
+		if errors.Is(err, accesstokens.ErrAccessTokenNotFound) {
+			ctx.JSON(404, gin.H{"error": "access token not found"})
+			return
+		}
+
+		ctx.JSON(500, gin.H{"error": "failed to revoke access token"})
+		return
+	}
+
+	ctx.JSON(200, gin.H{})
+}
+
+// federationStateMaxAge is how long the FSTATE cookie binding a federated login to the user agent lives
+const federationStateMaxAge = 10 * 60
+
+func (a *AuthenticationServerImpl) ListFederationProviders(ctx *gin.Context) {
+	resp := gen.FederationProviderList{Providers: []gen.FederationProvider{}}
+	if a.federator != nil {
+		for _, provider := range a.federator.Providers() {
+			resp.Providers = append(resp.Providers, gen.FederationProvider{
+				Id:   provider.ID,
+				Name: provider.Name,
+			})
+		}
+	}
+
+	ctx.JSON(200, resp)
+}
+
+func (a *AuthenticationServerImpl) FederationLogin(ctx *gin.Context, provider string, params gen.FederationLoginParams) {
+	if a.federator == nil {
+		ctx.JSON(404, gin.H{"error": "identity provider not found"})
+		return
+	}
+
+	var returnTo string
+	if params.ReturnTo != nil {
+		returnTo = *params.ReturnTo
+	}
+
+	authURL, state, err := a.federator.BeginLogin(ctx, provider, returnTo)
+	if err != nil {
+		switch {
+		case errors.Is(err, federation.ErrProviderNotFound):
+			ctx.JSON(404, gin.H{"error": "identity provider not found"})
+		case errors.Is(err, federation.ErrInvalidReturnTo):
+			ctx.JSON(400, gin.H{"error": "invalid return_to"})
+		case errors.Is(err, federation.ErrProviderError):
+			ctx.JSON(502, gin.H{"error": "identity provider unavailable"})
+		default:
+			ctx.JSON(500, gin.H{"error": "failed to start login"})
+		}
+		return
+	}
+
+	// the callback only accepts the state from the user agent that started the login, so an attacker
+	// can not log a victim into the attacker's account with their own callback url
+	ctx.SetCookie("FSTATE", state, federationStateMaxAge, "/auth/federation", "", true, true)
+	ctx.Redirect(http.StatusFound, authURL)
+}
+
+func (a *AuthenticationServerImpl) FederationCallback(ctx *gin.Context, provider string, params gen.FederationCallbackParams) {
+	if a.federator == nil {
+		ctx.JSON(404, gin.H{"error": "identity provider not found"})
+		return
+	}
+
+	boundState, _ := ctx.Cookie("FSTATE")
+	ctx.SetCookie("FSTATE", "", -1, "/auth/federation", "", true, true)
+
+	if params.Error != nil {
+		a.l.Info("identity provider login failed", zap.String("provider", provider), zap.String("error", *params.Error))
+		ctx.JSON(400, gin.H{"error": "login failed at the identity provider"})
+		return
+	}
+
+	if params.State == nil || params.Code == nil || boundState == "" || subtle.ConstantTimeCompare([]byte(boundState), []byte(*params.State)) != 1 {
+		ctx.JSON(400, gin.H{"error": "invalid login state"})
+		return
+	}
+
+	result, err := a.federator.CompleteLogin(clientContext(ctx), provider, *params.State, *params.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, federation.ErrProviderNotFound):
+			ctx.JSON(404, gin.H{"error": "identity provider not found"})
+		case errors.Is(err, federation.ErrInvalidState):
+			ctx.JSON(400, gin.H{"error": "invalid login state"})
+		case errors.Is(err, federation.ErrProviderError), errors.Is(err, federation.ErrInvalidIDToken):
+			ctx.JSON(502, gin.H{"error": "invalid identity provider response"})
+		case errors.Is(err, federation.ErrAccountNotLinked), errors.Is(err, federation.ErrAmbiguousAccounts), errors.Is(err, authentication.ErrEmailNotVerified):
+			ctx.JSON(403, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(500, gin.H{"error": "failed to log in"})
+		}
+		return
+	}
+
+	setLoginCookies(ctx, result.Tokens)
+	ctx.Redirect(http.StatusFound, result.ReturnTo)
+}
+
 func (a AuthenticationServerImpl) Register(ctx *gin.Context) {
 	var req gen.RegistrationRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -134,33 +1009,133 @@ func (a AuthenticationServerImpl) Register(ctx *gin.Context) {
 		return
 	}
 
+	var algorithmName string
+	if req.Algorithm != nil {
+		algorithmName = string(*req.Algorithm)
+	}
+
+	algorithm, err := authentication.ParseAlgorithm(algorithmName)
+	if err != nil {
+		ctx.JSON(400, gin.H{"error": "invalid algorithm"})
+		return
+	}
+
 	salt, err := a.Authenticator.ValidateRegistration(ctx, authentication.Registration{
-		Username: req.Username,
-		Key:      userKey,
-		Email:    req.Email,
-		Secret:   secret,
+		Username:  req.Username,
+		Key:       userKey,
+		Email:     req.Email,
+		Secret:    secret,
+		Algorithm: algorithm,
 	})
 	if err != nil {
 		ctx.JSON(400, gin.H{"error": "failed to validate registration"})
 		return
 	}
 
-	user, err = a.userService.CreateUser(authCtx, req.Email, string(userKey), string(salt[:]), req.Username)
+	// asymmetric keys are not derived from a password so they have no salt
+	userSalt := string(salt[:])
+	if algorithm.IsAsymmetric() {
+		userSalt = ""
+	}
+
+	user, err = a.userService.CreateUser(authCtx, req.Email, string(userKey), userSalt, req.Username, algorithm)
 	if err != nil {
 		ctx.JSON(400, gin.H{"error": "failed to create user"})
 		return
 	}
 
-	authed, err := a.Authenticator.AuthenticateNewUser(authCtx, user)
+	err = a.emailVerifier.SendVerification(ctx, user)
+	if err != nil {
+		a.l.Warn("failed to send verification email", zap.String("user_id", user.ID.String()), zap.Error(err))
+	}
+
+	authed, err := a.Authenticator.AuthenticateNewUser(authorization.NewInternalOperationContext(clientContext(ctx)), user)
+	if errors.Is(err, authentication.ErrEmailNotVerified) {
+		// the user logs in once their email is verified
+		ctx.JSON(200, gin.H{})
+		return
+	}
+
 	if err != nil {
 		a.l.Error("failed to get a token with newly created user", zap.Error(err))
 		ctx.JSON(500, gin.H{"error": "failed to get token"})
 		return
 	}
 
-	ctx.SetCookie("OKEY", authed.AuthToken, 0, "/", "", true, true)
-	ctx.SetCookie("RKEY", authed.RefreshToken, 0, "/", "", true, true)
-	ctx.SetCookie("UID", authed.UserId.String(), 0, "/", "", true, true)
+	setLoginCookies(ctx, authed)
+
+	ctx.JSON(200, gin.H{})
+}
+
+func (a *AuthenticationServerImpl) VerifyEmail(ctx *gin.Context, params gen.VerifyEmailParams) {
+	claims, err := a.emailVerifier.Verify(params.Token)
+	if err != nil {
+		ctx.JSON(401, gin.H{"error": "invalid verification token"})
+		return
+	}
+
+	err = a.userService.VerifyEmail(authorization.NewInternalOperationContext(ctx), claims.UserID, claims.Email)
+	if err != nil {
+		if errors.Is(err, users.ErrEmailChanged) {
+			ctx.JSON(409, gin.H{"error": "email changed"})
+			return
+		}
+
+		a.l.Error("failed to verify email", zap.String("user_id", claims.UserID.String()), zap.Error(err))
+		ctx.JSON(500, gin.H{"error": "failed to verify email"})
+		return
+	}
+
+	a.l.Info("user verified email", zap.String("user_id", claims.UserID.String()))
+	ctx.JSON(200, gin.H{})
+}
+
+// RequestMagicLink responds the same whether or not a link was sent so it can't be used to find emails
+func (a *AuthenticationServerImpl) RequestMagicLink(ctx *gin.Context) {
+	var request gen.MagicLinkRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	err := a.magicLinker.RequestMagicLink(ctx, string(request.Email))
+	if err != nil {
+		var limitedErr *authentication.MagicLinkRateLimitedError
+		if errors.As(err, &limitedErr) {
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(limitedErr.RetryAfter.Seconds()))))
+			ctx.JSON(429, gin.H{"error": "Too many magic links requested"})
+			return
+		}
+
+		a.l.Warn("failed to request magic link", zap.Error(err))
+	}
+
+	ctx.JSON(202, gin.H{})
+}
+
+func (a *AuthenticationServerImpl) VerifyMagicLink(ctx *gin.Context, params gen.VerifyMagicLinkParams) {
+	tokens, err := a.magicLinker.VerifyMagicLink(clientContext(ctx), params.Token)
+	respondLogin(ctx, tokens, err)
+}
+
+func (a *AuthenticationServerImpl) SetMagicLinkSettings(ctx *gin.Context) {
+	var request gen.MagicLinkSettings
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(400, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	claims, ok := a.authenticate(ctx)
+	if !ok {
+		return
+	}
+
+	err := a.userService.SetMagicLink(authorization.NewInternalOperationContext(ctx), claims.UserID, request.Enabled)
+	if err != nil {
+		a.l.Error("failed to set magic link", zap.String("user_id", claims.UserID.String()), zap.Error(err))
+		ctx.JSON(500, gin.H{"error": "failed to save magic link settings"})
+		return
+	}
 
 	ctx.JSON(200, gin.H{})
 }
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	authmocks "github.com/ooqls/go-auth/domain/v1/authentication/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestAuthenticateRefusesServiceAccounts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	gin.SetMode(gin.TestMode)
+
+	authenticator := authmocks.NewTestAuthenticator(ctrl, authmocks.NewMockAccessTokenVerifier(ctrl), nil)
+	tokens, err := authenticator.AuthenticateServiceAccount(t.Context(), uuid.New())
+	assert.Nilf(t, err, "AuthenticateServiceAccount should not return an error: %v", err)
+
+	// the account services are not expected to be called
+	server := NewAuthenticationServer(zap.NewNop(), authenticator, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	do := func(handler gin.HandlerFunc) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request = httptest.NewRequest("GET", "/", nil)
+		ctx.Request.AddCookie(&http.Cookie{Name: "OKEY", Value: tokens.AuthToken})
+		handler(ctx)
+		return w
+	}
+
+	w := do(server.ListSessions)
+	assert.Equalf(t, 403, w.Code, "service accounts should not list sessions")
+
+	w = do(server.ListAccessTokens)
+	assert.Equalf(t, 403, w.Code, "service accounts should not list personal access tokens")
+
+	w = do(server.EnrollMFA)
+	assert.Equalf(t, 403, w.Code, "service accounts should not enroll mfa")
+}
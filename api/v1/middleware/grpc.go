@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	v1 "github.com/ooqls/go-auth/api/v1"
+	"github.com/ooqls/go-auth/domain/v1/authorization"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MethodPermissions maps full method names, /package.Service/Method, to the permission the method requires.
+// Methods without one only require the caller to be authenticated
+type MethodPermissions map[string]Permission
+
+// MetadataToken returns the bearer token of the incoming authorization metadata
+func MetadataToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ""
+	}
+
+	return v1.ParseBearerToken(values[0])
+}
+
+// UnaryServerInterceptor authenticates calls and checks the permission of the method, the caller's authorization
+// context is on the context handlers are called with
+func UnaryServerInterceptor(verifier Verifier, permissions MethodPermissions) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		authCtx, err := authorizeCall(ctx, verifier, permissions, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(WithAuthorizationContext(ctx, authCtx), req)
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streams, the caller's authorization context is on the stream's context
+func StreamServerInterceptor(verifier Verifier, permissions MethodPermissions) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		authCtx, err := authorizeCall(stream.Context(), verifier, permissions, info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authorizedStream{
+			ServerStream: stream,
+			ctx:          WithAuthorizationContext(stream.Context(), authCtx),
+		})
+	}
+}
+
+// authorizeCall returns the authorization context of the call, or the status error to end it with
+func authorizeCall(ctx context.Context, verifier Verifier, permissions MethodPermissions, method string) (*authorization.Context, error) {
+	authCtx, err := verifier.Verify(ctx, MetadataToken(ctx))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUnauthenticated):
+			return nil, status.Error(codes.Unauthenticated, errorMessage(err))
+		case errors.Is(err, ErrTokenNotAccepted):
+			return nil, status.Error(codes.PermissionDenied, errorMessage(err))
+		default:
+			return nil, status.Error(codes.Internal, errorMessage(err))
+		}
+	}
+
+	if permission, ok := permissions[method]; ok && !permission.permits(authCtx) {
+		return nil, status.Error(codes.PermissionDenied, "Permission denied")
+	}
+
+	return authCtx, nil
+}
+
+// authorizedStream is a stream whose context carries the caller's authorization context
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
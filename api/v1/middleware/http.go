@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	v1 "github.com/ooqls/go-auth/api/v1"
+	"github.com/ooqls/go-auth/domain/v1/authorization"
+)
+
+// RequestToken returns the auth token of a net/http request, the OKEY cookie is preferred over the Authorization header
+func RequestToken(r *http.Request) string {
+	if okey, err := r.Cookie("OKEY"); err == nil && okey.Value != "" {
+		return okey.Value
+	}
+
+	return v1.ParseBearerToken(r.Header.Get("Authorization"))
+}
+
+// AuthenticateHandler is Authenticate for net/http, the caller's authorization context is on the request's context
+func AuthenticateHandler(verifier Verifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authCtx, err := verifier.Verify(r.Context(), RequestToken(r))
+			if err != nil {
+				writeError(w, statusCode(err), errorMessage(err))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithAuthorizationContext(r.Context(), authCtx)))
+		})
+	}
+}
+
+// RequirePermissionHandler is RequirePermission for net/http, handlers guarded by it must be wrapped by AuthenticateHandler
+func RequirePermissionHandler(group, kind, name string, action authorization.Action) func(http.Handler) http.Handler {
+	permission := Permission{Group: group, Kind: kind, Name: name, Action: action}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authCtx, ok := FromContext(r.Context())
+			if !ok {
+				writeError(w, 401, "Authentication failed")
+				return
+			}
+
+			if !permission.permits(authCtx) {
+				writeError(w, 403, "Permission denied")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writeError responds with the same body as the gin middleware
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/api/v1/middleware/mocks"
+	"github.com/ooqls/go-auth/domain/v1/authorization"
+	"github.com/ooqls/go-auth/records"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// newTestVerifier returns a verifier authenticating "reader" with a role reading widgets and "internal" with an internal error
+func newTestVerifier(ctrl *gomock.Controller) *mocks.MockVerifier {
+	verifier := mocks.NewMockVerifier(ctrl)
+	verifier.EXPECT().Verify(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(ctx context.Context, token string) (*authorization.Context, error) {
+			switch token {
+			case "reader":
+				authCtx := authorization.NewAuthorizationContext(records.UserAgg{UserId: uuid.New(), EmailVerified: true, Roles: []records.RoleAgg{
+					{RoleId: uuid.New(), Permissions: []records.Permission{{ResourceGroup: "core", ResourceKind: "widget", ResourceName: "*", Actions: authorization.ReadAction}}},
+				}})
+				authCtx.Context = ctx
+				return &authCtx, nil
+			case "internal":
+				return nil, ErrInternal
+			default:
+				return nil, ErrUnauthenticated
+			}
+		})
+
+	return verifier
+}
+
+func TestAuthenticateHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var authCtx *authorization.Context
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authCtx, _ = FromContext(r.Context())
+		w.WriteHeader(200)
+	})
+	authenticate := AuthenticateHandler(newTestVerifier(ctrl))
+	read := authenticate(RequirePermissionHandler("core", "widget", "*", authorization.ReadAction)(ok))
+	del := authenticate(RequirePermissionHandler("core", "widget", "*", authorization.DeleteAction)(ok))
+
+	do := func(handler http.Handler, token string) int {
+		authCtx = nil
+		r := httptest.NewRequest("GET", "/widgets", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	assert.Equalf(t, 200, do(read, "reader"), "should authenticate the bearer token")
+	assert.NotNilf(t, authCtx, "should put the authorization context on the request's context")
+	assert.Equalf(t, 403, do(del, "reader"), "should refuse actions the caller is not permitted")
+	assert.Equalf(t, 401, do(read, "unknown"), "should refuse invalid tokens")
+	assert.Equalf(t, 500, do(read, "internal"), "should fail when the token can not be verified")
+
+	r := httptest.NewRequest("GET", "/widgets", nil)
+	r.AddCookie(&http.Cookie{Name: "OKEY", Value: "reader"})
+	w := httptest.NewRecorder()
+	read.ServeHTTP(w, r)
+	assert.Equalf(t, 200, w.Code, "should authenticate the OKEY cookie")
+
+	w = httptest.NewRecorder()
+	RequirePermissionHandler("core", "widget", "*", authorization.ReadAction)(ok).ServeHTTP(w, httptest.NewRequest("GET", "/widgets", nil))
+	assert.Equalf(t, 401, w.Code, "should refuse requests that were not authenticated")
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestServerInterceptors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	permissions := MethodPermissions{
+		"/widgets.Widgets/Get":    {Group: "core", Kind: "widget", Name: "*", Action: authorization.ReadAction},
+		"/widgets.Widgets/Delete": {Group: "core", Kind: "widget", Name: "*", Action: authorization.DeleteAction},
+	}
+	verifier := newTestVerifier(ctrl)
+	unary := UnaryServerInterceptor(verifier, permissions)
+	stream := StreamServerInterceptor(verifier, permissions)
+
+	withToken := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	}
+	callUnary := func(method string, ctx context.Context) (*authorization.Context, error) {
+		var authCtx *authorization.Context
+		_, err := unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+			authCtx, _ = FromContext(ctx)
+			return nil, nil
+		})
+		return authCtx, err
+	}
+
+	authCtx, err := callUnary("/widgets.Widgets/Get", withToken("reader"))
+	assert.Nilf(t, err, "should authenticate the call: %v", err)
+	assert.NotNilf(t, authCtx, "should put the authorization context on the handler's context")
+
+	_, err = callUnary("/widgets.Widgets/List", withToken("reader"))
+	assert.Nilf(t, err, "methods without a permission should only require authentication: %v", err)
+
+	_, err = callUnary("/widgets.Widgets/Delete", withToken("reader"))
+	assert.Equalf(t, codes.PermissionDenied, status.Code(err), "should refuse methods the caller is not permitted")
+
+	_, err = callUnary("/widgets.Widgets/List", withToken("unknown"))
+	assert.Equalf(t, codes.Unauthenticated, status.Code(err), "should refuse invalid tokens")
+
+	_, err = callUnary("/widgets.Widgets/List", context.Background())
+	assert.Equalf(t, codes.Unauthenticated, status.Code(err), "should refuse calls without metadata")
+
+	_, err = callUnary("/widgets.Widgets/List", withToken("internal"))
+	assert.Equalf(t, codes.Internal, status.Code(err), "should fail when the token can not be verified")
+
+	var streamCtx *authorization.Context
+	err = stream(nil, &testServerStream{ctx: withToken("reader")}, &grpc.StreamServerInfo{FullMethod: "/widgets.Widgets/Get"}, func(srv any, stream grpc.ServerStream) error {
+		streamCtx, _ = FromContext(stream.Context())
+		return nil
+	})
+	assert.Nilf(t, err, "should authenticate the stream: %v", err)
+	assert.NotNilf(t, streamCtx, "should put the authorization context on the stream's context")
+
+	err = stream(nil, &testServerStream{ctx: withToken("reader")}, &grpc.StreamServerInfo{FullMethod: "/widgets.Widgets/Delete"}, func(srv any, stream grpc.ServerStream) error {
+		return nil
+	})
+	assert.Equalf(t, codes.PermissionDenied, status.Code(err), "should refuse streams the caller is not permitted")
+}
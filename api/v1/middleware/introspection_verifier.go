@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/ooqls/go-auth/api/v1/gen/gen_oauth"
+	"github.com/ooqls/go-auth/domain/v1/authorization"
+	"github.com/ooqls/go-auth/records"
+	"go.uber.org/zap"
+)
+
+var _ Verifier = &IntrospectionVerifier{}
+
+// IntrospectionVerifier verifies tokens with the introspection endpoint of the authorization server, for services
+// without the signing keys or the database. Every request is introspected so revoked tokens are refused right away
+type IntrospectionVerifier struct {
+	client       gen_oauth.ClientWithResponsesInterface
+	clientID     string
+	clientSecret string
+}
+
+// NewIntrospectionVerifier returns a Verifier introspecting tokens with client, the service authenticates as the
+// service account of clientID
+func NewIntrospectionVerifier(client gen_oauth.ClientWithResponsesInterface, clientID string, clientSecret string) *IntrospectionVerifier {
+	return &IntrospectionVerifier{
+		client:       client,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+	}
+}
+
+func (v *IntrospectionVerifier) Verify(ctx context.Context, token string) (*authorization.Context, error) {
+	if token == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	resp, err := v.client.IntrospectWithFormdataBodyWithResponse(ctx, gen_oauth.IntrospectFormdataRequestBody{
+		Token:        token,
+		ClientId:     &v.clientID,
+		ClientSecret: &v.clientSecret,
+	})
+	if err != nil {
+		l.Error("failed to introspect token", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	if resp.JSON200 == nil {
+		l.Error("failed to introspect token", zap.Int("status", resp.StatusCode()), zap.String("client_id", v.clientID))
+		return nil, ErrInternal
+	}
+
+	if !resp.JSON200.Active {
+		return nil, ErrUnauthenticated
+	}
+
+	return introspectedContext(ctx, resp.JSON200)
+}
+
+// introspectedContext returns the authorization context of an active token
+func introspectedContext(ctx context.Context, introspection *gen_oauth.IntrospectionResponse) (*authorization.Context, error) {
+	if introspection.Sub == nil {
+		l.Error("introspected token has no subject")
+		return nil, ErrInternal
+	}
+
+	roleAggs := []records.RoleAgg{}
+	if introspection.Roles != nil {
+		for _, id := range *introspection.Roles {
+			roleAggs = append(roleAggs, records.RoleAgg{RoleId: id, Permissions: []records.Permission{}})
+		}
+	}
+
+	if introspection.Permissions != nil {
+		for _, permission := range *introspection.Permissions {
+			for i := range roleAggs {
+				if roleAggs[i].RoleId != permission.RoleId {
+					continue
+				}
+
+				roleAggs[i].Permissions = append(roleAggs[i].Permissions, records.Permission{
+					ID:            permission.Id,
+					ResourceGroup: permission.ResourceGroup,
+					ResourceKind:  permission.ResourceKind,
+					ResourceName:  permission.ResourceName,
+					Actions:       permission.Actions,
+				})
+			}
+		}
+	}
+
+	if introspection.ServiceAccount != nil && *introspection.ServiceAccount {
+		authCtx := authorization.NewServiceAccountContext(ctx, records.UserAgg{UserId: *introspection.Sub, Roles: roleAggs})
+		return &authCtx, nil
+	}
+
+	// the authorization server returns no roles for users who have not verified their email
+	authCtx := authorization.NewAuthorizationContext(records.UserAgg{
+		UserId:        *introspection.Sub,
+		Roles:         roleAggs,
+		EmailVerified: true,
+	})
+	authCtx.Context = ctx
+	return &authCtx, nil
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/api/v1/gen/gen_oauth"
+	"github.com/ooqls/go-auth/domain/v1/authorization"
+	"github.com/ooqls/go-auth/records"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntrospectionVerifier(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	roleID := uuid.New()
+	emptyRoleID := uuid.New()
+	permissionID := uuid.New()
+	serviceAccount := true
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth/introspect" || r.PostFormValue("client_id") != "service" || r.PostFormValue("client_secret") != "secret" {
+			w.WriteHeader(401)
+			_ = json.NewEncoder(w).Encode(gen_oauth.OAuthErrorResponse{Error: "invalid_client"})
+			return
+		}
+
+		response := gen_oauth.IntrospectionResponse{Active: false}
+		switch r.PostFormValue("token") {
+		case "user", "account":
+			response = gen_oauth.IntrospectionResponse{
+				Active: true,
+				Sub:    &userID,
+				Roles:  &[]uuid.UUID{roleID, emptyRoleID},
+				Permissions: &[]gen_oauth.IntrospectionPermission{
+					{Id: permissionID, RoleId: roleID, ResourceGroup: "core", ResourceKind: "widget", ResourceName: "*", Actions: "read"},
+				},
+			}
+			if r.PostFormValue("token") == "account" {
+				response.ServiceAccount = &serviceAccount
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client, err := gen_oauth.NewClientWithResponses(server.URL)
+	assert.Nilf(t, err, "should create the client: %v", err)
+	verifier := NewIntrospectionVerifier(client, "service", "secret")
+
+	authCtx, err := verifier.Verify(ctx, "user")
+	assert.Nilf(t, err, "should verify the active token: %v", err)
+	assert.Equalf(t, userID, authCtx.GetUserID(), "context should be the token's subject")
+	assert.Equalf(t, []records.RoleAgg{
+		{RoleId: roleID, Permissions: []records.Permission{{ID: permissionID, ResourceGroup: "core", ResourceKind: "widget", ResourceName: "*", Actions: "read"}}},
+		{RoleId: emptyRoleID, Permissions: []records.Permission{}},
+	}, authCtx.GetRoles(), "context should hold the introspected roles and permissions")
+	assert.Truef(t, Permission{Group: "core", Kind: "widget", Name: "*", Action: authorization.ReadAction}.permits(authCtx), "introspected permissions should be checked")
+	assert.Falsef(t, authCtx.IsServiceAccount(), "users should not be service accounts")
+
+	authCtx, err = verifier.Verify(ctx, "account")
+	assert.Nilf(t, err, "should verify the service account's token: %v", err)
+	assert.Truef(t, authCtx.IsServiceAccount(), "context should be a service account's")
+
+	_, err = verifier.Verify(ctx, "revoked")
+	assert.ErrorIsf(t, err, ErrUnauthenticated, "inactive tokens should not be authenticated")
+
+	_, err = verifier.Verify(ctx, "")
+	assert.ErrorIsf(t, err, ErrUnauthenticated, "requests without a token should not be authenticated")
+
+	_, err = NewIntrospectionVerifier(client, "service", "wrong").Verify(ctx, "user")
+	assert.ErrorIsf(t, err, ErrInternal, "should fail when the service can not introspect")
+}
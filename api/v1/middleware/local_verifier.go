@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/domain/v1/accesstokens"
+	"github.com/ooqls/go-auth/domain/v1/authentication"
+	"github.com/ooqls/go-auth/domain/v1/authorization"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/roles"
+	"github.com/ooqls/go-auth/records/v1/serviceaccounts"
+	"github.com/ooqls/go-auth/records/v1/users"
+	"go.uber.org/zap"
+)
+
+var _ Verifier = &LocalVerifier{}
+
+// LocalVerifier verifies tokens with an Authenticator and reads the roles of the caller from the database
+type LocalVerifier struct {
+	authenticator authentication.Authenticator
+	userReader    users.Reader
+	roleReader    roles.AggRoleReader
+	// accountReader loads the roles of service accounts, service accounts hold no roles when it is nil
+	accountReader serviceaccounts.Reader
+	// accessTokens scopes the roles of personal access tokens, personal access tokens are refused when it is nil
+	accessTokens accesstokens.AccessTokenService
+}
+
+// NewLocalVerifier returns a Verifier verifying tokens with authenticator. The roles of users come from roleReader,
+// accountReader and accessTokens may be nil
+func NewLocalVerifier(
+	authenticator authentication.Authenticator,
+	userReader users.Reader,
+	roleReader roles.AggRoleReader,
+	accountReader serviceaccounts.Reader,
+	accessTokens accesstokens.AccessTokenService) *LocalVerifier {
+
+	return &LocalVerifier{
+		authenticator: authenticator,
+		userReader:    userReader,
+		roleReader:    roleReader,
+		accountReader: accountReader,
+		accessTokens:  accessTokens,
+	}
+}
+
+func (v *LocalVerifier) Verify(ctx context.Context, token string) (*authorization.Context, error) {
+	if token == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	claims, err := v.authenticator.IsAuthenticated(ctx, token)
+	if err != nil {
+		if errors.Is(err, authentication.ErrInternal) {
+			l.Error("failed to authenticate token", zap.Error(err))
+			return nil, ErrInternal
+		}
+
+		return nil, ErrUnauthenticated
+	}
+
+	if claims.AccessTokenID != uuid.Nil && v.accessTokens == nil {
+		return nil, ErrTokenNotAccepted
+	}
+
+	authCtx, err := v.AuthorizationContext(ctx, claims)
+	if err != nil {
+		// the token outlived its user
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, ErrUnauthenticated
+		}
+
+		l.Error("failed to get authorization context", zap.String("user_id", claims.UserID.String()), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	return authCtx, nil
+}
+
+// AuthorizationContext returns the context the claims are authorized with. Users who have not verified their email
+// hold no roles and the roles of personal access tokens only hold the permissions the token is scoped to
+func (v *LocalVerifier) AuthorizationContext(ctx context.Context, claims *authentication.UserClaims) (*authorization.Context, error) {
+	if claims.ServiceAccount {
+		var roleAggs []records.RoleAgg
+		if v.accountReader != nil {
+			var err error
+			roleAggs, err = v.accountReader.GetRoleAggs(ctx, claims.UserID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		authCtx := authorization.NewServiceAccountContext(ctx, records.UserAgg{UserId: claims.UserID, Roles: roleAggs})
+		return &authCtx, nil
+	}
+
+	if claims.AccessTokenID != uuid.Nil {
+		authCtx, err := v.accessTokens.AuthorizationContext(ctx, claims)
+		if errors.Is(err, accesstokens.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+
+		return authCtx, err
+	}
+
+	user, err := v.userReader.GetUser(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+
+		return nil, err
+	}
+
+	roleAggs, err := v.roleReader.GetRoleAggForUser(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	authCtx := authorization.NewAuthorizationContext(records.UserAgg{
+		UserId:        user.ID,
+		Roles:         roleAggs,
+		EmailVerified: user.EmailVerified,
+	})
+	authCtx.Context = ctx
+	return &authCtx, nil
+}
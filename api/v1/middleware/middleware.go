@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	v1 "github.com/ooqls/go-auth/api/v1"
+	"github.com/ooqls/go-auth/domain/v1/authorization"
+)
+
+// authorizationContextGinKey is the gin context key of the request's authorization context
+const authorizationContextGinKey = "github.com/ooqls/go-auth/authorization_context"
+
+// Token returns the request's auth token, the OKEY cookie is preferred over the Authorization header
+func Token(ctx *gin.Context) string {
+	if okey, err := ctx.Cookie("OKEY"); err == nil && okey != "" {
+		return okey
+	}
+
+	return v1.ParseToken(ctx)
+}
+
+// Authenticate responds with 401 to requests without a valid token. Authenticated requests carry the caller's
+// authorization context, with the roles and permissions they hold, on the gin context and the request's context
+func Authenticate(verifier Verifier) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authCtx, err := verifier.Verify(ctx.Request.Context(), Token(ctx))
+		if err != nil {
+			ctx.AbortWithStatusJSON(statusCode(err), gin.H{"error": errorMessage(err)})
+			return
+		}
+
+		ctx.Set(authorizationContextGinKey, authCtx)
+		ctx.Request = ctx.Request.WithContext(WithAuthorizationContext(ctx.Request.Context(), authCtx))
+		ctx.Next()
+	}
+}
+
+// GetAuthorizationContext returns the authorization context Authenticate put on the request, false is returned if
+// the request did not go through Authenticate
+func GetAuthorizationContext(ctx *gin.Context) (*authorization.Context, bool) {
+	value, ok := ctx.Get(authorizationContextGinKey)
+	if !ok {
+		return nil, false
+	}
+
+	authCtx, ok := value.(*authorization.Context)
+	return authCtx, ok
+}
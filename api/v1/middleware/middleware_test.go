@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	accesstokenmocks "github.com/ooqls/go-auth/domain/v1/accesstokens/mocks"
+	"github.com/ooqls/go-auth/domain/v1/authentication"
+	authmocks "github.com/ooqls/go-auth/domain/v1/authentication/mocks"
+	"github.com/ooqls/go-auth/domain/v1/authorization"
+	"github.com/ooqls/go-auth/records"
+	rolemocks "github.com/ooqls/go-auth/records/v1/roles/mocks"
+	serviceaccountmocks "github.com/ooqls/go-auth/records/v1/serviceaccounts/mocks"
+	"github.com/ooqls/go-auth/records/v1/users"
+	usermocks "github.com/ooqls/go-auth/records/v1/users/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAccessToken = authentication.AccessTokenPrefix + "test"
+
+func TestAuthenticate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	verified := &users.User{ID: uuid.New(), Username: "verified", EmailVerified: true}
+	unverified := &users.User{ID: uuid.New(), Username: "unverified"}
+	accountID := uuid.New()
+	role := records.RoleAgg{RoleId: uuid.New(), Permissions: []records.Permission{
+		{ID: uuid.New(), ResourceGroup: "core", ResourceKind: "widget", ResourceName: "*", Actions: authorization.ReadAction},
+	}}
+	accountRole := records.RoleAgg{RoleId: uuid.New(), Permissions: []records.Permission{
+		{ID: uuid.New(), ResourceGroup: "core", ResourceKind: "widget", ResourceName: "*", Actions: "*"},
+	}}
+
+	userReader := usermocks.NewMockReader(ctrl)
+	userReader.EXPECT().GetUser(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, id records.UserId) (*users.User, error) {
+			for _, user := range []*users.User{verified, unverified} {
+				if user.ID == id {
+					return user, nil
+				}
+			}
+			return nil, sql.ErrNoRows
+		})
+	roleReader := rolemocks.NewMockAggRoleReader(ctrl)
+	roleReader.EXPECT().GetRoleAggForUser(gomock.Any(), gomock.Any()).AnyTimes().Return([]records.RoleAgg{role}, nil)
+	accountReader := serviceaccountmocks.NewMockReader(ctrl)
+	accountReader.EXPECT().GetRoleAggs(gomock.Any(), accountID).AnyTimes().Return([]records.RoleAgg{accountRole}, nil)
+
+	accessTokens := accesstokenmocks.NewMockAccessTokenService(ctrl)
+	accessTokens.EXPECT().VerifyAccessToken(gomock.Any(), testAccessToken).AnyTimes().Return(&authentication.UserClaims{
+		UserID:        verified.ID,
+		AccessTokenID: uuid.New(),
+	}, nil)
+	accessTokens.EXPECT().AuthorizationContext(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, claims *authentication.UserClaims) (*authorization.Context, error) {
+			// the token is scoped to none of the user's permissions
+			authCtx := authorization.NewAuthorizationContext(records.UserAgg{UserId: claims.UserID, Roles: []records.RoleAgg{}, EmailVerified: true})
+			return &authCtx, nil
+		})
+
+	authenticator := authmocks.NewTestAuthenticator(ctrl, accessTokens, nil)
+	verifier := NewLocalVerifier(authenticator, userReader, roleReader, accountReader, accessTokens)
+
+	var authCtx *authorization.Context
+	router := gin.New()
+	router.Use(Authenticate(verifier))
+	router.GET("/widgets", RequirePermission("core", "widget", "*", authorization.ReadAction), func(c *gin.Context) {
+		authCtx, _ = GetAuthorizationContext(c)
+		c.Status(200)
+	})
+	router.DELETE("/widgets", RequirePermission("core", "widget", "*", authorization.DeleteAction), func(c *gin.Context) {
+		c.Status(200)
+	})
+
+	do := func(method string, setToken func(r *http.Request)) int {
+		authCtx = nil
+		r := httptest.NewRequest(method, "/widgets", nil)
+		setToken(r)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w.Code
+	}
+	bearer := func(token string) func(r *http.Request) {
+		return func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) }
+	}
+
+	tokens, err := authenticator.AuthenticateNewUser(ctx, verified)
+	assert.Nilf(t, err, "should log in the user: %v", err)
+
+	assert.Equalf(t, 401, do("GET", func(r *http.Request) {}), "requests without a token should be refused")
+	assert.Equalf(t, 401, do("GET", bearer("not a token")), "requests with an invalid token should be refused")
+
+	assert.Equalf(t, 200, do("GET", bearer(tokens.AuthToken)), "should authenticate the bearer token")
+	if assert.NotNilf(t, authCtx, "should put the authorization context on the request") {
+		assert.Equalf(t, verified.ID, authCtx.GetUserID(), "context should be the user's")
+		assert.Equalf(t, []records.RoleAgg{role}, authCtx.GetRoles(), "context should hold the user's roles")
+	}
+
+	assert.Equalf(t, 200, do("GET", func(r *http.Request) {
+		r.AddCookie(&http.Cookie{Name: "OKEY", Value: tokens.AuthToken})
+	}), "should authenticate the OKEY cookie")
+	assert.Equalf(t, 403, do("DELETE", bearer(tokens.AuthToken)), "should refuse actions the user is not permitted")
+
+	unverifiedTokens, err := authenticator.AuthenticateNewUser(ctx, unverified)
+	assert.Nilf(t, err, "should log in the user: %v", err)
+	assert.Equalf(t, 403, do("GET", bearer(unverifiedTokens.AuthToken)), "users who have not verified their email should hold no permissions")
+
+	assert.Equalf(t, 403, do("GET", bearer(testAccessToken)), "personal access tokens should only hold the permissions in scope")
+
+	accountTokens, err := authenticator.AuthenticateServiceAccount(ctx, accountID)
+	assert.Nilf(t, err, "should authenticate the service account: %v", err)
+	assert.Equalf(t, 200, do("DELETE", bearer(accountTokens.AuthToken)), "service accounts should hold their roles")
+
+	deleted, err := authenticator.AuthenticateNewUser(ctx, &users.User{ID: uuid.New(), Username: "deleted"})
+	assert.Nilf(t, err, "should log in the user: %v", err)
+	assert.Equalf(t, 401, do("GET", bearer(deleted.AuthToken)), "tokens outliving their user should be refused")
+
+	withoutAccessTokens := gin.New()
+	withoutAccessTokens.Use(Authenticate(NewLocalVerifier(authenticator, userReader, roleReader, nil, nil)))
+	withoutAccessTokens.GET("/widgets", func(c *gin.Context) { c.Status(200) })
+	r := httptest.NewRequest("GET", "/widgets", nil)
+	bearer(testAccessToken)(r)
+	w := httptest.NewRecorder()
+	withoutAccessTokens.ServeHTTP(w, r)
+	assert.Equalf(t, 403, w.Code, "personal access tokens should be refused when they can not be scoped")
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ooqls/go-auth/domain/v1/authorization"
+)
+
+// RequirePermission responds with 403 to callers without a permission allowing the action on the resource.
+// Routes guarded by it must go through Authenticate first, requests without an authorization context are refused with 401
+func RequirePermission(group, kind, name string, action authorization.Action) gin.HandlerFunc {
+	permission := Permission{Group: group, Kind: kind, Name: name, Action: action}
+
+	return func(ctx *gin.Context) {
+		authCtx, ok := GetAuthorizationContext(ctx)
+		if !ok {
+			ctx.AbortWithStatusJSON(401, gin.H{"error": "Authentication failed"})
+			return
+		}
+
+		if !permission.permits(authCtx) {
+			ctx.AbortWithStatusJSON(403, gin.H{"error": "Permission denied"})
+			return
+		}
+
+		ctx.Next()
+	}
+}
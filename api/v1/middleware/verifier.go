@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ooqls/go-auth/domain/v1/authorization"
+	"github.com/ooqls/go-log"
+	"go.uber.org/zap"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=verifier.go -destination=mocks/mock_verifier.go -package=mocks
+
+var l *zap.Logger
+
+func init() {
+	l = log.NewLogger("middleware")
+}
+
+var (
+	ErrUnauthenticated  error = errors.New("unauthenticated")
+	ErrTokenNotAccepted error = errors.New("token not accepted")
+	ErrUserNotFound     error = errors.New("user not found")
+	ErrInternal         error = errors.New("internal error")
+)
+
+// Verifier authenticates the tokens of requests, the gin, net/http and gRPC middlewares all authenticate with one
+type Verifier interface {
+	// Verify returns the authorization context of the token. ErrUnauthenticated is returned for invalid, expired and
+	// revoked tokens and tokens that outlived their user, ErrTokenNotAccepted for valid tokens the verifier refuses
+	Verify(ctx context.Context, token string) (*authorization.Context, error)
+}
+
+// statusCode returns the http status of a Verify error
+func statusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrUnauthenticated):
+		return 401
+	case errors.Is(err, ErrTokenNotAccepted):
+		return 403
+	default:
+		return 500
+	}
+}
+
+// errorMessage returns the message of a Verify error, internal errors are not described to callers
+func errorMessage(err error) string {
+	switch {
+	case errors.Is(err, ErrUnauthenticated):
+		return "Authentication failed"
+	case errors.Is(err, ErrTokenNotAccepted):
+		return "Token not accepted"
+	default:
+		return "failed to authenticate"
+	}
+}
+
+type authorizationContextKey struct{}
+
+// WithAuthorizationContext returns a context carrying the authorization context
+func WithAuthorizationContext(ctx context.Context, authCtx *authorization.Context) context.Context {
+	return context.WithValue(ctx, authorizationContextKey{}, authCtx)
+}
+
+// FromContext returns the authorization context a middleware attached to the request's context, false is returned if
+// the request was not authenticated by one
+func FromContext(ctx context.Context) (*authorization.Context, bool) {
+	authCtx, ok := ctx.Value(authorizationContextKey{}).(*authorization.Context)
+	return authCtx, ok
+}
+
+// Permission is what a route or method requires the caller to be permitted, "*" is not special in it,
+// it only matches permissions granted on "*"
+type Permission struct {
+	Group  string
+	Kind   string
+	Name   string
+	Action authorization.Action
+}
+
+// permits returns true if the roles of the authorization context allow the permission's action on its resource
+func (p Permission) permits(authCtx *authorization.Context) bool {
+	permitted := authorization.UserHasResourcePermission().IsAuthorized(authCtx, p.Action, authorization.Resource{
+		ResourceGroup: p.Group,
+		ResourceKind:  p.Kind,
+		ResourceName:  p.Name,
+	})
+	if !permitted {
+		l.Debug("permission denied",
+			zap.String("user_id", authCtx.GetUserID().String()),
+			zap.String("action", p.Action),
+			zap.String("resource", p.Group+"/"+p.Kind+"/"+p.Name))
+	}
+
+	return permitted
+}
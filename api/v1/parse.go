@@ -3,14 +3,18 @@ package v1
 import "github.com/gin-gonic/gin"
 
 func ParseToken(c *gin.Context) string {
-	token := c.Request.Header.Get("Authorization")
-	if token == "" {
+	return ParseBearerToken(c.Request.Header.Get("Authorization"))
+}
+
+// ParseBearerToken returns the token of an Authorization header or metadata value, "" is returned if it is not a bearer token
+func ParseBearerToken(authorization string) string {
+	if authorization == "" {
 		return ""
 	}
 
-	if len(token) < 7 || token[:7] != "Bearer " {
+	if len(authorization) < 7 || authorization[:7] != "Bearer " {
 		return ""
 	}
 
-	return token[7:]
+	return authorization[7:]
 }
@@ -0,0 +1,110 @@
+package authentication
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ooqls/go-auth/api/v1/gen/gen_oauth"
+	"github.com/ooqls/go-auth/domain/v1/oauth"
+)
+
+// slowDownIncrement is added to the polling interval every time the server answers slow_down, RFC 8628 3.5
+const slowDownIncrement = 5 * time.Second
+
+// OAuthError is an error response of the token or device authorization endpoint, Code is the RFC 6749 error code
+type OAuthError struct {
+	Code string
+}
+
+func (e *OAuthError) Error() string {
+	return e.Code
+}
+
+func unmarshalOAuthError(resp *http.Response) error {
+	var body gen_oauth.OAuthErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return err
+	}
+	return &OAuthError{Code: body.Error}
+}
+
+// DeviceClient logs in with the OAuth 2.0 device authorization grant, RFC 8628. The user approves
+// the login in a browser where they are already logged in instead of typing their password into the device
+type DeviceClient struct {
+	c        gen_oauth.Client
+	clientID string
+}
+
+// NewDeviceClient returns a DeviceClient for the public OAuth client with the id
+func NewDeviceClient(c gen_oauth.Client, clientID string) *DeviceClient {
+	return &DeviceClient{c: c, clientID: clientID}
+}
+
+// Login starts a device authorization and calls prompt with the page and code the user approves it with.
+// It polls for tokens until the user approves or denies the login, the code expires or ctx is done
+func (c *DeviceClient) Login(ctx context.Context, scope string, prompt func(device *gen_oauth.DeviceAuthorizationResponse)) (*gen_oauth.OAuthTokenResponse, error) {
+	request := gen_oauth.AuthorizeDeviceFormdataRequestBody{ClientId: &c.clientID}
+	if scope != "" {
+		request.Scope = &scope
+	}
+
+	resp, err := c.c.AuthorizeDeviceWithFormdataBody(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, unmarshalOAuthError(resp)
+	}
+
+	device, err := unmarshalResponse[gen_oauth.DeviceAuthorizationResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt(device)
+
+	interval := time.Duration(device.Interval) * time.Second
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		tokens, err := c.poll(ctx, device.DeviceCode)
+		var oauthErr *OAuthError
+		if errors.As(err, &oauthErr) {
+			switch oauthErr.Code {
+			case oauth.ErrAuthorizationPending.Error():
+				continue
+			case oauth.ErrSlowDown.Error():
+				interval += slowDownIncrement
+				continue
+			}
+		}
+
+		return tokens, err
+	}
+}
+
+func (c *DeviceClient) poll(ctx context.Context, deviceCode string) (*gen_oauth.OAuthTokenResponse, error) {
+	resp, err := c.c.TokenWithFormdataBody(ctx, gen_oauth.TokenFormdataRequestBody{
+		GrantType:  gen_oauth.UrnIetfParamsOauthGrantTypeDeviceCode,
+		DeviceCode: &deviceCode,
+		ClientId:   &c.clientID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, unmarshalOAuthError(resp)
+	}
+
+	return unmarshalResponse[gen_oauth.OAuthTokenResponse](resp)
+}
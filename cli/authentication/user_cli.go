@@ -2,14 +2,18 @@ package authentication
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/base64"
-	"encoding/binary"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"time"
 
+	openapi_types "github.com/oapi-codegen/runtime/types"
 	"github.com/ooqls/go-auth/api/v1/gen/gen_authentication"
+	"github.com/ooqls/go-auth/domain/v1/authentication"
 	"github.com/ooqls/go-crypto/crypto"
 )
 
@@ -29,6 +33,16 @@ func unmarshalError(resp *http.Response) error {
 	return errors.New(err.Error)
 }
 
+// MFARequiredError is returned by the login methods when the user has MFA enabled,
+// the login is finished by calling VerifyMFA with the token
+type MFARequiredError struct {
+	MFAToken string
+}
+
+func (e *MFARequiredError) Error() string {
+	return "mfa required"
+}
+
 type AuthenticationClient struct {
 	c gen_authentication.Client
 }
@@ -38,20 +52,13 @@ func NewAuthenticationClient(c gen_authentication.Client) *AuthenticationClient
 }
 
 func (c *AuthenticationClient) Register(ctx context.Context, email string, password string, username string) (*gen_authentication.RegisterResponse, error) {
-	usernameBytes := make([]byte, 8)
-	copy(usernameBytes, []byte(username))
-
-	seed := binary.LittleEndian.Uint64(usernameBytes)
-	salt := make([]byte, crypto.SALT_SIZE)
-	rng := crypto.NewPCG32(seed, 0)
-	rng.Read(salt)
-
-	key, err := crypto.DeriveAESGCMKey(password, [16]byte(salt))
+	salt := authentication.RegistrationSalt(username)
+	key, err := crypto.DeriveAESGCMKey(password, salt)
 	if err != nil {
 		return nil, err
 	}
 
-	encrypted, err := crypto.AESGCMEncryptWithKey(key, [16]byte(salt), []byte(username))
+	encrypted, err := crypto.AESGCMEncryptWithKey(key, salt, []byte(username))
 	if err != nil {
 		return nil, err
 	}
@@ -79,25 +86,179 @@ func (c *AuthenticationClient) Register(ctx context.Context, email string, passw
 	return body, nil
 }
 
-func (c *AuthenticationClient) Login(ctx context.Context, username string, password string) (uid *string, okey *string, rkey *string, err error) {
+// RegisterWithKey registers a user with an asymmetric key, privateKey is the PKCS #8 DER encoded
+// private key as returned by authentication.GenerateKey. Only the public key is sent to the server
+func (c *AuthenticationClient) RegisterWithKey(ctx context.Context, email string, username string, algorithm authentication.SupportedAlgorithm, privateKey []byte) (*gen_authentication.RegisterResponse, error) {
+	publicKey, err := authentication.PublicKey(algorithm, privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := authentication.Sign(algorithm, privateKey, []byte(username))
+	if err != nil {
+		return nil, err
+	}
+
+	keyAlgorithm := gen_authentication.KeyAlgorithm(algorithm)
+	resp, err := c.c.Register(ctx, gen_authentication.RegisterJSONRequestBody{
+		Email:           email,
+		Base64Key:       base64.StdEncoding.EncodeToString(publicKey),
+		Username:        username,
+		EncryptedSecret: base64.StdEncoding.EncodeToString(signature),
+		Algorithm:       &keyAlgorithm,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		err = unmarshalError(resp)
+		return nil, err
+	}
+
+	body, err := unmarshalResponse[gen_authentication.RegisterResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// RegisterWithSRP registers a user that logs in with SRP-6a, only the verifier of the password
+// and its random salt are sent to the server
+func (c *AuthenticationClient) RegisterWithSRP(ctx context.Context, email string, username string, password string) (*gen_authentication.RegisterResponse, error) {
+	salt := make([]byte, crypto.SALT_SIZE)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	verifier := authentication.SRPVerifier(username, password, salt)
+
+	keyAlgorithm := gen_authentication.SRP6A
+	resp, err := c.c.Register(ctx, gen_authentication.RegisterJSONRequestBody{
+		Email:           email,
+		Base64Key:       base64.StdEncoding.EncodeToString(verifier),
+		Username:        username,
+		EncryptedSecret: base64.StdEncoding.EncodeToString(salt),
+		Algorithm:       &keyAlgorithm,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		err = unmarshalError(resp)
+		return nil, err
+	}
+
+	body, err := unmarshalResponse[gen_authentication.RegisterResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+func (c *AuthenticationClient) requestChallenge(ctx context.Context, username string) (*gen_authentication.ChallengeServerResponse, []byte, error) {
 	challengeResp, err := c.c.LoginChallenge(ctx, gen_authentication.LoginChallengeJSONRequestBody{
 		Username: username,
 	})
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, err
 	}
 
 	if challengeResp.StatusCode != 200 {
 		err = unmarshalError(challengeResp)
-		return nil, nil, nil, err
+		return nil, nil, err
 	}
 
 	challenge, err := unmarshalResponse[gen_authentication.ChallengeServerResponse](challengeResp)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, err
 	}
 
 	challengeStr, err := base64.StdEncoding.DecodeString(challenge.Base64Challenge)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return challenge, challengeStr, nil
+}
+
+// answerChallenge sends the answer to the challenge, checkResponse is called with the login response before
+// the login is finished so the client can verify the server, it may be nil
+func (c *AuthenticationClient) answerChallenge(ctx context.Context, challengeId openapi_types.UUID, answer []byte, checkResponse func(*gen_authentication.LoginResponse) error) (uid *string, okey *string, rkey *string, err error) {
+	resp, err := c.c.LoginChallengeResponse(ctx, gen_authentication.LoginChallengeResponseJSONRequestBody{
+		Base64Challenge: base64.StdEncoding.EncodeToString(answer),
+		Id:              challengeId,
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		err = unmarshalError(resp)
+		return nil, nil, nil, err
+	}
+
+	body, err := unmarshalResponse[gen_authentication.LoginResponse](resp)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if checkResponse != nil {
+		err = checkResponse(body)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	if body.MfaRequired && body.MfaToken != nil {
+		return nil, nil, nil, &MFARequiredError{MFAToken: *body.MfaToken}
+	}
+
+	uid, okey, rkey = loginCookies(resp)
+	return
+}
+
+func loginCookies(resp *http.Response) (uid *string, okey *string, rkey *string) {
+	cookies := resp.Cookies()
+
+	for _, cookie := range cookies {
+		if cookie.Name == "RKEY" {
+			rkey = &cookie.Value
+		} else if cookie.Name == "OKEY" {
+			okey = &cookie.Value
+		} else if cookie.Name == "UID" {
+			uid = &cookie.Value
+		}
+	}
+
+	return
+}
+
+// VerifyMFA finishes a login that returned an MFARequiredError, code is a TOTP code or a recovery code
+func (c *AuthenticationClient) VerifyMFA(ctx context.Context, mfaToken string, code string) (uid *string, okey *string, rkey *string, err error) {
+	resp, err := c.c.VerifyMFA(ctx, gen_authentication.VerifyMFAJSONRequestBody{
+		MfaToken: mfaToken,
+		Code:     code,
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		err = unmarshalError(resp)
+		return nil, nil, nil, err
+	}
+
+	uid, okey, rkey = loginCookies(resp)
+	return
+}
+
+func (c *AuthenticationClient) Login(ctx context.Context, username string, password string) (uid *string, okey *string, rkey *string, err error) {
+	challenge, challengeStr, err := c.requestChallenge(ctx, username)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -121,30 +282,190 @@ func (c *AuthenticationClient) Login(ctx context.Context, username string, passw
 		return nil, nil, nil, err
 	}
 
-	resp, err := c.c.LoginChallengeResponse(ctx, gen_authentication.LoginChallengeResponseJSONRequestBody{
-		Base64Challenge: base64.StdEncoding.EncodeToString(encrypted),
-		Id:              challenge.Id,
-	})
+	return c.answerChallenge(ctx, challenge.Id, encrypted, nil)
+}
+
+// LoginWithKey logs in a user registered with RegisterWithKey by signing the server's challenge
+func (c *AuthenticationClient) LoginWithKey(ctx context.Context, username string, algorithm authentication.SupportedAlgorithm, privateKey []byte) (uid *string, okey *string, rkey *string, err error) {
+	challenge, challengeStr, err := c.requestChallenge(ctx, username)
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
-	if resp.StatusCode != 200 {
-		err = unmarshalError(resp)
+	if challenge.Algorithm != gen_authentication.KeyAlgorithm(algorithm) {
+		return nil, nil, nil, fmt.Errorf("user is registered with %s, not %s", challenge.Algorithm, algorithm)
+	}
+
+	signature, err := authentication.Sign(algorithm, privateKey, challengeStr)
+	if err != nil {
 		return nil, nil, nil, err
 	}
 
-	cookies := resp.Cookies()
+	return c.answerChallenge(ctx, challenge.Id, signature, nil)
+}
 
-	for _, cookie := range cookies {
-		if cookie.Name == "RKEY" {
-			rkey = &cookie.Value
-		} else if cookie.Name == "OKEY" {
-			okey = &cookie.Value
-		} else if cookie.Name == "UID" {
-			uid = &cookie.Value
+// LoginWithSRP logs in a user registered with RegisterWithSRP, the login fails if the server
+// can't prove it knows the user's verifier
+func (c *AuthenticationClient) LoginWithSRP(ctx context.Context, username string, password string) (uid *string, okey *string, rkey *string, err error) {
+	challenge, serverEphemeral, err := c.requestChallenge(ctx, username)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if challenge.Algorithm != gen_authentication.SRP6A {
+		return nil, nil, nil, fmt.Errorf("user is registered with %s, not %s", challenge.Algorithm, gen_authentication.SRP6A)
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(challenge.Base64Salt)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	client := authentication.NewSRPClient(username, password)
+	answer, err := client.Answer(salt, serverEphemeral)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return c.answerChallenge(ctx, challenge.Id, answer, func(body *gen_authentication.LoginResponse) error {
+		if body.Base64ServerProof == nil {
+			return authentication.ErrInvalidServerProof
+		}
+
+		serverProof, err := base64.StdEncoding.DecodeString(*body.Base64ServerProof)
+		if err != nil {
+			return err
 		}
+
+		return client.VerifyServerProof(serverProof)
+	})
+}
+
+// ChangePassword changes the password of the logged in user, the client must send the user's cookies.
+// The server's challenge is answered with the old password and the new key is sent encrypted with it,
+// every other session of the user is signed out
+func (c *AuthenticationClient) ChangePassword(ctx context.Context, username string, oldPassword string, newPassword string) error {
+	challengeResp, err := c.c.CredentialChangeChallenge(ctx)
+	if err != nil {
+		return err
 	}
 
-	return
+	if challengeResp.StatusCode != 200 {
+		return unmarshalError(challengeResp)
+	}
+
+	challenge, err := unmarshalResponse[gen_authentication.ChallengeServerResponse](challengeResp)
+	if err != nil {
+		return err
+	}
+
+	if challenge.Algorithm != gen_authentication.AESGCM {
+		return fmt.Errorf("user has %s credentials, not a password", challenge.Algorithm)
+	}
+
+	challengeStr, err := base64.StdEncoding.DecodeString(challenge.Base64Challenge)
+	if err != nil {
+		return err
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(challenge.Base64Salt)
+	if err != nil {
+		return err
+	}
+
+	oldKey, err := crypto.DeriveAESGCMKey(oldPassword, [16]byte(salt))
+	if err != nil {
+		return err
+	}
+
+	answer, err := crypto.AESGCMEncryptWithKey(oldKey, [16]byte(salt), challengeStr)
+	if err != nil {
+		return err
+	}
+
+	newKey, err := crypto.DeriveAESGCMKey(newPassword, [16]byte(salt))
+	if err != nil {
+		return err
+	}
+
+	secret, err := crypto.AESGCMEncryptWithKey(newKey, [16]byte(salt), []byte(username))
+	if err != nil {
+		return err
+	}
+
+	change, err := authentication.EncryptCredentialChange(authentication.SupportedAlgorithmAESGCM, oldKey, [16]byte(salt), authentication.CredentialChange{
+		Key:       newKey,
+		Secret:    secret,
+		Algorithm: authentication.SupportedAlgorithmAESGCM,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.c.ChangeCredentials(ctx, gen_authentication.ChangeCredentialsJSONRequestBody{
+		Id:              challenge.Id,
+		Base64Challenge: base64.StdEncoding.EncodeToString(answer),
+		Base64Change:    base64.StdEncoding.EncodeToString(change),
+	})
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != 200 {
+		return unmarshalError(resp)
+	}
+
+	return nil
+}
+
+// CreateAccessToken creates a personal access token for the logged in user, the client must send the user's cookies.
+// The token is scoped to the permissions, it is returned once and can not be retrieved later
+func (c *AuthenticationClient) CreateAccessToken(ctx context.Context, name string, expiresAt time.Time, permissionIDs []openapi_types.UUID) (*gen_authentication.CreatedAccessTokenResponse, error) {
+	resp, err := c.c.CreateAccessToken(ctx, gen_authentication.CreateAccessTokenJSONRequestBody{
+		Name:          name,
+		ExpiresAt:     expiresAt,
+		PermissionIds: permissionIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 201 {
+		return nil, unmarshalError(resp)
+	}
+
+	return unmarshalResponse[gen_authentication.CreatedAccessTokenResponse](resp)
+}
+
+// ListAccessTokens lists the personal access tokens of the logged in user
+func (c *AuthenticationClient) ListAccessTokens(ctx context.Context) ([]gen_authentication.AccessToken, error) {
+	resp, err := c.c.ListAccessTokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, unmarshalError(resp)
+	}
+
+	body, err := unmarshalResponse[gen_authentication.AccessTokenList](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return body.AccessTokens, nil
+}
+
+// RevokeAccessToken revokes one of the logged in user's personal access tokens
+func (c *AuthenticationClient) RevokeAccessToken(ctx context.Context, id openapi_types.UUID) error {
+	resp, err := c.c.RevokeAccessToken(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != 200 {
+		return unmarshalError(resp)
+	}
+
+	return nil
 }
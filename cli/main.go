@@ -2,46 +2,46 @@ package main
 
 import (
 	"context"
-	"encoding/json"
-	"io"
+	"flag"
+	"fmt"
 	"log"
-	"net/http"
+	"os"
+	"os/signal"
 
-	"github.com/ooqls/go-auth/api/v1/gen/gen_authentication"
+	"github.com/ooqls/go-auth/api/v1/gen/gen_oauth"
 	"github.com/ooqls/go-auth/cli/authentication"
 )
 
-func unmarshal(resp *http.Response, target interface{}) error {
-	b, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
-
-	return json.Unmarshal(b, target)
-}
-
 func main() {
-	c, err := gen_authentication.NewClient("http://localhost:8080")
-	if err != nil {
-		log.Fatal(err)
-	}
+	server := flag.String("server", "http://localhost:8080", "url of the auth service")
+	clientID := flag.String("client-id", "", "id of the public oauth client the cli logs in as")
+	scope := flag.String("scope", "", "scopes to request, the client's registered scopes when empty")
+	flag.Parse()
 
-	authCli := authentication.NewAuthenticationClient(*c)
+	if *clientID == "" {
+		log.Fatal("-client-id is required")
+	}
 
-	resp, err := authCli.Register(context.Background(), "test@test.com", "test", "test2")
+	c, err := gen_oauth.NewClient(*server)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	log.Printf("resp: %+v", resp)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-	uid, okey, rkey, err := authCli.Login(context.Background(), "test2", "test")
+	// the user approves the login in a browser where they are already logged in, no password is typed here
+	deviceCli := authentication.NewDeviceClient(*c, *clientID)
+	tokens, err := deviceCli.Login(ctx, *scope, func(device *gen_oauth.DeviceAuthorizationResponse) {
+		fmt.Printf("To log in, open %s and enter the code %s\n", device.VerificationUri, device.UserCode)
+		fmt.Printf("or open %s\n", device.VerificationUriComplete)
+	})
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	log.Printf("uid: %s", *uid)
-	log.Printf("okey: %s", *okey)
-	log.Printf("rkey: %s", *rkey)
-
+	log.Printf("access token: %s", tokens.AccessToken)
+	if tokens.RefreshToken != nil {
+		log.Printf("refresh token: %s", *tokens.RefreshToken)
+	}
 }
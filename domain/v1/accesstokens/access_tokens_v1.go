@@ -0,0 +1,281 @@
+package accesstokens
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/domain/v1/authentication"
+	"github.com/ooqls/go-auth/domain/v1/authorization"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/accesstokens"
+	"github.com/ooqls/go-auth/records/v1/roles"
+	"github.com/ooqls/go-auth/records/v1/users"
+	"go.uber.org/zap"
+)
+
+// lastUsedResolution is how often the last use of a token is written, uses in between are not recorded
+const lastUsedResolution = time.Minute
+
+// maxAccessTokenLength bounds the tokens that are hashed and looked up
+const maxAccessTokenLength = 128
+
+var _ AccessTokenService = &AccessTokenServiceV1{}
+
+type AccessTokenServiceV1 struct {
+	reader     accesstokens.Reader
+	writer     accesstokens.Writer
+	userReader users.Reader
+	roleReader roles.AggRoleReader
+}
+
+func NewAccessTokenServiceV1(
+	reader accesstokens.Reader,
+	writer accesstokens.Writer,
+	userReader users.Reader,
+	roleReader roles.AggRoleReader) *AccessTokenServiceV1 {
+
+	return &AccessTokenServiceV1{
+		reader:     reader,
+		writer:     writer,
+		userReader: userReader,
+		roleReader: roleReader,
+	}
+}
+
+// newToken returns a random personal access token
+func newToken() (string, error) {
+	var b [32]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return "", err
+	}
+
+	return authentication.AccessTokenPrefix + base64.RawURLEncoding.EncodeToString(b[:]), nil
+}
+
+// hashToken returns the hash a token is stored as, tokens are random so a fast hash is enough
+func hashToken(token string) []byte {
+	hash := sha256.Sum256([]byte(token))
+	return hash[:]
+}
+
+func (s *AccessTokenServiceV1) CreateAccessToken(ctx context.Context, userID records.UserId, name string, expiresAt time.Time, permissionIDs []records.PermissionId) (*AccessToken, string, error) {
+	l := l.With(zap.String("user_id", userID.String()))
+
+	if name == "" || len(name) > 255 {
+		return nil, "", ErrInvalidName
+	}
+
+	now := time.Now()
+	if !expiresAt.After(now) || expiresAt.After(now.Add(MaxAccessTokenLifetime)) {
+		return nil, "", ErrInvalidExpiry
+	}
+
+	if len(permissionIDs) == 0 {
+		return nil, "", ErrInvalidScope
+	}
+
+	userRoles, err := s.roleReader.GetRoleAggForUser(ctx, userID)
+	if err != nil {
+		l.Error("failed to get user roles", zap.Error(err))
+		return nil, "", ErrInternal
+	}
+
+	held := heldPermissionIDs(userRoles)
+	scope := []records.PermissionId{}
+	for _, permissionID := range permissionIDs {
+		if !held[permissionID] {
+			l.Info("user tried to scope an access token to a permission they do not hold", zap.String("permission_id", permissionID.String()))
+			return nil, "", ErrPermissionNotHeld
+		}
+
+		if !slices.Contains(scope, permissionID) {
+			scope = append(scope, permissionID)
+		}
+	}
+
+	token, err := newToken()
+	if err != nil {
+		l.Error("failed to generate access token", zap.Error(err))
+		return nil, "", ErrInternal
+	}
+
+	created, err := s.writer.CreateAccessToken(ctx, accesstokens.AccessToken{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: hashToken(token),
+		ExpiresAt: expiresAt,
+	}, scope)
+	if err != nil {
+		l.Error("failed to create access token", zap.Error(err))
+		return nil, "", ErrInternal
+	}
+
+	l.Info("created access token", zap.String("access_token_id", created.ID.String()))
+	return &AccessToken{
+		AccessToken:   *created,
+		PermissionIDs: scope,
+	}, token, nil
+}
+
+func (s *AccessTokenServiceV1) ListAccessTokens(ctx context.Context, userID records.UserId) ([]AccessToken, error) {
+	l := l.With(zap.String("user_id", userID.String()))
+
+	tokens, err := s.reader.ListAccessTokensForUser(ctx, userID)
+	if err != nil {
+		l.Error("failed to list access tokens", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	result := make([]AccessToken, 0, len(tokens))
+	for _, token := range tokens {
+		permissionIDs, err := s.reader.GetPermissionIDs(ctx, token.ID)
+		if err != nil {
+			l.Error("failed to get access token permissions", zap.String("access_token_id", token.ID.String()), zap.Error(err))
+			return nil, ErrInternal
+		}
+
+		result = append(result, AccessToken{
+			AccessToken:   token,
+			PermissionIDs: permissionIDs,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *AccessTokenServiceV1) RevokeAccessToken(ctx context.Context, userID records.UserId, id uuid.UUID) error {
+	l := l.With(zap.String("user_id", userID.String()), zap.String("access_token_id", id.String()))
+
+	deleted, err := s.writer.DeleteAccessToken(ctx, id, userID)
+	if err != nil {
+		l.Error("failed to delete access token", zap.Error(err))
+		return ErrInternal
+	}
+
+	if !deleted {
+		return ErrAccessTokenNotFound
+	}
+
+	l.Info("revoked access token")
+	return nil
+}
+
+// VerifyAccessToken looks the token up by its hash and records its use
+func (s *AccessTokenServiceV1) VerifyAccessToken(ctx context.Context, token string) (*authentication.UserClaims, error) {
+	if !authentication.IsAccessToken(token) || len(token) > maxAccessTokenLength {
+		return nil, authentication.ErrInvalidToken
+	}
+
+	stored, err := s.reader.GetAccessTokenByHash(ctx, hashToken(token))
+	if err != nil {
+		l.Error("failed to get access token", zap.Error(err))
+		return nil, authentication.ErrInternal
+	}
+
+	if stored == nil {
+		return nil, authentication.ErrInvalidToken
+	}
+
+	now := time.Now()
+	if !now.Before(stored.ExpiresAt) {
+		return nil, authentication.ErrTokenExpired
+	}
+
+	if !stored.LastUsedAt.Valid || now.Sub(stored.LastUsedAt.Time) >= lastUsedResolution {
+		// failing to record the use does not make the token invalid
+		err = s.writer.TouchAccessToken(ctx, stored.ID, now)
+		if err != nil {
+			l.Warn("failed to record access token use", zap.String("access_token_id", stored.ID.String()), zap.Error(err))
+		}
+	}
+
+	return &authentication.UserClaims{
+		UserID:        stored.UserID,
+		AccessTokenID: stored.ID,
+		IssuedAt:      stored.CreatedAt,
+		ExpiresAt:     stored.ExpiresAt,
+	}, nil
+}
+
+func (s *AccessTokenServiceV1) AuthorizationContext(ctx context.Context, claims *authentication.UserClaims) (*authorization.Context, error) {
+	l := l.With(zap.String("user_id", claims.UserID.String()))
+
+	user, err := s.userReader.GetUser(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+
+		l.Error("failed to get user", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	userRoles, err := s.roleReader.GetRoleAggForUser(ctx, claims.UserID)
+	if err != nil {
+		l.Error("failed to get user roles", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	if claims.AccessTokenID != uuid.Nil {
+		permissionIDs, err := s.reader.GetPermissionIDs(ctx, claims.AccessTokenID)
+		if err != nil {
+			l.Error("failed to get access token permissions", zap.String("access_token_id", claims.AccessTokenID.String()), zap.Error(err))
+			return nil, ErrInternal
+		}
+
+		userRoles = intersectRoles(userRoles, permissionIDs)
+	}
+
+	authCtx := authorization.NewAuthorizationContext(records.UserAgg{
+		UserId:        user.ID,
+		Roles:         userRoles,
+		EmailVerified: user.EmailVerified,
+	})
+	authCtx.Context = ctx
+	return &authCtx, nil
+}
+
+// heldPermissionIDs returns the ids of the permissions granted by the roles
+func heldPermissionIDs(roleAggs []records.RoleAgg) map[records.PermissionId]bool {
+	held := map[records.PermissionId]bool{}
+	for _, role := range roleAggs {
+		for _, permission := range role.Permissions {
+			held[permission.ID] = true
+		}
+	}
+
+	return held
+}
+
+// intersectRoles keeps the permissions of the roles that are in scope, roles left without permissions are dropped
+func intersectRoles(roleAggs []records.RoleAgg, scope []records.PermissionId) []records.RoleAgg {
+	result := []records.RoleAgg{}
+	for _, role := range roleAggs {
+		permissions := []records.Permission{}
+		for _, permission := range role.Permissions {
+			if slices.Contains(scope, permission.ID) {
+				permissions = append(permissions, permission)
+			}
+		}
+
+		if len(permissions) == 0 {
+			continue
+		}
+
+		result = append(result, records.RoleAgg{
+			RoleId:        role.RoleId,
+			RoleHierarchy: role.RoleHierarchy,
+			Permissions:   permissions,
+		})
+	}
+
+	return result
+}
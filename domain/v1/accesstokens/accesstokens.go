@@ -0,0 +1,55 @@
+package accesstokens
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/domain/v1/authentication"
+	"github.com/ooqls/go-auth/domain/v1/authorization"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/accesstokens"
+	"github.com/ooqls/go-log"
+	"go.uber.org/zap"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=accesstokens.go -destination=mocks/mock_access_tokens.go -package=mocks
+
+var l *zap.Logger
+
+func init() {
+	l = log.NewLogger("access_tokens")
+}
+
+var (
+	ErrInvalidName         error = errors.New("invalid access token name")
+	ErrInvalidExpiry       error = errors.New("invalid access token expiry")
+	ErrInvalidScope        error = errors.New("access token must be scoped to at least one permission")
+	ErrPermissionNotHeld   error = errors.New("user does not hold the permission")
+	ErrAccessTokenNotFound error = errors.New("access token not found")
+	ErrUserNotFound        error = errors.New("user not found")
+	ErrInternal            error = errors.New("internal error")
+)
+
+// MaxAccessTokenLifetime is the furthest in the future a personal access token may expire
+const MaxAccessTokenLifetime = 366 * 24 * time.Hour
+
+// AccessToken is a personal access token with the ids of the permissions it is scoped to
+type AccessToken struct {
+	accesstokens.AccessToken
+	PermissionIDs []records.PermissionId
+}
+
+// AccessTokenService manages personal access tokens. Personal access tokens are long lived tokens for scripts and CI,
+// they are scoped to a subset of their user's permissions and authorize no more than the user still holds
+type AccessTokenService interface {
+	authentication.AccessTokenVerifier
+	// CreateAccessToken creates a token scoped to permissions the user holds, the returned token is only shown once
+	CreateAccessToken(ctx context.Context, userID records.UserId, name string, expiresAt time.Time, permissionIDs []records.PermissionId) (*AccessToken, string, error)
+	ListAccessTokens(ctx context.Context, userID records.UserId) ([]AccessToken, error)
+	RevokeAccessToken(ctx context.Context, userID records.UserId, id uuid.UUID) error
+	// AuthorizationContext returns the context the claims of IsAuthenticated are authorized with, for a personal
+	// access token it holds the permissions of the user's roles the token is scoped to
+	AuthorizationContext(ctx context.Context, claims *authentication.UserClaims) (*authorization.Context, error)
+}
@@ -0,0 +1,229 @@
+package accesstokens
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/domain/v1/authentication"
+	authmocks "github.com/ooqls/go-auth/domain/v1/authentication/mocks"
+	"github.com/ooqls/go-auth/domain/v1/authorization"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/accesstokens"
+	"github.com/ooqls/go-auth/records/v1/accesstokens/mocks"
+	rolemocks "github.com/ooqls/go-auth/records/v1/roles/mocks"
+	"github.com/ooqls/go-auth/records/v1/users"
+	usermocks "github.com/ooqls/go-auth/records/v1/users/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestStore returns record mocks that keep the access tokens and their permissions like the database would
+func newTestStore(ctrl *gomock.Controller) (*mocks.MockReader, *mocks.MockWriter, map[uuid.UUID]*accesstokens.AccessToken) {
+	tokens := map[uuid.UUID]*accesstokens.AccessToken{}
+	permissions := map[uuid.UUID][]records.PermissionId{}
+
+	reader := mocks.NewMockReader(ctrl)
+	reader.EXPECT().GetAccessTokenByHash(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, tokenHash []byte) (*accesstokens.AccessToken, error) {
+			for _, token := range tokens {
+				if string(token.TokenHash) == string(tokenHash) {
+					stored := *token
+					return &stored, nil
+				}
+			}
+			return nil, nil
+		})
+	reader.EXPECT().ListAccessTokensForUser(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, userID records.UserId) ([]accesstokens.AccessToken, error) {
+			result := []accesstokens.AccessToken{}
+			for _, token := range tokens {
+				if token.UserID == userID {
+					result = append(result, *token)
+				}
+			}
+			return result, nil
+		})
+	reader.EXPECT().GetPermissionIDs(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, id uuid.UUID) ([]records.PermissionId, error) {
+			return permissions[id], nil
+		})
+
+	writer := mocks.NewMockWriter(ctrl)
+	writer.EXPECT().CreateAccessToken(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, token accesstokens.AccessToken, permissionIDs []records.PermissionId) (*accesstokens.AccessToken, error) {
+			token.ID = uuid.New()
+			tokens[token.ID] = &token
+			permissions[token.ID] = permissionIDs
+			return &token, nil
+		})
+	writer.EXPECT().TouchAccessToken(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, id uuid.UUID, usedAt time.Time) error {
+			if token, ok := tokens[id]; ok {
+				token.LastUsedAt = sql.NullTime{Time: usedAt, Valid: true}
+			}
+			return nil
+		})
+	writer.EXPECT().DeleteAccessToken(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, id uuid.UUID, userID records.UserId) (bool, error) {
+			token, ok := tokens[id]
+			if !ok || token.UserID != userID {
+				return false, nil
+			}
+			delete(tokens, id)
+			delete(permissions, id)
+			return true, nil
+		})
+
+	return reader, writer, tokens
+}
+
+type testUser struct {
+	user  users.User
+	roles []records.RoleAgg
+	read  records.Permission
+	write records.Permission
+}
+
+func newTestUser() testUser {
+	read := records.Permission{ID: uuid.New(), ResourceGroup: "core", ResourceKind: "user", ResourceName: "*", Actions: authorization.ReadAction}
+	write := records.Permission{ID: uuid.New(), ResourceGroup: "core", ResourceKind: "user", ResourceName: "*", Actions: authorization.UpdateAction}
+	return testUser{
+		user: users.User{ID: uuid.New(), Username: "test", Email: "test", EmailVerified: true},
+		roles: []records.RoleAgg{
+			{RoleId: uuid.New(), Permissions: []records.Permission{read}},
+			{RoleId: uuid.New(), Permissions: []records.Permission{write}},
+		},
+		read:  read,
+		write: write,
+	}
+}
+
+func newTestService(ctrl *gomock.Controller, user testUser) (*AccessTokenServiceV1, map[uuid.UUID]*accesstokens.AccessToken) {
+	reader, writer, tokens := newTestStore(ctrl)
+	roleReader := rolemocks.NewMockAggRoleReader(ctrl)
+	roleReader.EXPECT().GetRoleAggForUser(gomock.Any(), user.user.ID).AnyTimes().Return(user.roles, nil)
+	return NewAccessTokenServiceV1(reader, writer, usermocks.ReturnUser(ctrl, user.user), roleReader), tokens
+}
+
+func TestAccessTokens_Create(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	user := newTestUser()
+	service, tokens := newTestService(ctrl, user)
+	expiresAt := time.Now().Add(30 * 24 * time.Hour)
+
+	_, _, err := service.CreateAccessToken(ctx, user.user.ID, "", expiresAt, []records.PermissionId{user.read.ID})
+	assert.ErrorIsf(t, err, ErrInvalidName, "a token needs a name")
+
+	_, _, err = service.CreateAccessToken(ctx, user.user.ID, "ci", time.Now().Add(-time.Minute), []records.PermissionId{user.read.ID})
+	assert.ErrorIsf(t, err, ErrInvalidExpiry, "a token can not expire in the past")
+
+	_, _, err = service.CreateAccessToken(ctx, user.user.ID, "ci", time.Now().Add(2*MaxAccessTokenLifetime), []records.PermissionId{user.read.ID})
+	assert.ErrorIsf(t, err, ErrInvalidExpiry, "a token can not outlive MaxAccessTokenLifetime")
+
+	_, _, err = service.CreateAccessToken(ctx, user.user.ID, "ci", expiresAt, nil)
+	assert.ErrorIsf(t, err, ErrInvalidScope, "a token needs at least one permission")
+
+	_, _, err = service.CreateAccessToken(ctx, user.user.ID, "ci", expiresAt, []records.PermissionId{uuid.New()})
+	assert.ErrorIsf(t, err, ErrPermissionNotHeld, "a token can not be scoped to a permission the user does not hold")
+
+	created, token, err := service.CreateAccessToken(ctx, user.user.ID, "ci", expiresAt, []records.PermissionId{user.read.ID, user.read.ID})
+	assert.Nilf(t, err, "CreateAccessToken should not return an error: %v", err)
+	assert.Truef(t, authentication.IsAccessToken(token), "the token should have the access token prefix")
+	assert.Equalf(t, []records.PermissionId{user.read.ID}, created.PermissionIDs, "the scope should be deduplicated")
+	assert.NotContainsf(t, string(tokens[created.ID].TokenHash), token, "only a hash of the token should be stored")
+
+	listed, err := service.ListAccessTokens(ctx, user.user.ID)
+	assert.Nilf(t, err, "ListAccessTokens should not return an error: %v", err)
+	assert.Lenf(t, listed, 1, "the user should have one token")
+	assert.Equalf(t, created.PermissionIDs, listed[0].PermissionIDs, "the listed token should have its scope")
+
+	err = service.RevokeAccessToken(ctx, uuid.New(), created.ID)
+	assert.ErrorIsf(t, err, ErrAccessTokenNotFound, "only the user can revoke their token")
+
+	err = service.RevokeAccessToken(ctx, user.user.ID, created.ID)
+	assert.Nilf(t, err, "RevokeAccessToken should not return an error: %v", err)
+
+	err = service.RevokeAccessToken(ctx, user.user.ID, created.ID)
+	assert.ErrorIsf(t, err, ErrAccessTokenNotFound, "a token can only be revoked once")
+}
+
+func TestAccessTokens_IsAuthenticated(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	user := newTestUser()
+	service, tokens := newTestService(ctrl, user)
+	authenticator := authmocks.NewTestAuthenticator(ctrl, service, nil)
+
+	created, token, err := service.CreateAccessToken(ctx, user.user.ID, "ci", time.Now().Add(time.Hour), []records.PermissionId{user.read.ID})
+	assert.Nilf(t, err, "CreateAccessToken should not return an error: %v", err)
+
+	claims, err := authenticator.IsAuthenticated(ctx, token)
+	assert.Nilf(t, err, "IsAuthenticated should accept the access token: %v", err)
+	assert.Equalf(t, user.user.ID, claims.UserID, "the claims should be the user's")
+	assert.Equalf(t, created.ID, claims.AccessTokenID, "the claims should name the access token")
+	assert.Truef(t, tokens[created.ID].LastUsedAt.Valid, "the use of the token should be recorded")
+
+	_, err = authenticator.IsAuthenticated(ctx, authentication.AccessTokenPrefix+"unknown")
+	assert.ErrorIsf(t, err, authentication.ErrInvalidToken, "an unknown access token should not be accepted")
+
+	tokens[created.ID].ExpiresAt = time.Now().Add(-time.Second)
+	_, err = authenticator.IsAuthenticated(ctx, token)
+	assert.ErrorIsf(t, err, authentication.ErrTokenExpired, "an expired access token should not be accepted")
+
+	_, token, err = service.CreateAccessToken(ctx, user.user.ID, "revoked", time.Now().Add(time.Hour), []records.PermissionId{user.read.ID})
+	assert.Nilf(t, err, "CreateAccessToken should not return an error: %v", err)
+	claims, err = authenticator.IsAuthenticated(ctx, token)
+	assert.Nilf(t, err, "IsAuthenticated should accept the access token: %v", err)
+
+	err = service.RevokeAccessToken(ctx, user.user.ID, claims.AccessTokenID)
+	assert.Nilf(t, err, "RevokeAccessToken should not return an error: %v", err)
+	_, err = authenticator.IsAuthenticated(ctx, token)
+	assert.ErrorIsf(t, err, authentication.ErrInvalidToken, "a revoked access token should not be accepted")
+
+	withoutTokens := authmocks.NewTestAuthenticator(ctrl, nil, nil)
+	_, token, err = service.CreateAccessToken(ctx, user.user.ID, "disabled", time.Now().Add(time.Hour), []records.PermissionId{user.read.ID})
+	assert.Nilf(t, err, "CreateAccessToken should not return an error: %v", err)
+	_, err = withoutTokens.IsAuthenticated(ctx, token)
+	assert.NotNilf(t, err, "access tokens should not be accepted without a verifier")
+}
+
+func TestAccessTokens_AuthorizationContext(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	user := newTestUser()
+	service, _ := newTestService(ctrl, user)
+	resource := authorization.Resource{ResourceGroup: "core", ResourceKind: "user", ResourceName: uuid.NewString()}
+	checkpoint := authorization.UserHasResourcePermission()
+
+	userCtx, err := service.AuthorizationContext(ctx, &authentication.UserClaims{UserID: user.user.ID})
+	assert.Nilf(t, err, "AuthorizationContext should not return an error: %v", err)
+	assert.Truef(t, checkpoint.IsAuthorized(userCtx, authorization.ReadAction, resource), "the user should be able to read")
+	assert.Truef(t, checkpoint.IsAuthorized(userCtx, authorization.UpdateAction, resource), "the user should be able to update")
+
+	_, token, err := service.CreateAccessToken(ctx, user.user.ID, "ci", time.Now().Add(time.Hour), []records.PermissionId{user.read.ID})
+	assert.Nilf(t, err, "CreateAccessToken should not return an error: %v", err)
+	claims, err := service.VerifyAccessToken(ctx, token)
+	assert.Nilf(t, err, "VerifyAccessToken should not return an error: %v", err)
+
+	tokenCtx, err := service.AuthorizationContext(ctx, claims)
+	assert.Nilf(t, err, "AuthorizationContext should not return an error: %v", err)
+	assert.Truef(t, checkpoint.IsAuthorized(tokenCtx, authorization.ReadAction, resource), "the token should be able to read")
+	assert.Falsef(t, checkpoint.IsAuthorized(tokenCtx, authorization.UpdateAction, resource), "the token should not be able to update, it is not in its scope")
+	assert.Lenf(t, tokenCtx.GetRoles(), 1, "roles without permissions in scope should be dropped")
+
+	// the user losing a role takes its permissions from their tokens as well
+	user.roles[0].Permissions = []records.Permission{}
+	tokenCtx, err = service.AuthorizationContext(ctx, claims)
+	assert.Nilf(t, err, "AuthorizationContext should not return an error: %v", err)
+	assert.Falsef(t, checkpoint.IsAuthorized(tokenCtx, authorization.ReadAction, resource), "the token should not outlive the user's permissions")
+
+	deletedReader := usermocks.NewMockReader(ctrl)
+	deletedReader.EXPECT().GetUser(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, sql.ErrNoRows)
+	deleted := NewAccessTokenServiceV1(service.reader, service.writer, deletedReader, service.roleReader)
+	_, err = deleted.AuthorizationContext(ctx, claims)
+	assert.ErrorIsf(t, err, ErrUserNotFound, "deleted users should not be found")
+}
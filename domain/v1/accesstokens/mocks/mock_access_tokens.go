@@ -0,0 +1,116 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: accesstokens.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	accesstokens "github.com/ooqls/go-auth/domain/v1/accesstokens"
+	authentication "github.com/ooqls/go-auth/domain/v1/authentication"
+	authorization "github.com/ooqls/go-auth/domain/v1/authorization"
+	records "github.com/ooqls/go-auth/records"
+)
+
+// MockAccessTokenService is a mock of AccessTokenService interface.
+type MockAccessTokenService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessTokenServiceMockRecorder
+}
+
+// MockAccessTokenServiceMockRecorder is the mock recorder for MockAccessTokenService.
+type MockAccessTokenServiceMockRecorder struct {
+	mock *MockAccessTokenService
+}
+
+// NewMockAccessTokenService creates a new mock instance.
+func NewMockAccessTokenService(ctrl *gomock.Controller) *MockAccessTokenService {
+	mock := &MockAccessTokenService{ctrl: ctrl}
+	mock.recorder = &MockAccessTokenServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessTokenService) EXPECT() *MockAccessTokenServiceMockRecorder {
+	return m.recorder
+}
+
+// AuthorizationContext mocks base method.
+func (m *MockAccessTokenService) AuthorizationContext(ctx context.Context, claims *authentication.UserClaims) (*authorization.Context, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizationContext", ctx, claims)
+	ret0, _ := ret[0].(*authorization.Context)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthorizationContext indicates an expected call of AuthorizationContext.
+func (mr *MockAccessTokenServiceMockRecorder) AuthorizationContext(ctx, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizationContext", reflect.TypeOf((*MockAccessTokenService)(nil).AuthorizationContext), ctx, claims)
+}
+
+// CreateAccessToken mocks base method.
+func (m *MockAccessTokenService) CreateAccessToken(ctx context.Context, userID records.UserId, name string, expiresAt time.Time, permissionIDs []records.PermissionId) (*accesstokens.AccessToken, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccessToken", ctx, userID, name, expiresAt, permissionIDs)
+	ret0, _ := ret[0].(*accesstokens.AccessToken)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateAccessToken indicates an expected call of CreateAccessToken.
+func (mr *MockAccessTokenServiceMockRecorder) CreateAccessToken(ctx, userID, name, expiresAt, permissionIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccessToken", reflect.TypeOf((*MockAccessTokenService)(nil).CreateAccessToken), ctx, userID, name, expiresAt, permissionIDs)
+}
+
+// ListAccessTokens mocks base method.
+func (m *MockAccessTokenService) ListAccessTokens(ctx context.Context, userID records.UserId) ([]accesstokens.AccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccessTokens", ctx, userID)
+	ret0, _ := ret[0].([]accesstokens.AccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccessTokens indicates an expected call of ListAccessTokens.
+func (mr *MockAccessTokenServiceMockRecorder) ListAccessTokens(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccessTokens", reflect.TypeOf((*MockAccessTokenService)(nil).ListAccessTokens), ctx, userID)
+}
+
+// RevokeAccessToken mocks base method.
+func (m *MockAccessTokenService) RevokeAccessToken(ctx context.Context, userID records.UserId, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAccessToken", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAccessToken indicates an expected call of RevokeAccessToken.
+func (mr *MockAccessTokenServiceMockRecorder) RevokeAccessToken(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAccessToken", reflect.TypeOf((*MockAccessTokenService)(nil).RevokeAccessToken), ctx, userID, id)
+}
+
+// VerifyAccessToken mocks base method.
+func (m *MockAccessTokenService) VerifyAccessToken(ctx context.Context, token string) (*authentication.UserClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAccessToken", ctx, token)
+	ret0, _ := ret[0].(*authentication.UserClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyAccessToken indicates an expected call of VerifyAccessToken.
+func (mr *MockAccessTokenServiceMockRecorder) VerifyAccessToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAccessToken", reflect.TypeOf((*MockAccessTokenService)(nil).VerifyAccessToken), ctx, token)
+}
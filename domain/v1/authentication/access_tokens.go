@@ -0,0 +1,24 @@
+package authentication
+
+import (
+	"context"
+	"strings"
+)
+
+// AccessTokenPrefix starts every personal access token, it tells them apart from signed auth tokens
+// and makes them easy to find for secret scanners
+const AccessTokenPrefix = "ooq_pat_"
+
+//go:generate go run github.com/golang/mock/mockgen -source=access_tokens.go -destination=mocks/mock_access_token_verifier.go -package=mocks
+
+// AccessTokenVerifier verifies personal access tokens, see accesstokens
+type AccessTokenVerifier interface {
+	// VerifyAccessToken returns the claims of the token's user with AccessTokenID set,
+	// ErrInvalidToken if the token is unknown and ErrTokenExpired if it expired
+	VerifyAccessToken(ctx context.Context, token string) (*UserClaims, error)
+}
+
+// IsAccessToken returns true if the token is a personal access token rather than an auth token
+func IsAccessToken(token string) bool {
+	return strings.HasPrefix(token, AccessTokenPrefix)
+}
@@ -5,15 +5,18 @@ import (
 	"errors"
 	"time"
 
+	jwtv5 "github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/mfa"
+	"github.com/ooqls/go-auth/records/v1/sessions"
 	"github.com/ooqls/go-auth/records/v1/users"
 	"github.com/ooqls/go-cache/cache"
 	"github.com/ooqls/go-cache/factory"
+	"github.com/ooqls/go-cache/store"
 	"github.com/ooqls/go-crypto/crypto"
 	"github.com/ooqls/go-crypto/jwt"
 	"github.com/ooqls/go-log"
-	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
@@ -24,10 +27,11 @@ func init() {
 }
 
 type Registration struct {
-	Username string
-	Key      []byte
-	Email    string
-	Secret   []byte
+	Username  string
+	Key       []byte
+	Email     string
+	Secret    []byte
+	Algorithm SupportedAlgorithm
 }
 
 var (
@@ -39,22 +43,62 @@ var (
 	ErrInternal            error = errors.New("internal error")
 	ErrRegistrationExpired error = errors.New("registration expired")
 	ErrInvalidRegistration error = errors.New("invalid registration")
+	ErrRefreshTokenReused  error = errors.New("refresh token reused")
+	ErrSessionNotFound     error = errors.New("session not found")
 )
 
+// refreshFamilyTTL is how long a refresh token family is remembered after its last rotation
+const refreshFamilyTTL = 24 * time.Hour
+
 type Authenticator interface {
 	ValidateRegistration(ctx context.Context, reg Registration) ([crypto.SALT_SIZE]byte, error)
 	ChallengeRequest(ctx context.Context, user *users.User) (*Challenge, error)
-	ChallengeResponse(ctx context.Context, challengeId uuid.UUID, solvedChallenge []byte) (okey string, rkey string, uid string, err error)
+	ChallengeResponse(ctx context.Context, challengeId uuid.UUID, solvedChallenge []byte) (*TokenResponse, error)
 	AuthenticateNewUser(ctx context.Context, user *users.User) (*TokenResponse, error)
+	AuthenticateExternalUser(ctx context.Context, user *users.User) (*TokenResponse, error)
+	AuthenticateMagicLinkUser(ctx context.Context, user *users.User) (*TokenResponse, error)
 	AuthenticateWithToken(ctx context.Context, authToken string) (*TokenResponse, error)
+	AuthenticateServiceAccount(ctx context.Context, id records.UserId) (*TokenResponse, error)
 	IsAuthenticated(ctx context.Context, token string) (*UserClaims, error)
+	Refresh(ctx context.Context, refreshToken string) (*TokenResponse, error)
+	Revoke(ctx context.Context, token string) error
+	RevokeAllForUser(ctx context.Context, userId records.UserId) error
+	ListSessions(ctx context.Context, userId records.UserId) ([]sessions.Session, error)
+	TerminateSession(ctx context.Context, userId records.UserId, sessionId sessions.SessionId) error
+	TerminateOtherSessions(ctx context.Context, userId records.UserId, currentSessionId sessions.SessionId) error
+	EnrollMFA(ctx context.Context, userId records.UserId, accountName string) (*MFAEnrollment, error)
+	ConfirmMFA(ctx context.Context, userId records.UserId, code string) ([]string, error)
+	VerifyMFA(ctx context.Context, mfaToken string, code string) (*TokenResponse, error)
+	ChangeCredentials(ctx context.Context, userId records.UserId, challengeId uuid.UUID, solvedChallenge []byte, change []byte) (*NewCredentials, error)
+}
+
+// refreshFamily tracks the chain of refresh tokens issued from a single login.
+// Only the most recently issued refresh token of a family may be exchanged,
+// presenting an older one revokes the whole family. The family id is also the
+// id of the session started by the login.
+type refreshFamily struct {
+	ID      uuid.UUID
+	UserID  records.UserId
+	TokenID string
+	Revoked bool
 }
 
 type AuthenticatorV1 struct {
 	tokenCache          cache.GenericCache
+	refreshFamilies     store.GenericInterface
+	revocations         store.GenericInterface
+	mfaPending          store.GenericInterface
+	mfaFailures         store.GenericInterface
 	authorizationIssuer jwt.TokenIssuer[UserClaims]
 	refreshIssuer       jwt.TokenIssuer[UserClaims]
 	challenger          Challenger
+	sessionReader       sessions.Reader
+	sessionWriter       sessions.Writer
+	mfaReader           mfa.Reader
+	mfaWriter           mfa.Writer
+	emailPolicy         EmailVerificationPolicy
+	accessTokens        AccessTokenVerifier
+	claimsMapper        ClaimsMapper
 	audience            []string
 }
 
@@ -63,13 +107,31 @@ func NewAuthenticatorV1(
 	refreshIssuer jwt.TokenIssuer[UserClaims],
 	cacheFactory factory.CacheFactory,
 	challenger Challenger,
+	sessionReader sessions.Reader,
+	sessionWriter sessions.Writer,
+	mfaReader mfa.Reader,
+	mfaWriter mfa.Writer,
+	emailPolicy EmailVerificationPolicy,
+	accessTokens AccessTokenVerifier,
+	claimsMapper ClaimsMapper,
 	audience []string) Authenticator {
 
 	return &AuthenticatorV1{
 		tokenCache:          cacheFactory.NewCache("token", 10*time.Minute),
+		refreshFamilies:     cacheFactory.NewStore("refresh_families", refreshFamilyTTL),
+		revocations:         cacheFactory.NewStore("revocations", revocationTTL),
+		mfaPending:          cacheFactory.NewStore("mfa_pending", mfaPendingTTL),
+		mfaFailures:         cacheFactory.NewStore("mfa_failures", mfaFailureWindow),
 		authorizationIssuer: authorizationIssuer,
 		refreshIssuer:       refreshIssuer,
 		challenger:          challenger,
+		sessionReader:       sessionReader,
+		sessionWriter:       sessionWriter,
+		mfaReader:           mfaReader,
+		mfaWriter:           mfaWriter,
+		emailPolicy:         emailPolicy,
+		accessTokens:        accessTokens,
+		claimsMapper:        claimsMapper,
 		audience:            audience,
 	}
 }
@@ -77,7 +139,7 @@ func NewAuthenticatorV1(
 func (a *AuthenticatorV1) ValidateRegistration(ctx context.Context, reg Registration) ([crypto.SALT_SIZE]byte, error) {
 	l := l.With(zap.String("username", reg.Username))
 
-	salt, err := a.challenger.VerifyRegistration(ctx, reg.Username, reg.Secret, reg.Key)
+	salt, err := a.challenger.VerifyRegistration(ctx, reg)
 	if err != nil {
 		l.Error("failed to verify registration", zap.Error(err))
 		return [crypto.SALT_SIZE]byte{}, err
@@ -115,24 +177,38 @@ func (a *AuthenticatorV1) ChallengeRequest(ctx context.Context, user *users.User
 }
 
 // ChallengeResponse will verify the user's challenge response
-// Returns the auth token, refresh token, user id, and any errors
-func (a *AuthenticatorV1) ChallengeResponse(ctx context.Context, challengeId uuid.UUID, solvedChallenge []byte) (okey string, rkey string, uid string, err error) {
+// Returns the tokens of the new session, or an mfa token when the user has MFA enabled
+func (a *AuthenticatorV1) ChallengeResponse(ctx context.Context, challengeId uuid.UUID, solvedChallenge []byte) (*TokenResponse, error) {
 	result, err := a.challenger.VerifyChallenge(ctx, challengeId, solvedChallenge)
 	if err != nil {
-		return "", "", "", err
+		return nil, err
 	}
 
-	authToken, err := a.issueNewAuthToken(ctx, result.User.ID)
+	err = a.emailPolicy.checkLogin(result.User)
 	if err != nil {
-		return "", "", "", err
+		l.Info("unverified user is not allowed to log in", zap.String("user_id", result.User.ID.String()))
+		return nil, err
 	}
 
-	refreshToken, err := a.issueNewRefreshToken(ctx, result.User.ID)
+	mfaEnabled, err := a.mfaEnabled(ctx, result.User.ID)
 	if err != nil {
-		return "", "", "", err
+		l.Error("failed to check if mfa is enabled", zap.String("user_id", result.User.ID.String()), zap.Error(err))
+		return nil, ErrInternal
 	}
 
-	return authToken, refreshToken, result.User.ID.String(), nil
+	login := Login{Methods: result.Methods, Time: time.Now()}
+	var tokens *TokenResponse
+	if mfaEnabled {
+		tokens, err = a.requireMFA(ctx, result.User.ID, login)
+	} else {
+		tokens, err = a.startSession(ctx, result.User.ID, login)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tokens.ServerProof = result.ServerProof
+	return tokens, nil
 }
 
 // IsAuthenticated will check if a user is authenticated
@@ -141,7 +217,12 @@ func (a *AuthenticatorV1) ChallengeResponse(ctx context.Context, challengeId uui
 // 2. the token is not expired
 // 3. the token is not revoked
 // 4. the token is not blacklisted
+// personal access tokens are accepted as well when an AccessTokenVerifier is configured
 func (a *AuthenticatorV1) IsAuthenticated(ctx context.Context, token string) (*UserClaims, error) {
+	if a.accessTokens != nil && IsAccessToken(token) {
+		return a.accessTokens.VerifyAccessToken(ctx, token)
+	}
+
 	claims, err := a.getAuthTokenAuthentication(ctx, token)
 	if err != nil {
 		return nil, err
@@ -150,109 +231,290 @@ func (a *AuthenticatorV1) IsAuthenticated(ctx context.Context, token string) (*U
 	return claims, nil
 }
 
-func (a *AuthenticatorV1) AuthenticateNewUser(ctx context.Context, user *users.User) (*TokenResponse, error) {
-	authToken, err := a.issueNewAuthToken(ctx, user.ID)
+// Refresh exchanges a refresh token for a new auth token and refresh token.
+// The presented refresh token is rotated out, if it is ever presented again the
+// whole token family is revoked and ErrRefreshTokenReused is returned
+func (a *AuthenticatorV1) Refresh(ctx context.Context, refreshToken string) (*TokenResponse, error) {
+	if refreshToken == "" || len(refreshToken) > 1024 {
+		return nil, ErrInvalidToken
+	}
+
+	claims, tokenID, err := a.getRefreshTokenAuthentication(ctx, refreshToken)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := a.issueNewRefreshToken(ctx, user.ID)
+	l := l.With(zap.String("user_id", claims.UserID.String()), zap.String("family_id", claims.FamilyID.String()))
+
+	login := Login{Methods: claims.AMR}
+	if claims.AuthTime != 0 {
+		login.Time = time.Unix(claims.AuthTime, 0)
+	}
+
+	newRefreshToken, newRegClaims, err := a.signRefreshToken(claims.UserID, claims.FamilyID, login)
+	if err != nil {
+		l.Error("failed to sign refresh token", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	reused := false
+	err = a.refreshFamilies.Update(ctx, claims.FamilyID.String(), func(load func(target any) error) (any, error) {
+		var family refreshFamily
+		if err := load(&family); err != nil {
+			return nil, err
+		}
+
+		if family.Revoked || family.UserID != claims.UserID {
+			return nil, ErrInvalidToken
+		}
+
+		if family.TokenID != tokenID {
+			reused = true
+			family.Revoked = true
+			return family, nil
+		}
+
+		family.TokenID = newRegClaims.ID
+		return family, nil
+	})
+	if err != nil {
+		if cache.IsCacheMissErr(err) || errors.Is(err, ErrInvalidToken) {
+			l.Warn("refresh token family is unknown or revoked")
+			return nil, ErrInvalidToken
+		}
+
+		l.Error("failed to rotate refresh token family", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	if reused {
+		l.Warn("refresh token was reused, revoked token family")
+		return nil, ErrRefreshTokenReused
+	}
+
+	err = a.sessionWriter.TouchSession(ctx, claims.FamilyID, newRegClaims.ID, newRegClaims.ExpiresAt.Time)
+	if err != nil {
+		l.Error("failed to update session", zap.Error(err))
+	}
+
+	authToken, err := a.issueNewAuthToken(ctx, claims.UserID, claims.FamilyID, login)
 	if err != nil {
 		return nil, err
 	}
 
 	return &TokenResponse{
 		AuthToken:    authToken,
-		RefreshToken: refreshToken,
-		UserId:       user.ID,
+		RefreshToken: newRefreshToken,
+		UserId:       claims.UserID,
 	}, nil
 }
 
-func (a *AuthenticatorV1) issueNewAuthToken(ctx context.Context, id records.UserId) (string, error) {
-	claims := UserClaims{UserID: id}
-	token, _, err := a.authorizationIssuer.IssueToken(id.String(), UserClaims{UserID: id})
+func (a *AuthenticatorV1) AuthenticateNewUser(ctx context.Context, user *users.User) (*TokenResponse, error) {
+	err := a.emailPolicy.checkLogin(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.startSession(ctx, user.ID, Login{Time: time.Now()})
+}
+
+// AuthenticateExternalUser logs in a user whose password was checked by an external directory instead of a challenge.
+// Unlike AuthenticateNewUser the user is asked for their second factor when they have MFA enabled
+func (a *AuthenticatorV1) AuthenticateExternalUser(ctx context.Context, user *users.User) (*TokenResponse, error) {
+	return a.authenticateFirstFactor(ctx, user, Login{Methods: []string{AuthMethodPassword}, Time: time.Now()})
+}
+
+// AuthenticateMagicLinkUser logs in a user who opened a magic link emailed to them. Like AuthenticateExternalUser
+// the user is asked for their second factor when they have MFA enabled, the link only proves access to the email
+func (a *AuthenticatorV1) AuthenticateMagicLinkUser(ctx context.Context, user *users.User) (*TokenResponse, error) {
+	return a.authenticateFirstFactor(ctx, user, Login{Time: time.Now()})
+}
+
+// authenticateFirstFactor starts a session for a user who passed the first factor of the login, or parks the login
+// until the user provides their second factor when they have MFA enabled
+func (a *AuthenticatorV1) authenticateFirstFactor(ctx context.Context, user *users.User, login Login) (*TokenResponse, error) {
+	err := a.emailPolicy.checkLogin(user)
+	if err != nil {
+		return nil, err
+	}
+
+	mfaEnabled, err := a.mfaEnabled(ctx, user.ID)
+	if err != nil {
+		l.Error("failed to check if mfa is enabled", zap.String("user_id", user.ID.String()), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	if mfaEnabled {
+		return a.requireMFA(ctx, user.ID, login)
+	}
+
+	return a.startSession(ctx, user.ID, login)
+}
+
+// AuthenticateServiceAccount issues an auth token for a service account whose credentials were checked by the caller.
+// Service accounts have no session and get no refresh token, they authenticate again once the token expires
+func (a *AuthenticatorV1) AuthenticateServiceAccount(ctx context.Context, id records.UserId) (*TokenResponse, error) {
+	token, regClaims, err := a.issueAuthToken(ctx, UserClaims{UserID: id, ServiceAccount: true}, Login{Time: time.Now()})
 	if err != nil {
-		return "", err
+		l.Error("failed to issue service account token", zap.String("service_account_id", id.String()), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	response := &TokenResponse{
+		AuthToken: token,
+		UserId:    id,
+	}
+	if regClaims.ExpiresAt != nil {
+		response.ExpiresIn = int(time.Until(regClaims.ExpiresAt.Time).Seconds())
 	}
 
-	err = a.tokenCache.Set(ctx, token, claims)
+	return response, nil
+}
+
+func (a *AuthenticatorV1) issueNewAuthToken(ctx context.Context, id records.UserId, sessionID sessions.SessionId, login Login) (string, error) {
+	token, _, err := a.issueAuthToken(ctx, UserClaims{UserID: id, SessionID: sessionID}, login)
+	return token, err
+}
+
+// issueAuthToken signs an auth token with the claims and caches it, returning the token and its registered claims.
+// The claims mapper adds its claims first
+func (a *AuthenticatorV1) issueAuthToken(ctx context.Context, claims UserClaims, login Login) (string, *jwtv5.RegisteredClaims, error) {
+	if a.claimsMapper != nil {
+		err := a.claimsMapper.MapClaims(ctx, &claims, login)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	token, jwtToken, err := a.authorizationIssuer.IssueToken(claims.UserID.String(), claims)
+	if err != nil {
+		return "", nil, err
+	}
+
+	regClaims, ok := registeredClaims(jwtToken)
+	if !ok {
+		return "", nil, ErrInvalidToken
+	}
+
+	err = a.tokenCache.Set(ctx, token, newCachedToken(claims, regClaims))
 	if err != nil {
 		l.Error("failed to set token in cache", zap.Error(err))
 	}
 
-	return token, nil
+	return token, regClaims, nil
 }
 
-func (a *AuthenticatorV1) issueNewRefreshToken(ctx context.Context, id records.UserId) (string, error) {
-	claims := UserClaims{UserID: id}
-	token, _, err := a.refreshIssuer.IssueToken(id.String(), claims)
+// issueNewRefreshToken starts a new refresh token family for the user and
+// returns the first refresh token of that family
+func (a *AuthenticatorV1) issueNewRefreshToken(ctx context.Context, id records.UserId, familyID uuid.UUID, login Login) (string, *jwtv5.RegisteredClaims, error) {
+	token, regClaims, err := a.signRefreshToken(id, familyID, login)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
-	err = a.tokenCache.Set(ctx, token, claims)
+	err = a.refreshFamilies.Set(ctx, familyID.String(), refreshFamily{
+		ID:      familyID,
+		UserID:  id,
+		TokenID: regClaims.ID,
+	})
 	if err != nil {
-		l.Error("failed to set refresh token in cache", zap.Error(err))
+		l.Error("failed to store refresh token family", zap.Error(err))
+		return "", nil, ErrInternal
 	}
 
-	return token, nil
+	return token, regClaims, nil
 }
 
-func (a *AuthenticatorV1) getRefreshTokenAuthentication(ctx context.Context, token string) (*UserClaims, error) {
-	var cachedClaims UserClaims
-	err := a.tokenCache.Get(ctx, token, &cachedClaims)
-	if err != nil && err != redis.Nil {
-		l.Error("failed to get user authentication from cache", zap.Error(err))
+// signRefreshToken returns a refresh token for the given family and its registered claims, the login is
+// always kept in refresh tokens so the auth tokens of the session can be mapped the same way
+func (a *AuthenticatorV1) signRefreshToken(id records.UserId, familyID uuid.UUID, login Login) (string, *jwtv5.RegisteredClaims, error) {
+	claims := UserClaims{UserID: id, SessionID: familyID, FamilyID: familyID, AMR: login.Methods}
+	if !login.Time.IsZero() {
+		claims.AuthTime = login.Time.Unix()
+	}
+
+	token, jwtToken, err := a.refreshIssuer.IssueToken(id.String(), claims)
+	if err != nil {
+		return "", nil, err
 	}
 
-	if err == nil {
-		return &cachedClaims, nil
+	regClaims, ok := registeredClaims(jwtToken)
+	if !ok || regClaims.ExpiresAt == nil {
+		return "", nil, ErrInvalidToken
 	}
+
+	return token, regClaims, nil
+}
+
+// getRefreshTokenAuthentication verifies the refresh token and returns its claims and token id.
+// Refresh tokens are not served from the token cache since they are single use
+func (a *AuthenticatorV1) getRefreshTokenAuthentication(ctx context.Context, token string) (*UserClaims, string, error) {
 	jwtToken, claims, err := a.refreshIssuer.Decrypt(token)
 	if err != nil {
 		l.Error("failed to decrypt jwt token", zap.Error(err))
-		return nil, ErrInvalidToken
+		return nil, "", ErrInvalidToken
 	}
 
 	if !jwtToken.Valid {
 		l.Error("token is not valid")
-		return nil, ErrInvalidToken
+		return nil, "", ErrInvalidToken
 	}
 
-	err = a.tokenCache.Set(ctx, token, claims)
+	regClaims, ok := registeredClaims(jwtToken)
+	if !ok || regClaims.ID == "" || claims.FamilyID == uuid.Nil {
+		l.Error("refresh token is missing its token id or family")
+		return nil, "", ErrInvalidToken
+	}
+
+	err = a.checkNotRevoked(ctx, newCachedToken(claims, regClaims))
 	if err != nil {
-		l.Error("failed to set user authentication in cache", zap.Error(err))
+		return nil, "", err
 	}
 
-	return &claims, nil
+	return &claims, regClaims.ID, nil
 }
 
 func (a *AuthenticatorV1) getAuthTokenAuthentication(ctx context.Context, token string) (*UserClaims, error) {
-	var cachedClaims UserClaims
-	err := a.tokenCache.Get(ctx, token, &cachedClaims)
-	if err != nil && err != redis.Nil {
+	var cached cachedToken
+	err := a.tokenCache.Get(ctx, token, &cached)
+	if err != nil && !cache.IsCacheMissErr(err) {
 		l.Error("failed to get user authentication from cache", zap.Error(err))
 	}
 
-	if err == nil {
-		return &cachedClaims, nil
-	}
-
-	jwtToken, claims, err := a.authorizationIssuer.Decrypt(token)
 	if err != nil {
-		l.Error("failed to decrypt jwt token", zap.Error(err))
-		return nil, ErrInvalidToken
+		// the issuer picks the verification key by the token's kid, so tokens signed before a rotation stay valid
+		jwtToken, claims, err := a.authorizationIssuer.Decrypt(token)
+		if err != nil {
+			l.Error("failed to decrypt jwt token", zap.String("kid", tokenKeyID(jwtToken)), zap.Error(err))
+			return nil, ErrInvalidToken
+		}
+
+		if !jwtToken.Valid {
+			l.Error("token is not valid")
+			return nil, ErrInvalidToken
+		}
+
+		regClaims, ok := registeredClaims(jwtToken)
+		if !ok {
+			l.Error("token is missing registered claims")
+			return nil, ErrInvalidToken
+		}
+
+		cached = newCachedToken(claims, regClaims)
+		err = a.tokenCache.Set(ctx, token, cached)
+		if err != nil {
+			l.Error("failed to set user authentication in cache", zap.Error(err))
+		}
 	}
 
-	if !jwtToken.Valid {
-		l.Error("token is not valid")
-		return nil, ErrInvalidToken
+	if time.Now().After(cached.ExpiresAt) {
+		return nil, ErrTokenExpired
 	}
 
-	err = a.tokenCache.Set(ctx, token, claims)
+	err = a.checkNotRevoked(ctx, cached)
 	if err != nil {
-		l.Error("failed to set user authentication in cache", zap.Error(err))
+		return nil, err
 	}
 
-	return &claims, nil
+	return cached.userClaims(), nil
 }
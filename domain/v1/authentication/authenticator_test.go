@@ -7,6 +7,11 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	attemptmocks "github.com/ooqls/go-auth/records/v1/challengeattempts/mocks"
+	"github.com/ooqls/go-auth/records/v1/mfa"
+	mfamocks "github.com/ooqls/go-auth/records/v1/mfa/mocks"
+	"github.com/ooqls/go-auth/records/v1/sessions"
+	sessionmocks "github.com/ooqls/go-auth/records/v1/sessions/mocks"
 	"github.com/ooqls/go-auth/records/v1/users"
 	"github.com/ooqls/go-cache/factory"
 	"github.com/ooqls/go-cache/store"
@@ -74,13 +79,20 @@ func TestAuthenticator_IsAuthenticated(t *testing.T) {
 			Key:      key,
 			Salt:     saltB,
 		}
-		challenger := NewChallengerV1(store.NewMemStore("test", time.Second*10))
+		challenger := NewChallengerV1(store.NewMemStore("test", time.Second*10), attemptmocks.NoFailedAttempts(ctrl), attemptmocks.NoopWriter(ctrl), DefaultLockoutPolicy())
 
 		authenticator := NewAuthenticatorV1(
 			issuer,
 			refreshIssuer,
 			&factory.MemCacheFactory{},
 			challenger,
+			sessionmocks.ReturnSessions(ctrl),
+			sessionmocks.AcceptSessions(ctrl),
+			mfamocks.NotEnrolled(ctrl),
+			mfamocks.NewMockWriter(ctrl),
+			UnverifiedLoginAllowed,
+			nil,
+			nil,
 			[]string{"test"},
 		)
 
@@ -89,14 +101,294 @@ func TestAuthenticator_IsAuthenticated(t *testing.T) {
 
 		response := tc.solveChallenge(t, string(resp.Challenge), keyAlgo)
 
-		okey, rkey, uid, err := authenticator.ChallengeResponse(ctx, resp.ID, response)
+		tokens, err := authenticator.ChallengeResponse(ctx, resp.ID, response)
 		if tc.shouldAuthenticate {
 			assert.Nilf(t, err, "ChallengeResponse should not return an error: %v", err)
-			assert.NotNilf(t, okey, "okey should not be nil")
-			assert.NotNilf(t, rkey, "rkey should not be nil")
-			assert.NotNilf(t, uid, "uid should not be nil")
+			assert.NotEmptyf(t, tokens.AuthToken, "auth token should not be empty")
+			assert.NotEmptyf(t, tokens.RefreshToken, "refresh token should not be empty")
+			assert.Equalf(t, user.ID, tokens.UserId, "user id should be the user's")
 		} else {
 			assert.NotNilf(t, err, "ChallengeResponse should return an error: %v", err)
 		}
 	}
 }
+
+func newTestAuthenticator(t *testing.T) Authenticator {
+	ctrl := gomock.NewController(t)
+	return newTestAuthenticatorWithSessions(t, sessionmocks.ReturnSessions(ctrl), sessionmocks.AcceptSessions(ctrl))
+}
+
+func newTestAuthenticatorWithSessions(t *testing.T, sessionReader sessions.Reader, sessionWriter sessions.Writer) Authenticator {
+	ctrl := gomock.NewController(t)
+	return newTestAuthenticatorWith(t, sessionReader, sessionWriter, mfamocks.NotEnrolled(ctrl), mfamocks.NewMockWriter(ctrl), UnverifiedLoginAllowed)
+}
+
+func newTestAuthenticatorWith(t *testing.T, sessionReader sessions.Reader, sessionWriter sessions.Writer, mfaReader mfa.Reader, mfaWriter mfa.Writer, emailPolicy EmailVerificationPolicy) Authenticator {
+	ctrl := gomock.NewController(t)
+	rKey, err := keys.NewRSA()
+	assert.Nilf(t, err, "failed to create new key: %v", err)
+	jwtKey := keys.NewJWTKey(*rKey)
+	issuer := jwt.NewJwtTokenIssuer[UserClaims](&jwt.TokenConfiguration{
+		Issuer:                  "test",
+		Audience:                []string{"test"},
+		ValidityDurationSeconds: 300,
+	}, jwtKey)
+
+	refreshIssuer := jwt.NewJwtTokenIssuer[UserClaims](&jwt.TokenConfiguration{
+		Issuer:                  "refresh",
+		Audience:                []string{"refresh"},
+		ValidityDurationSeconds: 300,
+	}, jwtKey)
+
+	return NewAuthenticatorV1(
+		issuer,
+		refreshIssuer,
+		&factory.MemCacheFactory{},
+		NewChallengerV1(store.NewMemStore("test", time.Second*10), attemptmocks.NoFailedAttempts(ctrl), attemptmocks.NoopWriter(ctrl), DefaultLockoutPolicy()),
+		sessionReader,
+		sessionWriter,
+		mfaReader,
+		mfaWriter,
+		emailPolicy,
+		nil,
+		nil,
+		[]string{"test"},
+	)
+}
+
+func TestAuthenticator_Refresh(t *testing.T) {
+	ctx := context.Background()
+	authenticator := newTestAuthenticator(t)
+	user := &users.User{ID: uuid.New(), Username: "test", Email: "test"}
+
+	tokens, err := authenticator.AuthenticateNewUser(ctx, user)
+	assert.Nilf(t, err, "AuthenticateNewUser should not return an error: %v", err)
+
+	_, err = authenticator.Refresh(ctx, tokens.AuthToken)
+	assert.ErrorIsf(t, err, ErrInvalidToken, "an auth token should not be accepted as a refresh token")
+
+	refreshed, err := authenticator.Refresh(ctx, tokens.RefreshToken)
+	assert.Nilf(t, err, "Refresh should not return an error: %v", err)
+	assert.Equalf(t, user.ID, refreshed.UserId, "refreshed tokens should belong to the user")
+	assert.NotEqualf(t, tokens.RefreshToken, refreshed.RefreshToken, "refresh token should be rotated")
+
+	claims, err := authenticator.IsAuthenticated(ctx, refreshed.AuthToken)
+	assert.Nilf(t, err, "refreshed auth token should be valid: %v", err)
+	assert.Equalf(t, user.ID, claims.UserID, "refreshed auth token should belong to the user")
+
+	_, err = authenticator.Refresh(ctx, tokens.RefreshToken)
+	assert.ErrorIsf(t, err, ErrRefreshTokenReused, "replaying a rotated refresh token should be detected")
+
+	_, err = authenticator.Refresh(ctx, refreshed.RefreshToken)
+	assert.ErrorIsf(t, err, ErrInvalidToken, "the token family should be revoked after reuse")
+
+	other, err := authenticator.AuthenticateNewUser(ctx, user)
+	assert.Nilf(t, err, "AuthenticateNewUser should not return an error: %v", err)
+
+	_, err = authenticator.Refresh(ctx, other.RefreshToken)
+	assert.Nilf(t, err, "other token families should not be affected by the revocation: %v", err)
+}
+
+func TestAuthenticator_Revoke(t *testing.T) {
+	ctx := context.Background()
+	authenticator := newTestAuthenticator(t)
+	user := &users.User{ID: uuid.New(), Username: "test", Email: "test"}
+
+	tokens, err := authenticator.AuthenticateNewUser(ctx, user)
+	assert.Nilf(t, err, "AuthenticateNewUser should not return an error: %v", err)
+
+	_, err = authenticator.IsAuthenticated(ctx, tokens.AuthToken)
+	assert.Nilf(t, err, "auth token should be valid before it is revoked: %v", err)
+
+	err = authenticator.Revoke(ctx, tokens.AuthToken)
+	assert.Nilf(t, err, "Revoke should not return an error: %v", err)
+
+	_, err = authenticator.IsAuthenticated(ctx, tokens.AuthToken)
+	assert.ErrorIsf(t, err, ErrTokenRevoked, "a revoked auth token should not be accepted")
+
+	err = authenticator.Revoke(ctx, tokens.RefreshToken)
+	assert.Nilf(t, err, "Revoke should not return an error: %v", err)
+
+	_, err = authenticator.Refresh(ctx, tokens.RefreshToken)
+	assert.ErrorIsf(t, err, ErrTokenRevoked, "a revoked refresh token should not be accepted")
+
+	err = authenticator.Revoke(ctx, "not a token")
+	assert.ErrorIsf(t, err, ErrInvalidToken, "revoking an invalid token should fail")
+}
+
+func TestAuthenticator_RevokeAllForUser(t *testing.T) {
+	ctx := context.Background()
+	authenticator := newTestAuthenticator(t)
+	user := &users.User{ID: uuid.New(), Username: "test", Email: "test"}
+	otherUser := &users.User{ID: uuid.New(), Username: "other", Email: "other"}
+
+	tokens, err := authenticator.AuthenticateNewUser(ctx, user)
+	assert.Nilf(t, err, "AuthenticateNewUser should not return an error: %v", err)
+
+	otherTokens, err := authenticator.AuthenticateNewUser(ctx, otherUser)
+	assert.Nilf(t, err, "AuthenticateNewUser should not return an error: %v", err)
+
+	// issue times have a precision of one second
+	time.Sleep(time.Second)
+
+	err = authenticator.RevokeAllForUser(ctx, user.ID)
+	assert.Nilf(t, err, "RevokeAllForUser should not return an error: %v", err)
+
+	_, err = authenticator.IsAuthenticated(ctx, tokens.AuthToken)
+	assert.ErrorIsf(t, err, ErrTokenRevoked, "auth tokens issued before the revocation should not be accepted")
+
+	_, err = authenticator.Refresh(ctx, tokens.RefreshToken)
+	assert.ErrorIsf(t, err, ErrTokenRevoked, "refresh tokens issued before the revocation should not be accepted")
+
+	_, err = authenticator.IsAuthenticated(ctx, otherTokens.AuthToken)
+	assert.Nilf(t, err, "other users should not be affected by the revocation: %v", err)
+
+	time.Sleep(time.Second)
+
+	newTokens, err := authenticator.AuthenticateNewUser(ctx, user)
+	assert.Nilf(t, err, "AuthenticateNewUser should not return an error: %v", err)
+
+	_, err = authenticator.IsAuthenticated(ctx, newTokens.AuthToken)
+	assert.Nilf(t, err, "tokens issued after the revocation should be accepted: %v", err)
+}
+
+func TestAuthenticator_RevokeAllForUser_SameSecond(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	user := &users.User{ID: uuid.New(), Username: "test", Email: "test"}
+
+	// the writer reports the sessions it deletes like the database would
+	var created []sessions.SessionId
+	sessionWriter := sessionmocks.NewMockWriter(ctrl)
+	sessionWriter.EXPECT().CreateSession(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ any, session sessions.Session) (*sessions.Session, error) {
+			created = append(created, session.ID)
+			return &session, nil
+		})
+	sessionWriter.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+	sessionWriter.EXPECT().DeleteSession(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(true, nil)
+	sessionWriter.EXPECT().DeleteOtherSessions(gomock.Any(), user.ID, uuid.Nil).AnyTimes().DoAndReturn(
+		func(_ any, _ any, _ any) ([]sessions.SessionId, error) {
+			deleted := created
+			created = nil
+			return deleted, nil
+		})
+	authenticator := newTestAuthenticatorWithSessions(t, sessionmocks.ReturnSessions(ctrl), sessionWriter)
+
+	// align to the start of a second so the tokens and the revocation share it
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+
+	tokens, err := authenticator.AuthenticateNewUser(ctx, user)
+	assert.Nilf(t, err, "AuthenticateNewUser should not return an error: %v", err)
+	accountTokens, err := authenticator.AuthenticateServiceAccount(ctx, user.ID)
+	assert.Nilf(t, err, "AuthenticateServiceAccount should not return an error: %v", err)
+
+	err = authenticator.RevokeAllForUser(ctx, user.ID)
+	assert.Nilf(t, err, "RevokeAllForUser should not return an error: %v", err)
+
+	_, err = authenticator.IsAuthenticated(ctx, tokens.AuthToken)
+	assert.ErrorIsf(t, err, ErrTokenRevoked, "auth tokens issued within the second of the revocation should not be accepted")
+
+	_, err = authenticator.Refresh(ctx, tokens.RefreshToken)
+	assert.NotNilf(t, err, "refresh tokens issued within the second of the revocation should not be accepted")
+
+	_, err = authenticator.IsAuthenticated(ctx, accountTokens.AuthToken)
+	assert.ErrorIsf(t, err, ErrTokenRevoked, "tokens without a session issued within the second of the revocation should not be accepted")
+
+	newTokens, err := authenticator.AuthenticateNewUser(ctx, user)
+	assert.Nilf(t, err, "AuthenticateNewUser should not return an error: %v", err)
+
+	_, err = authenticator.IsAuthenticated(ctx, newTokens.AuthToken)
+	assert.Nilf(t, err, "new sessions started within the second of the revocation should be accepted: %v", err)
+}
+
+func TestAuthenticator_AuthenticateServiceAccount(t *testing.T) {
+	ctx := context.Background()
+	authenticator := newTestAuthenticator(t)
+	accountID := uuid.New()
+
+	tokens, err := authenticator.AuthenticateServiceAccount(ctx, accountID)
+	assert.Nilf(t, err, "AuthenticateServiceAccount should not return an error: %v", err)
+	assert.Emptyf(t, tokens.RefreshToken, "service accounts should not get a refresh token")
+	assert.InDeltaf(t, 300, tokens.ExpiresIn, 2, "the lifetime of the auth token should be returned")
+
+	claims, err := authenticator.IsAuthenticated(ctx, tokens.AuthToken)
+	assert.Nilf(t, err, "the auth token should be valid: %v", err)
+	assert.Equalf(t, accountID, claims.UserID, "the auth token should belong to the service account")
+	assert.Truef(t, claims.ServiceAccount, "the auth token should be marked as a service account")
+	assert.Equalf(t, uuid.Nil, claims.SessionID, "service account tokens should have no session")
+
+	user := &users.User{ID: uuid.New(), Username: "test", Email: "test"}
+	userTokens, err := authenticator.AuthenticateNewUser(ctx, user)
+	assert.Nilf(t, err, "AuthenticateNewUser should not return an error: %v", err)
+
+	userClaims, err := authenticator.IsAuthenticated(ctx, userTokens.AuthToken)
+	assert.Nilf(t, err, "the auth token should be valid: %v", err)
+	assert.Falsef(t, userClaims.ServiceAccount, "user tokens should not be marked as a service account")
+}
+
+func TestAuthenticator_Sessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := WithClientInfo(context.Background(), ClientInfo{UserAgent: "test-agent", IPAddress: "127.0.0.1"})
+	user := &users.User{ID: uuid.New(), Username: "test", Email: "test"}
+
+	sessionWriter := sessionmocks.NewMockWriter(ctrl)
+	sessionWriter.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(2).DoAndReturn(
+		func(_ context.Context, session sessions.Session) (*sessions.Session, error) {
+			assert.Equalf(t, user.ID, session.UserID, "session should belong to the user")
+			assert.Equalf(t, "test-agent", session.UserAgent, "session should record the user agent")
+			assert.Equalf(t, "127.0.0.1", session.IpAddress, "session should record the ip address")
+			assert.NotEmptyf(t, session.Token, "session should record the refresh token id")
+			return &session, nil
+		})
+	authenticator := newTestAuthenticatorWithSessions(t, sessionmocks.NewMockReader(ctrl), sessionWriter)
+
+	current, err := authenticator.AuthenticateNewUser(ctx, user)
+	assert.Nilf(t, err, "AuthenticateNewUser should not return an error: %v", err)
+
+	other, err := authenticator.AuthenticateNewUser(ctx, user)
+	assert.Nilf(t, err, "AuthenticateNewUser should not return an error: %v", err)
+
+	currentClaims, err := authenticator.IsAuthenticated(ctx, current.AuthToken)
+	assert.Nilf(t, err, "auth token should be valid: %v", err)
+
+	otherClaims, err := authenticator.IsAuthenticated(ctx, other.AuthToken)
+	assert.Nilf(t, err, "auth token should be valid: %v", err)
+	assert.NotEqualf(t, currentClaims.SessionID, otherClaims.SessionID, "each login should start a new session")
+
+	sessionWriter.EXPECT().TouchSession(gomock.Any(), currentClaims.SessionID, gomock.Any(), gomock.Any()).Return(nil)
+	refreshed, err := authenticator.Refresh(ctx, current.RefreshToken)
+	assert.Nilf(t, err, "Refresh should not return an error: %v", err)
+
+	refreshedClaims, err := authenticator.IsAuthenticated(ctx, refreshed.AuthToken)
+	assert.Nilf(t, err, "refreshed auth token should be valid: %v", err)
+	assert.Equalf(t, currentClaims.SessionID, refreshedClaims.SessionID, "refreshing should keep the session")
+
+	sessionWriter.EXPECT().DeleteOtherSessions(gomock.Any(), user.ID, currentClaims.SessionID).Return([]sessions.SessionId{otherClaims.SessionID}, nil)
+	err = authenticator.TerminateOtherSessions(ctx, user.ID, currentClaims.SessionID)
+	assert.Nilf(t, err, "TerminateOtherSessions should not return an error: %v", err)
+
+	_, err = authenticator.IsAuthenticated(ctx, other.AuthToken)
+	assert.ErrorIsf(t, err, ErrTokenRevoked, "auth tokens of terminated sessions should not be accepted")
+
+	_, err = authenticator.Refresh(ctx, other.RefreshToken)
+	assert.ErrorIsf(t, err, ErrTokenRevoked, "refresh tokens of terminated sessions should not be accepted")
+
+	_, err = authenticator.IsAuthenticated(ctx, refreshed.AuthToken)
+	assert.Nilf(t, err, "the current session should not be terminated: %v", err)
+
+	sessionWriter.EXPECT().DeleteSession(gomock.Any(), user.ID, otherClaims.SessionID).Return(false, nil)
+	err = authenticator.TerminateSession(ctx, user.ID, otherClaims.SessionID)
+	assert.ErrorIsf(t, err, ErrSessionNotFound, "terminating an unknown session should fail")
+
+	sessionWriter.EXPECT().DeleteSession(gomock.Any(), user.ID, currentClaims.SessionID).Return(true, nil)
+	err = authenticator.TerminateSession(ctx, user.ID, currentClaims.SessionID)
+	assert.Nilf(t, err, "TerminateSession should not return an error: %v", err)
+
+	_, err = authenticator.IsAuthenticated(ctx, refreshed.AuthToken)
+	assert.ErrorIsf(t, err, ErrTokenRevoked, "auth tokens of terminated sessions should not be accepted")
+}
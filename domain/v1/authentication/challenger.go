@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/records/v1/challengeattempts"
 	"github.com/ooqls/go-auth/records/v1/users"
 	"github.com/ooqls/go-cache/cache"
 	"github.com/ooqls/go-cache/store"
@@ -25,6 +26,8 @@ type Challenge struct {
 	User      users.User `json:"user"`
 	Challenge []byte     `json:"challenge"`
 	CreatedAt time.Time  `json:"created_at"`
+	// ServerSecret is the server's private ephemeral value for protocols that need one, it is never sent to the client
+	ServerSecret []byte `json:"-"`
 }
 
 func NewChallenge(user *users.User) Challenge {
@@ -39,21 +42,36 @@ func NewChallenge(user *users.User) Challenge {
 type AuthedResult struct {
 	ChallengeID uuid.UUID   `json:"challenge_id"`
 	User        *users.User `json:"user"`
+	// ServerProof proves to the client that the server knows the user's verifier, only set for SRP6A users
+	ServerProof []byte `json:"server_proof,omitempty"`
+	// Methods are the amr values of the challenge, see AuthMethodPassword
+	Methods []string `json:"methods,omitempty"`
 }
 
 //go:generate go run github.com/golang/mock/mockgen -source=challenger.go -destination=mocks/mock_challenger.go -package=mocks -mock_names=Challenger=MockChallenger
 type Challenger interface {
 	IssueChallenge(ctx context.Context, user *users.User) (*Challenge, error)
 	VerifyChallenge(ctx context.Context, challengeId uuid.UUID, solvedChallenge []byte) (*AuthedResult, error)
-	VerifyRegistration(ctx context.Context, username string, secret []byte, key []byte) ([16]byte, error)
+	VerifyRegistration(ctx context.Context, reg Registration) ([16]byte, error)
 }
 
 type ChallengerV1 struct {
+	attemptTracker
 	store store.GenericInterface
 }
 
-func NewChallengerV1(store store.GenericInterface) Challenger {
+func NewChallengerV1(
+	store store.GenericInterface,
+	attemptReader challengeattempts.Reader,
+	attemptWriter challengeattempts.Writer,
+	lockoutPolicy LockoutPolicy) Challenger {
+
 	return &ChallengerV1{
+		attemptTracker: attemptTracker{
+			attemptReader: attemptReader,
+			attemptWriter: attemptWriter,
+			lockoutPolicy: lockoutPolicy,
+		},
 		store: store,
 	}
 }
@@ -72,7 +90,15 @@ func (c *ChallengerV1) IssueChallenge(ctx context.Context, user *users.User) (*C
 	}
 
 	chal := NewChallenge(user)
-	l.Sugar().Infow("storing challenge", "chal", chal)
+	if user.Algorithm == string(SupportedAlgorithmSRP6A) {
+		// the challenge is the server's public ephemeral value B
+		chal.ServerSecret, chal.Challenge, err = srpServerEphemeral(user.Key)
+		if err != nil {
+			l.Error("failed to generate srp ephemeral", zap.String("user_id", user.ID.String()), zap.Error(err))
+			return nil, ErrInternal
+		}
+	}
+	l.Info("storing challenge", zap.String("user_id", user.ID.String()), zap.String("challenge_id", chal.ID.String()))
 
 	err = c.store.Set(ctx, chal.ID.String(), chal)
 	if err != nil {
@@ -95,30 +121,113 @@ func (c *ChallengerV1) VerifyChallenge(ctx context.Context, challengeId uuid.UUI
 		return nil, ErrInternal
 	}
 
-	decryptedChallenge, err := crypto.AESGCMDecryptWithKey(challenge.User.Key, solvedChallenge)
+	err = c.checkLockout(ctx, challenge.User.ID)
 	if err != nil {
-		l.Warn("failed to decrypt given challenge with user key", zap.Error(err))
-		return nil, ErrChallengeFailed
-	}
-
-	authed := bytes.Equal(decryptedChallenge, challenge.Challenge)
-
-	if !authed {
-		return nil, ErrChallengeFailed
+		return nil, err
 	}
 
+	// a challenge can only be answered once, whether or not the answer is right
 	err = c.store.Delete(ctx, challengeId.String())
 	if err != nil {
 		l.Error("failed to delete challenge from store", zap.String("challenge_id", challengeId.String()), zap.Error(err))
 	}
 
+	serverProof, err := verifyChallengeResponse(challenge, solvedChallenge)
+	if err != nil {
+		l.Warn("failed to verify challenge response", zap.String("user_id", challenge.User.ID.String()), zap.Error(err))
+		c.recordAttempt(ctx, challenge, false)
+		return nil, ErrChallengeFailed
+	}
+
+	c.recordAttempt(ctx, challenge, true)
+
 	return &AuthedResult{
 		ChallengeID: challenge.ID,
 		User:        &challenge.User,
+		ServerProof: serverProof,
+		Methods:     authMethods(challenge.User.Algorithm),
 	}, nil
 }
 
-func (c *ChallengerV1) VerifyRegistration(ctx context.Context, username string, secret []byte, key []byte) ([crypto.SALT_SIZE]byte, error) {
+// verifyChallengeResponse checks the user's answer to the challenge using the user's algorithm.
+// AESGCM users encrypt the challenge with their key, asymmetric users sign it and SRP6A users
+// prove they derived the same session key, in which case the server's proof is returned
+func verifyChallengeResponse(challenge Challenge, response []byte) ([]byte, error) {
+	alg, err := ParseAlgorithm(challenge.User.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	if alg == SupportedAlgorithmSRP6A {
+		return verifySRPAnswer(challenge.User.Username, challenge.User.Salt, challenge.User.Key, challenge.ServerSecret, challenge.Challenge, response)
+	}
+
+	if alg.IsAsymmetric() {
+		return nil, Verify(alg, challenge.User.Key, challenge.Challenge, response)
+	}
+
+	decryptedChallenge, err := crypto.AESGCMDecryptWithKey(challenge.User.Key, response)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(decryptedChallenge, challenge.Challenge) {
+		return nil, ErrChallengeFailed
+	}
+
+	return nil, nil
+}
+
+// VerifyRegistration checks that the user holds the key they are registering with.
+// AESGCM registrations encrypt the username with the derived key and get back the salt the key was derived with.
+// Asymmetric registrations upload a public key and sign the username, they have no salt
+func (c *ChallengerV1) VerifyRegistration(ctx context.Context, reg Registration) ([crypto.SALT_SIZE]byte, error) {
+	alg, err := ParseAlgorithm(string(reg.Algorithm))
+	if err != nil {
+		return [crypto.SALT_SIZE]byte{}, ErrInvalidRegistration
+	}
+
+	if alg == SupportedAlgorithmSRP6A {
+		return c.verifySRPRegistration(reg)
+	}
+
+	if alg.IsAsymmetric() {
+		return c.verifySignedRegistration(alg, reg)
+	}
+
+	return c.verifyAESGCMRegistration(reg.Username, reg.Secret, reg.Key)
+}
+
+// verifySRPRegistration checks the verifier, the secret is the random salt the client derived the verifier with.
+// Nothing proves the client knows the password behind the verifier, but only that client can log in with it
+func (c *ChallengerV1) verifySRPRegistration(reg Registration) ([crypto.SALT_SIZE]byte, error) {
+	l := l.With(zap.String("username", reg.Username))
+	err := validateSRPVerifier(reg.Key)
+	if err != nil {
+		l.Warn("invalid srp verifier", zap.Error(err))
+		return [crypto.SALT_SIZE]byte{}, ErrInvalidRegistration
+	}
+
+	if len(reg.Secret) != crypto.SALT_SIZE {
+		l.Warn("invalid srp salt size", zap.Int("size", len(reg.Secret)))
+		return [crypto.SALT_SIZE]byte{}, ErrInvalidRegistration
+	}
+
+	return [crypto.SALT_SIZE]byte(reg.Secret), nil
+}
+
+func (c *ChallengerV1) verifySignedRegistration(alg SupportedAlgorithm, reg Registration) ([crypto.SALT_SIZE]byte, error) {
+	l := l.With(zap.String("username", reg.Username), zap.String("algorithm", string(alg)))
+	err := Verify(alg, reg.Key, []byte(reg.Username), reg.Secret)
+	if err != nil {
+		l.Warn("failed to verify registration signature", zap.Error(err))
+		return [crypto.SALT_SIZE]byte{}, ErrInvalidRegistration
+	}
+
+	return [crypto.SALT_SIZE]byte{}, nil
+}
+
+func (c *ChallengerV1) verifyAESGCMRegistration(username string, secret []byte, key []byte) ([crypto.SALT_SIZE]byte, error) {
 	l := l.With(zap.String("username", username))
 	err := crypto.VerifyGCMAESKey(key)
 	if err != nil {
@@ -137,13 +246,7 @@ func (c *ChallengerV1) VerifyRegistration(ctx context.Context, username string,
 		return [crypto.SALT_SIZE]byte{}, ErrInvalidRegistration
 	}
 	
-    usernameBytes := make([]byte, 8)
-	copy(usernameBytes, []byte(username))
-	seed := binary.LittleEndian.Uint64(usernameBytes)
-	rng := crypto.NewPCG32(seed, 0)
-
-	salt := make([]byte, crypto.SALT_SIZE)
-	rng.Read(salt)
+	salt := RegistrationSalt(username)
 
 	givenSalt, _, _, err := crypto.DecodeAESGCM(secret)
 	if err != nil {
@@ -151,10 +254,22 @@ func (c *ChallengerV1) VerifyRegistration(ctx context.Context, username string,
 		return [crypto.SALT_SIZE]byte{}, ErrInvalidRegistration
 	}
 
-	if !bytes.Equal(salt, givenSalt[:]) {
-		l.Warn("given salt does not match expected calculated salt", zap.ByteString("expected", salt), zap.ByteString("given", givenSalt[:]))
+	if !bytes.Equal(salt[:], givenSalt[:]) {
+		l.Warn("given salt does not match expected calculated salt", zap.ByteString("expected", salt[:]), zap.ByteString("given", givenSalt[:]))
 		return [crypto.SALT_SIZE]byte{}, ErrInvalidRegistration
 	}
 
-	return [crypto.SALT_SIZE]byte(salt), nil
+	return salt, nil
+}
+
+// RegistrationSalt returns the salt AESGCM keys are derived with, it is derived from the username
+func RegistrationSalt(username string) [crypto.SALT_SIZE]byte {
+	usernameBytes := make([]byte, 8)
+	copy(usernameBytes, []byte(username))
+	seed := binary.LittleEndian.Uint64(usernameBytes)
+	rng := crypto.NewPCG32(seed, 0)
+
+	var salt [crypto.SALT_SIZE]byte
+	rng.Read(salt[:])
+	return salt
 }
@@ -6,7 +6,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	attemptmocks "github.com/ooqls/go-auth/records/v1/challengeattempts/mocks"
 	"github.com/ooqls/go-auth/records/v1/users"
 	"github.com/ooqls/go-cache/store"
 	"github.com/ooqls/go-db/redis"
@@ -26,7 +28,10 @@ func generateUserKey(t *testing.T) (crypto.Algorithm, []byte, [16]byte) {
 }
 
 func TestChallenger_IssueChallenge(t *testing.T) {
-	challenger := NewChallengerV1(store.NewMemStore("test", time.Second * 10))
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	challenger := NewChallengerV1(store.NewMemStore("test", time.Second*10), attemptmocks.NoFailedAttempts(ctrl), attemptmocks.NoopWriter(ctrl), DefaultLockoutPolicy())
 	
 	// should not get a result because the challenge does not exist
 	res, err := challenger.VerifyChallenge(context.Background(), uuid.New(), []byte("fnjnjekw"))
@@ -36,6 +41,8 @@ func TestChallenger_IssueChallenge(t *testing.T) {
 
 func TestChallenger_VerifyChallenge(t *testing.T) {
     testutils.StartRedis(context.Background())
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
 	type TestCase struct {
 		description    string
@@ -99,7 +106,7 @@ func TestChallenger_VerifyChallenge(t *testing.T) {
 
 	for _, tc := range cases {
 
-		challenger := NewChallengerV1(memStore)
+		challenger := NewChallengerV1(memStore, attemptmocks.NoFailedAttempts(ctrl), attemptmocks.NoopWriter(ctrl), DefaultLockoutPolicy())
 
 		challenge, err := challenger.IssueChallenge(context.Background(), tc.user)
 		assert.Nilf(t, err, "%s: should not get an error when getting key", tc.description)
@@ -121,3 +128,52 @@ func TestChallenger_VerifyChallenge(t *testing.T) {
 		}
 	}
 }
+
+func TestChallenger_AsymmetricKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	challenger := NewChallengerV1(store.NewMemStore("test", time.Second*10), attemptmocks.NoFailedAttempts(ctrl), attemptmocks.NoopWriter(ctrl), DefaultLockoutPolicy())
+
+	for _, alg := range []SupportedAlgorithm{SupportedAlgorithmEd25519, SupportedAlgorithmECDSAP256} {
+		privateKey, err := GenerateKey(alg)
+		assert.Nilf(t, err, "%s: should not fail to generate a key: %v", alg, err)
+
+		otherKey, err := GenerateKey(alg)
+		assert.Nilf(t, err, "%s: should not fail to generate a key: %v", alg, err)
+
+		publicKey, err := PublicKey(alg, []byte(privateKey))
+		assert.Nilf(t, err, "%s: should not fail to get the public key: %v", alg, err)
+
+		signature, err := Sign(alg, []byte(privateKey), []byte("testuser"))
+		assert.Nilf(t, err, "%s: should not fail to sign the username: %v", alg, err)
+
+		_, err = challenger.VerifyRegistration(ctx, Registration{Username: "testuser", Key: publicKey, Secret: signature, Algorithm: alg})
+		assert.Nilf(t, err, "%s: should verify the registration: %v", alg, err)
+
+		_, err = challenger.VerifyRegistration(ctx, Registration{Username: "otheruser", Key: publicKey, Secret: signature, Algorithm: alg})
+		assert.ErrorIsf(t, err, ErrInvalidRegistration, "%s: should not verify a signature of another username", alg)
+
+		user := &users.User{ID: uuid.New(), Username: "testuser", Key: publicKey, Algorithm: string(alg)}
+
+		challenge, err := challenger.IssueChallenge(ctx, user)
+		assert.Nilf(t, err, "%s: should not fail to issue a challenge: %v", alg, err)
+
+		wrongSignature, err := Sign(alg, []byte(otherKey), challenge.Challenge)
+		assert.Nilf(t, err, "%s: should not fail to sign the challenge: %v", alg, err)
+
+		_, err = challenger.VerifyChallenge(ctx, challenge.ID, wrongSignature)
+		assert.ErrorIsf(t, err, ErrChallengeFailed, "%s: should not verify a challenge signed with another key", alg)
+
+		challenge, err = challenger.IssueChallenge(ctx, user)
+		assert.Nilf(t, err, "%s: should not fail to issue a challenge: %v", alg, err)
+
+		solved, err := Sign(alg, []byte(privateKey), challenge.Challenge)
+		assert.Nilf(t, err, "%s: should not fail to sign the challenge: %v", alg, err)
+
+		result, err := challenger.VerifyChallenge(ctx, challenge.ID, solved)
+		assert.Nilf(t, err, "%s: should verify the challenge: %v", alg, err)
+		assert.Equalf(t, user.ID, result.User.ID, "%s: result should belong to the user", alg)
+	}
+}
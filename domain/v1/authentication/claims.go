@@ -1,9 +1,94 @@
 package authentication
 
-import "github.com/ooqls/go-auth/records/v1/users"
+import (
+	"context"
+	"time"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/domain/v1/authorization"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/users"
+	"github.com/ooqls/go-crypto/jwt"
+)
 
 type UserClaims struct {
-	UserID users.UserId `json:"user_id"`
+	UserID    users.UserId `json:"user_id"`
+	SessionID uuid.UUID    `json:"sid"`
+	FamilyID  uuid.UUID    `json:"family_id"`
+	// ServiceAccount is set when UserID is a service account rather than a user, see serviceaccounts
+	ServiceAccount bool `json:"service_account,omitempty"`
+	// AccessTokenID is set when the user authenticated with a personal access token, it is never part of a signed token
+	AccessTokenID uuid.UUID `json:"-"`
+	// IssuedAt and ExpiresAt are set by IsAuthenticated from the token, they are never part of its custom claims
+	IssuedAt  time.Time `json:"-"`
+	ExpiresAt time.Time `json:"-"`
+	// Roles, Domain, AMR and AuthTime are only in auth tokens when the authenticator's ClaimsMapper puts them in.
+	// Roles is nil when they were left out and empty when the user holds none
+	Roles    []RoleClaim `json:"roles,omitzero"`
+	Domain   string      `json:"domain,omitempty"`
+	AMR      []string    `json:"amr,omitempty"`
+	AuthTime int64       `json:"auth_time,omitempty"`
+}
+
+// RoleClaim is a role embedded in an auth token, its permissions are not embedded
+type RoleClaim struct {
+	ID        records.RoleId `json:"id"`
+	Hierarchy int32          `json:"hierarchy"`
 }
 
+// AuthorizationContext returns the context the claims are authorized with from the roles embedded in the token,
+// so services can authorize without loading the user's roles. The roles carry no permissions. ok is false if
+// the token was issued without roles or is a personal access token, whose context must be loaded, see accesstokens
+func (c *UserClaims) AuthorizationContext(ctx context.Context) (authorization.Context, bool) {
+	if c.Roles == nil || c.AccessTokenID != uuid.Nil {
+		return authorization.Context{}, false
+	}
+
+	user := records.UserAgg{
+		UserId: c.UserID,
+		Roles:  make([]records.RoleAgg, 0, len(c.Roles)),
+		// roles are only embedded for users who verified their email
+		EmailVerified: true,
+	}
+	for _, role := range c.Roles {
+		user.Roles = append(user.Roles, records.RoleAgg{RoleId: role.ID, RoleHierarchy: role.Hierarchy})
+	}
+
+	var authCtx authorization.Context
+	if c.ServiceAccount {
+		authCtx = authorization.NewServiceAccountContext(ctx, user)
+	} else {
+		authCtx = authorization.NewAuthorizationContext(user)
+		authCtx.Context = ctx
+	}
+
+	authCtx.Domain = c.Domain
+	return authCtx, true
+}
 
+// registeredClaims returns the standard claims (jti, exp, iat...) of a token issued for UserClaims
+func registeredClaims(token *jwtv5.Token) (*jwtv5.RegisteredClaims, bool) {
+	if token == nil {
+		return nil, false
+	}
+
+	switch c := token.Claims.(type) {
+	case *jwt.ClaimsWrapper[UserClaims]:
+		return &c.RegisteredClaims, true
+	case jwt.ClaimsWrapper[UserClaims]:
+		return &c.RegisteredClaims, true
+	}
+
+	return nil, false
+}
+
+// tokenKeyID returns the kid header of a token, it is empty for tokens signed before the key ring
+func tokenKeyID(token *jwtv5.Token) string {
+	if token == nil {
+		return ""
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	return kid
+}
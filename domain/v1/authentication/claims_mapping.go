@@ -0,0 +1,171 @@
+package authentication
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/roles"
+	"github.com/ooqls/go-auth/records/v1/serviceaccounts"
+	"github.com/ooqls/go-auth/records/v1/users"
+	"go.uber.org/zap"
+)
+
+// The RFC 8176 authentication method references of the amr claim
+const (
+	// AuthMethodPassword is a password or a key derived from one, including passwords checked by a directory
+	AuthMethodPassword = "pwd"
+	// AuthMethodSoftwareKey is a challenge signed with the user's private key
+	AuthMethodSoftwareKey = "swk"
+	// AuthMethodHardwareKey is a passkey
+	AuthMethodHardwareKey = "hwk"
+	// AuthMethodOTP is a TOTP code or recovery code
+	AuthMethodOTP = "otp"
+	// AuthMethodMFA is added when the user provided a second factor
+	AuthMethodMFA = "mfa"
+)
+
+// authMethods returns the method of a challenge login with the algorithm
+func authMethods(algorithm string) []string {
+	switch SupportedAlgorithm(algorithm) {
+	case SupportedAlgorithmEd25519, SupportedAlgorithmECDSAP256:
+		return []string{AuthMethodSoftwareKey}
+	}
+
+	return []string{AuthMethodPassword}
+}
+
+// Login is how the session of a token was started, it is kept in the session's refresh tokens so
+// auth tokens issued on refresh keep it. Service accounts have no session, Time is when they authenticated
+type Login struct {
+	Methods []string
+	Time    time.Time
+}
+
+// ClaimsMapper adds claims to auth tokens, it is called before every auth token is signed including
+// tokens issued on refresh. Returning an error fails the login or refresh
+type ClaimsMapper interface {
+	MapClaims(ctx context.Context, claims *UserClaims, login Login) error
+}
+
+// ClaimsMapping decides which claims a ClaimsMapperV1 puts in auth tokens. Embedded roles are only as
+// fresh as the token, role changes reach services when the token is refreshed. The session id is
+// always in the auth tokens of users since revoking a session relies on it
+type ClaimsMapping struct {
+	// Roles embeds the ids and hierarchy of the roles the user or service account holds
+	Roles bool
+	// Domain is embedded as the domain of the authorization context when it is not empty
+	Domain string
+	// AMR embeds the methods the user logged in with
+	AMR bool
+	// AuthTime embeds when the user logged in
+	AuthTime bool
+}
+
+// ParseClaimsMapping returns the mapping of a comma separated list of the claims roles, amr and auth_time.
+// domain is embedded as is
+func ParseClaimsMapping(claims string, domain string) (ClaimsMapping, error) {
+	mapping := ClaimsMapping{Domain: domain}
+	for _, claim := range strings.Split(claims, ",") {
+		switch strings.TrimSpace(claim) {
+		case "":
+		case "roles":
+			mapping.Roles = true
+		case "amr":
+			mapping.AMR = true
+		case "auth_time":
+			mapping.AuthTime = true
+		default:
+			return ClaimsMapping{}, fmt.Errorf("unsupported claim: %s", claim)
+		}
+	}
+
+	return mapping, nil
+}
+
+// Enabled returns true if the mapping puts any claim in auth tokens
+func (m ClaimsMapping) Enabled() bool {
+	return m.Roles || m.Domain != "" || m.AMR || m.AuthTime
+}
+
+var _ ClaimsMapper = &ClaimsMapperV1{}
+
+type ClaimsMapperV1 struct {
+	mapping       ClaimsMapping
+	userReader    users.Reader
+	roleReader    roles.AggRoleReader
+	accountReader serviceaccounts.Reader
+}
+
+// NewClaimsMapperV1 returns a ClaimsMapper embedding the claims of the mapping. The roles of users come from roleReader
+// and those of service accounts from accountReader, service accounts get no roles if accountReader is nil
+func NewClaimsMapperV1(mapping ClaimsMapping, userReader users.Reader, roleReader roles.AggRoleReader, accountReader serviceaccounts.Reader) *ClaimsMapperV1 {
+	return &ClaimsMapperV1{
+		mapping:       mapping,
+		userReader:    userReader,
+		roleReader:    roleReader,
+		accountReader: accountReader,
+	}
+}
+
+func (m *ClaimsMapperV1) MapClaims(ctx context.Context, claims *UserClaims, login Login) error {
+	if m.mapping.Roles {
+		roleAggs, err := m.roleAggs(ctx, claims)
+		if err != nil {
+			// the refresh token outlived its user
+			if errors.Is(err, ErrUserNotFound) {
+				return err
+			}
+
+			l.Error("failed to get roles to embed", zap.String("user_id", claims.UserID.String()), zap.Error(err))
+			return ErrInternal
+		}
+
+		claims.Roles = make([]RoleClaim, 0, len(roleAggs))
+		for _, role := range roleAggs {
+			claims.Roles = append(claims.Roles, RoleClaim{ID: role.RoleId, Hierarchy: role.RoleHierarchy})
+		}
+	}
+
+	claims.Domain = m.mapping.Domain
+
+	if m.mapping.AMR {
+		claims.AMR = login.Methods
+	}
+
+	if m.mapping.AuthTime && !login.Time.IsZero() {
+		claims.AuthTime = login.Time.Unix()
+	}
+
+	return nil
+}
+
+// roleAggs returns the roles to embed, users who have not verified their email hold none like in authorization.NewAuthorizationContext
+func (m *ClaimsMapperV1) roleAggs(ctx context.Context, claims *UserClaims) ([]records.RoleAgg, error) {
+	if claims.ServiceAccount {
+		if m.accountReader == nil {
+			return nil, nil
+		}
+
+		return m.accountReader.GetRoleAggs(ctx, claims.UserID)
+	}
+
+	user, err := m.userReader.GetUser(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+
+		return nil, err
+	}
+
+	if !user.EmailVerified {
+		return nil, nil
+	}
+
+	return m.roleReader.GetRoleAggForUser(ctx, claims.UserID)
+}
@@ -0,0 +1,158 @@
+package authentication
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/records"
+	attemptmocks "github.com/ooqls/go-auth/records/v1/challengeattempts/mocks"
+	mfamocks "github.com/ooqls/go-auth/records/v1/mfa/mocks"
+	rolemocks "github.com/ooqls/go-auth/records/v1/roles/mocks"
+	serviceaccountmocks "github.com/ooqls/go-auth/records/v1/serviceaccounts/mocks"
+	sessionmocks "github.com/ooqls/go-auth/records/v1/sessions/mocks"
+	"github.com/ooqls/go-auth/records/v1/users"
+	"github.com/ooqls/go-cache/factory"
+	"github.com/ooqls/go-cache/store"
+	"github.com/ooqls/go-crypto/crypto"
+	"github.com/ooqls/go-crypto/jwt"
+	"github.com/ooqls/go-crypto/keys"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseClaimsMapping(t *testing.T) {
+	mapping, err := ParseClaimsMapping("roles, amr,auth_time", "tenant")
+	assert.Nilf(t, err, "should parse the claims: %v", err)
+	assert.Equalf(t, ClaimsMapping{Roles: true, Domain: "tenant", AMR: true, AuthTime: true}, mapping, "should map every listed claim")
+
+	mapping, err = ParseClaimsMapping("", "")
+	assert.Nilf(t, err, "should parse no claims: %v", err)
+	assert.Falsef(t, mapping.Enabled(), "no claims should be mapped by default")
+
+	_, err = ParseClaimsMapping("roles,email", "")
+	assert.NotNilf(t, err, "should not map unsupported claims")
+}
+
+func TestAuthenticator_ClaimsMapping(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	salt := []byte(generateRandomSalt())
+	key, err := crypto.DeriveAESGCMKey("password", [16]byte(salt))
+	assert.Nilf(t, err, "should derive the key: %v", err)
+	keyAlgo := crypto.NewAESGCMAlgorithmWithKey(key, [16]byte(salt))
+
+	verified := &users.User{ID: uuid.New(), Username: "verified", Email: "verified@example.com", EmailVerified: true, Key: key, Salt: salt}
+	unverified := &users.User{ID: uuid.New(), Username: "unverified", Email: "unverified@example.com"}
+	accountID := uuid.New()
+	role := records.RoleAgg{RoleId: uuid.New(), RoleHierarchy: 10, Permissions: []records.Permission{{ID: uuid.New()}}}
+	accountRole := records.RoleAgg{RoleId: uuid.New(), RoleHierarchy: 1}
+
+	roleReader := rolemocks.NewMockAggRoleReader(ctrl)
+	roleReader.EXPECT().GetRoleAggForUser(gomock.Any(), verified.ID).AnyTimes().Return([]records.RoleAgg{role}, nil)
+	accountReader := serviceaccountmocks.NewMockReader(ctrl)
+	accountReader.EXPECT().GetRoleAggs(gomock.Any(), accountID).AnyTimes().Return([]records.RoleAgg{accountRole}, nil)
+	all := map[records.UserId]*users.User{verified.ID: verified, unverified.ID: unverified}
+	userReader := newTestUserReader(ctrl, all)
+
+	rKey, err := keys.NewRSA()
+	assert.Nilf(t, err, "failed to create new key: %v", err)
+	jwtKey := keys.NewJWTKey(*rKey)
+	issuer := jwt.NewJwtTokenIssuer[UserClaims](&jwt.TokenConfiguration{
+		Issuer:                  "test",
+		Audience:                []string{"test"},
+		ValidityDurationSeconds: 300,
+	}, jwtKey)
+	refreshIssuer := jwt.NewJwtTokenIssuer[UserClaims](&jwt.TokenConfiguration{
+		Issuer:                  "refresh",
+		Audience:                []string{"refresh"},
+		ValidityDurationSeconds: 300,
+	}, jwtKey)
+
+	mapper := NewClaimsMapperV1(ClaimsMapping{Roles: true, Domain: "tenant", AMR: true, AuthTime: true}, userReader, roleReader, accountReader)
+	authenticator := NewAuthenticatorV1(
+		issuer,
+		refreshIssuer,
+		&factory.MemCacheFactory{},
+		NewChallengerV1(store.NewMemStore("test", time.Second*10), attemptmocks.NoFailedAttempts(ctrl), attemptmocks.NoopWriter(ctrl), DefaultLockoutPolicy()),
+		sessionmocks.ReturnSessions(ctrl),
+		sessionmocks.AcceptSessions(ctrl),
+		mfamocks.NotEnrolled(ctrl),
+		mfamocks.NewMockWriter(ctrl),
+		UnverifiedLoginAllowed,
+		nil,
+		mapper,
+		[]string{"test"},
+	)
+
+	challenge, err := authenticator.ChallengeRequest(ctx, verified)
+	assert.Nilf(t, err, "ChallengeRequest should not return an error: %v", err)
+	solved, err := keyAlgo.Encrypt(challenge.Challenge)
+	assert.Nilf(t, err, "should solve the challenge: %v", err)
+
+	loggedInAt := time.Now().Unix()
+	tokens, err := authenticator.ChallengeResponse(ctx, challenge.ID, solved)
+	assert.Nilf(t, err, "ChallengeResponse should not return an error: %v", err)
+
+	// auth times have a precision of one second, refreshing must keep the time of the login
+	time.Sleep(time.Second)
+
+	refreshed, err := authenticator.Refresh(ctx, tokens.RefreshToken)
+	assert.Nilf(t, err, "Refresh should not return an error: %v", err)
+
+	for _, token := range []string{tokens.AuthToken, refreshed.AuthToken} {
+		_, claims, err := issuer.Decrypt(token)
+		assert.Nilf(t, err, "auth token should be valid: %v", err)
+		assert.Equalf(t, []RoleClaim{{ID: role.RoleId, Hierarchy: role.RoleHierarchy}}, claims.Roles, "should embed the user's roles")
+		assert.Equalf(t, "tenant", claims.Domain, "should embed the domain")
+		assert.Equalf(t, []string{AuthMethodPassword}, claims.AMR, "should embed how the user logged in")
+		assert.InDeltaf(t, loggedInAt, claims.AuthTime, 1, "should embed when the user logged in")
+		assert.NotEqualf(t, uuid.Nil, claims.SessionID, "should embed the session")
+
+		authCtx, ok := claims.AuthorizationContext(ctx)
+		assert.Truef(t, ok, "should reconstruct the authorization context from the token")
+		assert.Equalf(t, verified.ID, authCtx.GetUserID(), "context should be the user's")
+		assert.Equalf(t, []records.RoleAgg{{RoleId: role.RoleId, RoleHierarchy: role.RoleHierarchy}}, authCtx.GetRoles(), "context should hold the embedded roles")
+		assert.Equalf(t, "tenant", authCtx.Domain, "context should be in the embedded domain")
+	}
+
+	unverifiedTokens, err := authenticator.AuthenticateNewUser(ctx, unverified)
+	assert.Nilf(t, err, "AuthenticateNewUser should not return an error: %v", err)
+	_, claims, err := issuer.Decrypt(unverifiedTokens.AuthToken)
+	assert.Nilf(t, err, "auth token should be valid: %v", err)
+	assert.NotNilf(t, claims.Roles, "roles should be embedded for users who hold none")
+	assert.Emptyf(t, claims.Roles, "users who have not verified their email should hold no roles")
+	assert.Emptyf(t, claims.AMR, "no method should be embedded for logins without a challenge")
+
+	authCtx, ok := claims.AuthorizationContext(ctx)
+	assert.Truef(t, ok, "should reconstruct the context of a user without roles")
+	assert.Emptyf(t, authCtx.GetRoles(), "context should hold no roles")
+
+	// the second call reads the claims from the token cache
+	for i := 0; i < 2; i++ {
+		authenticated, err := authenticator.IsAuthenticated(ctx, unverifiedTokens.AuthToken)
+		assert.Nilf(t, err, "IsAuthenticated should not return an error: %v", err)
+		_, ok = authenticated.AuthorizationContext(ctx)
+		assert.Truef(t, ok, "embedded roles should be told apart from missing roles on call %d", i+1)
+	}
+
+	accountTokens, err := authenticator.AuthenticateServiceAccount(ctx, accountID)
+	assert.Nilf(t, err, "AuthenticateServiceAccount should not return an error: %v", err)
+	_, claims, err = issuer.Decrypt(accountTokens.AuthToken)
+	assert.Nilf(t, err, "auth token should be valid: %v", err)
+
+	authCtx, ok = claims.AuthorizationContext(ctx)
+	assert.Truef(t, ok, "should reconstruct the context of a service account")
+	assert.Truef(t, authCtx.IsServiceAccount(), "context should be a service account's")
+	assert.Equalf(t, []records.RoleAgg{{RoleId: accountRole.RoleId, RoleHierarchy: accountRole.RoleHierarchy}}, authCtx.GetRoles(), "context should hold the account's roles")
+
+	_, ok = (&UserClaims{UserID: verified.ID}).AuthorizationContext(ctx)
+	assert.Falsef(t, ok, "should not reconstruct the context of a token issued without roles")
+
+	delete(all, verified.ID)
+	_, err = authenticator.Refresh(ctx, refreshed.RefreshToken)
+	assert.ErrorIsf(t, err, ErrUserNotFound, "refresh tokens should not outlive their user")
+}
@@ -0,0 +1,115 @@
+package authentication
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-crypto/crypto"
+	"go.uber.org/zap"
+)
+
+// CredentialChange is the new credential a user switches to, the fields are the ones of a Registration.
+// AESGCM users send it encrypted with their current key, the other algorithms have no key the
+// server could decrypt with and send it as plain JSON
+type CredentialChange struct {
+	Key       []byte             `json:"key"`
+	Secret    []byte             `json:"secret"`
+	Algorithm SupportedAlgorithm `json:"algorithm"`
+}
+
+// NewCredentials is a verified credential change, OldKey is the key the change was proven with and is nil for recoveries
+type NewCredentials struct {
+	OldKey    []byte
+	Key       []byte
+	Salt      []byte
+	Algorithm SupportedAlgorithm
+}
+
+// EncryptCredentialChange encodes the change the way ChangeCredentials expects it from a user of the
+// current algorithm, currentKey and salt are only used for AESGCM users
+func EncryptCredentialChange(current SupportedAlgorithm, currentKey []byte, salt [crypto.SALT_SIZE]byte, change CredentialChange) ([]byte, error) {
+	payload, err := json.Marshal(change)
+	if err != nil {
+		return nil, err
+	}
+
+	if current != SupportedAlgorithmAESGCM {
+		return payload, nil
+	}
+
+	return crypto.AESGCMEncryptWithKey(currentKey, salt, payload)
+}
+
+// ChangeCredentials checks the answer to a challenge issued for the user's current key and the new
+// credential, which is verified like a registration. The caller persists the returned credentials
+func (a *AuthenticatorV1) ChangeCredentials(ctx context.Context, userId records.UserId, challengeId uuid.UUID, solvedChallenge []byte, change []byte) (*NewCredentials, error) {
+	l := l.With(zap.String("user_id", userId.String()))
+
+	result, err := a.challenger.VerifyChallenge(ctx, challengeId, solvedChallenge)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.User.ID != userId {
+		l.Warn("credential change challenge was issued for another user", zap.String("challenge_user_id", result.User.ID.String()))
+		return nil, ErrChallengeFailed
+	}
+
+	current, err := ParseAlgorithm(result.User.Algorithm)
+	if err != nil {
+		l.Error("user has an unsupported key algorithm", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	payload := change
+	if current == SupportedAlgorithmAESGCM {
+		payload, err = crypto.AESGCMDecryptWithKey(result.User.Key, change)
+		if err != nil {
+			l.Warn("failed to decrypt credential change", zap.Error(err))
+			return nil, ErrInvalidRegistration
+		}
+	}
+
+	var newCredential CredentialChange
+	err = json.Unmarshal(payload, &newCredential)
+	if err != nil {
+		l.Warn("failed to decode credential change", zap.Error(err))
+		return nil, ErrInvalidRegistration
+	}
+
+	alg, err := ParseAlgorithm(string(newCredential.Algorithm))
+	if err != nil {
+		return nil, ErrInvalidRegistration
+	}
+
+	salt, err := a.challenger.VerifyRegistration(ctx, Registration{
+		Username:  result.User.Username,
+		Key:       newCredential.Key,
+		Secret:    newCredential.Secret,
+		Algorithm: alg,
+	})
+	if err != nil {
+		l.Warn("failed to verify the new credential", zap.Error(err))
+		return nil, err
+	}
+
+	return newCredentials(result.User.Key, newCredential.Key, salt, alg), nil
+}
+
+func newCredentials(oldKey []byte, key []byte, salt [crypto.SALT_SIZE]byte, alg SupportedAlgorithm) *NewCredentials {
+	credentials := &NewCredentials{
+		OldKey:    oldKey,
+		Key:       key,
+		Salt:      salt[:],
+		Algorithm: alg,
+	}
+
+	// asymmetric keys are not derived from a password so they have no salt
+	if alg.IsAsymmetric() {
+		credentials.Salt = nil
+	}
+
+	return credentials
+}
@@ -0,0 +1,87 @@
+package authentication
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/records/v1/users"
+	"github.com/ooqls/go-crypto/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticator_ChangeCredentials(t *testing.T) {
+	ctx := context.Background()
+	a := newTestAuthenticator(t)
+
+	salt := RegistrationSalt("testuser")
+	oldKey, err := crypto.DeriveAESGCMKey("old password", salt)
+	assert.Nilf(t, err, "should not fail to derive the old key: %v", err)
+
+	newKey, err := crypto.DeriveAESGCMKey("new password", salt)
+	assert.Nilf(t, err, "should not fail to derive the new key: %v", err)
+
+	newSecret, err := crypto.AESGCMEncryptWithKey(newKey, salt, []byte("testuser"))
+	assert.Nilf(t, err, "should not fail to encrypt the username: %v", err)
+
+	user := &users.User{ID: uuid.New(), Username: "testuser", Key: oldKey, Salt: salt[:], Algorithm: string(SupportedAlgorithmAESGCM)}
+	change := CredentialChange{Key: newKey, Secret: newSecret, Algorithm: SupportedAlgorithmAESGCM}
+
+	// proveOldKey issues a challenge and answers it with the old key
+	proveOldKey := func() (uuid.UUID, []byte) {
+		challenge, err := a.ChallengeRequest(ctx, user)
+		assert.Nilf(t, err, "should not fail to issue a challenge: %v", err)
+
+		proof, err := crypto.AESGCMEncryptWithKey(oldKey, salt, challenge.Challenge)
+		assert.Nilf(t, err, "should not fail to answer the challenge: %v", err)
+
+		return challenge.ID, proof
+	}
+
+	encrypted, err := EncryptCredentialChange(SupportedAlgorithmAESGCM, oldKey, salt, change)
+	assert.Nilf(t, err, "should not fail to encrypt the change: %v", err)
+
+	challengeId, proof := proveOldKey()
+	_, err = a.ChangeCredentials(ctx, uuid.New(), challengeId, proof, encrypted)
+	assert.ErrorIsf(t, err, ErrChallengeFailed, "should not change the credentials of another user")
+
+	wronglyEncrypted, err := EncryptCredentialChange(SupportedAlgorithmAESGCM, newKey, salt, change)
+	assert.Nilf(t, err, "should not fail to encrypt the change: %v", err)
+
+	challengeId, proof = proveOldKey()
+	_, err = a.ChangeCredentials(ctx, user.ID, challengeId, proof, wronglyEncrypted)
+	assert.ErrorIsf(t, err, ErrInvalidRegistration, "should not accept a change that is not encrypted with the old key")
+
+	challengeId, _ = proveOldKey()
+	wrongProof, err := crypto.AESGCMEncryptWithKey(newKey, salt, []byte("not the challenge"))
+	assert.Nilf(t, err, "should not fail to encrypt: %v", err)
+
+	_, err = a.ChangeCredentials(ctx, user.ID, challengeId, wrongProof, encrypted)
+	assert.ErrorIsf(t, err, ErrChallengeFailed, "should not change the credentials without proving the old key")
+
+	challengeId, proof = proveOldKey()
+	credentials, err := a.ChangeCredentials(ctx, user.ID, challengeId, proof, encrypted)
+	assert.Nilf(t, err, "should change the credentials: %v", err)
+	assert.Equalf(t, oldKey, credentials.OldKey, "should return the key the change was proven with")
+	assert.Equalf(t, newKey, credentials.Key, "should return the new key")
+	assert.Equalf(t, salt[:], credentials.Salt, "should re-derive the salt")
+
+	// switching to an asymmetric key drops the salt
+	privateKey, err := GenerateKey(SupportedAlgorithmEd25519)
+	assert.Nilf(t, err, "should not fail to generate a key: %v", err)
+
+	publicKey, err := PublicKey(SupportedAlgorithmEd25519, []byte(privateKey))
+	assert.Nilf(t, err, "should not fail to get the public key: %v", err)
+
+	signature, err := Sign(SupportedAlgorithmEd25519, []byte(privateKey), []byte("testuser"))
+	assert.Nilf(t, err, "should not fail to sign the username: %v", err)
+
+	encrypted, err = EncryptCredentialChange(SupportedAlgorithmAESGCM, oldKey, salt, CredentialChange{Key: publicKey, Secret: signature, Algorithm: SupportedAlgorithmEd25519})
+	assert.Nilf(t, err, "should not fail to encrypt the change: %v", err)
+
+	challengeId, proof = proveOldKey()
+	credentials, err = a.ChangeCredentials(ctx, user.ID, challengeId, proof, encrypted)
+	assert.Nilf(t, err, "should change to an asymmetric key: %v", err)
+	assert.Equalf(t, SupportedAlgorithmEd25519, credentials.Algorithm, "should return the new algorithm")
+	assert.Nilf(t, credentials.Salt, "asymmetric keys should not have a salt")
+}
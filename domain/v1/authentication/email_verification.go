@@ -0,0 +1,133 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ooqls/go-auth/domain/v1/mail"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/users"
+	"github.com/ooqls/go-crypto/jwt"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrEmailNotVerified         error = errors.New("email not verified")
+	ErrInvalidVerificationToken error = errors.New("invalid email verification token")
+)
+
+// EmailVerificationTTL is how long a verification link can be used
+const EmailVerificationTTL = 24 * time.Hour
+
+// EmailVerificationPolicy decides what users who have not verified their email can do.
+// Unverified users never hold roles, see authorization.NewAuthorizationContext
+type EmailVerificationPolicy string
+
+const (
+	// UnverifiedLoginAllowed lets unverified users log in without their roles
+	UnverifiedLoginAllowed EmailVerificationPolicy = "allow_login"
+	// UnverifiedLoginDenied doesn't let unverified users log in until they verify their email
+	UnverifiedLoginDenied EmailVerificationPolicy = "deny_login"
+)
+
+// ParseEmailVerificationPolicy returns the policy with the given name, empty names default to UnverifiedLoginAllowed
+func ParseEmailVerificationPolicy(policy string) (EmailVerificationPolicy, error) {
+	switch EmailVerificationPolicy(policy) {
+	case "", UnverifiedLoginAllowed:
+		return UnverifiedLoginAllowed, nil
+	case UnverifiedLoginDenied:
+		return UnverifiedLoginDenied, nil
+	}
+
+	return "", fmt.Errorf("unsupported email verification policy: %s", policy)
+}
+
+// checkLogin returns ErrEmailNotVerified if the policy doesn't let the user log in
+func (p EmailVerificationPolicy) checkLogin(user *users.User) error {
+	if p == UnverifiedLoginDenied && !user.EmailVerified {
+		return ErrEmailNotVerified
+	}
+
+	return nil
+}
+
+// EmailVerificationClaims are the claims of a verification link, the link only verifies the email it was sent to
+type EmailVerificationClaims struct {
+	UserID records.UserId `json:"user_id"`
+	Email  string         `json:"email"`
+}
+
+// EmailVerifier emails users signed links to verify their email with
+type EmailVerifier struct {
+	issuer    jwt.TokenIssuer[EmailVerificationClaims]
+	mailer    mail.Mailer
+	verifyURL string
+}
+
+// NewEmailVerifier returns an EmailVerifier that emails links to verifyURL with the token in the "token" query parameter.
+// The issuer should have its own audience so its tokens can't be used as auth tokens
+func NewEmailVerifier(issuer jwt.TokenIssuer[EmailVerificationClaims], mailer mail.Mailer, verifyURL string) *EmailVerifier {
+	return &EmailVerifier{
+		issuer:    issuer,
+		mailer:    mailer,
+		verifyURL: verifyURL,
+	}
+}
+
+// SendVerification emails the user a link to verify their current email
+func (v *EmailVerifier) SendVerification(ctx context.Context, user *users.User) error {
+	l := l.With(zap.String("user_id", user.ID.String()))
+
+	if user.Email == "" {
+		return ErrNoEmail
+	}
+
+	token, _, err := v.issuer.IssueToken(user.ID.String(), EmailVerificationClaims{UserID: user.ID, Email: user.Email})
+	if err != nil {
+		l.Error("failed to issue email verification token", zap.Error(err))
+		return ErrInternal
+	}
+
+	link, err := url.Parse(v.verifyURL)
+	if err != nil {
+		l.Error("invalid verify url", zap.Error(err))
+		return ErrInternal
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	err = v.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to verify your email, it expires in %d hours.\n\n%s\n\n"+
+			"If you did not create an account you can ignore this email.", user.Username, int(EmailVerificationTTL.Hours()), link.String()),
+	})
+	if err != nil {
+		l.Error("failed to send verification email", zap.Error(err))
+		return ErrInternal
+	}
+
+	l.Info("sent verification email")
+	return nil
+}
+
+// Verify checks the signature and expiry of a verification token and returns the email it verifies.
+// The caller marks the email as verified if it is still the user's email
+func (v *EmailVerifier) Verify(token string) (*EmailVerificationClaims, error) {
+	if token == "" || len(token) > 4096 {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	_, claims, err := v.issuer.Decrypt(token)
+	if err != nil {
+		l.Warn("invalid email verification token", zap.Error(err))
+		return nil, ErrInvalidVerificationToken
+	}
+
+	return &claims, nil
+}
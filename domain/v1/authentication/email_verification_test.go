@@ -0,0 +1,104 @@
+package authentication
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/domain/v1/mail"
+	mfamocks "github.com/ooqls/go-auth/records/v1/mfa/mocks"
+	sessionmocks "github.com/ooqls/go-auth/records/v1/sessions/mocks"
+	"github.com/ooqls/go-auth/records/v1/users"
+	"github.com/ooqls/go-crypto/crypto"
+	"github.com/ooqls/go-crypto/jwt"
+	"github.com/ooqls/go-crypto/keys"
+	"github.com/stretchr/testify/assert"
+)
+
+var verificationLinkRegex = regexp.MustCompile(`https://example\.com/verify\?token=(\S+)`)
+
+func TestEmailVerifier(t *testing.T) {
+	ctx := context.Background()
+
+	rKey, err := keys.NewRSA()
+	assert.Nilf(t, err, "failed to create new key: %v", err)
+	jwtKey := keys.NewJWTKey(*rKey)
+
+	issuer := jwt.NewJwtTokenIssuer[EmailVerificationClaims](&jwt.TokenConfiguration{
+		Issuer:                  "test",
+		Audience:                []string{"email_verification"},
+		ValidityDurationSeconds: EmailVerificationTTL.Seconds(),
+	}, jwtKey)
+
+	otherIssuer := jwt.NewJwtTokenIssuer[EmailVerificationClaims](&jwt.TokenConfiguration{
+		Issuer:                  "test",
+		Audience:                []string{"test"},
+		ValidityDurationSeconds: 300,
+	}, jwtKey)
+
+	var sent bytes.Buffer
+	verifier := NewEmailVerifier(issuer, mail.NewFileMailer(&sent), "https://example.com/verify")
+
+	user := &users.User{ID: uuid.New(), Username: "testuser", Email: "test@example.com"}
+	err = verifier.SendVerification(ctx, user)
+	assert.Nilf(t, err, "should not fail to send the verification: %v", err)
+	assert.Containsf(t, sent.String(), "To: test@example.com", "should email the user")
+
+	match := verificationLinkRegex.FindStringSubmatch(sent.String())
+	assert.Lenf(t, match, 2, "email should contain the verification link, got: %s", sent.String())
+	token, err := url.QueryUnescape(match[1])
+	assert.Nilf(t, err, "should not fail to unescape the token: %v", err)
+
+	claims, err := verifier.Verify(token)
+	assert.Nilf(t, err, "should verify the token: %v", err)
+	assert.Equalf(t, user.ID, claims.UserID, "should verify the user the link was sent to")
+	assert.Equalf(t, user.Email, claims.Email, "should verify the email the link was sent to")
+
+	_, err = verifier.Verify(token[:len(token)-2])
+	assert.ErrorIsf(t, err, ErrInvalidVerificationToken, "should not verify a tampered token")
+
+	otherToken, _, err := otherIssuer.IssueToken(user.ID.String(), EmailVerificationClaims{UserID: user.ID, Email: user.Email})
+	assert.Nilf(t, err, "should not fail to issue a token: %v", err)
+
+	_, err = verifier.Verify(otherToken)
+	assert.ErrorIsf(t, err, ErrInvalidVerificationToken, "should not verify a token for another audience")
+}
+
+func TestAuthenticator_UnverifiedLoginDenied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	a := newTestAuthenticatorWith(t, sessionmocks.ReturnSessions(ctrl), sessionmocks.AcceptSessions(ctrl), mfamocks.NotEnrolled(ctrl), mfamocks.NewMockWriter(ctrl), UnverifiedLoginDenied)
+
+	salt := RegistrationSalt("testuser")
+	key, err := crypto.DeriveAESGCMKey("password", salt)
+	assert.Nilf(t, err, "should not fail to derive the key: %v", err)
+
+	user := &users.User{ID: uuid.New(), Username: "testuser", Email: "test@example.com", Key: key, Salt: salt[:], Algorithm: string(SupportedAlgorithmAESGCM)}
+
+	_, err = a.AuthenticateNewUser(ctx, user)
+	assert.ErrorIsf(t, err, ErrEmailNotVerified, "should not start a session for a new unverified user")
+
+	login := func() (*TokenResponse, error) {
+		challenge, err := a.ChallengeRequest(ctx, user)
+		assert.Nilf(t, err, "should not fail to issue a challenge: %v", err)
+
+		answer, err := crypto.AESGCMEncryptWithKey(key, salt, challenge.Challenge)
+		assert.Nilf(t, err, "should not fail to answer the challenge: %v", err)
+
+		return a.ChallengeResponse(ctx, challenge.ID, answer)
+	}
+
+	_, err = login()
+	assert.ErrorIsf(t, err, ErrEmailNotVerified, "should not log in an unverified user")
+
+	user.EmailVerified = true
+	tokens, err := login()
+	assert.Nilf(t, err, "should log in a verified user: %v", err)
+	assert.NotEmptyf(t, tokens.AuthToken, "auth token should not be empty")
+}
@@ -1,20 +1,75 @@
 package authentication
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
 	"fmt"
+
+	gocrypto "github.com/ooqls/go-crypto/crypto"
+)
+
+var (
+	ErrUnsupportedAlgorithm error = errors.New("unsupported algorithm")
+	ErrInvalidKey           error = errors.New("invalid key")
+	ErrInvalidSignature     error = errors.New("invalid signature")
 )
 
 type SupportedAlgorithm string
 
 const (
+	// SupportedAlgorithmAESGCM keys are derived from the user's password, the server stores the derived key
 	SupportedAlgorithmAESGCM SupportedAlgorithm = "AESGCM"
+	// SupportedAlgorithmEd25519 users sign challenges with an Ed25519 key, the server only stores the public key
+	SupportedAlgorithmEd25519 SupportedAlgorithm = "ED25519"
+	// SupportedAlgorithmECDSAP256 users sign challenges with an ECDSA P-256 key, the server only stores the public key
+	SupportedAlgorithmECDSAP256 SupportedAlgorithm = "ECDSA_P256"
+	// SupportedAlgorithmSRP6A users log in with the SRP-6a protocol, the server only stores the password verifier
+	SupportedAlgorithmSRP6A SupportedAlgorithm = "SRP6A"
 )
 
+// ParseAlgorithm returns the algorithm with the given name, an empty name is AESGCM
+func ParseAlgorithm(name string) (SupportedAlgorithm, error) {
+	switch alg := SupportedAlgorithm(name); alg {
+	case "":
+		return SupportedAlgorithmAESGCM, nil
+	case SupportedAlgorithmAESGCM, SupportedAlgorithmEd25519, SupportedAlgorithmECDSAP256, SupportedAlgorithmSRP6A:
+		return alg, nil
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, name)
+}
+
+// IsAsymmetric returns true when users of the algorithm register a public key and sign challenges
+func (a SupportedAlgorithm) IsAsymmetric() bool {
+	return a == SupportedAlgorithmEd25519 || a == SupportedAlgorithmECDSAP256
+}
+
+// GenerateKey returns a new key for the algorithm.
+// For asymmetric algorithms this is the PKCS #8 DER encoded private key
 func GenerateKey(key SupportedAlgorithm) (string, error) {
 	switch key {
 	case SupportedAlgorithmAESGCM:
 		return generateAESGCMKey()
+	case SupportedAlgorithmEd25519:
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return "", err
+		}
+
+		return marshalPrivateKey(privateKey)
+	case SupportedAlgorithmECDSAP256:
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return "", err
+		}
+
+		return marshalPrivateKey(privateKey)
 	}
 	return "", fmt.Errorf("unsupported algorithm: %s", key)
 }
@@ -28,3 +83,125 @@ func generateAESGCMKey() (string, error) {
 
 	return string(key[:]), nil
 }
+
+func marshalPrivateKey(privateKey crypto.PrivateKey) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	return string(der), nil
+}
+
+// PublicKey returns the PKIX DER encoded public key of a PKCS #8 DER encoded private key
+func PublicKey(alg SupportedAlgorithm, privateKey []byte) ([]byte, error) {
+	key, err := parsePrivateKey(alg, privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return x509.MarshalPKIXPublicKey(key.Public())
+}
+
+// Sign signs the message with a PKCS #8 DER encoded private key, this is what clients
+// of asymmetric algorithms do to answer a challenge
+func Sign(alg SupportedAlgorithm, privateKey []byte, message []byte) ([]byte, error) {
+	key, err := parsePrivateKey(alg, privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		return ed25519.Sign(k, message), nil
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256(message)
+		return ecdsa.SignASN1(rand.Reader, k, digest[:])
+	}
+
+	return nil, ErrInvalidKey
+}
+
+// Verify checks the signature of the message against a PKIX DER encoded public key
+func Verify(alg SupportedAlgorithm, publicKey []byte, message []byte, signature []byte) error {
+	key, err := parsePublicKey(alg, publicKey)
+	if err != nil {
+		return err
+	}
+
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, message, signature) {
+			return ErrInvalidSignature
+		}
+
+		return nil
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(message)
+		if !ecdsa.VerifyASN1(k, digest[:], signature) {
+			return ErrInvalidSignature
+		}
+
+		return nil
+	}
+
+	return ErrInvalidKey
+}
+
+// ValidateKey checks that the key a user registers is usable with the algorithm.
+// AESGCM keys are the raw derived key, SRP6A keys are the verifier and asymmetric keys are PKIX DER encoded public keys
+func ValidateKey(alg SupportedAlgorithm, key []byte) error {
+	switch alg {
+	case SupportedAlgorithmAESGCM:
+		if err := gocrypto.VerifyGCMAESKey(key); err != nil {
+			return ErrInvalidKey
+		}
+
+		return nil
+	case SupportedAlgorithmSRP6A:
+		return validateSRPVerifier(key)
+	}
+
+	_, err := parsePublicKey(alg, key)
+	return err
+}
+
+func parsePublicKey(alg SupportedAlgorithm, der []byte) (crypto.PublicKey, error) {
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		if alg == SupportedAlgorithmEd25519 {
+			return k, nil
+		}
+	case *ecdsa.PublicKey:
+		if alg == SupportedAlgorithmECDSAP256 && k.Curve == elliptic.P256() {
+			return k, nil
+		}
+	}
+
+	return nil, ErrInvalidKey
+}
+
+func parsePrivateKey(alg SupportedAlgorithm, der []byte) (crypto.Signer, error) {
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		if alg == SupportedAlgorithmEd25519 {
+			return k, nil
+		}
+	case *ecdsa.PrivateKey:
+		if alg == SupportedAlgorithmECDSAP256 && k.Curve == elliptic.P256() {
+			return k, nil
+		}
+	}
+
+	return nil, ErrInvalidKey
+}
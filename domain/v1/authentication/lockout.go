@@ -0,0 +1,116 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/challengeattempts"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrAccountLocked error = errors.New("account locked")
+)
+
+// AccountLockedError is returned when a user has failed too many challenges,
+// it matches ErrAccountLocked with errors.Is
+type AccountLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrAccountLocked.Error(), e.RetryAfter)
+}
+
+func (e *AccountLockedError) Is(target error) bool {
+	return target == ErrAccountLocked
+}
+
+// LockoutPolicy locks a user out once they fail MaxFailures challenges within Window.
+// The lock lasts BaseLockout and doubles with every further failure, up to MaxLockout.
+// A successful challenge resets the count
+type LockoutPolicy struct {
+	MaxFailures int
+	Window      time.Duration
+	BaseLockout time.Duration
+	MaxLockout  time.Duration
+}
+
+func DefaultLockoutPolicy() LockoutPolicy {
+	return LockoutPolicy{
+		MaxFailures: 5,
+		Window:      15 * time.Minute,
+		BaseLockout: time.Minute,
+		MaxLockout:  time.Hour,
+	}
+}
+
+// windowMinutes returns the window rounded up to whole minutes
+func (p LockoutPolicy) windowMinutes() int {
+	return int(math.Ceil(p.Window.Minutes()))
+}
+
+// lockoutDuration returns how long a user is locked out after the given number of failures
+func (p LockoutPolicy) lockoutDuration(failures int) time.Duration {
+	if p.MaxFailures <= 0 || failures < p.MaxFailures {
+		return 0
+	}
+
+	lockout := p.BaseLockout
+	for i := p.MaxFailures; i < failures && lockout < p.MaxLockout; i++ {
+		lockout *= 2
+	}
+
+	if p.MaxLockout > 0 && lockout > p.MaxLockout {
+		lockout = p.MaxLockout
+	}
+
+	return lockout
+}
+
+// lockedUntil returns when the lock caused by the failed attempts ends, failed must be sorted most recent first
+func (p LockoutPolicy) lockedUntil(failed []challengeattempts.ChallengeAttempt) time.Time {
+	lockout := p.lockoutDuration(len(failed))
+	if lockout == 0 {
+		return time.Time{}
+	}
+
+	return failed[0].CreatedAt.Add(lockout)
+}
+
+// attemptTracker records challenge outcomes and enforces the lockout policy,
+// it is shared by the challengers so every kind of challenge counts towards the same lock
+type attemptTracker struct {
+	attemptReader challengeattempts.Reader
+	attemptWriter challengeattempts.Writer
+	lockoutPolicy LockoutPolicy
+}
+
+// checkLockout returns an AccountLockedError when the user is locked out
+func (c *attemptTracker) checkLockout(ctx context.Context, userId records.UserId) error {
+	failed, err := c.attemptReader.GetFailedAttempts(ctx, userId, c.lockoutPolicy.windowMinutes())
+	if err != nil {
+		l.Error("failed to get failed challenge attempts", zap.String("user_id", userId.String()), zap.Error(err))
+		return ErrInternal
+	}
+
+	retryAfter := time.Until(c.lockoutPolicy.lockedUntil(failed))
+	if retryAfter > 0 {
+		l.Warn("user is locked out", zap.String("user_id", userId.String()), zap.Int("failures", len(failed)), zap.Duration("retry_after", retryAfter))
+		return &AccountLockedError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+// recordAttempt records the outcome of a challenge, failing to record is logged but not fatal
+func (c *attemptTracker) recordAttempt(ctx context.Context, challenge Challenge, success bool) {
+	err := c.attemptWriter.CreateChallengeAttempt(ctx, challenge.User.ID, challenge.ID, success)
+	if err != nil {
+		l.Error("failed to record challenge attempt", zap.String("user_id", challenge.User.ID.String()), zap.Error(err))
+	}
+}
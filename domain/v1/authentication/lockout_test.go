@@ -0,0 +1,91 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/records/v1/challengeattempts"
+	attemptmocks "github.com/ooqls/go-auth/records/v1/challengeattempts/mocks"
+	"github.com/ooqls/go-auth/records/v1/users"
+	"github.com/ooqls/go-cache/store"
+	"github.com/ooqls/go-crypto/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func failedAttempts(n int, at time.Time) []challengeattempts.ChallengeAttempt {
+	attempts := make([]challengeattempts.ChallengeAttempt, n)
+	for i := range attempts {
+		attempts[i] = challengeattempts.ChallengeAttempt{ID: uuid.New(), CreatedAt: at}
+	}
+
+	return attempts
+}
+
+func TestLockoutPolicy_LockoutDuration(t *testing.T) {
+	policy := LockoutPolicy{
+		MaxFailures: 3,
+		Window:      15 * time.Minute,
+		BaseLockout: time.Minute,
+		MaxLockout:  10 * time.Minute,
+	}
+
+	assert.Equalf(t, time.Duration(0), policy.lockoutDuration(2), "should not lock before reaching max failures")
+	assert.Equalf(t, time.Minute, policy.lockoutDuration(3), "should lock for the base lockout at max failures")
+	assert.Equalf(t, 2*time.Minute, policy.lockoutDuration(4), "should double the lockout for every further failure")
+	assert.Equalf(t, 8*time.Minute, policy.lockoutDuration(6), "should double the lockout for every further failure")
+	assert.Equalf(t, 10*time.Minute, policy.lockoutDuration(100), "should cap the lockout at max lockout")
+	assert.Equalf(t, time.Duration(0), LockoutPolicy{}.lockoutDuration(100), "an empty policy should never lock")
+}
+
+func TestChallenger_Lockout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	policy := DefaultLockoutPolicy()
+	_, userKey, salt := generateUserKey(t)
+	user := &users.User{ID: uuid.New(), Username: "test", Key: userKey, Salt: salt[:]}
+
+	attemptReader := attemptmocks.NewMockReader(ctrl)
+	attemptWriter := attemptmocks.NewMockWriter(ctrl)
+	challenger := NewChallengerV1(store.NewMemStore("test", time.Second*10), attemptReader, attemptWriter, policy)
+
+	// a wrong answer is recorded as a failed attempt
+	attemptReader.EXPECT().GetFailedAttempts(gomock.Any(), user.ID, 15).Return(failedAttempts(policy.MaxFailures-1, time.Now()), nil)
+	challenge, err := challenger.IssueChallenge(ctx, user)
+	assert.Nilf(t, err, "IssueChallenge should not return an error: %v", err)
+
+	attemptWriter.EXPECT().CreateChallengeAttempt(gomock.Any(), user.ID, challenge.ID, false).Return(nil)
+	_, err = challenger.VerifyChallenge(ctx, challenge.ID, []byte("wrong"))
+	assert.ErrorIsf(t, err, ErrChallengeFailed, "a wrong answer should fail the challenge")
+
+	_, err = challenger.VerifyChallenge(ctx, challenge.ID, []byte("wrong"))
+	assert.ErrorIsf(t, err, ErrChallengeExpired, "a challenge should only be answered once")
+
+	// once max failures are reached the user is locked out without the answer being checked
+	attemptReader.EXPECT().GetFailedAttempts(gomock.Any(), user.ID, 15).Return(failedAttempts(policy.MaxFailures, time.Now()), nil)
+	challenge, err = challenger.IssueChallenge(ctx, user)
+	assert.Nilf(t, err, "IssueChallenge should not return an error: %v", err)
+
+	_, err = challenger.VerifyChallenge(ctx, challenge.ID, []byte("wrong"))
+	assert.ErrorIsf(t, err, ErrAccountLocked, "the user should be locked out")
+
+	var lockedErr *AccountLockedError
+	assert.Truef(t, errors.As(err, &lockedErr), "the error should carry the retry after")
+	assert.InDeltaf(t, policy.BaseLockout.Seconds(), lockedErr.RetryAfter.Seconds(), 1, "the user should be locked out for the base lockout")
+
+	// the lock ends after the lockout has passed
+	attemptReader.EXPECT().GetFailedAttempts(gomock.Any(), user.ID, 15).Return(failedAttempts(policy.MaxFailures, time.Now().Add(-policy.BaseLockout)), nil)
+	attemptWriter.EXPECT().CreateChallengeAttempt(gomock.Any(), user.ID, challenge.ID, true).Return(nil)
+	userAlgo := crypto.NewAESGCMAlgorithmWithKey(userKey, salt)
+	solved, err := userAlgo.Encrypt(challenge.Challenge)
+	assert.Nilf(t, err, "should not fail to solve challenge: %v", err)
+
+	result, err := challenger.VerifyChallenge(ctx, challenge.ID, solved)
+	assert.Nilf(t, err, "the user should no longer be locked out: %v", err)
+	assert.Equalf(t, user.ID, result.User.ID, "result should belong to the user")
+}
@@ -0,0 +1,300 @@
+package authentication
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ooqls/go-auth/domain/v1/mail"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/users"
+	"github.com/ooqls/go-cache/cache"
+	"github.com/ooqls/go-cache/factory"
+	"github.com/ooqls/go-cache/store"
+	"go.uber.org/zap"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=magic_link.go -destination=mocks/mock_magic_linker.go -package=mocks
+
+var (
+	ErrInvalidMagicLink     error = errors.New("invalid magic link")
+	ErrMagicLinkRateLimited error = errors.New("too many magic link requests")
+)
+
+// MagicLinkRateLimitedError is returned when an address asked for too many links,
+// it matches ErrMagicLinkRateLimited with errors.Is
+type MagicLinkRateLimitedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *MagicLinkRateLimitedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrMagicLinkRateLimited.Error(), e.RetryAfter)
+}
+
+func (e *MagicLinkRateLimitedError) Is(target error) bool {
+	return target == ErrMagicLinkRateLimited
+}
+
+const (
+	// magicLinkTTL is how long an emailed magic link can be used
+	magicLinkTTL = 15 * time.Minute
+	// magicLinkMaxRequests is how many links an address can ask for within magicLinkRequestWindow
+	magicLinkMaxRequests   = 3
+	magicLinkRequestWindow = 15 * time.Minute
+)
+
+// MagicLinkPolicy decides which users can log in with a link emailed to them
+type MagicLinkPolicy string
+
+const (
+	// MagicLinkOptIn only lets users who turned magic links on log in with them
+	MagicLinkOptIn MagicLinkPolicy = "opt_in"
+	// MagicLinkAll lets every user with a verified email log in with a magic link
+	MagicLinkAll MagicLinkPolicy = "all"
+)
+
+// ParseMagicLinkPolicy returns the policy with the given name, empty names default to MagicLinkOptIn
+func ParseMagicLinkPolicy(policy string) (MagicLinkPolicy, error) {
+	switch MagicLinkPolicy(policy) {
+	case "", MagicLinkOptIn:
+		return MagicLinkOptIn, nil
+	case MagicLinkAll:
+		return MagicLinkAll, nil
+	}
+
+	return "", fmt.Errorf("unsupported magic link policy: %s", policy)
+}
+
+// allows returns true if the user can log in with a magic link. Links are only sent to verified emails
+// so a link can't log anyone into an account whose email belongs to someone else
+func (p MagicLinkPolicy) allows(user *users.User) bool {
+	if user.Email == "" || !user.EmailVerified {
+		return false
+	}
+
+	return p == MagicLinkAll || user.MagicLinkEnabled
+}
+
+// MagicLinker logs users in with single-use links emailed to them instead of a challenge
+type MagicLinker interface {
+	// RequestMagicLink emails a link to every user of the email who can use one. It returns nil whether or not
+	// a link was sent so it can't be used to find emails, unless the address asked for too many links
+	RequestMagicLink(ctx context.Context, email string) error
+	// VerifyMagicLink logs in the user the link was sent to, a link can only be used once. Users with MFA
+	// enabled still have to provide their second factor
+	VerifyMagicLink(ctx context.Context, token string) (*TokenResponse, error)
+}
+
+// pendingMagicLink is a magic link that was emailed to the user, only the hash of its token is stored
+type pendingMagicLink struct {
+	UserID    records.UserId
+	Email     string
+	ExpiresAt time.Time
+	Used      bool
+}
+
+// magicLinkRequests counts the links an address asked for since WindowStart
+type magicLinkRequests struct {
+	Count       int
+	WindowStart time.Time
+}
+
+// magicLinkKey returns the key tokens and addresses are stored under, they are hashed so the store doesn't hold them
+func magicLinkKey(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+var _ MagicLinker = &MagicLinkerV1{}
+
+type MagicLinkerV1 struct {
+	tokens        store.GenericInterface
+	requests      store.GenericInterface
+	userReader    users.Reader
+	authenticator Authenticator
+	mailer        mail.Mailer
+	verifyURL     string
+	policy        MagicLinkPolicy
+}
+
+// NewMagicLinkerV1 returns a MagicLinker that emails links to verifyURL with the token in the "token" query parameter
+func NewMagicLinkerV1(cacheFactory factory.CacheFactory, userReader users.Reader, authenticator Authenticator, mailer mail.Mailer, verifyURL string, policy MagicLinkPolicy) MagicLinker {
+	return &MagicLinkerV1{
+		tokens:        cacheFactory.NewStore("magic_links", magicLinkTTL),
+		requests:      cacheFactory.NewStore("magic_link_requests", magicLinkRequestWindow),
+		userReader:    userReader,
+		authenticator: authenticator,
+		mailer:        mailer,
+		verifyURL:     verifyURL,
+		policy:        policy,
+	}
+}
+
+// RequestMagicLink emails a magic link to the users of the email who can log in with one
+func (m *MagicLinkerV1) RequestMagicLink(ctx context.Context, email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" || len(email) > 320 {
+		return nil
+	}
+
+	err := m.countRequest(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	candidates, err := m.userReader.GetUsersByEmail(ctx, email)
+	if err != nil {
+		l.Error("failed to get users by email", zap.Error(err))
+		return ErrInternal
+	}
+
+	for i := range candidates {
+		user := &candidates[i]
+		if !m.policy.allows(user) {
+			continue
+		}
+
+		err = m.sendMagicLink(ctx, user)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// countRequest counts a request for the address and returns a MagicLinkRateLimitedError once it asked for too many
+func (m *MagicLinkerV1) countRequest(ctx context.Context, email string) error {
+	key := magicLinkKey(strings.ToLower(email))
+	now := time.Now()
+
+	var requests magicLinkRequests
+	err := m.requests.Get(ctx, key, &requests)
+	if err != nil && !cache.IsCacheMissErr(err) {
+		l.Error("failed to get magic link requests", zap.Error(err))
+		return ErrInternal
+	}
+
+	if err != nil || now.Sub(requests.WindowStart) >= magicLinkRequestWindow {
+		requests = magicLinkRequests{WindowStart: now}
+	}
+
+	if requests.Count >= magicLinkMaxRequests {
+		return &MagicLinkRateLimitedError{RetryAfter: requests.WindowStart.Add(magicLinkRequestWindow).Sub(now)}
+	}
+
+	requests.Count++
+	err = m.requests.Set(ctx, key, requests)
+	if err != nil {
+		l.Error("failed to store magic link requests", zap.Error(err))
+		return ErrInternal
+	}
+
+	return nil
+}
+
+func (m *MagicLinkerV1) sendMagicLink(ctx context.Context, user *users.User) error {
+	l := l.With(zap.String("user_id", user.ID.String()))
+
+	var b [32]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return ErrInternal
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b[:])
+	link, err := url.Parse(m.verifyURL)
+	if err != nil {
+		l.Error("invalid magic link url", zap.Error(err))
+		return ErrInternal
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	pending := pendingMagicLink{UserID: user.ID, Email: user.Email, ExpiresAt: time.Now().Add(magicLinkTTL)}
+	err = m.tokens.Set(ctx, magicLinkKey(token), pending)
+	if err != nil {
+		l.Error("failed to store magic link", zap.Error(err))
+		return ErrInternal
+	}
+
+	err = m.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Your login link",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to log in, it expires in %d minutes and can only be used once.\n\n%s\n\n"+
+			"If you did not ask to log in you can ignore this email.", user.Username, int(magicLinkTTL.Minutes()), link.String()),
+	})
+	if err != nil {
+		l.Error("failed to send magic link", zap.Error(err))
+		return ErrInternal
+	}
+
+	l.Info("sent magic link")
+	return nil
+}
+
+// VerifyMagicLink checks the token and starts a session for its user, users with MFA enabled get an mfa token
+// instead. The user is checked again since they may have turned magic links off or changed their email after
+// the link was sent
+func (m *MagicLinkerV1) VerifyMagicLink(ctx context.Context, token string) (*TokenResponse, error) {
+	if token == "" || len(token) > 1024 {
+		return nil, ErrInvalidMagicLink
+	}
+
+	// a link can only be used once, whether or not the login succeeds
+	var pending pendingMagicLink
+	err := m.tokens.Update(ctx, magicLinkKey(token), func(load func(target any) error) (any, error) {
+		if err := load(&pending); err != nil {
+			return nil, err
+		}
+
+		if pending.Used {
+			return nil, ErrInvalidMagicLink
+		}
+
+		pending.Used = true
+		return pending, nil
+	})
+	if err != nil {
+		if cache.IsCacheMissErr(err) || errors.Is(err, ErrInvalidMagicLink) {
+			return nil, ErrInvalidMagicLink
+		}
+
+		l.Error("failed to get magic link", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	l := l.With(zap.String("user_id", pending.UserID.String()))
+
+	if time.Now().After(pending.ExpiresAt) {
+		return nil, ErrInvalidMagicLink
+	}
+
+	user, err := m.userReader.GetUser(ctx, pending.UserID)
+	if err != nil {
+		l.Warn("failed to get the user of a magic link", zap.Error(err))
+		return nil, ErrInvalidMagicLink
+	}
+
+	if user.Email != pending.Email || !m.policy.allows(user) {
+		l.Warn("user can no longer log in with the magic link")
+		return nil, ErrInvalidMagicLink
+	}
+
+	tokens, err := m.authenticator.AuthenticateMagicLinkUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	l.Info("logged in with a magic link")
+	return tokens, nil
+}
@@ -0,0 +1,156 @@
+package authentication
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/domain/v1/mail"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/mfa"
+	mfamocks "github.com/ooqls/go-auth/records/v1/mfa/mocks"
+	sessionmocks "github.com/ooqls/go-auth/records/v1/sessions/mocks"
+	"github.com/ooqls/go-auth/records/v1/users"
+	usermocks "github.com/ooqls/go-auth/records/v1/users/mocks"
+	"github.com/ooqls/go-cache/factory"
+	"github.com/stretchr/testify/assert"
+)
+
+var magicLinkRegex = regexp.MustCompile(`https://example\.com/magic\?token=([A-Za-z0-9_-]+)`)
+
+func newTestUserReader(ctrl *gomock.Controller, all map[records.UserId]*users.User) users.Reader {
+	userReader := usermocks.NewMockReader(ctrl)
+	userReader.EXPECT().GetUser(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(ctx context.Context, id records.UserId) (*users.User, error) {
+			user, ok := all[id]
+			if !ok {
+				return nil, sql.ErrNoRows
+			}
+
+			return user, nil
+		})
+	userReader.EXPECT().GetUsersByEmail(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(ctx context.Context, email string) ([]users.User, error) {
+			var found []users.User
+			for _, user := range all {
+				if user.Email == email {
+					found = append(found, *user)
+				}
+			}
+
+			return found, nil
+		})
+
+	return userReader
+}
+
+func TestMagicLinker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	optedIn := &users.User{ID: uuid.New(), Username: "optedin", Email: "optedin@example.com", EmailVerified: true, MagicLinkEnabled: true}
+	optedOut := &users.User{ID: uuid.New(), Username: "optedout", Email: "optedout@example.com", EmailVerified: true}
+	unverified := &users.User{ID: uuid.New(), Username: "unverified", Email: "unverified@example.com", MagicLinkEnabled: true}
+	userReader := newTestUserReader(ctrl, map[records.UserId]*users.User{optedIn.ID: optedIn, optedOut.ID: optedOut, unverified.ID: unverified})
+
+	var sent bytes.Buffer
+	linker := NewMagicLinkerV1(&factory.MemCacheFactory{}, userReader, newTestAuthenticator(t), mail.NewFileMailer(&sent), "https://example.com/magic", MagicLinkOptIn)
+
+	for _, email := range []string{"unknown@example.com", optedOut.Email, unverified.Email} {
+		err := linker.RequestMagicLink(ctx, email)
+		assert.Nilf(t, err, "should not tell %s apart from a user who can use magic links: %v", email, err)
+	}
+	assert.Emptyf(t, sent.String(), "should only email users who can use magic links")
+
+	err := linker.RequestMagicLink(ctx, optedIn.Email)
+	assert.Nilf(t, err, "should not fail to request a magic link: %v", err)
+	assert.Containsf(t, sent.String(), "To: optedin@example.com", "should email the user")
+
+	match := magicLinkRegex.FindStringSubmatch(sent.String())
+	assert.Lenf(t, match, 2, "email should contain the magic link, got: %s", sent.String())
+	token := match[1]
+
+	_, err = linker.VerifyMagicLink(ctx, "not a token")
+	assert.ErrorIsf(t, err, ErrInvalidMagicLink, "should not accept an unknown token")
+
+	tokens, err := linker.VerifyMagicLink(ctx, token)
+	assert.Nilf(t, err, "should log in with the magic link: %v", err)
+	assert.Equalf(t, optedIn.ID, tokens.UserId, "should log in the user the link was sent to")
+	assert.NotEmptyf(t, tokens.AuthToken, "should issue an auth token")
+	assert.NotEmptyf(t, tokens.RefreshToken, "should issue a refresh token")
+
+	_, err = linker.VerifyMagicLink(ctx, token)
+	assert.ErrorIsf(t, err, ErrInvalidMagicLink, "should not use a link twice")
+
+	sent.Reset()
+	err = linker.RequestMagicLink(ctx, optedIn.Email)
+	assert.Nilf(t, err, "should not fail to request another magic link: %v", err)
+	match = magicLinkRegex.FindStringSubmatch(sent.String())
+	assert.Lenf(t, match, 2, "email should contain the magic link, got: %s", sent.String())
+
+	optedIn.MagicLinkEnabled = false
+	_, err = linker.VerifyMagicLink(ctx, match[1])
+	assert.ErrorIsf(t, err, ErrInvalidMagicLink, "should not log in a user who turned magic links off after the link was sent")
+}
+
+func TestMagicLinker_MFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	user := &users.User{ID: uuid.New(), Username: "testuser", Email: "test@example.com", EmailVerified: true, MagicLinkEnabled: true}
+	userReader := newTestUserReader(ctrl, map[records.UserId]*users.User{user.ID: user})
+
+	mfaReader := mfamocks.NewMockReader(ctrl)
+	mfaReader.EXPECT().GetMFA(gomock.Any(), user.ID).AnyTimes().Return(&mfa.MFA{UserID: user.ID, Confirmed: true}, nil)
+	authenticator := newTestAuthenticatorWith(t, sessionmocks.ReturnSessions(ctrl), sessionmocks.AcceptSessions(ctrl), mfaReader, mfamocks.NewMockWriter(ctrl), UnverifiedLoginAllowed)
+
+	var sent bytes.Buffer
+	linker := NewMagicLinkerV1(&factory.MemCacheFactory{}, userReader, authenticator, mail.NewFileMailer(&sent), "https://example.com/magic", MagicLinkOptIn)
+
+	err := linker.RequestMagicLink(ctx, user.Email)
+	assert.Nilf(t, err, "should not fail to request a magic link: %v", err)
+	match := magicLinkRegex.FindStringSubmatch(sent.String())
+	assert.Lenf(t, match, 2, "email should contain the magic link, got: %s", sent.String())
+
+	tokens, err := linker.VerifyMagicLink(ctx, match[1])
+	assert.Nilf(t, err, "should accept the magic link: %v", err)
+	assert.Truef(t, tokens.MFARequired, "should ask for the second factor of users with mfa enabled")
+	assert.NotEmptyf(t, tokens.MFAToken, "should return an mfa token")
+	assert.Emptyf(t, tokens.AuthToken, "should not issue an auth token before the second factor")
+	assert.Emptyf(t, tokens.RefreshToken, "should not issue a refresh token before the second factor")
+}
+
+func TestMagicLinker_RateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	user := &users.User{ID: uuid.New(), Username: "testuser", Email: "test@example.com", EmailVerified: true}
+	userReader := newTestUserReader(ctrl, map[records.UserId]*users.User{user.ID: user})
+
+	var sent bytes.Buffer
+	linker := NewMagicLinkerV1(&factory.MemCacheFactory{}, userReader, newTestAuthenticator(t), mail.NewFileMailer(&sent), "https://example.com/magic", MagicLinkAll)
+
+	for i := 0; i < magicLinkMaxRequests; i++ {
+		err := linker.RequestMagicLink(ctx, user.Email)
+		assert.Nilf(t, err, "should not fail to request a magic link: %v", err)
+	}
+	assert.Lenf(t, magicLinkRegex.FindAllString(sent.String(), -1), magicLinkMaxRequests, "should email every user when the policy allows all")
+
+	err := linker.RequestMagicLink(ctx, "TEST@example.com")
+	assert.ErrorIsf(t, err, ErrMagicLinkRateLimited, "should rate limit the address regardless of case")
+
+	var limitedErr *MagicLinkRateLimitedError
+	assert.ErrorAsf(t, err, &limitedErr, "should say when to retry")
+	assert.Greaterf(t, limitedErr.RetryAfter, time.Duration(0), "should retry later")
+
+	err = linker.RequestMagicLink(ctx, "other@example.com")
+	assert.Nilf(t, err, "should rate limit every address on its own: %v", err)
+}
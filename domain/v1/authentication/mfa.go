@@ -0,0 +1,339 @@
+package authentication
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-cache/cache"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrMFAFailed         error = errors.New("mfa verification failed")
+	ErrMFAAlreadyEnabled error = errors.New("mfa already enabled")
+	ErrMFANotEnrolled    error = errors.New("mfa not enrolled")
+)
+
+const (
+	// mfaPendingTTL is how long a user has to finish logging in with their second factor
+	mfaPendingTTL = 5 * time.Minute
+	// maxMFAAttempts is how many codes can be tried with one mfa token
+	maxMFAAttempts = 5
+	// maxMFAFailures is how many wrong codes a user can enter within mfaFailureWindow, over all of their mfa tokens
+	maxMFAFailures    = 10
+	mfaFailureWindow  = 15 * time.Minute
+	recoveryCodeCount = 10
+)
+
+var recoveryCodeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// MFAEnrollment is the secret a user adds to their authenticator app, it is not enabled until confirmed with a code
+type MFAEnrollment struct {
+	Secret string
+	URI    string
+}
+
+// pendingMFA is a login that passed the challenge and is waiting for the second factor
+type pendingMFA struct {
+	UserID   records.UserId
+	Login    Login
+	Attempts int
+}
+
+// mfaFailures counts the wrong codes a user entered since WindowStart
+type mfaFailures struct {
+	Count       int
+	WindowStart time.Time
+}
+
+func pendingMFAKey(mfaToken string) string {
+	sum := sha256.Sum256([]byte(mfaToken))
+	return hex.EncodeToString(sum[:])
+}
+
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+func generateRecoveryCodes() ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		var b [7]byte
+		_, err := rand.Read(b[:])
+		if err != nil {
+			return nil, err
+		}
+
+		code := recoveryCodeEncoding.EncodeToString(b[:])[:10]
+		codes = append(codes, code[:5]+"-"+code[5:])
+	}
+
+	return codes, nil
+}
+
+// mfaEnabled returns true when the user has confirmed MFA
+func (a *AuthenticatorV1) mfaEnabled(ctx context.Context, userId records.UserId) (bool, error) {
+	enrollment, err := a.mfaReader.GetMFA(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+
+	return enrollment != nil && enrollment.Confirmed, nil
+}
+
+// requireMFA parks a login that passed the challenge until the user provides their second factor
+func (a *AuthenticatorV1) requireMFA(ctx context.Context, userId records.UserId, login Login) (*TokenResponse, error) {
+	var b [32]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return nil, ErrInternal
+	}
+
+	mfaToken := base64.RawURLEncoding.EncodeToString(b[:])
+	err = a.mfaPending.Set(ctx, pendingMFAKey(mfaToken), pendingMFA{UserID: userId, Login: login})
+	if err != nil {
+		l.Error("failed to store pending mfa login", zap.String("user_id", userId.String()), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	return &TokenResponse{
+		UserId:      userId,
+		MFARequired: true,
+		MFAToken:    mfaToken,
+	}, nil
+}
+
+// EnrollMFA generates a new TOTP secret for the user, enrolling again before confirming replaces the secret
+func (a *AuthenticatorV1) EnrollMFA(ctx context.Context, userId records.UserId, accountName string) (*MFAEnrollment, error) {
+	l := l.With(zap.String("user_id", userId.String()))
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		l.Error("failed to generate totp secret", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	enrolled, err := a.mfaWriter.EnrollMFA(ctx, userId, secret)
+	if err != nil {
+		l.Error("failed to enroll mfa", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	if !enrolled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	return &MFAEnrollment{
+		Secret: totpEncoding.EncodeToString(secret),
+		URI:    totpURI(a.authorizationIssuer.GetIssuer(), accountName, secret),
+	}, nil
+}
+
+// ConfirmMFA enables MFA once the user proves their authenticator app generates valid codes.
+// Returns the recovery codes, they are only stored hashed so this is the only time they are available
+func (a *AuthenticatorV1) ConfirmMFA(ctx context.Context, userId records.UserId, code string) ([]string, error) {
+	l := l.With(zap.String("user_id", userId.String()))
+
+	enrollment, err := a.mfaReader.GetMFA(ctx, userId)
+	if err != nil {
+		l.Error("failed to get mfa enrollment", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	if enrollment == nil {
+		return nil, ErrMFANotEnrolled
+	}
+
+	if enrollment.Confirmed {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	step, ok := matchTOTP(enrollment.Secret, code, time.Now())
+	if !ok {
+		return nil, ErrMFAFailed
+	}
+
+	used, err := a.mfaWriter.UseStep(ctx, userId, step)
+	if err != nil {
+		l.Error("failed to use totp step", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	if !used {
+		return nil, ErrMFAFailed
+	}
+
+	codes, err := generateRecoveryCodes()
+	if err != nil {
+		l.Error("failed to generate recovery codes", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	err = a.mfaWriter.ConfirmMFA(ctx, userId, hashes)
+	if err != nil {
+		l.Error("failed to confirm mfa", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	l.Info("enabled mfa")
+	return codes, nil
+}
+
+// VerifyMFA finishes a login that required MFA, the code is either a TOTP code or an unused recovery code.
+// An AccountLockedError is returned once the user entered too many wrong codes
+func (a *AuthenticatorV1) VerifyMFA(ctx context.Context, mfaToken string, code string) (*TokenResponse, error) {
+	if mfaToken == "" || len(mfaToken) > 1024 {
+		return nil, ErrMFAFailed
+	}
+
+	key := pendingMFAKey(mfaToken)
+
+	var pending pendingMFA
+	err := a.mfaPending.Update(ctx, key, func(load func(target any) error) (any, error) {
+		if err := load(&pending); err != nil {
+			return nil, err
+		}
+
+		pending.Attempts++
+		return pending, nil
+	})
+	if err != nil {
+		if cache.IsCacheMissErr(err) {
+			return nil, ErrMFAFailed
+		}
+
+		l.Error("failed to get pending mfa login", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	l := l.With(zap.String("user_id", pending.UserID.String()))
+
+	if pending.Attempts > maxMFAAttempts {
+		l.Warn("too many mfa attempts")
+		a.deletePendingMFA(ctx, key)
+		return nil, ErrMFAFailed
+	}
+
+	err = a.checkMFALockout(ctx, pending.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := a.verifyMFACode(ctx, pending.UserID, code)
+	if err != nil {
+		l.Error("failed to verify mfa code", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	if !ok {
+		a.recordMFAFailure(ctx, pending.UserID)
+		return nil, ErrMFAFailed
+	}
+
+	a.deletePendingMFA(ctx, key)
+	a.clearMFAFailures(ctx, pending.UserID)
+
+	login := pending.Login
+	login.Methods = append(login.Methods, AuthMethodOTP, AuthMethodMFA)
+	return a.startSession(ctx, pending.UserID, login)
+}
+
+// checkMFALockout returns an AccountLockedError once the user entered too many wrong codes. The failures are counted
+// per user rather than per mfa token, so passing the first factor again does not give more tries
+func (a *AuthenticatorV1) checkMFALockout(ctx context.Context, userId records.UserId) error {
+	var failures mfaFailures
+	err := a.mfaFailures.Get(ctx, userId.String(), &failures)
+	if err != nil {
+		if cache.IsCacheMissErr(err) {
+			return nil
+		}
+
+		l.Error("failed to get mfa failures", zap.String("user_id", userId.String()), zap.Error(err))
+		return ErrInternal
+	}
+
+	retryAfter := time.Until(failures.WindowStart.Add(mfaFailureWindow))
+	if failures.Count >= maxMFAFailures && retryAfter > 0 {
+		l.Warn("user is locked out of mfa", zap.String("user_id", userId.String()), zap.Int("failures", failures.Count), zap.Duration("retry_after", retryAfter))
+		return &AccountLockedError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+// recordMFAFailure counts a wrong code against the user, failing to record is logged but not fatal
+func (a *AuthenticatorV1) recordMFAFailure(ctx context.Context, userId records.UserId) {
+	now := time.Now()
+	err := a.mfaFailures.Update(ctx, userId.String(), func(load func(target any) error) (any, error) {
+		var failures mfaFailures
+		if err := load(&failures); err != nil {
+			return nil, err
+		}
+
+		if now.Sub(failures.WindowStart) >= mfaFailureWindow {
+			failures = mfaFailures{WindowStart: now}
+		}
+
+		failures.Count++
+		return failures, nil
+	})
+	if cache.IsCacheMissErr(err) {
+		err = a.mfaFailures.Set(ctx, userId.String(), mfaFailures{Count: 1, WindowStart: now})
+	}
+	if err != nil {
+		l.Error("failed to record mfa failure", zap.String("user_id", userId.String()), zap.Error(err))
+	}
+}
+
+// clearMFAFailures forgets the wrong codes of a user who provided their second factor
+func (a *AuthenticatorV1) clearMFAFailures(ctx context.Context, userId records.UserId) {
+	err := a.mfaFailures.Delete(ctx, userId.String())
+	if err != nil && !cache.IsCacheMissErr(err) {
+		l.Error("failed to clear mfa failures", zap.String("user_id", userId.String()), zap.Error(err))
+	}
+}
+
+func (a *AuthenticatorV1) deletePendingMFA(ctx context.Context, key string) {
+	err := a.mfaPending.Delete(ctx, key)
+	if err != nil && !cache.IsCacheMissErr(err) {
+		l.Error("failed to delete pending mfa login", zap.Error(err))
+	}
+}
+
+// verifyMFACode checks a TOTP code or recovery code, each code can only be used once
+func (a *AuthenticatorV1) verifyMFACode(ctx context.Context, userId records.UserId, code string) (bool, error) {
+	enrollment, err := a.mfaReader.GetMFA(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+
+	if enrollment == nil || !enrollment.Confirmed {
+		return false, nil
+	}
+
+	if len(code) == totpDigits {
+		step, ok := matchTOTP(enrollment.Secret, code, time.Now())
+		if !ok {
+			return false, nil
+		}
+
+		return a.mfaWriter.UseStep(ctx, userId, step)
+	}
+
+	return a.mfaWriter.UseRecoveryCode(ctx, userId, hashRecoveryCode(code))
+}
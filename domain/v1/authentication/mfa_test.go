@@ -0,0 +1,166 @@
+package authentication
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/mfa"
+	mfamocks "github.com/ooqls/go-auth/records/v1/mfa/mocks"
+	sessionmocks "github.com/ooqls/go-auth/records/v1/sessions/mocks"
+	"github.com/ooqls/go-auth/records/v1/users"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticator_MFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	privateKey, err := GenerateKey(SupportedAlgorithmEd25519)
+	assert.Nilf(t, err, "should not fail to generate a key: %v", err)
+
+	publicKey, err := PublicKey(SupportedAlgorithmEd25519, []byte(privateKey))
+	assert.Nilf(t, err, "should not fail to get the public key: %v", err)
+
+	user := &users.User{ID: uuid.New(), Username: "test", Key: publicKey, Algorithm: string(SupportedAlgorithmEd25519)}
+
+	// the mocks keep the enrollment state like the database would
+	enrollment := mfa.MFA{UserID: user.ID}
+	recoveryCodes := map[string]bool{}
+
+	mfaReader := mfamocks.NewMockReader(ctrl)
+	mfaReader.EXPECT().GetMFA(gomock.Any(), user.ID).AnyTimes().DoAndReturn(
+		func(context.Context, records.UserId) (*mfa.MFA, error) {
+			if enrollment.Secret == nil {
+				return nil, nil
+			}
+
+			e := enrollment
+			return &e, nil
+		})
+
+	mfaWriter := mfamocks.NewMockWriter(ctrl)
+	mfaWriter.EXPECT().EnrollMFA(gomock.Any(), user.ID, gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, _ records.UserId, secret []byte) (bool, error) {
+			if enrollment.Confirmed {
+				return false, nil
+			}
+
+			enrollment.Secret = secret
+			return true, nil
+		})
+	mfaWriter.EXPECT().UseStep(gomock.Any(), user.ID, gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, _ records.UserId, step int64) (bool, error) {
+			if step <= enrollment.LastUsedStep {
+				return false, nil
+			}
+
+			enrollment.LastUsedStep = step
+			return true, nil
+		})
+	mfaWriter.EXPECT().ConfirmMFA(gomock.Any(), user.ID, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ records.UserId, hashes []string) error {
+			enrollment.Confirmed = true
+			for _, hash := range hashes {
+				recoveryCodes[hash] = true
+			}
+
+			return nil
+		})
+	mfaWriter.EXPECT().UseRecoveryCode(gomock.Any(), user.ID, gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, _ records.UserId, hash string) (bool, error) {
+			unused := recoveryCodes[hash]
+			recoveryCodes[hash] = false
+			return unused, nil
+		})
+
+	authenticator := newTestAuthenticatorWith(t, sessionmocks.ReturnSessions(ctrl), sessionmocks.AcceptSessions(ctrl), mfaReader, mfaWriter, UnverifiedLoginAllowed)
+
+	login := func() *TokenResponse {
+		challenge, err := authenticator.ChallengeRequest(ctx, user)
+		assert.Nilf(t, err, "ChallengeRequest should not return an error: %v", err)
+
+		solved, err := Sign(SupportedAlgorithmEd25519, []byte(privateKey), challenge.Challenge)
+		assert.Nilf(t, err, "should not fail to sign the challenge: %v", err)
+
+		resp, err := authenticator.ChallengeResponse(ctx, challenge.ID, solved)
+		assert.Nilf(t, err, "ChallengeResponse should not return an error: %v", err)
+		return resp
+	}
+
+	resp := login()
+	assert.Falsef(t, resp.MFARequired, "mfa should not be required before it is confirmed")
+
+	enrolled, err := authenticator.EnrollMFA(ctx, user.ID, user.Username)
+	assert.Nilf(t, err, "EnrollMFA should not return an error: %v", err)
+	assert.Containsf(t, enrolled.URI, "otpauth://totp/test:test?", "the uri should name the issuer and account")
+	assert.Containsf(t, enrolled.URI, "secret="+enrolled.Secret, "the uri should contain the secret")
+
+	_, err = authenticator.ConfirmMFA(ctx, user.ID, "000000")
+	assert.ErrorIsf(t, err, ErrMFAFailed, "a wrong code should not confirm mfa")
+
+	now := time.Now()
+	codes, err := authenticator.ConfirmMFA(ctx, user.ID, totpCode(enrollment.Secret, totpStep(now)-1))
+	assert.Nilf(t, err, "ConfirmMFA should not return an error: %v", err)
+	assert.Lenf(t, codes, recoveryCodeCount, "should return the recovery codes")
+
+	_, err = authenticator.EnrollMFA(ctx, user.ID, user.Username)
+	assert.ErrorIsf(t, err, ErrMFAAlreadyEnabled, "should not enroll again once confirmed")
+
+	resp = login()
+	assert.Truef(t, resp.MFARequired, "mfa should be required once confirmed")
+	assert.Emptyf(t, resp.AuthToken, "no auth token should be issued before mfa")
+	assert.Emptyf(t, resp.RefreshToken, "no refresh token should be issued before mfa")
+
+	_, err = authenticator.VerifyMFA(ctx, resp.MFAToken, "not-a-code")
+	assert.ErrorIsf(t, err, ErrMFAFailed, "a wrong code should fail")
+
+	tokens, err := authenticator.VerifyMFA(ctx, resp.MFAToken, totpCode(enrollment.Secret, totpStep(now)))
+	assert.Nilf(t, err, "VerifyMFA should not return an error: %v", err)
+	assert.NotEmptyf(t, tokens.AuthToken, "auth token should be issued after mfa")
+
+	_, err = authenticator.IsAuthenticated(ctx, tokens.AuthToken)
+	assert.Nilf(t, err, "auth token should be valid: %v", err)
+
+	_, err = authenticator.VerifyMFA(ctx, resp.MFAToken, totpCode(enrollment.Secret, totpStep(now)+1))
+	assert.ErrorIsf(t, err, ErrMFAFailed, "an mfa token should only be used once")
+
+	resp = login()
+	_, err = authenticator.VerifyMFA(ctx, resp.MFAToken, totpCode(enrollment.Secret, totpStep(now)))
+	assert.ErrorIsf(t, err, ErrMFAFailed, "a totp code should only be used once")
+
+	_, err = authenticator.VerifyMFA(ctx, resp.MFAToken, codes[0])
+	assert.Nilf(t, err, "a recovery code should be accepted: %v", err)
+
+	resp = login()
+	_, err = authenticator.VerifyMFA(ctx, resp.MFAToken, codes[0])
+	assert.ErrorIsf(t, err, ErrMFAFailed, "a recovery code should only be used once")
+
+	for i := 1; i < maxMFAAttempts; i++ {
+		_, err = authenticator.VerifyMFA(ctx, resp.MFAToken, "wrong")
+		assert.ErrorIsf(t, err, ErrMFAFailed, "a wrong code should fail")
+	}
+
+	_, err = authenticator.VerifyMFA(ctx, resp.MFAToken, codes[1])
+	assert.ErrorIsf(t, err, ErrMFAFailed, "the mfa token should be invalidated after too many attempts")
+
+	// wrong codes of every mfa token count towards the same lock, passing the challenge again gives no more tries
+	for failures := maxMFAAttempts; failures < maxMFAFailures; failures++ {
+		resp = login()
+		_, err = authenticator.VerifyMFA(ctx, resp.MFAToken, "wrong")
+		assert.ErrorIsf(t, err, ErrMFAFailed, "a wrong code should fail")
+	}
+
+	resp = login()
+	_, err = authenticator.VerifyMFA(ctx, resp.MFAToken, codes[1])
+	assert.ErrorIsf(t, err, ErrAccountLocked, "the user should be locked out of mfa after too many wrong codes")
+
+	var lockedErr *AccountLockedError
+	assert.ErrorAsf(t, err, &lockedErr, "should say when to retry")
+	assert.Greaterf(t, lockedErr.RetryAfter, time.Duration(0), "should retry later")
+}
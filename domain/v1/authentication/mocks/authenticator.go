@@ -0,0 +1,51 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/ooqls/go-auth/domain/v1/authentication"
+	attemptmocks "github.com/ooqls/go-auth/records/v1/challengeattempts/mocks"
+	"github.com/ooqls/go-auth/records/v1/mfa"
+	mfamocks "github.com/ooqls/go-auth/records/v1/mfa/mocks"
+	sessionmocks "github.com/ooqls/go-auth/records/v1/sessions/mocks"
+	"github.com/ooqls/go-cache/factory"
+	"github.com/ooqls/go-cache/store"
+	"github.com/ooqls/go-crypto/jwt"
+	"github.com/ooqls/go-crypto/keys"
+)
+
+// NewTestAuthenticator returns an in memory authenticator signing auth and refresh tokens for the audience "test"
+// with a new key. Sessions are accepted, users are not enrolled in mfa when mfaReader is nil and personal
+// access tokens are refused when accessTokens is nil
+func NewTestAuthenticator(ctrl *gomock.Controller, accessTokens authentication.AccessTokenVerifier, mfaReader mfa.Reader) authentication.Authenticator {
+	rKey, err := keys.NewRSA()
+	if err != nil {
+		ctrl.T.Fatalf("failed to create new key: %v", err)
+	}
+	jwtKey := keys.NewJWTKey(*rKey)
+	issuer := jwt.NewJwtTokenIssuer[authentication.UserClaims](&jwt.TokenConfiguration{
+		Issuer:                  "test",
+		Audience:                []string{"test"},
+		ValidityDurationSeconds: 300,
+	}, jwtKey)
+
+	if mfaReader == nil {
+		mfaReader = mfamocks.NotEnrolled(ctrl)
+	}
+
+	return authentication.NewAuthenticatorV1(
+		issuer,
+		issuer,
+		&factory.MemCacheFactory{},
+		authentication.NewChallengerV1(store.NewMemStore("test", time.Second*10), attemptmocks.NoFailedAttempts(ctrl), attemptmocks.NoopWriter(ctrl), authentication.DefaultLockoutPolicy()),
+		sessionmocks.ReturnSessions(ctrl),
+		sessionmocks.AcceptSessions(ctrl),
+		mfaReader,
+		mfamocks.NewMockWriter(ctrl),
+		authentication.UnverifiedLoginAllowed,
+		accessTokens,
+		nil,
+		[]string{"test"},
+	)
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: access_tokens.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	authentication "github.com/ooqls/go-auth/domain/v1/authentication"
+)
+
+// MockAccessTokenVerifier is a mock of AccessTokenVerifier interface.
+type MockAccessTokenVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockAccessTokenVerifierMockRecorder
+}
+
+// MockAccessTokenVerifierMockRecorder is the mock recorder for MockAccessTokenVerifier.
+type MockAccessTokenVerifierMockRecorder struct {
+	mock *MockAccessTokenVerifier
+}
+
+// NewMockAccessTokenVerifier creates a new mock instance.
+func NewMockAccessTokenVerifier(ctrl *gomock.Controller) *MockAccessTokenVerifier {
+	mock := &MockAccessTokenVerifier{ctrl: ctrl}
+	mock.recorder = &MockAccessTokenVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAccessTokenVerifier) EXPECT() *MockAccessTokenVerifierMockRecorder {
+	return m.recorder
+}
+
+// VerifyAccessToken mocks base method.
+func (m *MockAccessTokenVerifier) VerifyAccessToken(ctx context.Context, token string) (*authentication.UserClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAccessToken", ctx, token)
+	ret0, _ := ret[0].(*authentication.UserClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyAccessToken indicates an expected call of VerifyAccessToken.
+func (mr *MockAccessTokenVerifierMockRecorder) VerifyAccessToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAccessToken", reflect.TypeOf((*MockAccessTokenVerifier)(nil).VerifyAccessToken), ctx, token)
+}
@@ -68,16 +68,16 @@ func (mr *MockChallengerMockRecorder) VerifyChallenge(ctx, challengeId, solvedCh
 }
 
 // VerifyRegistration mocks base method.
-func (m *MockChallenger) VerifyRegistration(ctx context.Context, username string, secret, key []byte) ([16]byte, error) {
+func (m *MockChallenger) VerifyRegistration(ctx context.Context, reg authentication.Registration) ([16]byte, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "VerifyRegistration", ctx, username, secret, key)
+	ret := m.ctrl.Call(m, "VerifyRegistration", ctx, reg)
 	ret0, _ := ret[0].([16]byte)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // VerifyRegistration indicates an expected call of VerifyRegistration.
-func (mr *MockChallengerMockRecorder) VerifyRegistration(ctx, username, secret, key interface{}) *gomock.Call {
+func (mr *MockChallengerMockRecorder) VerifyRegistration(ctx, reg interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyRegistration", reflect.TypeOf((*MockChallenger)(nil).VerifyRegistration), ctx, username, secret, key)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyRegistration", reflect.TypeOf((*MockChallenger)(nil).VerifyRegistration), ctx, reg)
 }
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: magic_link.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	authentication "github.com/ooqls/go-auth/domain/v1/authentication"
+)
+
+// MockMagicLinker is a mock of MagicLinker interface.
+type MockMagicLinker struct {
+	ctrl     *gomock.Controller
+	recorder *MockMagicLinkerMockRecorder
+}
+
+// MockMagicLinkerMockRecorder is the mock recorder for MockMagicLinker.
+type MockMagicLinkerMockRecorder struct {
+	mock *MockMagicLinker
+}
+
+// NewMockMagicLinker creates a new mock instance.
+func NewMockMagicLinker(ctrl *gomock.Controller) *MockMagicLinker {
+	mock := &MockMagicLinker{ctrl: ctrl}
+	mock.recorder = &MockMagicLinkerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMagicLinker) EXPECT() *MockMagicLinkerMockRecorder {
+	return m.recorder
+}
+
+// RequestMagicLink mocks base method.
+func (m *MockMagicLinker) RequestMagicLink(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestMagicLink", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestMagicLink indicates an expected call of RequestMagicLink.
+func (mr *MockMagicLinkerMockRecorder) RequestMagicLink(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestMagicLink", reflect.TypeOf((*MockMagicLinker)(nil).RequestMagicLink), ctx, email)
+}
+
+// VerifyMagicLink mocks base method.
+func (m *MockMagicLinker) VerifyMagicLink(ctx context.Context, token string) (*authentication.TokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyMagicLink", ctx, token)
+	ret0, _ := ret[0].(*authentication.TokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyMagicLink indicates an expected call of VerifyMagicLink.
+func (mr *MockMagicLinkerMockRecorder) VerifyMagicLink(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyMagicLink", reflect.TypeOf((*MockMagicLinker)(nil).VerifyMagicLink), ctx, token)
+}
@@ -0,0 +1,69 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: webauthn.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	authentication "github.com/ooqls/go-auth/domain/v1/authentication"
+	passkeys "github.com/ooqls/go-auth/records/v1/passkeys"
+	users "github.com/ooqls/go-auth/records/v1/users"
+)
+
+// MockPasskeyRegistrar is a mock of PasskeyRegistrar interface.
+type MockPasskeyRegistrar struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasskeyRegistrarMockRecorder
+}
+
+// MockPasskeyRegistrarMockRecorder is the mock recorder for MockPasskeyRegistrar.
+type MockPasskeyRegistrarMockRecorder struct {
+	mock *MockPasskeyRegistrar
+}
+
+// NewMockPasskeyRegistrar creates a new mock instance.
+func NewMockPasskeyRegistrar(ctrl *gomock.Controller) *MockPasskeyRegistrar {
+	mock := &MockPasskeyRegistrar{ctrl: ctrl}
+	mock.recorder = &MockPasskeyRegistrarMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasskeyRegistrar) EXPECT() *MockPasskeyRegistrarMockRecorder {
+	return m.recorder
+}
+
+// BeginPasskeyRegistration mocks base method.
+func (m *MockPasskeyRegistrar) BeginPasskeyRegistration(ctx context.Context, user *users.User) (*authentication.Challenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginPasskeyRegistration", ctx, user)
+	ret0, _ := ret[0].(*authentication.Challenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginPasskeyRegistration indicates an expected call of BeginPasskeyRegistration.
+func (mr *MockPasskeyRegistrarMockRecorder) BeginPasskeyRegistration(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginPasskeyRegistration", reflect.TypeOf((*MockPasskeyRegistrar)(nil).BeginPasskeyRegistration), ctx, user)
+}
+
+// FinishPasskeyRegistration mocks base method.
+func (m *MockPasskeyRegistrar) FinishPasskeyRegistration(ctx context.Context, user *users.User, challengeId uuid.UUID, response []byte) (*passkeys.Credential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishPasskeyRegistration", ctx, user, challengeId, response)
+	ret0, _ := ret[0].(*passkeys.Credential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishPasskeyRegistration indicates an expected call of FinishPasskeyRegistration.
+func (mr *MockPasskeyRegistrarMockRecorder) FinishPasskeyRegistration(ctx, user, challengeId, response interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishPasskeyRegistration", reflect.TypeOf((*MockPasskeyRegistrar)(nil).FinishPasskeyRegistration), ctx, user, challengeId, response)
+}
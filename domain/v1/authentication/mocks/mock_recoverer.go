@@ -0,0 +1,68 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: recovery.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	authentication "github.com/ooqls/go-auth/domain/v1/authentication"
+	records "github.com/ooqls/go-auth/records"
+	users "github.com/ooqls/go-auth/records/v1/users"
+)
+
+// MockRecoverer is a mock of Recoverer interface.
+type MockRecoverer struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecovererMockRecorder
+}
+
+// MockRecovererMockRecorder is the mock recorder for MockRecoverer.
+type MockRecovererMockRecorder struct {
+	mock *MockRecoverer
+}
+
+// NewMockRecoverer creates a new mock instance.
+func NewMockRecoverer(ctrl *gomock.Controller) *MockRecoverer {
+	mock := &MockRecoverer{ctrl: ctrl}
+	mock.recorder = &MockRecovererMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecoverer) EXPECT() *MockRecovererMockRecorder {
+	return m.recorder
+}
+
+// CompleteRecovery mocks base method.
+func (m *MockRecoverer) CompleteRecovery(ctx context.Context, token string, reg authentication.Registration) (records.UserId, *authentication.NewCredentials, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteRecovery", ctx, token, reg)
+	ret0, _ := ret[0].(records.UserId)
+	ret1, _ := ret[1].(*authentication.NewCredentials)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CompleteRecovery indicates an expected call of CompleteRecovery.
+func (mr *MockRecovererMockRecorder) CompleteRecovery(ctx, token, reg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteRecovery", reflect.TypeOf((*MockRecoverer)(nil).CompleteRecovery), ctx, token, reg)
+}
+
+// RequestRecovery mocks base method.
+func (m *MockRecoverer) RequestRecovery(ctx context.Context, user *users.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestRecovery", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestRecovery indicates an expected call of RequestRecovery.
+func (mr *MockRecovererMockRecorder) RequestRecovery(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestRecovery", reflect.TypeOf((*MockRecoverer)(nil).RequestRecovery), ctx, user)
+}
@@ -0,0 +1,216 @@
+package authentication
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ooqls/go-auth/domain/v1/mail"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/users"
+	"github.com/ooqls/go-cache/cache"
+	"github.com/ooqls/go-cache/factory"
+	"github.com/ooqls/go-cache/store"
+	"go.uber.org/zap"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=recovery.go -destination=mocks/mock_recoverer.go -package=mocks
+
+var (
+	ErrRecoveryFailed      error = errors.New("account recovery failed")
+	ErrNoEmail             error = errors.New("user has no email")
+	ErrRecoveryRateLimited error = errors.New("too many recovery requests")
+)
+
+const (
+	// recoveryTokenTTL is how long an emailed recovery token can be used
+	recoveryTokenTTL = 30 * time.Minute
+	// recoveryMaxRequests is how many recovery emails an address can be sent within recoveryRequestWindow
+	recoveryMaxRequests   = 3
+	recoveryRequestWindow = 15 * time.Minute
+)
+
+// Recoverer lets users who lost their key register a new one after proving they own their email
+type Recoverer interface {
+	RequestRecovery(ctx context.Context, user *users.User) error
+	CompleteRecovery(ctx context.Context, token string, reg Registration) (records.UserId, *NewCredentials, error)
+}
+
+// pendingRecovery is a recovery token that was emailed to the user, only its hash is stored
+type pendingRecovery struct {
+	UserID    records.UserId
+	Username  string
+	ExpiresAt time.Time
+	Used      bool
+}
+
+// recoveryRequests counts the recovery emails an address was sent since WindowStart
+type recoveryRequests struct {
+	Count       int
+	WindowStart time.Time
+}
+
+func recoveryTokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+var _ Recoverer = &RecovererV1{}
+
+type RecovererV1 struct {
+	tokens     store.GenericInterface
+	requests   store.GenericInterface
+	challenger Challenger
+	mailer     mail.Mailer
+	resetURL   string
+}
+
+// NewRecovererV1 returns a Recoverer that emails links to resetURL with the token in the "token" query parameter
+func NewRecovererV1(cacheFactory factory.CacheFactory, challenger Challenger, mailer mail.Mailer, resetURL string) Recoverer {
+	return &RecovererV1{
+		tokens:     cacheFactory.NewStore("recovery_tokens", recoveryTokenTTL),
+		requests:   cacheFactory.NewStore("recovery_requests", recoveryRequestWindow),
+		challenger: challenger,
+		mailer:     mailer,
+		resetURL:   resetURL,
+	}
+}
+
+// RequestRecovery emails the user a one-time token to register a new key with. ErrRecoveryRateLimited is returned
+// once the user's address was sent too many recovery emails
+func (r *RecovererV1) RequestRecovery(ctx context.Context, user *users.User) error {
+	l := l.With(zap.String("user_id", user.ID.String()))
+
+	if user.Email == "" {
+		return ErrNoEmail
+	}
+
+	err := r.countRequest(ctx, user.Email)
+	if err != nil {
+		return err
+	}
+
+	var b [32]byte
+	_, err = rand.Read(b[:])
+	if err != nil {
+		return ErrInternal
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b[:])
+	link, err := url.Parse(r.resetURL)
+	if err != nil {
+		l.Error("invalid reset url", zap.Error(err))
+		return ErrInternal
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	pending := pendingRecovery{UserID: user.ID, Username: user.Username, ExpiresAt: time.Now().Add(recoveryTokenTTL)}
+	err = r.tokens.Set(ctx, recoveryTokenKey(token), pending)
+	if err != nil {
+		l.Error("failed to store recovery token", zap.Error(err))
+		return ErrInternal
+	}
+
+	err = r.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to set a new password, it expires in %d minutes.\n\n%s\n\n"+
+			"If you did not ask to reset your password you can ignore this email.", user.Username, int(recoveryTokenTTL.Minutes()), link.String()),
+	})
+	if err != nil {
+		l.Error("failed to send recovery email", zap.Error(err))
+		return ErrInternal
+	}
+
+	l.Info("sent recovery email")
+	return nil
+}
+
+// countRequest counts a recovery email to the address and returns ErrRecoveryRateLimited once it was sent too many
+func (r *RecovererV1) countRequest(ctx context.Context, email string) error {
+	key := recoveryTokenKey(strings.ToLower(email))
+	now := time.Now()
+
+	var requests recoveryRequests
+	err := r.requests.Get(ctx, key, &requests)
+	if err != nil && !cache.IsCacheMissErr(err) {
+		l.Error("failed to get recovery requests", zap.Error(err))
+		return ErrInternal
+	}
+
+	if err != nil || now.Sub(requests.WindowStart) >= recoveryRequestWindow {
+		requests = recoveryRequests{WindowStart: now}
+	}
+
+	if requests.Count >= recoveryMaxRequests {
+		return ErrRecoveryRateLimited
+	}
+
+	requests.Count++
+	err = r.requests.Set(ctx, key, requests)
+	if err != nil {
+		l.Error("failed to store recovery requests", zap.Error(err))
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// CompleteRecovery checks the token and verifies the new key like a registration of the same username.
+// The token can only be used once, the caller persists the returned credentials and revokes the user's sessions
+func (r *RecovererV1) CompleteRecovery(ctx context.Context, token string, reg Registration) (records.UserId, *NewCredentials, error) {
+	if token == "" || len(token) > 1024 {
+		return records.UserId{}, nil, ErrRecoveryFailed
+	}
+
+	// the key is verified before the token is used so a rejected registration can be retried with the same token
+	salt, err := r.challenger.VerifyRegistration(ctx, reg)
+	if err != nil {
+		l.Warn("failed to verify the new credential", zap.Error(err))
+		return records.UserId{}, nil, err
+	}
+
+	var pending pendingRecovery
+	err = r.tokens.Update(ctx, recoveryTokenKey(token), func(load func(target any) error) (any, error) {
+		if err := load(&pending); err != nil {
+			return nil, err
+		}
+
+		if pending.Used || time.Now().After(pending.ExpiresAt) {
+			return nil, ErrRecoveryFailed
+		}
+
+		if reg.Username != pending.Username {
+			return nil, ErrInvalidRegistration
+		}
+
+		pending.Used = true
+		return pending, nil
+	})
+	if err != nil {
+		if cache.IsCacheMissErr(err) || errors.Is(err, ErrRecoveryFailed) {
+			return records.UserId{}, nil, ErrRecoveryFailed
+		}
+
+		if errors.Is(err, ErrInvalidRegistration) {
+			l.Warn("recovery registration is for another username", zap.String("user_id", pending.UserID.String()))
+			return records.UserId{}, nil, ErrInvalidRegistration
+		}
+
+		l.Error("failed to get recovery token", zap.Error(err))
+		return records.UserId{}, nil, ErrInternal
+	}
+
+	l.Info("recovered account", zap.String("user_id", pending.UserID.String()))
+	return pending.UserID, newCredentials(nil, reg.Key, salt, reg.Algorithm), nil
+}
@@ -0,0 +1,117 @@
+package authentication
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/domain/v1/mail"
+	attemptmocks "github.com/ooqls/go-auth/records/v1/challengeattempts/mocks"
+	"github.com/ooqls/go-auth/records/v1/users"
+	"github.com/ooqls/go-cache/factory"
+	"github.com/ooqls/go-cache/store"
+	"github.com/ooqls/go-crypto/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+var recoveryLinkRegex = regexp.MustCompile(`https://example\.com/reset\?token=([A-Za-z0-9_-]+)`)
+
+func TestRecoverer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	var sent bytes.Buffer
+	challenger := NewChallengerV1(store.NewMemStore("test", time.Second*10), attemptmocks.NoFailedAttempts(ctrl), attemptmocks.NoopWriter(ctrl), DefaultLockoutPolicy())
+	recoverer := NewRecovererV1(&factory.MemCacheFactory{}, challenger, mail.NewFileMailer(&sent), "https://example.com/reset")
+
+	err := recoverer.RequestRecovery(ctx, &users.User{ID: uuid.New(), Username: "noemail"})
+	assert.ErrorIsf(t, err, ErrNoEmail, "should not recover a user without an email")
+
+	user := &users.User{ID: uuid.New(), Username: "testuser", Email: "test@example.com"}
+	err = recoverer.RequestRecovery(ctx, user)
+	assert.Nilf(t, err, "should not fail to request recovery: %v", err)
+	assert.Containsf(t, sent.String(), "To: test@example.com", "should email the user")
+
+	match := recoveryLinkRegex.FindStringSubmatch(sent.String())
+	assert.Lenf(t, match, 2, "email should contain the reset link, got: %s", sent.String())
+	token := match[1]
+
+	salt := RegistrationSalt(user.Username)
+	key, err := crypto.DeriveAESGCMKey("new password", salt)
+	assert.Nilf(t, err, "should not fail to derive the key: %v", err)
+
+	secret, err := crypto.AESGCMEncryptWithKey(key, salt, []byte(user.Username))
+	assert.Nilf(t, err, "should not fail to encrypt the username: %v", err)
+
+	reg := Registration{Username: user.Username, Key: key, Secret: secret, Algorithm: SupportedAlgorithmAESGCM}
+
+	_, _, err = recoverer.CompleteRecovery(ctx, "not a token", reg)
+	assert.ErrorIsf(t, err, ErrRecoveryFailed, "should not accept an unknown token")
+
+	_, _, err = recoverer.CompleteRecovery(ctx, token, Registration{Username: "otheruser", Key: key, Secret: secret, Algorithm: SupportedAlgorithmAESGCM})
+	assert.ErrorIsf(t, err, ErrInvalidRegistration, "should not register a key for another username")
+
+	_, _, err = recoverer.CompleteRecovery(ctx, token, Registration{Username: user.Username, Key: key, Secret: []byte("not the username"), Algorithm: SupportedAlgorithmAESGCM})
+	assert.ErrorIsf(t, err, ErrInvalidRegistration, "should verify the new key like a registration")
+
+	userId, credentials, err := recoverer.CompleteRecovery(ctx, token, reg)
+	assert.Nilf(t, err, "should complete the recovery: %v", err)
+	assert.Equalf(t, user.ID, userId, "should recover the user the token was issued for")
+	assert.Equalf(t, key, credentials.Key, "should return the new key")
+	assert.Equalf(t, salt[:], credentials.Salt, "should return the registration salt")
+
+	_, _, err = recoverer.CompleteRecovery(ctx, token, reg)
+	assert.ErrorIsf(t, err, ErrRecoveryFailed, "should not use a token twice")
+}
+
+func TestRecoverer_Expired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	challenger := NewChallengerV1(store.NewMemStore("test", time.Second*10), attemptmocks.NoFailedAttempts(ctrl), attemptmocks.NoopWriter(ctrl), DefaultLockoutPolicy())
+	recoverer := NewRecovererV1(&factory.MemCacheFactory{}, challenger, mail.NewFileMailer(&bytes.Buffer{}), "https://example.com/reset")
+
+	user := &users.User{ID: uuid.New(), Username: "testuser", Email: "test@example.com"}
+	salt := RegistrationSalt(user.Username)
+	key, err := crypto.DeriveAESGCMKey("new password", salt)
+	assert.Nilf(t, err, "should not fail to derive the key: %v", err)
+	secret, err := crypto.AESGCMEncryptWithKey(key, salt, []byte(user.Username))
+	assert.Nilf(t, err, "should not fail to encrypt the username: %v", err)
+	reg := Registration{Username: user.Username, Key: key, Secret: secret, Algorithm: SupportedAlgorithmAESGCM}
+
+	// stores without a ttl keep the token, it must still expire
+	token := "expired"
+	err = recoverer.(*RecovererV1).tokens.Set(ctx, recoveryTokenKey(token), pendingRecovery{UserID: user.ID, Username: user.Username, ExpiresAt: time.Now().Add(-time.Second)})
+	assert.Nilf(t, err, "should store the token: %v", err)
+	_, _, err = recoverer.CompleteRecovery(ctx, token, reg)
+	assert.ErrorIsf(t, err, ErrRecoveryFailed, "should not accept an expired token")
+}
+
+func TestRecoverer_RateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	var sent bytes.Buffer
+	challenger := NewChallengerV1(store.NewMemStore("test", time.Second*10), attemptmocks.NoFailedAttempts(ctrl), attemptmocks.NoopWriter(ctrl), DefaultLockoutPolicy())
+	recoverer := NewRecovererV1(&factory.MemCacheFactory{}, challenger, mail.NewFileMailer(&sent), "https://example.com/reset")
+
+	user := &users.User{ID: uuid.New(), Username: "testuser", Email: "test@example.com"}
+	for i := 0; i < recoveryMaxRequests; i++ {
+		err := recoverer.RequestRecovery(ctx, user)
+		assert.Nilf(t, err, "should not fail to request recovery: %v", err)
+	}
+
+	err := recoverer.RequestRecovery(ctx, &users.User{ID: uuid.New(), Username: "other", Email: "TEST@example.com"})
+	assert.ErrorIsf(t, err, ErrRecoveryRateLimited, "should rate limit the address regardless of case")
+	assert.Lenf(t, recoveryLinkRegex.FindAllString(sent.String(), -1), recoveryMaxRequests, "should not email the address once it is rate limited")
+
+	err = recoverer.RequestRecovery(ctx, &users.User{ID: uuid.New(), Username: "other", Email: "other@example.com"})
+	assert.Nilf(t, err, "should rate limit every address on its own: %v", err)
+}
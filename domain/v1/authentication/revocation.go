@@ -0,0 +1,196 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-cache/cache"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrTokenRevoked error = errors.New("token revoked")
+)
+
+// revocationTTL is how long revocations are remembered, it must outlive every token we issue
+const revocationTTL = 24 * time.Hour
+
+// cachedToken is what the token cache holds for a verified token, the registered
+// claims are kept so that revocations can be checked without decrypting the token again
+type cachedToken struct {
+	Claims    UserClaims
+	TokenID   string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	// RolesEmbedded is kept apart from the claims since the cache decodes empty roles as nil
+	RolesEmbedded bool
+}
+
+func newCachedToken(claims UserClaims, regClaims *jwtv5.RegisteredClaims) cachedToken {
+	cached := cachedToken{
+		Claims:        claims,
+		TokenID:       regClaims.ID,
+		RolesEmbedded: claims.Roles != nil,
+	}
+
+	if regClaims.IssuedAt != nil {
+		cached.IssuedAt = regClaims.IssuedAt.Time
+	}
+
+	if regClaims.ExpiresAt != nil {
+		cached.ExpiresAt = regClaims.ExpiresAt.Time
+	}
+
+	return cached
+}
+
+// userClaims returns the cached claims with IssuedAt and ExpiresAt set and embedded roles told apart from missing ones
+func (t cachedToken) userClaims() *UserClaims {
+	claims := t.Claims
+	claims.IssuedAt = t.IssuedAt
+	claims.ExpiresAt = t.ExpiresAt
+	if t.RolesEmbedded && claims.Roles == nil {
+		claims.Roles = []RoleClaim{}
+	}
+
+	return &claims
+}
+
+func tokenRevocationKey(tokenID string) string {
+	return fmt.Sprintf("jti:%s", tokenID)
+}
+
+func userRevocationKey(userId records.UserId) string {
+	return fmt.Sprintf("user:%s", userId.String())
+}
+
+// Revoke adds the token to the denylist so it is no longer accepted by IsAuthenticated or Refresh.
+// Revoking a refresh token also revokes the token family it belongs to
+func (a *AuthenticatorV1) Revoke(ctx context.Context, token string) error {
+	if token == "" || len(token) > 1024 {
+		return ErrInvalidToken
+	}
+
+	jwtToken, claims, err := a.authorizationIssuer.Decrypt(token)
+	if err != nil || !jwtToken.Valid {
+		jwtToken, claims, err = a.refreshIssuer.Decrypt(token)
+		if err != nil || !jwtToken.Valid {
+			return ErrInvalidToken
+		}
+	}
+
+	regClaims, ok := registeredClaims(jwtToken)
+	if !ok || regClaims.ID == "" {
+		return ErrInvalidToken
+	}
+
+	l := l.With(zap.String("user_id", claims.UserID.String()), zap.String("token_id", regClaims.ID))
+
+	err = a.revocations.Set(ctx, tokenRevocationKey(regClaims.ID), time.Now())
+	if err != nil {
+		l.Error("failed to add token to denylist", zap.Error(err))
+		return ErrInternal
+	}
+
+	err = a.tokenCache.Delete(ctx, token)
+	if err != nil && !cache.IsCacheMissErr(err) {
+		l.Warn("failed to remove revoked token from cache", zap.Error(err))
+	}
+
+	if claims.FamilyID != uuid.Nil {
+		err = a.revokeRefreshFamily(ctx, claims.FamilyID)
+		if err != nil {
+			l.Error("failed to revoke refresh token family", zap.Error(err))
+			return ErrInternal
+		}
+
+		// the refresh token is what keeps a session alive, so revoking it ends the session
+		_, err = a.sessionWriter.DeleteSession(ctx, claims.UserID, claims.FamilyID)
+		if err != nil {
+			l.Error("failed to delete session", zap.Error(err))
+			return ErrInternal
+		}
+	}
+
+	l.Info("revoked token")
+	return nil
+}
+
+// RevokeAllForUser revokes every token issued to the user up to now and ends all of the user's sessions.
+// Token issue times have a precision of one second, so the sessions are added to the denylist as well to
+// revoke their tokens issued within the same second. Tokens without a session issued within that second
+// are refused too, they can not be told apart from the ones issued before the revocation
+func (a *AuthenticatorV1) RevokeAllForUser(ctx context.Context, userId records.UserId) error {
+	err := a.revocations.Set(ctx, userRevocationKey(userId), time.Now().Truncate(time.Second))
+	if err != nil {
+		l.Error("failed to revoke tokens for user", zap.String("user_id", userId.String()), zap.Error(err))
+		return ErrInternal
+	}
+
+	deleted, err := a.sessionWriter.DeleteOtherSessions(ctx, userId, uuid.Nil)
+	if err != nil {
+		l.Error("failed to delete sessions for user", zap.String("user_id", userId.String()), zap.Error(err))
+		return ErrInternal
+	}
+
+	for _, sessionId := range deleted {
+		err = a.revokeSession(ctx, sessionId)
+		if err != nil {
+			return err
+		}
+	}
+
+	l.Info("revoked all tokens for user", zap.String("user_id", userId.String()))
+	return nil
+}
+
+// checkNotRevoked returns ErrTokenRevoked when the token or its session is on the denylist
+// or the token was issued before all of the user's tokens were revoked
+func (a *AuthenticatorV1) checkNotRevoked(ctx context.Context, token cachedToken) error {
+	var revokedAt time.Time
+	err := a.revocations.Get(ctx, tokenRevocationKey(token.TokenID), &revokedAt)
+	if err == nil {
+		return ErrTokenRevoked
+	}
+
+	if !cache.IsCacheMissErr(err) {
+		l.Error("failed to check token denylist", zap.Error(err))
+		return ErrInternal
+	}
+
+	if token.Claims.SessionID != uuid.Nil {
+		var sessionId uuid.UUID
+		err = a.revocations.Get(ctx, sessionRevocationKey(token.Claims.SessionID), &sessionId)
+		if err == nil {
+			return ErrTokenRevoked
+		}
+
+		if !cache.IsCacheMissErr(err) {
+			l.Error("failed to check session denylist", zap.Error(err))
+			return ErrInternal
+		}
+	}
+
+	var notBefore time.Time
+	err = a.revocations.Get(ctx, userRevocationKey(token.Claims.UserID), &notBefore)
+	if err == nil {
+		// tokens of a session issued within the second of the revocation are refused by their session
+		if token.IssuedAt.Before(notBefore) || (token.Claims.SessionID == uuid.Nil && token.IssuedAt.Equal(notBefore)) {
+			return ErrTokenRevoked
+		}
+
+		return nil
+	}
+
+	if !cache.IsCacheMissErr(err) {
+		l.Error("failed to check user revocations", zap.Error(err))
+		return ErrInternal
+	}
+
+	return nil
+}
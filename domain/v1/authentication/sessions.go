@@ -0,0 +1,155 @@
+package authentication
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/sessions"
+	"github.com/ooqls/go-cache/cache"
+	"go.uber.org/zap"
+)
+
+type clientInfoKey struct{}
+
+// ClientInfo describes the device a request was made from, it is recorded on the session started by a login
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// WithClientInfo returns a context carrying the client info of the request
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoKey{}, info)
+}
+
+// ClientInfoFromContext returns the client info of the request, or an empty ClientInfo if there is none
+func ClientInfoFromContext(ctx context.Context) ClientInfo {
+	info, _ := ctx.Value(clientInfoKey{}).(ClientInfo)
+	return info
+}
+
+func sessionRevocationKey(sessionId sessions.SessionId) string {
+	return fmt.Sprintf("sid:%s", sessionId.String())
+}
+
+// startSession issues the first auth and refresh tokens of a new session and persists the session
+func (a *AuthenticatorV1) startSession(ctx context.Context, userId records.UserId, login Login) (*TokenResponse, error) {
+	sessionId := uuid.New()
+	l := l.With(zap.String("user_id", userId.String()), zap.String("session_id", sessionId.String()))
+
+	refreshToken, regClaims, err := a.issueNewRefreshToken(ctx, userId, sessionId, login)
+	if err != nil {
+		return nil, err
+	}
+
+	info := ClientInfoFromContext(ctx)
+	_, err = a.sessionWriter.CreateSession(ctx, sessions.Session{
+		ID:        sessionId,
+		UserID:    userId,
+		Token:     regClaims.ID,
+		UserAgent: info.UserAgent,
+		IpAddress: info.IPAddress,
+		ExpiresAt: regClaims.ExpiresAt.Time,
+	})
+	if err != nil {
+		l.Error("failed to create session", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	authToken, err := a.issueNewAuthToken(ctx, userId, sessionId, login)
+	if err != nil {
+		return nil, err
+	}
+
+	l.Info("started session")
+
+	return &TokenResponse{
+		AuthToken:    authToken,
+		RefreshToken: refreshToken,
+		UserId:       userId,
+	}, nil
+}
+
+// ListSessions returns the user's active sessions, most recently seen first
+func (a *AuthenticatorV1) ListSessions(ctx context.Context, userId records.UserId) ([]sessions.Session, error) {
+	userSessions, err := a.sessionReader.ListSessionsForUser(ctx, userId)
+	if err != nil {
+		l.Error("failed to list sessions", zap.String("user_id", userId.String()), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	return userSessions, nil
+}
+
+// TerminateSession ends one of the user's sessions, every token issued to the session is revoked
+func (a *AuthenticatorV1) TerminateSession(ctx context.Context, userId records.UserId, sessionId sessions.SessionId) error {
+	found, err := a.sessionWriter.DeleteSession(ctx, userId, sessionId)
+	if err != nil {
+		l.Error("failed to delete session", zap.String("session_id", sessionId.String()), zap.Error(err))
+		return ErrInternal
+	}
+
+	if !found {
+		return ErrSessionNotFound
+	}
+
+	return a.revokeSession(ctx, sessionId)
+}
+
+// TerminateOtherSessions ends every session of the user except the current one, signing out other devices
+func (a *AuthenticatorV1) TerminateOtherSessions(ctx context.Context, userId records.UserId, currentSessionId sessions.SessionId) error {
+	deleted, err := a.sessionWriter.DeleteOtherSessions(ctx, userId, currentSessionId)
+	if err != nil {
+		l.Error("failed to delete sessions", zap.String("user_id", userId.String()), zap.Error(err))
+		return ErrInternal
+	}
+
+	for _, sessionId := range deleted {
+		err = a.revokeSession(ctx, sessionId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// revokeSession denylists every token of the session and revokes its refresh token family
+func (a *AuthenticatorV1) revokeSession(ctx context.Context, sessionId sessions.SessionId) error {
+	l := l.With(zap.String("session_id", sessionId.String()))
+
+	err := a.revocations.Set(ctx, sessionRevocationKey(sessionId), sessionId)
+	if err != nil {
+		l.Error("failed to add session to denylist", zap.Error(err))
+		return ErrInternal
+	}
+
+	err = a.revokeRefreshFamily(ctx, sessionId)
+	if err != nil {
+		l.Error("failed to revoke refresh token family", zap.Error(err))
+		return ErrInternal
+	}
+
+	l.Info("terminated session")
+	return nil
+}
+
+// revokeRefreshFamily marks the refresh token family as revoked, unknown families are ignored
+func (a *AuthenticatorV1) revokeRefreshFamily(ctx context.Context, familyId uuid.UUID) error {
+	err := a.refreshFamilies.Update(ctx, familyId.String(), func(load func(target any) error) (any, error) {
+		var family refreshFamily
+		if err := load(&family); err != nil {
+			return nil, err
+		}
+
+		family.Revoked = true
+		return family, nil
+	})
+	if err != nil && !cache.IsCacheMissErr(err) {
+		return err
+	}
+
+	return nil
+}
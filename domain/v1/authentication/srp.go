@@ -0,0 +1,232 @@
+package authentication
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"math/big"
+)
+
+// SRP-6a (RFC 2945, RFC 5054) with the 2048 bit group of RFC 5054 and SHA-256.
+// Users register a verifier v = g^x where x is derived from their salt, username and password.
+// The verifier can't be used to log in, and the password never leaves the client
+
+var (
+	ErrInvalidSRPParameter error = errors.New("invalid srp parameter")
+	ErrInvalidServerProof  error = errors.New("invalid srp server proof")
+)
+
+const srpGroup2048 = "AC6BDB41324A9A9BF166DE5E1389582FAF72B6651987EE07FC3192943DB56050" +
+	"A37329CBB4A099ED8193E0757767A13DD52312AB4B03310DCD7F48A9DA04FD50" +
+	"E8083969EDB767B0CF6095179A163AB3661A05FBD5FAAAE82918A9962F0B93B8" +
+	"55F97993EC975EEAA80D740ADBF4FF747359D041D5C33EA71D281E446B14773B" +
+	"CA97B43A23FB801676BD207A436C6481F1D2B9078717461A5B9D32E688F87748" +
+	"544523B524B0D57D5EA77A2775D2ECFA032CFBDBF52FB3786160279004E57AE6" +
+	"AF874E7303CE53299CCC041C7BC308D82A5698F3A8D0C38271AE35F8E9DBFBB6" +
+	"94B5C803D89F7AE435DE236D525F54759B65E372FCD68EF20FA7111F9E4AFF73"
+
+var (
+	srpN, _ = new(big.Int).SetString(srpGroup2048, 16)
+	srpG    = big.NewInt(2)
+	srpK    = new(big.Int).SetBytes(srpHash(srpPad(srpN), srpPad(srpG)))
+)
+
+// srpAnswer is what the client answers an SRP challenge with, its ephemeral value and its proof of the session key
+type srpAnswer struct {
+	A  []byte `json:"a"`
+	M1 []byte `json:"m1"`
+}
+
+// srpPad left pads i to the length of N
+func srpPad(i *big.Int) []byte {
+	return i.FillBytes(make([]byte, (srpN.BitLen()+7)/8))
+}
+
+func srpHash(parts ...[]byte) []byte {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write(part)
+	}
+
+	return h.Sum(nil)
+}
+
+// srpX returns x = H(s | H(I | ":" | P))
+func srpX(salt []byte, username string, password string) *big.Int {
+	return new(big.Int).SetBytes(srpHash(salt, srpHash([]byte(username+":"+password))))
+}
+
+// srpU returns u = H(PAD(A) | PAD(B))
+func srpU(A *big.Int, B *big.Int) *big.Int {
+	return new(big.Int).SetBytes(srpHash(srpPad(A), srpPad(B)))
+}
+
+// srpClientProof returns M1 = H(H(N) xor H(g) | H(I) | s | PAD(A) | PAD(B) | K)
+func srpClientProof(username string, salt []byte, A *big.Int, B *big.Int, K []byte) []byte {
+	hN := srpHash(srpPad(srpN))
+	hG := srpHash(srpPad(srpG))
+	for i := range hN {
+		hN[i] ^= hG[i]
+	}
+
+	return srpHash(hN, srpHash([]byte(username)), salt, srpPad(A), srpPad(B), K)
+}
+
+// srpServerProof returns M2 = H(PAD(A) | M1 | K)
+func srpServerProof(A *big.Int, M1 []byte, K []byte) []byte {
+	return srpHash(srpPad(A), M1, K)
+}
+
+// srpValid returns true when the value is a usable group element, 0 < i mod N
+func srpValid(i *big.Int) bool {
+	return new(big.Int).Mod(i, srpN).Sign() != 0
+}
+
+func srpRandom() (*big.Int, error) {
+	buf := make([]byte, 32)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(buf), nil
+}
+
+// SRPVerifier returns the verifier v = g^x the user registers instead of a key
+func SRPVerifier(username string, password string, salt []byte) []byte {
+	x := srpX(salt, username, password)
+	return srpPad(new(big.Int).Exp(srpG, x, srpN))
+}
+
+// validateSRPVerifier checks that the verifier is an element of the group
+func validateSRPVerifier(verifier []byte) error {
+	v := new(big.Int).SetBytes(verifier)
+	if v.Cmp(big.NewInt(1)) <= 0 || v.Cmp(srpN) >= 0 {
+		return ErrInvalidKey
+	}
+
+	return nil
+}
+
+// srpServerEphemeral returns the server's private value b and public value B = kv + g^b
+func srpServerEphemeral(verifier []byte) (b []byte, B []byte, err error) {
+	v := new(big.Int).SetBytes(verifier)
+	for {
+		private, err := srpRandom()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		public := new(big.Int).Mul(srpK, v)
+		public.Add(public, new(big.Int).Exp(srpG, private, srpN))
+		public.Mod(public, srpN)
+		if srpValid(public) {
+			return private.Bytes(), srpPad(public), nil
+		}
+	}
+}
+
+// verifySRPAnswer checks the client's proof of the session key and returns the server's proof M2
+func verifySRPAnswer(username string, salt []byte, verifier []byte, b []byte, B []byte, response []byte) ([]byte, error) {
+	var answer srpAnswer
+	err := json.Unmarshal(response, &answer)
+	if err != nil {
+		return nil, ErrInvalidSRPParameter
+	}
+
+	A := new(big.Int).SetBytes(answer.A)
+	if !srpValid(A) {
+		return nil, ErrInvalidSRPParameter
+	}
+
+	publicB := new(big.Int).SetBytes(B)
+	u := srpU(A, publicB)
+	if u.Sign() == 0 {
+		return nil, ErrInvalidSRPParameter
+	}
+
+	// S = (A * v^u) ^ b
+	v := new(big.Int).SetBytes(verifier)
+	S := new(big.Int).Exp(v, u, srpN)
+	S.Mul(S, A)
+	S.Exp(S, new(big.Int).SetBytes(b), srpN)
+	K := srpHash(srpPad(S))
+
+	M1 := srpClientProof(username, salt, A, publicB, K)
+	if subtle.ConstantTimeCompare(M1, answer.M1) != 1 {
+		return nil, ErrChallengeFailed
+	}
+
+	return srpServerProof(A, M1, K), nil
+}
+
+// SRPClient runs the client side of an SRP login, the server's proof is only known once the challenge is answered
+type SRPClient struct {
+	username      string
+	password      string
+	expectedProof []byte
+}
+
+func NewSRPClient(username string, password string) *SRPClient {
+	return &SRPClient{
+		username: username,
+		password: password,
+	}
+}
+
+// Answer answers the server's challenge B with the client's ephemeral value A and the proof M1
+func (c *SRPClient) Answer(salt []byte, serverEphemeral []byte) ([]byte, error) {
+	B := new(big.Int).SetBytes(serverEphemeral)
+	if !srpValid(B) {
+		return nil, ErrInvalidSRPParameter
+	}
+
+	var a, A *big.Int
+	for {
+		var err error
+		a, err = srpRandom()
+		if err != nil {
+			return nil, err
+		}
+
+		A = new(big.Int).Exp(srpG, a, srpN)
+		if srpValid(A) {
+			break
+		}
+	}
+
+	u := srpU(A, B)
+	if u.Sign() == 0 {
+		return nil, ErrInvalidSRPParameter
+	}
+
+	// S = (B - k * g^x) ^ (a + u * x)
+	x := srpX(salt, c.username, c.password)
+	base := new(big.Int).Exp(srpG, x, srpN)
+	base.Mul(base, srpK)
+	base.Sub(B, base)
+	base.Mod(base, srpN)
+	exp := new(big.Int).Mul(u, x)
+	exp.Add(exp, a)
+	S := new(big.Int).Exp(base, exp, srpN)
+	K := srpHash(srpPad(S))
+
+	M1 := srpClientProof(c.username, salt, A, B, K)
+	c.expectedProof = srpServerProof(A, M1, K)
+
+	return json.Marshal(srpAnswer{
+		A:  srpPad(A),
+		M1: M1,
+	})
+}
+
+// VerifyServerProof checks that the server knows the verifier, so the client did not log in to an impostor
+func (c *SRPClient) VerifyServerProof(proof []byte) error {
+	if c.expectedProof == nil || subtle.ConstantTimeCompare(c.expectedProof, proof) != 1 {
+		return ErrInvalidServerProof
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package authentication
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	attemptmocks "github.com/ooqls/go-auth/records/v1/challengeattempts/mocks"
+	"github.com/ooqls/go-auth/records/v1/users"
+	"github.com/ooqls/go-cache/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChallenger_SRP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	challenger := NewChallengerV1(store.NewMemStore("test", time.Second*10), attemptmocks.NoFailedAttempts(ctrl), attemptmocks.NoopWriter(ctrl), DefaultLockoutPolicy())
+
+	salt := make([]byte, 16)
+	rand.Read(salt)
+	verifier := SRPVerifier("testuser", "password", salt)
+
+	registeredSalt, err := challenger.VerifyRegistration(ctx, Registration{Username: "testuser", Key: verifier, Secret: salt, Algorithm: SupportedAlgorithmSRP6A})
+	assert.Nilf(t, err, "should verify the registration: %v", err)
+	assert.Equalf(t, salt, registeredSalt[:], "should return the client's salt")
+
+	_, err = challenger.VerifyRegistration(ctx, Registration{Username: "testuser", Key: []byte{1}, Secret: salt, Algorithm: SupportedAlgorithmSRP6A})
+	assert.ErrorIsf(t, err, ErrInvalidRegistration, "should not accept a verifier outside of the group")
+
+	_, err = challenger.VerifyRegistration(ctx, Registration{Username: "testuser", Key: verifier, Secret: salt[:8], Algorithm: SupportedAlgorithmSRP6A})
+	assert.ErrorIsf(t, err, ErrInvalidRegistration, "should not accept a short salt")
+
+	user := &users.User{ID: uuid.New(), Username: "testuser", Key: verifier, Salt: salt, Algorithm: string(SupportedAlgorithmSRP6A)}
+
+	challenge, err := challenger.IssueChallenge(ctx, user)
+	assert.Nilf(t, err, "should not fail to issue a challenge: %v", err)
+
+	wrongClient := NewSRPClient("testuser", "wrong password")
+	answer, err := wrongClient.Answer(salt, challenge.Challenge)
+	assert.Nilf(t, err, "should not fail to answer the challenge: %v", err)
+
+	_, err = challenger.VerifyChallenge(ctx, challenge.ID, answer)
+	assert.ErrorIsf(t, err, ErrChallengeFailed, "should not verify an answer with the wrong password")
+
+	challenge, err = challenger.IssueChallenge(ctx, user)
+	assert.Nilf(t, err, "should not fail to issue a challenge: %v", err)
+
+	_, err = NewSRPClient("testuser", "password").Answer(salt, make([]byte, 256))
+	assert.ErrorIsf(t, err, ErrInvalidSRPParameter, "should not answer a zero server value")
+
+	_, err = challenger.VerifyChallenge(ctx, challenge.ID, []byte(`{"a":"AA==","m1":"AA=="}`))
+	assert.ErrorIsf(t, err, ErrChallengeFailed, "should not verify a zero client value")
+
+	challenge, err = challenger.IssueChallenge(ctx, user)
+	assert.Nilf(t, err, "should not fail to issue a challenge: %v", err)
+
+	client := NewSRPClient("testuser", "password")
+	answer, err = client.Answer(salt, challenge.Challenge)
+	assert.Nilf(t, err, "should not fail to answer the challenge: %v", err)
+
+	result, err := challenger.VerifyChallenge(ctx, challenge.ID, answer)
+	assert.Nilf(t, err, "should verify the challenge: %v", err)
+	assert.Equalf(t, user.ID, result.User.ID, "result should belong to the user")
+
+	err = client.VerifyServerProof(result.ServerProof)
+	assert.Nilf(t, err, "should verify the server's proof: %v", err)
+
+	err = wrongClient.VerifyServerProof(result.ServerProof)
+	assert.ErrorIsf(t, err, ErrInvalidServerProof, "should not verify the proof of another login")
+}
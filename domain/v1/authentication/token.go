@@ -2,10 +2,17 @@ package authentication
 
 import "github.com/ooqls/go-auth/records"
 
+// TokenResponse holds the tokens of a successful login. When the user has MFA
+// enabled the login is not finished yet, MFARequired is set and only MFAToken
+// is returned, it is exchanged for the tokens with VerifyMFA. ServerProof is
+// set for logins of SRP6A users so the client can check the server. ExpiresIn is the
+// lifetime of the auth token in seconds, it is only set for service accounts
 type TokenResponse struct {
-	AuthToken    string        `json:"auth_token"`
-	RefreshToken string        `json:"refresh_token"`
+	AuthToken    string         `json:"auth_token"`
+	RefreshToken string         `json:"refresh_token"`
 	UserId       records.UserId `json:"user_id"`
+	MFARequired  bool           `json:"mfa_required"`
+	MFAToken     string         `json:"mfa_token,omitempty"`
+	ServerProof  []byte         `json:"server_proof,omitempty"`
+	ExpiresIn    int            `json:"expires_in,omitempty"`
 }
-
-
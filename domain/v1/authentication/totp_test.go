@@ -0,0 +1,46 @@
+package authentication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTOTP_RFC6238Vectors(t *testing.T) {
+	// test vectors from RFC 6238 appendix B, truncated to 6 digits
+	secret := []byte("12345678901234567890")
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, code := range vectors {
+		assert.Equalf(t, code, totpCode(secret, totpStep(time.Unix(unix, 0))), "code for %d should match the RFC", unix)
+	}
+}
+
+func TestTOTP_Match(t *testing.T) {
+	secret, err := generateTOTPSecret()
+	assert.Nilf(t, err, "should not fail to generate a secret: %v", err)
+
+	now := time.Now()
+	step := totpStep(now)
+
+	matched, ok := matchTOTP(secret, totpCode(secret, step), now)
+	assert.Truef(t, ok, "the current code should match")
+	assert.Equalf(t, step, matched, "the current code should match the current step")
+
+	_, ok = matchTOTP(secret, totpCode(secret, step-1), now)
+	assert.Truef(t, ok, "the previous code should match to allow for clock drift")
+
+	_, ok = matchTOTP(secret, totpCode(secret, step-2), now)
+	assert.Falsef(t, ok, "older codes should not match")
+
+	_, ok = matchTOTP(secret, "12345", now)
+	assert.Falsef(t, ok, "codes of the wrong length should not match")
+}
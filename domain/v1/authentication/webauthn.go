@@ -0,0 +1,376 @@
+package authentication
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/records/v1/challengeattempts"
+	"github.com/ooqls/go-auth/records/v1/passkeys"
+	"github.com/ooqls/go-auth/records/v1/users"
+	"github.com/ooqls/go-cache/cache"
+	"github.com/ooqls/go-cache/store"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrNoPasskeys          error = errors.New("user has no passkeys")
+	ErrSignCountRegression error = errors.New("authenticator sign count regressed")
+)
+
+// WebAuthnConfig describes the relying party passkeys are bound to
+type WebAuthnConfig struct {
+	RPID          string
+	RPDisplayName string
+	RPOrigins     []string
+}
+
+// PasskeyRegistrar adds passkeys to the account of a user that is already signed in
+//
+//go:generate go run github.com/golang/mock/mockgen -source=webauthn.go -destination=mocks/mock_passkey_registrar.go -package=mocks
+type PasskeyRegistrar interface {
+	BeginPasskeyRegistration(ctx context.Context, user *users.User) (*Challenge, error)
+	FinishPasskeyRegistration(ctx context.Context, user *users.User, challengeId uuid.UUID, response []byte) (*passkeys.Credential, error)
+}
+
+// webAuthnCeremony is what is kept between the start and the end of a registration or login,
+// the challenge is handed out to the client and the session is what the response is checked against
+type webAuthnCeremony struct {
+	Challenge Challenge
+	Session   webauthn.SessionData
+}
+
+// webAuthnUser adapts a user and their stored passkeys to the webauthn library
+type webAuthnUser struct {
+	user        *users.User
+	credentials []webauthn.Credential
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte {
+	return u.user.ID[:]
+}
+
+func (u *webAuthnUser) WebAuthnName() string {
+	return u.user.Username
+}
+
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	return u.user.Username
+}
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	return u.credentials
+}
+
+// WebAuthnChallenger authenticates users with passkeys. The challenge handed out is the
+// JSON encoded options for navigator.credentials.get and the solved challenge is the
+// JSON encoded PublicKeyCredential the browser returns
+type WebAuthnChallenger struct {
+	attemptTracker
+	webauthn      *webauthn.WebAuthn
+	store         store.GenericInterface
+	passkeyReader passkeys.Reader
+	passkeyWriter passkeys.Writer
+}
+
+var _ Challenger = &WebAuthnChallenger{}
+var _ PasskeyRegistrar = &WebAuthnChallenger{}
+
+func NewWebAuthnChallenger(
+	config WebAuthnConfig,
+	store store.GenericInterface,
+	passkeyReader passkeys.Reader,
+	passkeyWriter passkeys.Writer,
+	attemptReader challengeattempts.Reader,
+	attemptWriter challengeattempts.Writer,
+	lockoutPolicy LockoutPolicy) (*WebAuthnChallenger, error) {
+
+	w, err := webauthn.New(&webauthn.Config{
+		RPID:          config.RPID,
+		RPDisplayName: config.RPDisplayName,
+		RPOrigins:     config.RPOrigins,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebAuthnChallenger{
+		attemptTracker: attemptTracker{
+			attemptReader: attemptReader,
+			attemptWriter: attemptWriter,
+			lockoutPolicy: lockoutPolicy,
+		},
+		webauthn:      w,
+		store:         store,
+		passkeyReader: passkeyReader,
+		passkeyWriter: passkeyWriter,
+	}, nil
+}
+
+func loginCeremonyKey(id uuid.UUID) string {
+	return "login:" + id.String()
+}
+
+func registrationCeremonyKey(id uuid.UUID) string {
+	return "registration:" + id.String()
+}
+
+func (c *WebAuthnChallenger) IssueChallenge(ctx context.Context, user *users.User) (*Challenge, error) {
+	l := l.With(zap.String("user_id", user.ID.String()))
+
+	waUser, err := c.webAuthnUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(waUser.credentials) == 0 {
+		return nil, ErrNoPasskeys
+	}
+
+	assertion, session, err := c.webauthn.BeginLogin(waUser)
+	if err != nil {
+		l.Error("failed to begin passkey login", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	return c.startCeremony(ctx, user, loginCeremonyKey, assertion, session)
+}
+
+func (c *WebAuthnChallenger) VerifyChallenge(ctx context.Context, challengeId uuid.UUID, solvedChallenge []byte) (*AuthedResult, error) {
+	ceremony, err := c.takeCeremony(ctx, loginCeremonyKey(challengeId), func(user *users.User) error {
+		return c.checkLockout(ctx, user.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := ceremony.Challenge
+	l := l.With(zap.String("user_id", challenge.User.ID.String()))
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(solvedChallenge)
+	if err != nil {
+		l.Warn("failed to parse passkey assertion", zap.Error(err))
+		c.recordAttempt(ctx, challenge, false)
+		return nil, ErrChallengeFailed
+	}
+
+	waUser, err := c.webAuthnUser(ctx, &challenge.User)
+	if err != nil {
+		return nil, err
+	}
+
+	cred, err := c.webauthn.ValidateLogin(waUser, ceremony.Session, parsed)
+	if err != nil {
+		l.Warn("failed to validate passkey assertion", zap.Error(err))
+		c.recordAttempt(ctx, challenge, false)
+		return nil, ErrChallengeFailed
+	}
+
+	// a counter that does not move forward means the private key may have been copied
+	if cred.Authenticator.CloneWarning {
+		l.Warn("passkey sign count regressed", zap.Binary("credential_id", cred.ID), zap.Uint32("sign_count", parsed.Response.AuthenticatorData.Counter))
+		c.recordAttempt(ctx, challenge, false)
+		return nil, ErrSignCountRegression
+	}
+
+	updated, err := c.passkeyWriter.UpdateSignCount(ctx, cred.ID, cred.Authenticator.SignCount, cred.Flags.BackupState)
+	if err != nil {
+		l.Error("failed to update passkey sign count", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	// another login with the same passkey got in first with an equal or higher count
+	if !updated {
+		l.Warn("passkey sign count regressed", zap.Binary("credential_id", cred.ID), zap.Uint32("sign_count", cred.Authenticator.SignCount))
+		c.recordAttempt(ctx, challenge, false)
+		return nil, ErrSignCountRegression
+	}
+
+	c.recordAttempt(ctx, challenge, true)
+
+	return &AuthedResult{
+		ChallengeID: challenge.ID,
+		User:        &challenge.User,
+		Methods:     []string{AuthMethodHardwareKey},
+	}, nil
+}
+
+// VerifyRegistration always fails, accounts are registered with a key and passkeys are
+// added to them afterwards with BeginPasskeyRegistration and FinishPasskeyRegistration
+func (c *WebAuthnChallenger) VerifyRegistration(ctx context.Context, reg Registration) ([16]byte, error) {
+	return [16]byte{}, ErrInvalidRegistration
+}
+
+// BeginPasskeyRegistration returns the JSON encoded options for navigator.credentials.create,
+// passkeys the user already has are excluded so the same authenticator is not registered twice
+func (c *WebAuthnChallenger) BeginPasskeyRegistration(ctx context.Context, user *users.User) (*Challenge, error) {
+	waUser, err := c.webAuthnUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	creation, session, err := c.webauthn.BeginRegistration(waUser,
+		webauthn.WithExclusions(webauthn.Credentials(waUser.credentials).CredentialDescriptors()),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementPreferred))
+	if err != nil {
+		l.Error("failed to begin passkey registration", zap.String("user_id", user.ID.String()), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	return c.startCeremony(ctx, user, registrationCeremonyKey, creation, session)
+}
+
+// FinishPasskeyRegistration checks the attestation the browser returned and stores the new passkey
+func (c *WebAuthnChallenger) FinishPasskeyRegistration(ctx context.Context, user *users.User, challengeId uuid.UUID, response []byte) (*passkeys.Credential, error) {
+	l := l.With(zap.String("user_id", user.ID.String()))
+
+	ceremony, err := c.takeCeremony(ctx, registrationCeremonyKey(challengeId), func(owner *users.User) error {
+		if owner.ID != user.ID {
+			l.Warn("passkey registration was started by another user", zap.String("owner_id", owner.ID.String()))
+			return ErrChallengeFailed
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		l.Warn("failed to parse passkey attestation", zap.Error(err))
+		return nil, ErrInvalidRegistration
+	}
+
+	waUser, err := c.webAuthnUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	cred, err := c.webauthn.CreateCredential(waUser, ceremony.Session, parsed)
+	if err != nil {
+		l.Warn("failed to validate passkey attestation", zap.Error(err))
+		return nil, ErrInvalidRegistration
+	}
+
+	transports := make([]string, len(cred.Transport))
+	for i, t := range cred.Transport {
+		transports[i] = string(t)
+	}
+
+	created, err := c.passkeyWriter.CreateCredential(ctx, passkeys.Credential{
+		UserID:          user.ID,
+		CredentialID:    cred.ID,
+		PublicKey:       cred.PublicKey,
+		AttestationType: cred.AttestationType,
+		Aaguid:          cred.Authenticator.AAGUID,
+		SignCount:       int64(cred.Authenticator.SignCount),
+		Transports:      transports,
+		BackupEligible:  cred.Flags.BackupEligible,
+		BackupState:     cred.Flags.BackupState,
+	})
+	if err != nil {
+		l.Error("failed to store passkey", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	return created, nil
+}
+
+// startCeremony stores the session of a new registration or login and returns the challenge for the client
+func (c *WebAuthnChallenger) startCeremony(ctx context.Context, user *users.User, key func(uuid.UUID) string, options any, session *webauthn.SessionData) (*Challenge, error) {
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		l.Error("failed to marshal passkey options", zap.String("user_id", user.ID.String()), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	challenge := Challenge{
+		ID:        uuid.New(),
+		User:      *user,
+		Challenge: optionsJSON,
+		CreatedAt: time.Now(),
+	}
+
+	err = c.store.Set(ctx, key(challenge.ID), webAuthnCeremony{
+		Challenge: challenge,
+		Session:   *session,
+	})
+	if err != nil {
+		l.Error("failed to set passkey ceremony in store", zap.String("user_id", user.ID.String()), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	return &challenge, nil
+}
+
+// takeCeremony loads a ceremony and deletes it once check passes, so every ceremony is answered at most once
+func (c *WebAuthnChallenger) takeCeremony(ctx context.Context, key string, check func(user *users.User) error) (*webAuthnCeremony, error) {
+	var ceremony webAuthnCeremony
+	err := c.store.Get(ctx, key, &ceremony)
+	if err != nil {
+		if cache.IsCacheMissErr(err) {
+			return nil, ErrChallengeExpired
+		}
+
+		l.Error("failed to get passkey ceremony from store", zap.String("key", key), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	err = check(&ceremony.Challenge.User)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.store.Delete(ctx, key)
+	if err != nil {
+		l.Error("failed to delete passkey ceremony from store", zap.String("key", key), zap.Error(err))
+	}
+
+	return &ceremony, nil
+}
+
+// webAuthnUser loads the user's passkeys
+func (c *WebAuthnChallenger) webAuthnUser(ctx context.Context, user *users.User) (*webAuthnUser, error) {
+	stored, err := c.passkeyReader.ListCredentialsForUser(ctx, user.ID)
+	if err != nil {
+		l.Error("failed to list passkeys", zap.String("user_id", user.ID.String()), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	credentials := make([]webauthn.Credential, len(stored))
+	for i, s := range stored {
+		credentials[i] = toWebAuthnCredential(s)
+	}
+
+	return &webAuthnUser{
+		user:        user,
+		credentials: credentials,
+	}, nil
+}
+
+func toWebAuthnCredential(s passkeys.Credential) webauthn.Credential {
+	transports := make([]protocol.AuthenticatorTransport, len(s.Transports))
+	for i, t := range s.Transports {
+		transports[i] = protocol.AuthenticatorTransport(t)
+	}
+
+	return webauthn.Credential{
+		ID:              s.CredentialID,
+		PublicKey:       s.PublicKey,
+		AttestationType: s.AttestationType,
+		Transport:       transports,
+		Flags: webauthn.CredentialFlags{
+			BackupEligible: s.BackupEligible,
+			BackupState:    s.BackupState,
+		},
+		Authenticator: webauthn.Authenticator{
+			AAGUID:    s.Aaguid,
+			SignCount: uint32(s.SignCount),
+		},
+	}
+}
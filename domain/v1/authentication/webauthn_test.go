@@ -0,0 +1,243 @@
+package authentication
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/records"
+	attemptmocks "github.com/ooqls/go-auth/records/v1/challengeattempts/mocks"
+	"github.com/ooqls/go-auth/records/v1/passkeys"
+	passkeymocks "github.com/ooqls/go-auth/records/v1/passkeys/mocks"
+	"github.com/ooqls/go-auth/records/v1/users"
+	"github.com/ooqls/go-cache/store"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testRPID   = "example.com"
+	testOrigin = "https://example.com"
+)
+
+// softAuthenticator is a software passkey holding a single P-256 credential
+type softAuthenticator struct {
+	key       *ecdsa.PrivateKey
+	id        []byte
+	origin    string
+	signCount uint32
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nilf(t, err, "should not fail to generate a key: %v", err)
+
+	id := make([]byte, 32)
+	rand.Read(id)
+
+	return &softAuthenticator{key: key, id: id, origin: testOrigin}
+}
+
+func (a *softAuthenticator) clientData(t *testing.T, ceremonyType string, challenge []byte) []byte {
+	clientData, err := json.Marshal(map[string]string{
+		"type":      ceremonyType,
+		"challenge": base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":    a.origin,
+	})
+	assert.Nilf(t, err, "should not fail to marshal client data: %v", err)
+	return clientData
+}
+
+// authData builds the authenticator data, attested data is only included when creating the credential
+func (a *softAuthenticator) authData(t *testing.T, attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(testRPID))
+	flags := byte(protocol.FlagUserPresent | protocol.FlagUserVerified)
+	if attested {
+		flags |= byte(protocol.FlagAttestedCredentialData)
+	}
+
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	if !attested {
+		return data
+	}
+
+	coseKey, err := webauthncbor.Marshal(map[int]any{
+		1:  2,  // kty: EC2
+		3:  -7, // alg: ES256
+		-1: 1,  // crv: P-256
+		-2: a.key.X.FillBytes(make([]byte, 32)),
+		-3: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	assert.Nilf(t, err, "should not fail to marshal the public key: %v", err)
+
+	data = append(data, make([]byte, 16)...) // aaguid
+	data = binary.BigEndian.AppendUint16(data, uint16(len(a.id)))
+	data = append(data, a.id...)
+	return append(data, coseKey...)
+}
+
+// create answers navigator.credentials.create options with a "none" attestation
+func (a *softAuthenticator) create(t *testing.T, options []byte) []byte {
+	var creation protocol.CredentialCreation
+	err := json.Unmarshal(options, &creation)
+	assert.Nilf(t, err, "should not fail to unmarshal creation options: %v", err)
+
+	attestation, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": a.authData(t, true),
+	})
+	assert.Nilf(t, err, "should not fail to marshal the attestation: %v", err)
+
+	return a.credential(t, map[string]string{
+		"clientDataJSON":    base64.RawURLEncoding.EncodeToString(a.clientData(t, "webauthn.create", creation.Response.Challenge)),
+		"attestationObject": base64.RawURLEncoding.EncodeToString(attestation),
+	})
+}
+
+// get answers navigator.credentials.get options, every assertion bumps the sign count
+func (a *softAuthenticator) get(t *testing.T, options []byte) []byte {
+	var assertion protocol.CredentialAssertion
+	err := json.Unmarshal(options, &assertion)
+	assert.Nilf(t, err, "should not fail to unmarshal assertion options: %v", err)
+
+	a.signCount++
+	authData := a.authData(t, false)
+	clientData := a.clientData(t, "webauthn.get", assertion.Response.Challenge)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	assert.Nilf(t, err, "should not fail to sign the assertion: %v", err)
+
+	return a.credential(t, map[string]string{
+		"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+		"authenticatorData": base64.RawURLEncoding.EncodeToString(authData),
+		"signature":         base64.RawURLEncoding.EncodeToString(signature),
+	})
+}
+
+func (a *softAuthenticator) credential(t *testing.T, response map[string]string) []byte {
+	id := base64.RawURLEncoding.EncodeToString(a.id)
+	credential, err := json.Marshal(map[string]any{
+		"id":       id,
+		"rawId":    id,
+		"type":     "public-key",
+		"response": response,
+	})
+	assert.Nilf(t, err, "should not fail to marshal the credential: %v", err)
+	return credential
+}
+
+// newTestWebAuthnChallenger returns a challenger whose passkey mocks keep the credentials like the database would
+func newTestWebAuthnChallenger(t *testing.T, ctrl *gomock.Controller, user *users.User) *WebAuthnChallenger {
+	var stored []passkeys.Credential
+
+	passkeyReader := passkeymocks.NewMockReader(ctrl)
+	passkeyReader.EXPECT().ListCredentialsForUser(gomock.Any(), user.ID).AnyTimes().DoAndReturn(
+		func(context.Context, records.UserId) ([]passkeys.Credential, error) {
+			return append([]passkeys.Credential{}, stored...), nil
+		})
+
+	passkeyWriter := passkeymocks.NewMockWriter(ctrl)
+	passkeyWriter.EXPECT().CreateCredential(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, cred passkeys.Credential) (*passkeys.Credential, error) {
+			cred.ID = uuid.New()
+			stored = append(stored, cred)
+			return &cred, nil
+		})
+	passkeyWriter.EXPECT().UpdateSignCount(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, _ []byte, signCount uint32, _ bool) (bool, error) {
+			if int64(signCount) <= stored[0].SignCount {
+				return false, nil
+			}
+
+			stored[0].SignCount = int64(signCount)
+			return true, nil
+		})
+
+	attemptWriter := attemptmocks.NewMockWriter(ctrl)
+	attemptWriter.EXPECT().CreateChallengeAttempt(gomock.Any(), user.ID, gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+
+	challenger, err := NewWebAuthnChallenger(WebAuthnConfig{
+		RPID:          testRPID,
+		RPDisplayName: "test",
+		RPOrigins:     []string{testOrigin},
+	}, store.NewMemStore("webauthn", time.Minute), passkeyReader, passkeyWriter, attemptmocks.NoFailedAttempts(ctrl), attemptWriter, DefaultLockoutPolicy())
+	assert.Nilf(t, err, "should not fail to create the challenger: %v", err)
+
+	return challenger
+}
+
+func TestWebAuthnChallenger(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	user := &users.User{ID: uuid.New(), Username: "test"}
+	challenger := newTestWebAuthnChallenger(t, ctrl, user)
+	authenticator := newSoftAuthenticator(t)
+
+	_, err := challenger.IssueChallenge(ctx, user)
+	assert.Truef(t, errors.Is(err, ErrNoPasskeys), "should not issue a challenge before a passkey is registered, got: %v", err)
+
+	registration, err := challenger.BeginPasskeyRegistration(ctx, user)
+	assert.Nilf(t, err, "should not fail to begin registration: %v", err)
+
+	_, err = challenger.FinishPasskeyRegistration(ctx, &users.User{ID: uuid.New()}, registration.ID, authenticator.create(t, registration.Challenge))
+	assert.Truef(t, errors.Is(err, ErrChallengeFailed), "another user should not finish the registration, got: %v", err)
+
+	cred, err := challenger.FinishPasskeyRegistration(ctx, user, registration.ID, authenticator.create(t, registration.Challenge))
+	assert.Nilf(t, err, "should not fail to finish registration: %v", err)
+	assert.Equalf(t, authenticator.id, cred.CredentialID, "should store the credential id")
+
+	_, err = challenger.FinishPasskeyRegistration(ctx, user, registration.ID, authenticator.create(t, registration.Challenge))
+	assert.Truef(t, errors.Is(err, ErrChallengeExpired), "should not finish a registration twice, got: %v", err)
+
+	challenge, err := challenger.IssueChallenge(ctx, user)
+	assert.Nilf(t, err, "should not fail to issue a challenge: %v", err)
+
+	result, err := challenger.VerifyChallenge(ctx, challenge.ID, authenticator.get(t, challenge.Challenge))
+	assert.Nilf(t, err, "should not fail to verify the assertion: %v", err)
+	assert.Equalf(t, user.ID, result.User.ID, "should authenticate the user")
+
+	_, err = challenger.VerifyChallenge(ctx, challenge.ID, authenticator.get(t, challenge.Challenge))
+	assert.Truef(t, errors.Is(err, ErrChallengeExpired), "should not answer a challenge twice, got: %v", err)
+
+	// an assertion for another origin is rejected
+	challenge, err = challenger.IssueChallenge(ctx, user)
+	assert.Nilf(t, err, "should not fail to issue a challenge: %v", err)
+
+	authenticator.origin = "https://evil.example.org"
+	_, err = challenger.VerifyChallenge(ctx, challenge.ID, authenticator.get(t, challenge.Challenge))
+	assert.Truef(t, errors.Is(err, ErrChallengeFailed), "should reject another origin, got: %v", err)
+	authenticator.origin = testOrigin
+
+	// a clone of the authenticator reports a count that was already used
+	challenge, err = challenger.IssueChallenge(ctx, user)
+	assert.Nilf(t, err, "should not fail to issue a challenge: %v", err)
+
+	clone := *authenticator
+	clone.signCount = 0
+	_, err = challenger.VerifyChallenge(ctx, challenge.ID, clone.get(t, challenge.Challenge))
+	assert.Truef(t, errors.Is(err, ErrSignCountRegression), "should detect the sign count regression, got: %v", err)
+
+	challenge, err = challenger.IssueChallenge(ctx, user)
+	assert.Nilf(t, err, "should not fail to issue a challenge: %v", err)
+
+	_, err = challenger.VerifyChallenge(ctx, challenge.ID, authenticator.get(t, challenge.Challenge))
+	assert.Nilf(t, err, "the original authenticator should still log in: %v", err)
+}
@@ -17,12 +17,31 @@ type Context struct {
 	Roles               []authv1.RoleAgg
 	Domain              string
 	internalOperation bool
+	serviceAccount    bool
 }
 
+// NewAuthorizationContext returns the context of the user, users who have not verified their email hold no roles
 func NewAuthorizationContext(user authv1.UserAgg) Context {
-	return Context{
+	ctx := Context{
 		User: user,
 	}
+
+	if user.EmailVerified {
+		ctx.Roles = user.Roles
+	}
+
+	return ctx
+}
+
+// NewServiceAccountContext returns the context of a service account, service accounts have no email to verify
+// and hold the roles granted to them
+func NewServiceAccountContext(ctx context.Context, account authv1.UserAgg) Context {
+	return Context{
+		Context:        ctx,
+		User:           account,
+		Roles:          account.Roles,
+		serviceAccount: true,
+	}
 }
 
 func NewInternalOperationContext(ctx context.Context) Context {
@@ -40,6 +59,10 @@ func (a *Context) IsInternalOperation() bool {
 	return a.internalOperation
 }
 
+func (a *Context) IsServiceAccount() bool {
+	return a.serviceAccount
+}
+
 func (a *Context) GetUserID() authv1.UserId {
 	return a.User.UserId
 }
@@ -0,0 +1,409 @@
+package directory
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/ooqls/go-auth/domain/v1/authentication"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/federatedidentities"
+	"github.com/ooqls/go-auth/records/v1/roles"
+	"github.com/ooqls/go-auth/records/v1/users"
+	"go.uber.org/zap"
+)
+
+// directoryTimeout bounds dialing and every request to the directory
+const directoryTimeout = 10 * time.Second
+
+// maxUsernameLength bounds the usernames that are sent to the directory
+const maxUsernameLength = 255
+
+// entry is what the directory says about the user who logged in
+type entry struct {
+	DN      string
+	Subject string
+	Email   string
+	Groups  []string
+}
+
+var _ Authenticator = &AuthenticatorV1{}
+
+type AuthenticatorV1 struct {
+	config        Config
+	groupRoles    map[string][]string
+	managedRoles  []string
+	reader        federatedidentities.Reader
+	writer        federatedidentities.Writer
+	userReader    users.Reader
+	userWriter    users.Writer
+	roleReader    roles.Reader
+	roleWriter    roles.Writer
+	authenticator authentication.Authenticator
+}
+
+func NewAuthenticatorV1(
+	config Config,
+	reader federatedidentities.Reader,
+	writer federatedidentities.Writer,
+	userReader users.Reader,
+	userWriter users.Writer,
+	roleReader roles.Reader,
+	roleWriter roles.Writer,
+	authenticator authentication.Authenticator) (*AuthenticatorV1, error) {
+
+	u, err := url.Parse(config.URL)
+	if err != nil || (u.Scheme != "ldap" && u.Scheme != "ldaps") || u.Host == "" {
+		return nil, fmt.Errorf("%w: url must be an ldap:// or ldaps:// url", ErrInvalidConfig)
+	}
+
+	if strings.Count(config.BindDN, "%s") != 1 {
+		return nil, fmt.Errorf("%w: bind_dn must contain %%s once", ErrInvalidConfig)
+	}
+
+	if config.SearchBase != "" && strings.Count(config.SearchFilter, "%s") != 1 {
+		return nil, fmt.Errorf("%w: search_filter must contain %%s once when search_base is set", ErrInvalidConfig)
+	}
+
+	if u.Scheme == "ldap" && !config.StartTLS {
+		l.Warn("directory passwords are sent in plain text, use ldaps:// or start_tls", zap.String("url", config.URL))
+	}
+
+	if config.IDAttribute == "" {
+		config.IDAttribute = "entryUUID"
+	}
+
+	if config.EmailAttribute == "" {
+		config.EmailAttribute = "mail"
+	}
+
+	if config.GroupAttribute == "" {
+		config.GroupAttribute = "memberOf"
+	}
+
+	// group DNs are compared case insensitively, directories do not agree on the case of attribute types
+	groupRoles := map[string][]string{}
+	var managedRoles []string
+	for group, names := range config.GroupRoles {
+		key := normalizeDN(group)
+		groupRoles[key] = append(groupRoles[key], names...)
+		for _, name := range names {
+			if !slices.Contains(managedRoles, name) {
+				managedRoles = append(managedRoles, name)
+			}
+		}
+	}
+
+	return &AuthenticatorV1{
+		config:        config,
+		groupRoles:    groupRoles,
+		managedRoles:  managedRoles,
+		reader:        reader,
+		writer:        writer,
+		userReader:    userReader,
+		userWriter:    userWriter,
+		roleReader:    roleReader,
+		roleWriter:    roleWriter,
+		authenticator: authenticator,
+	}, nil
+}
+
+func normalizeDN(dn string) string {
+	return strings.ToLower(strings.ReplaceAll(dn, ", ", ","))
+}
+
+// validUsername only lets through names that need no escaping in a DN or user principal name,
+// anything else could bind as a different entry than the one the user typed
+func validUsername(username string) bool {
+	if username == "" || len(username) > maxUsernameLength {
+		return false
+	}
+
+	for _, r := range username {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '.', r == '_', r == '-', r == '@':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func (a *AuthenticatorV1) Login(ctx context.Context, username string, password string) (*authentication.TokenResponse, error) {
+	// an empty password is an unauthenticated bind, which most directories accept for any DN
+	if !validUsername(username) || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	l := l.With(zap.String("username", username))
+
+	e, err := a.bind(ctx, username, password)
+	if err != nil {
+		if errors.Is(err, ErrInvalidCredentials) {
+			l.Info("directory rejected credentials")
+			return nil, err
+		}
+
+		l.Error("failed to authenticate with directory", zap.Error(err))
+		return nil, err
+	}
+
+	user, err := a.resolveUser(ctx, username, e)
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.syncRoles(ctx, user.ID, e.Groups)
+	if err != nil {
+		return nil, err
+	}
+
+	l.Info("user logged in with directory", zap.String("user_id", user.ID.String()))
+	return a.authenticator.AuthenticateExternalUser(ctx, user)
+}
+
+// bind checks the password by binding as the user and reads the user's entry with their own permissions
+func (a *AuthenticatorV1) bind(ctx context.Context, username string, password string) (*entry, error) {
+	conn, err := ldap.DialURL(a.config.URL, ldap.DialWithDialer(&net.Dialer{Timeout: directoryTimeout}))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDirectoryUnavailable, err)
+	}
+	defer conn.Close()
+
+	// go-ldap does not take a context, closing the connection aborts the pending request instead
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	conn.SetTimeout(directoryTimeout)
+
+	if a.config.StartTLS {
+		u, _ := url.Parse(a.config.URL)
+		err = conn.StartTLS(&tls.Config{ServerName: u.Hostname()})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrDirectoryUnavailable, err)
+		}
+	}
+
+	bindDN := fmt.Sprintf(a.config.BindDN, username)
+	err = conn.Bind(bindDN, password)
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+
+		return nil, fmt.Errorf("%w: %v", ErrDirectoryUnavailable, err)
+	}
+
+	request := ldap.NewSearchRequest(
+		bindDN, ldap.ScopeBaseObject, ldap.NeverDerefAliases, 1, int(directoryTimeout.Seconds()), false,
+		"(objectClass=*)",
+		[]string{a.config.IDAttribute, a.config.EmailAttribute, a.config.GroupAttribute},
+		nil,
+	)
+	if a.config.SearchBase != "" {
+		request.BaseDN = a.config.SearchBase
+		request.Scope = ldap.ScopeWholeSubtree
+		request.Filter = fmt.Sprintf(a.config.SearchFilter, ldap.EscapeFilter(username))
+		// a size limit of one fails with more than one match instead of picking one of them
+		request.SizeLimit = 2
+	}
+
+	result, err := conn.Search(request)
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil, ErrEntryNotFound
+		}
+
+		return nil, fmt.Errorf("%w: %v", ErrDirectoryUnavailable, err)
+	}
+
+	if len(result.Entries) != 1 {
+		return nil, fmt.Errorf("%w: the search found %d entries", ErrEntryNotFound, len(result.Entries))
+	}
+
+	found := result.Entries[0]
+	e := &entry{
+		DN:     found.DN,
+		Email:  found.GetAttributeValue(a.config.EmailAttribute),
+		Groups: found.GetAttributeValues(a.config.GroupAttribute),
+	}
+
+	// objectGUID is binary, entryUUID is a string
+	id := found.GetRawAttributeValue(a.config.IDAttribute)
+	switch {
+	case len(id) == 0:
+		l.Warn("directory entry has no id attribute, falling back to its dn", zap.String("dn", found.DN), zap.String("attribute", a.config.IDAttribute))
+		e.Subject = normalizeDN(found.DN)
+	case utf8.Valid(id):
+		e.Subject = string(id)
+	default:
+		e.Subject = hex.EncodeToString(id)
+	}
+
+	return e, nil
+}
+
+// resolveUser returns the user linked to the entry, provisioning one on its first login
+func (a *AuthenticatorV1) resolveUser(ctx context.Context, username string, e *entry) (*users.User, error) {
+	l := l.With(zap.String("subject", e.Subject))
+
+	linked, err := a.reader.GetIdentity(ctx, IdentityProvider, e.Subject)
+	if err != nil {
+		l.Error("failed to get federated identity", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	if linked != nil {
+		user, err := a.userReader.GetUser(ctx, linked.UserID)
+		if err != nil || user == nil {
+			l.Error("failed to get linked user", zap.String("user_id", linked.UserID.String()), zap.Error(err))
+			return nil, ErrInternal
+		}
+
+		err = a.writer.TouchIdentity(ctx, IdentityProvider, e.Subject, e.Email)
+		if err != nil {
+			l.Warn("failed to record directory login", zap.Error(err))
+		}
+
+		return user, nil
+	}
+
+	user, err := a.provision(ctx, username, e)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = a.writer.CreateIdentity(ctx, federatedidentities.Identity{
+		Provider: IdentityProvider,
+		Subject:  e.Subject,
+		UserID:   user.ID,
+		Email:    e.Email,
+	})
+	if err != nil {
+		l.Error("failed to link directory entry", zap.String("user_id", user.ID.String()), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	return user, nil
+}
+
+// provision creates a user for the entry with the username it was logged in with. Local accounts are never
+// taken over, a username that is already taken fails the login until an administrator resolves it
+func (a *AuthenticatorV1) provision(ctx context.Context, username string, e *entry) (*users.User, error) {
+	l := l.With(zap.String("subject", e.Subject))
+
+	existing, err := a.userReader.GetUserByUsername(ctx, username)
+	if err != nil {
+		l.Error("failed to get user by username", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	if existing != nil {
+		l.Warn("directory username is taken by a local account", zap.String("user_id", existing.ID.String()))
+		return nil, ErrUsernameTaken
+	}
+
+	// the user has no credentials, the key only has to be something no challenge answer matches
+	key := make([]byte, 32)
+	salt := make([]byte, 16)
+	_, err = rand.Read(key)
+	if err == nil {
+		_, err = rand.Read(salt)
+	}
+	if err != nil {
+		return nil, ErrInternal
+	}
+
+	user := users.User{
+		ID:        records.NewUserID(),
+		Username:  username,
+		Email:     e.Email,
+		Key:       key,
+		Salt:      salt,
+		Algorithm: DirectoryAlgorithm,
+	}
+	err = a.userWriter.CreateUser(ctx, user)
+	if err != nil {
+		l.Error("failed to provision user", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	if a.config.TrustEmail && e.Email != "" {
+		verified, err := a.userWriter.VerifyEmail(ctx, user.ID, e.Email)
+		if err != nil {
+			l.Error("failed to verify email of provisioned user", zap.String("user_id", user.ID.String()), zap.Error(err))
+			return nil, ErrInternal
+		}
+
+		user.EmailVerified = verified
+	}
+
+	l.Info("provisioned user", zap.String("user_id", user.ID.String()))
+	return &user, nil
+}
+
+// syncRoles grants the mapped roles of the user's groups and revokes the mapped roles of groups they left
+func (a *AuthenticatorV1) syncRoles(ctx context.Context, userID records.UserId, groups []string) error {
+	if len(a.managedRoles) == 0 {
+		return nil
+	}
+
+	l := l.With(zap.String("user_id", userID.String()))
+
+	var wanted []string
+	for _, group := range groups {
+		wanted = append(wanted, a.groupRoles[normalizeDN(group)]...)
+	}
+
+	current, err := a.roleReader.GetRolesForUser(ctx, userID)
+	if err != nil {
+		l.Error("failed to get roles for user", zap.Error(err))
+		return ErrInternal
+	}
+
+	has := map[string]bool{}
+	for _, role := range current {
+		has[role.RoleName] = true
+	}
+
+	for _, name := range a.managedRoles {
+		if slices.Contains(wanted, name) == has[name] {
+			continue
+		}
+
+		role, err := a.roleReader.GetRoleByName(ctx, name)
+		if err != nil {
+			l.Error("failed to get role", zap.String("role", name), zap.Error(err))
+			return ErrInternal
+		}
+
+		if role == nil {
+			l.Warn("mapped role does not exist", zap.String("role", name))
+			continue
+		}
+
+		if has[name] {
+			err = a.roleWriter.RemoveRoleFromUser(ctx, userID, role.ID)
+		} else {
+			err = a.roleWriter.AddRoleToUser(ctx, userID, role.ID)
+		}
+		if err != nil {
+			l.Error("failed to update role of user", zap.String("role", name), zap.Error(err))
+			return ErrInternal
+		}
+	}
+
+	return nil
+}
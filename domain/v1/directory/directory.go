@@ -0,0 +1,91 @@
+package directory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ooqls/go-auth/domain/v1/authentication"
+	"github.com/ooqls/go-log"
+	"go.uber.org/zap"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=directory.go -destination=mocks/mock_directory_authenticator.go -package=mocks
+
+var l *zap.Logger
+
+func init() {
+	l = log.NewLogger("directory")
+}
+
+var (
+	ErrInvalidConfig        error = errors.New("invalid directory configuration")
+	ErrInvalidCredentials   error = errors.New("invalid credentials")
+	ErrDirectoryUnavailable error = errors.New("directory unavailable")
+	ErrEntryNotFound        error = errors.New("directory entry not found")
+	ErrUsernameTaken        error = errors.New("username belongs to an account that is not in the directory")
+	ErrInternal             error = errors.New("internal error")
+)
+
+// DirectoryAlgorithm is the algorithm of users provisioned from the directory. They have no credentials
+// of their own, challenges for them always fail since the algorithm is not a supported one
+const DirectoryAlgorithm = "LDAP"
+
+// IdentityProvider is the provider directory entries are linked to users under in the federated identities
+const IdentityProvider = "ldap"
+
+// Config configures the LDAP or Active Directory server users log in with
+type Config struct {
+	// URL of the directory, ldap://host:389 or ldaps://host:636
+	URL string `json:"url"`
+	// StartTLS upgrades an ldap:// connection before the password is sent
+	StartTLS bool `json:"start_tls"`
+	// BindDN is the name users bind as, %s is replaced by the username. For example
+	// uid=%s,ou=people,dc=example,dc=com or, for Active Directory, %s@corp.example.com
+	BindDN string `json:"bind_dn"`
+	// SearchBase and SearchFilter find the user's entry once bound, %s in the filter is replaced by the username.
+	// The entry of BindDN itself is read when SearchBase is not set, BindDN has to be a DN then
+	SearchBase   string `json:"search_base,omitempty"`
+	SearchFilter string `json:"search_filter,omitempty"`
+	// IDAttribute is the attribute that identifies an entry across renames, entryUUID by default or objectGUID for Active Directory
+	IDAttribute    string `json:"id_attribute,omitempty"`
+	EmailAttribute string `json:"email_attribute,omitempty"`
+	GroupAttribute string `json:"group_attribute,omitempty"`
+	// TrustEmail marks the email of provisioned users verified, the directory is trusted to own it
+	TrustEmail bool `json:"trust_email"`
+	// GroupRoles maps group DNs to the names of the roles their members have. Mapped roles are granted and
+	// revoked on every login to follow the user's groups, roles that are not mapped are left alone
+	GroupRoles map[string][]string `json:"group_roles,omitempty"`
+}
+
+// Authenticator logs users in with their directory password. The password is checked by binding to the directory
+// as the user, users are provisioned on their first login and their roles follow their groups
+type Authenticator interface {
+	// Login returns the tokens of a new session, or an mfa token when the user has MFA enabled
+	Login(ctx context.Context, username string, password string) (*authentication.TokenResponse, error)
+}
+
+// LoadConfig reads the directory configuration from JSON
+func LoadConfig(r io.Reader) (*Config, error) {
+	var config Config
+	err := json.NewDecoder(r).Decode(&config)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	return &config, nil
+}
+
+// LoadConfigFile reads the directory configuration from a JSON file
+func LoadConfigFile(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return LoadConfig(f)
+}
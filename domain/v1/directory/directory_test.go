@@ -0,0 +1,474 @@
+package directory
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	authmocks "github.com/ooqls/go-auth/domain/v1/authentication/mocks"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/federatedidentities"
+	"github.com/ooqls/go-auth/records/v1/federatedidentities/mocks"
+	"github.com/ooqls/go-auth/records/v1/mfa"
+	mfamocks "github.com/ooqls/go-auth/records/v1/mfa/mocks"
+	"github.com/ooqls/go-auth/records/v1/roles"
+	rolemocks "github.com/ooqls/go-auth/records/v1/roles/mocks"
+	"github.com/ooqls/go-auth/records/v1/users"
+	usermocks "github.com/ooqls/go-auth/records/v1/users/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testAdminsGroup = "cn=admins,ou=groups,dc=example,dc=test"
+	testStaffGroup  = "cn=staff,ou=groups,dc=example,dc=test"
+)
+
+// testDirectory is a stand-in LDAP server that answers simple binds and searches over its entries.
+// Only equality filters are supported, which is all the authenticator sends
+type testDirectory struct {
+	listener  net.Listener
+	mu        sync.Mutex
+	entries   map[string]map[string][]string
+	passwords map[string]string
+	// names maps bind names that are not DNs, like user principal names, to the DN of their entry
+	names map[string]string
+}
+
+func newTestDirectory(t *testing.T) *testDirectory {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nilf(t, err, "failed to listen: %v", err)
+
+	d := &testDirectory{
+		listener:  listener,
+		entries:   map[string]map[string][]string{},
+		passwords: map[string]string{},
+		names:     map[string]string{},
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go d.serve(conn)
+		}
+	}()
+
+	return d
+}
+
+func (d *testDirectory) url() string {
+	return "ldap://" + d.listener.Addr().String()
+}
+
+func (d *testDirectory) add(dn string, password string, attributes map[string][]string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.entries[normalizeDN(dn)] = attributes
+	d.passwords[normalizeDN(dn)] = password
+	attributes["dn"] = []string{dn}
+}
+
+func (d *testDirectory) setGroups(dn string, groups ...string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.entries[normalizeDN(dn)]["memberOf"] = groups
+}
+
+func (d *testDirectory) serve(conn net.Conn) {
+	defer conn.Close()
+
+	bound := ""
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+
+		id := packet.Children[0].Value.(int64)
+		op := packet.Children[1]
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			bound = d.bind(op.Children[1].Data.String(), op.Children[2].Data.String())
+			code := int64(ldap.LDAPResultSuccess)
+			if bound == "" {
+				code = ldap.LDAPResultInvalidCredentials
+			}
+			conn.Write(ldapResult(id, ldap.ApplicationBindResponse, code).Bytes())
+		case ldap.ApplicationSearchRequest:
+			if bound == "" {
+				conn.Write(ldapResult(id, ldap.ApplicationSearchResultDone, ldap.LDAPResultInsufficientAccessRights).Bytes())
+				continue
+			}
+
+			filter, _ := ldap.DecompileFilter(op.Children[6])
+			var requested []string
+			for _, attribute := range op.Children[7].Children {
+				requested = append(requested, attribute.Data.String())
+			}
+
+			found, code := d.search(op.Children[0].Data.String(), op.Children[1].Value.(int64), filter)
+			for _, dn := range found {
+				conn.Write(d.searchEntry(id, dn, requested).Bytes())
+			}
+			conn.Write(ldapResult(id, ldap.ApplicationSearchResultDone, code).Bytes())
+		case ldap.ApplicationUnbindRequest:
+			return
+		}
+	}
+}
+
+// bind returns the DN of the entry the name and password authenticate, or an empty string
+func (d *testDirectory) bind(name string, password string) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	dn, ok := d.names[strings.ToLower(name)]
+	if !ok {
+		dn = normalizeDN(name)
+	}
+
+	expected, ok := d.passwords[dn]
+	if !ok || password == "" || expected != password {
+		return ""
+	}
+
+	return dn
+}
+
+func (d *testDirectory) search(base string, scope int64, filter string) ([]string, int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	base = normalizeDN(base)
+	if scope == ldap.ScopeBaseObject {
+		if _, ok := d.entries[base]; !ok {
+			return nil, ldap.LDAPResultNoSuchObject
+		}
+		return []string{base}, ldap.LDAPResultSuccess
+	}
+
+	var found []string
+	for dn, attributes := range d.entries {
+		if !strings.HasSuffix(dn, ","+base) {
+			continue
+		}
+
+		for name, values := range attributes {
+			for _, value := range values {
+				if strings.EqualFold(filter, fmt.Sprintf("(%s=%s)", name, ldap.EscapeFilter(value))) {
+					found = append(found, dn)
+				}
+			}
+		}
+	}
+
+	return found, ldap.LDAPResultSuccess
+}
+
+func (d *testDirectory) searchEntry(id int64, dn string, requested []string) *ber.Packet {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	attributes := d.entries[dn]
+	entry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "")
+	entry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, attributes["dn"][0], ""))
+	list := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+	for _, name := range requested {
+		values, ok := attributes[name]
+		if !ok {
+			continue
+		}
+
+		attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+		attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, ""))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "")
+		for _, value := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, ""))
+		}
+		attribute.AppendChild(set)
+		list.AppendChild(attribute)
+	}
+	entry.AppendChild(list)
+
+	return ldapMessage(id, entry)
+}
+
+func ldapMessage(id int64, op *ber.Packet) *ber.Packet {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, ""))
+	packet.AppendChild(op)
+	return packet
+}
+
+func ldapResult(id int64, tag ber.Tag, code int64) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "")
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, ""))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", ""))
+	return ldapMessage(id, op)
+}
+
+// testStore keeps users, their identities and their roles like the database would
+type testStore struct {
+	users      map[uuid.UUID]users.User
+	identities map[string]federatedidentities.Identity
+	roles      map[string]roles.Role
+	userRoles  map[uuid.UUID]map[uuid.UUID]bool
+}
+
+func newTestStore(existing ...users.User) *testStore {
+	s := &testStore{
+		users:      map[uuid.UUID]users.User{},
+		identities: map[string]federatedidentities.Identity{},
+		roles:      map[string]roles.Role{},
+		userRoles:  map[uuid.UUID]map[uuid.UUID]bool{},
+	}
+	for _, user := range existing {
+		s.users[user.ID] = user
+	}
+	for _, name := range []string{"admin", "staff", "auditor"} {
+		s.roles[name] = roles.Role{ID: uuid.New(), RoleName: name}
+	}
+
+	return s
+}
+
+func (s *testStore) roleNames(userID uuid.UUID) []string {
+	var names []string
+	for name, role := range s.roles {
+		if s.userRoles[userID][role.ID] {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+func (s *testStore) mocks(ctrl *gomock.Controller) (*usermocks.MockReader, *usermocks.MockWriter, *mocks.MockReader, *mocks.MockWriter, *rolemocks.MockReader, *rolemocks.MockWriter) {
+	userReader := usermocks.NewMockReader(ctrl)
+	userReader.EXPECT().GetUser(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, id uuid.UUID) (*users.User, error) {
+			user, ok := s.users[id]
+			if !ok {
+				return nil, nil
+			}
+			return &user, nil
+		})
+	userReader.EXPECT().GetUserByUsername(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, username string) (*users.User, error) {
+			for _, user := range s.users {
+				if user.Username == username {
+					return &user, nil
+				}
+			}
+			return nil, nil
+		})
+
+	userWriter := usermocks.NewMockWriter(ctrl)
+	userWriter.EXPECT().CreateUser(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, user users.User) error {
+			s.users[user.ID] = user
+			return nil
+		})
+	userWriter.EXPECT().VerifyEmail(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, id uuid.UUID, email string) (bool, error) {
+			user, ok := s.users[id]
+			if !ok || user.Email != email {
+				return false, nil
+			}
+			user.EmailVerified = true
+			s.users[id] = user
+			return true, nil
+		})
+
+	reader := mocks.NewMockReader(ctrl)
+	reader.EXPECT().GetIdentity(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, provider string, subject string) (*federatedidentities.Identity, error) {
+			identity, ok := s.identities[provider+"/"+subject]
+			if !ok {
+				return nil, nil
+			}
+			return &identity, nil
+		})
+
+	writer := mocks.NewMockWriter(ctrl)
+	writer.EXPECT().CreateIdentity(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, identity federatedidentities.Identity) (*federatedidentities.Identity, error) {
+			s.identities[identity.Provider+"/"+identity.Subject] = identity
+			return &identity, nil
+		})
+	writer.EXPECT().TouchIdentity(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+
+	roleReader := rolemocks.NewMockReader(ctrl)
+	roleReader.EXPECT().GetRoleByName(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, name string) (*roles.Role, error) {
+			role, ok := s.roles[name]
+			if !ok {
+				return nil, nil
+			}
+			return &role, nil
+		})
+	roleReader.EXPECT().GetRolesForUser(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, userID uuid.UUID) ([]roles.Role, error) {
+			var result []roles.Role
+			for _, name := range s.roleNames(userID) {
+				result = append(result, s.roles[name])
+			}
+			return result, nil
+		})
+
+	roleWriter := rolemocks.NewMockWriter(ctrl)
+	roleWriter.EXPECT().AddRoleToUser(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, userID uuid.UUID, roleID uuid.UUID) error {
+			if s.userRoles[userID] == nil {
+				s.userRoles[userID] = map[uuid.UUID]bool{}
+			}
+			s.userRoles[userID][roleID] = true
+			return nil
+		})
+	roleWriter.EXPECT().RemoveRoleFromUser(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, userID uuid.UUID, roleID uuid.UUID) error {
+			delete(s.userRoles[userID], roleID)
+			return nil
+		})
+
+	return userReader, userWriter, reader, writer, roleReader, roleWriter
+}
+
+func newTestDirectoryAuthenticator(t *testing.T, ctrl *gomock.Controller, d *testDirectory, s *testStore, config Config) *AuthenticatorV1 {
+	config.URL = d.url()
+	if config.BindDN == "" {
+		config.BindDN = "uid=%s,ou=people,dc=example,dc=test"
+	}
+
+	userReader, userWriter, reader, writer, roleReader, roleWriter := s.mocks(ctrl)
+	a, err := NewAuthenticatorV1(config, reader, writer, userReader, userWriter, roleReader, roleWriter, authmocks.NewTestAuthenticator(ctrl, nil, nil))
+	assert.Nilf(t, err, "NewAuthenticatorV1 should not return an error: %v", err)
+	return a
+}
+
+func TestDirectory_Login(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	d := newTestDirectory(t)
+	aliceDN := "uid=alice,ou=people,dc=example,dc=test"
+	d.add(aliceDN, "alice-password", map[string][]string{
+		"uid":       {"alice"},
+		"entryUUID": {"5c1c4c9e-7d5a-4f6b-9d55-2f3c1e1f0a01"},
+		"mail":      {"alice@example.test"},
+		"memberOf":  {"CN=Admins,OU=Groups,DC=example,DC=test", testStaffGroup},
+	})
+
+	s := newTestStore()
+	a := newTestDirectoryAuthenticator(t, ctrl, d, s, Config{
+		TrustEmail: true,
+		GroupRoles: map[string][]string{
+			testAdminsGroup: {"admin"},
+			testStaffGroup:  {"staff"},
+		},
+	})
+
+	_, err := a.Login(ctx, "alice", "wrong-password")
+	assert.ErrorIsf(t, err, ErrInvalidCredentials, "a wrong password should be rejected by the directory")
+
+	_, err = a.Login(ctx, "alice", "")
+	assert.ErrorIsf(t, err, ErrInvalidCredentials, "an empty password must never be sent as an unauthenticated bind")
+
+	_, err = a.Login(ctx, "alice,ou=people", "alice-password")
+	assert.ErrorIsf(t, err, ErrInvalidCredentials, "usernames that change the DN should be rejected")
+
+	tokens, err := a.Login(ctx, "alice", "alice-password")
+	assert.Nilf(t, err, "Login should not return an error: %v", err)
+	assert.NotEmptyf(t, tokens.AuthToken, "the login should issue an auth token")
+	assert.NotEmptyf(t, tokens.RefreshToken, "the login should issue a refresh token")
+
+	user := s.users[tokens.UserId]
+	assert.Equalf(t, "alice", user.Username, "the user should be provisioned with the directory username")
+	assert.Equalf(t, DirectoryAlgorithm, user.Algorithm, "the provisioned user should have no credentials")
+	assert.Truef(t, user.EmailVerified, "the email of a trusted directory should be verified")
+	assert.ElementsMatchf(t, []string{"admin", "staff"}, s.roleNames(user.ID), "the user should have the roles of their groups")
+
+	auditor := s.roles["auditor"]
+	s.userRoles[user.ID][auditor.ID] = true
+	d.setGroups(aliceDN, testStaffGroup)
+
+	again, err := a.Login(ctx, "alice", "alice-password")
+	assert.Nilf(t, err, "Login should not return an error: %v", err)
+	assert.Equalf(t, tokens.UserId, again.UserId, "the entry should stay linked to its user")
+	assert.Lenf(t, s.users, 1, "the second login should not provision a user")
+	assert.ElementsMatchf(t, []string{"staff", "auditor"}, s.roleNames(user.ID), "mapped roles should follow the groups and others should be left alone")
+}
+
+func TestDirectory_Search(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	d := newTestDirectory(t)
+	bobDN := "CN=Bob Smith,OU=Staff,DC=corp,DC=test"
+	d.add(bobDN, "bob-password", map[string][]string{
+		"sAMAccountName": {"bob"},
+		"objectGUID":     {string([]byte{0xff, 0x01, 0xfe, 0x02})},
+		"mail":           {"bob@corp.test"},
+	})
+	d.names["bob@corp.test"] = normalizeDN(bobDN)
+
+	s := newTestStore(users.User{ID: records.NewUserID(), Username: "carol"})
+	a := newTestDirectoryAuthenticator(t, ctrl, d, s, Config{
+		BindDN:       "%s@corp.test",
+		SearchBase:   "DC=corp,DC=test",
+		SearchFilter: "(sAMAccountName=%s)",
+		IDAttribute:  "objectGUID",
+	})
+
+	tokens, err := a.Login(ctx, "bob", "bob-password")
+	assert.Nilf(t, err, "Login should not return an error: %v", err)
+	assert.Equalf(t, "bob", s.users[tokens.UserId].Username, "the user should be provisioned with the directory username")
+	assert.Falsef(t, s.users[tokens.UserId].EmailVerified, "the email should not be verified unless the directory is trusted")
+	_, ok := s.identities[IdentityProvider+"/ff01fe02"]
+	assert.Truef(t, ok, "a binary id should be linked hex encoded")
+
+	carolDN := "CN=Carol,OU=Staff,DC=corp,DC=test"
+	d.add(carolDN, "carol-password", map[string][]string{"sAMAccountName": {"carol"}, "objectGUID": {"carol-guid"}})
+	d.names["carol@corp.test"] = normalizeDN(carolDN)
+
+	_, err = a.Login(ctx, "carol", "carol-password")
+	assert.ErrorIsf(t, err, ErrUsernameTaken, "a local account should not be taken over by the directory")
+}
+
+func TestDirectory_MFA(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	d := newTestDirectory(t)
+	d.add("uid=dave,ou=people,dc=example,dc=test", "dave-password", map[string][]string{"entryUUID": {"dave-uuid"}})
+
+	mfaReader := mfamocks.NewMockReader(ctrl)
+	mfaReader.EXPECT().GetMFA(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, id uuid.UUID) (*mfa.MFA, error) {
+			return &mfa.MFA{UserID: id, Confirmed: true}, nil
+		})
+
+	s := newTestStore()
+	userReader, userWriter, reader, writer, roleReader, roleWriter := s.mocks(ctrl)
+	a, err := NewAuthenticatorV1(Config{URL: d.url(), BindDN: "uid=%s,ou=people,dc=example,dc=test"}, reader, writer, userReader, userWriter, roleReader, roleWriter, authmocks.NewTestAuthenticator(ctrl, nil, mfaReader))
+	assert.Nilf(t, err, "NewAuthenticatorV1 should not return an error: %v", err)
+
+	tokens, err := a.Login(ctx, "dave", "dave-password")
+	assert.Nilf(t, err, "Login should not return an error: %v", err)
+	assert.Truef(t, tokens.MFARequired, "a directory password should not skip the second factor")
+	assert.Emptyf(t, tokens.AuthToken, "no auth token should be issued before the second factor")
+	assert.NotEmptyf(t, tokens.MFAToken, "an mfa token should be issued to finish the login with")
+
+	_, err = NewAuthenticatorV1(Config{URL: "https://" + d.listener.Addr().String(), BindDN: "uid=%s"}, reader, writer, userReader, userWriter, roleReader, roleWriter, nil)
+	assert.ErrorIsf(t, err, ErrInvalidConfig, "only ldap urls should be accepted")
+}
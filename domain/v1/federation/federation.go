@@ -0,0 +1,127 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ooqls/go-auth/domain/v1/authentication"
+	"github.com/ooqls/go-auth/records/v1/users"
+	"github.com/ooqls/go-log"
+	"go.uber.org/zap"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=federation.go -destination=mocks/mock_federator.go -package=mocks
+
+var l *zap.Logger
+
+func init() {
+	l = log.NewLogger("federation")
+}
+
+var (
+	ErrProviderNotFound  error = errors.New("identity provider not found")
+	ErrInvalidProvider   error = errors.New("invalid identity provider configuration")
+	ErrInvalidReturnTo   error = errors.New("invalid return_to")
+	ErrInvalidState      error = errors.New("invalid or expired login state")
+	ErrProviderError     error = errors.New("identity provider returned an error")
+	ErrInvalidIDToken    error = errors.New("invalid id token")
+	ErrAccountNotLinked  error = errors.New("no account is linked to the identity")
+	ErrAmbiguousAccounts error = errors.New("several accounts have the identity's email")
+	ErrInternal          error = errors.New("internal error")
+)
+
+// FederatedAlgorithm is the algorithm of users provisioned from an identity provider. They have no credentials
+// of their own, challenges for them always fail since the algorithm is not a supported one
+const FederatedAlgorithm = "FEDERATED"
+
+// ProviderConfig configures an upstream OpenID Connect identity provider. The endpoints are discovered
+// from the issuer when they are not set
+type ProviderConfig struct {
+	// ID names the provider in urls, it must not change once identities are linked
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Issuer is the iss of the provider's id tokens, OpenID Connect Discovery 4 is done against it
+	Issuer       string `json:"issuer"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	// ClientSecretEnv names an environment variable the client secret is read from instead
+	ClientSecretEnv       string   `json:"client_secret_env,omitempty"`
+	Scopes                []string `json:"scopes,omitempty"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint,omitempty"`
+	TokenEndpoint         string   `json:"token_endpoint,omitempty"`
+	JWKSURI               string   `json:"jwks_uri,omitempty"`
+	// Provision creates a user on the first login of an identity that can not be linked to an existing one
+	Provision bool `json:"provision"`
+	// LinkByEmail links the first login of an identity to the user with the same email,
+	// both the provider and the user must have verified the email
+	LinkByEmail bool `json:"link_by_email"`
+}
+
+// Provider is the public description of a configured identity provider
+type Provider struct {
+	ID   string
+	Name string
+}
+
+// Identity is who the provider's id token says the user is
+type Identity struct {
+	Provider          string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+}
+
+// LoginResult is the outcome of a federated login
+type LoginResult struct {
+	User     *users.User
+	Tokens   *authentication.TokenResponse
+	ReturnTo string
+	// Provisioned is set when the user was created by the login
+	Provisioned bool
+}
+
+// Federator logs users in with upstream OpenID Connect identity providers using the authorization code flow.
+// Identities are linked to users on their first login, by email or by provisioning a new user, and later logins
+// get the tokens of the linked user. The provider is trusted to have authenticated the user, MFA is not asked for again
+type Federator interface {
+	Providers() []Provider
+	// BeginLogin returns the provider's authorization url the user is sent to and the state of the login,
+	// the state comes back to the callback and should be bound to the user agent
+	BeginLogin(ctx context.Context, providerID string, returnTo string) (string, string, error)
+	// CompleteLogin exchanges the code the provider sent to the callback, verifies the id token and
+	// starts a session for the identity's user
+	CompleteLogin(ctx context.Context, providerID string, state string, code string) (*LoginResult, error)
+}
+
+// LoadProviders reads the provider configurations from a JSON array
+func LoadProviders(r io.Reader) ([]ProviderConfig, error) {
+	var configs []ProviderConfig
+	err := json.NewDecoder(r).Decode(&configs)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProvider, err)
+	}
+
+	for i := range configs {
+		if configs[i].ClientSecretEnv != "" {
+			configs[i].ClientSecret = os.Getenv(configs[i].ClientSecretEnv)
+		}
+	}
+
+	return configs, nil
+}
+
+// LoadProvidersFile reads the provider configurations from a JSON file
+func LoadProvidersFile(path string) ([]ProviderConfig, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return LoadProviders(f)
+}
@@ -0,0 +1,352 @@
+package federation
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	authmocks "github.com/ooqls/go-auth/domain/v1/authentication/mocks"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/federatedidentities"
+	"github.com/ooqls/go-auth/records/v1/federatedidentities/mocks"
+	"github.com/ooqls/go-auth/records/v1/users"
+	usermocks "github.com/ooqls/go-auth/records/v1/users/mocks"
+	"github.com/ooqls/go-cache/factory"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testClientID     = "test-client"
+	testClientSecret = "test-secret"
+	testCallbackURL  = "https://auth.test/auth/federation"
+)
+
+// testProvider is a stand-in OpenID Connect provider. Logins are approved by calling approve with the
+// authorization url the federator sent the user to, the id token claims can be changed with tamper
+type testProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	mu     sync.Mutex
+	codes  map[string]testGrant
+}
+
+type testGrant struct {
+	claims        jwtv5.MapClaims
+	codeChallenge string
+	redirectURI   string
+}
+
+func newTestProvider(t *testing.T) *testProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nilf(t, err, "failed to generate key: %v", err)
+
+	p := &testProvider{key: key, codes: map[string]testGrant{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.server.URL,
+			"authorization_endpoint": p.server.URL + "/authorize",
+			"token_endpoint":         p.server.URL + "/token",
+			"jwks_uri":               p.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"use": "sig",
+				"kid": "test-key",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, secret, ok := r.BasicAuth()
+		if !ok || clientID != testClientID || secret != testClientSecret {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+			return
+		}
+
+		p.mu.Lock()
+		grant, ok := p.codes[r.PostFormValue("code")]
+		delete(p.codes, r.PostFormValue("code"))
+		p.mu.Unlock()
+
+		verifier := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+		if !ok || grant.redirectURI != r.PostFormValue("redirect_uri") || grant.codeChallenge != base64.RawURLEncoding.EncodeToString(verifier[:]) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		token := jwtv5.NewWithClaims(jwtv5.SigningMethodRS256, grant.claims)
+		token.Header["kid"] = "test-key"
+		idToken, err := token.SignedString(key)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{"id_token": idToken, "access_token": "upstream", "token_type": "Bearer"})
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+// approve logs the subject in at the provider and returns the state and code it redirects back with
+func (p *testProvider) approve(t *testing.T, authURL string, subject string, email string, emailVerified bool, tamper func(jwtv5.MapClaims)) (string, string) {
+	u, err := url.Parse(authURL)
+	assert.Nilf(t, err, "authorization url should parse: %v", err)
+	query := u.Query()
+	assert.Equalf(t, "S256", query.Get("code_challenge_method"), "the login should use PKCE")
+
+	now := time.Now()
+	claims := jwtv5.MapClaims{
+		"iss":            p.server.URL,
+		"sub":            subject,
+		"aud":            testClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"nonce":          query.Get("nonce"),
+		"email":          email,
+		"email_verified": emailVerified,
+	}
+	if tamper != nil {
+		tamper(claims)
+	}
+
+	code := uuid.NewString()
+	p.mu.Lock()
+	p.codes[code] = testGrant{claims: claims, codeChallenge: query.Get("code_challenge"), redirectURI: query.Get("redirect_uri")}
+	p.mu.Unlock()
+	return query.Get("state"), code
+}
+
+// newTestStore returns record mocks that keep users and their identities like the database would
+func newTestStore(ctrl *gomock.Controller, existing ...users.User) (*usermocks.MockReader, *usermocks.MockWriter, *mocks.MockReader, *mocks.MockWriter) {
+	userMap := map[uuid.UUID]users.User{}
+	for _, user := range existing {
+		userMap[user.ID] = user
+	}
+	identities := map[string]federatedidentities.Identity{}
+
+	userReader := usermocks.NewMockReader(ctrl)
+	userReader.EXPECT().GetUser(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, id uuid.UUID) (*users.User, error) {
+			user, ok := userMap[id]
+			if !ok {
+				return nil, nil
+			}
+			return &user, nil
+		})
+	userReader.EXPECT().GetUserByUsername(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, username string) (*users.User, error) {
+			for _, user := range userMap {
+				if user.Username == username {
+					return &user, nil
+				}
+			}
+			return nil, nil
+		})
+	userReader.EXPECT().GetUsersByEmail(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, email string) ([]users.User, error) {
+			result := []users.User{}
+			for _, user := range userMap {
+				if user.Email == email {
+					result = append(result, user)
+				}
+			}
+			return result, nil
+		})
+
+	userWriter := usermocks.NewMockWriter(ctrl)
+	userWriter.EXPECT().CreateUser(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, user users.User) error {
+			userMap[user.ID] = user
+			return nil
+		})
+	userWriter.EXPECT().VerifyEmail(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, id uuid.UUID, email string) (bool, error) {
+			user, ok := userMap[id]
+			if !ok || user.Email != email {
+				return false, nil
+			}
+			user.EmailVerified = true
+			userMap[id] = user
+			return true, nil
+		})
+
+	reader := mocks.NewMockReader(ctrl)
+	reader.EXPECT().GetIdentity(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, provider string, subject string) (*federatedidentities.Identity, error) {
+			identity, ok := identities[provider+"/"+subject]
+			if !ok {
+				return nil, nil
+			}
+			return &identity, nil
+		})
+
+	writer := mocks.NewMockWriter(ctrl)
+	writer.EXPECT().CreateIdentity(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, identity federatedidentities.Identity) (*federatedidentities.Identity, error) {
+			identities[identity.Provider+"/"+identity.Subject] = identity
+			return &identity, nil
+		})
+	writer.EXPECT().TouchIdentity(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+
+	return userReader, userWriter, reader, writer
+}
+
+func newTestFederator(t *testing.T, ctrl *gomock.Controller, p *testProvider, config ProviderConfig, existing ...users.User) (*FederatorV1, *usermocks.MockReader) {
+	config.ID = "test"
+	config.Issuer = p.server.URL
+	config.ClientID = testClientID
+	config.ClientSecret = testClientSecret
+
+	userReader, userWriter, reader, writer := newTestStore(ctrl, existing...)
+	f, err := NewFederatorV1([]ProviderConfig{config}, testCallbackURL, reader, writer, userReader, userWriter, authmocks.NewTestAuthenticator(ctrl, nil, nil), &factory.MemCacheFactory{}, p.server.Client())
+	assert.Nilf(t, err, "NewFederatorV1 should not return an error: %v", err)
+	return f, userReader
+}
+
+func TestFederation_Provision(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	p := newTestProvider(t)
+	f, userReader := newTestFederator(t, ctrl, p, ProviderConfig{Provision: true})
+
+	assert.Equalf(t, []Provider{{ID: "test", Name: "test"}}, f.Providers(), "the provider should be listed")
+
+	_, _, err := f.BeginLogin(ctx, "test", "https://evil.test/")
+	assert.ErrorIsf(t, err, ErrInvalidReturnTo, "users must not be sent to other sites")
+
+	_, _, err = f.BeginLogin(ctx, "unknown", "/")
+	assert.ErrorIsf(t, err, ErrProviderNotFound, "unknown providers should not be logged in with")
+
+	authURL, state, err := f.BeginLogin(ctx, "test", "/welcome")
+	assert.Nilf(t, err, "BeginLogin should not return an error: %v", err)
+	returnedState, code := p.approve(t, authURL, "subject-1", "alice@test.com", true, nil)
+	assert.Equalf(t, state, returnedState, "the provider should return the state")
+
+	result, err := f.CompleteLogin(ctx, "test", state, code)
+	assert.Nilf(t, err, "CompleteLogin should not return an error: %v", err)
+	assert.Truef(t, result.Provisioned, "the first login should provision a user")
+	assert.Equalf(t, "/welcome", result.ReturnTo, "the user should return where they started")
+	assert.Equalf(t, "alice", result.User.Username, "the username should come from the email")
+	assert.Truef(t, result.User.EmailVerified, "the email the provider verified should be verified")
+	assert.Equalf(t, FederatedAlgorithm, result.User.Algorithm, "the provisioned user should have no credentials")
+	assert.NotEmptyf(t, result.Tokens.AuthToken, "the login should issue an auth token")
+
+	_, err = f.CompleteLogin(ctx, "test", state, code)
+	assert.ErrorIsf(t, err, ErrInvalidState, "a state can only be used once")
+
+	authURL, state, err = f.BeginLogin(ctx, "test", "")
+	assert.Nilf(t, err, "BeginLogin should not return an error: %v", err)
+	_, code = p.approve(t, authURL, "subject-1", "alice@test.com", true, nil)
+	again, err := f.CompleteLogin(ctx, "test", state, code)
+	assert.Nilf(t, err, "CompleteLogin should not return an error: %v", err)
+	assert.Falsef(t, again.Provisioned, "the second login should not provision a user")
+	assert.Equalf(t, result.User.ID, again.User.ID, "the identity should stay linked to its user")
+
+	authURL, state, err = f.BeginLogin(ctx, "test", "")
+	assert.Nilf(t, err, "BeginLogin should not return an error: %v", err)
+	_, code = p.approve(t, authURL, "subject-2", "alice@other.test", false, func(claims jwtv5.MapClaims) {
+		claims["preferred_username"] = "alice"
+	})
+	other, err := f.CompleteLogin(ctx, "test", state, code)
+	assert.Nilf(t, err, "CompleteLogin should not return an error: %v", err)
+	assert.NotEqualf(t, "alice", other.User.Username, "a taken username should not be reused")
+	assert.Falsef(t, other.User.EmailVerified, "an email the provider did not verify should not be verified")
+
+	stored, err := userReader.GetUserByUsername(ctx, other.User.Username)
+	assert.Nilf(t, err, "GetUserByUsername should not return an error: %v", err)
+	assert.NotNilf(t, stored, "the provisioned user should be stored")
+}
+
+func TestFederation_LinkByEmail(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	p := newTestProvider(t)
+	verified := users.User{ID: records.NewUserID(), Username: "bob", Email: "bob@test.com", EmailVerified: true}
+	unverified := users.User{ID: records.NewUserID(), Username: "mallory", Email: "carol@test.com"}
+	f, _ := newTestFederator(t, ctrl, p, ProviderConfig{LinkByEmail: true}, verified, unverified)
+
+	login := func(subject string, email string, emailVerified bool) (*LoginResult, error) {
+		authURL, state, err := f.BeginLogin(ctx, "test", "/")
+		assert.Nilf(t, err, "BeginLogin should not return an error: %v", err)
+		_, code := p.approve(t, authURL, subject, email, emailVerified, nil)
+		return f.CompleteLogin(ctx, "test", state, code)
+	}
+
+	result, err := login("bob-subject", "bob@test.com", true)
+	assert.Nilf(t, err, "CompleteLogin should not return an error: %v", err)
+	assert.Equalf(t, verified.ID, result.User.ID, "the identity should be linked to the user with the verified email")
+
+	_, err = login("other-bob", "bob@test.com", false)
+	assert.ErrorIsf(t, err, ErrAccountNotLinked, "an email the provider did not verify should not be linked")
+
+	_, err = login("carol-subject", "carol@test.com", true)
+	assert.ErrorIsf(t, err, ErrAccountNotLinked, "a user who did not verify their email should not be linked")
+}
+
+func TestFederation_IDToken(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	p := newTestProvider(t)
+	f, _ := newTestFederator(t, ctrl, p, ProviderConfig{Provision: true})
+
+	testCases := []struct {
+		name   string
+		tamper func(jwtv5.MapClaims)
+	}{
+		{name: "wrong nonce", tamper: func(c jwtv5.MapClaims) { c["nonce"] = "replayed" }},
+		{name: "wrong audience", tamper: func(c jwtv5.MapClaims) { c["aud"] = "other-client" }},
+		{name: "wrong issuer", tamper: func(c jwtv5.MapClaims) { c["iss"] = "https://evil.test" }},
+		{name: "expired", tamper: func(c jwtv5.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{name: "no expiry", tamper: func(c jwtv5.MapClaims) { delete(c, "exp") }},
+		{name: "no subject", tamper: func(c jwtv5.MapClaims) { delete(c, "sub") }},
+		{name: "other authorized party", tamper: func(c jwtv5.MapClaims) {
+			c["aud"] = []string{testClientID, "other-client"}
+			c["azp"] = "other-client"
+		}},
+	}
+
+	for _, tc := range testCases {
+		authURL, state, err := f.BeginLogin(ctx, "test", "/")
+		assert.Nilf(t, err, "BeginLogin should not return an error: %v", err)
+		_, code := p.approve(t, authURL, "subject", "dave@test.com", true, tc.tamper)
+		_, err = f.CompleteLogin(ctx, "test", state, code)
+		assert.ErrorIsf(t, err, ErrInvalidIDToken, "%s: the id token should be rejected", tc.name)
+	}
+
+	authURL, state, err := f.BeginLogin(ctx, "test", "/")
+	assert.Nilf(t, err, "BeginLogin should not return an error: %v", err)
+	_, _ = p.approve(t, authURL, "subject", "dave@test.com", true, nil)
+	_, err = f.CompleteLogin(ctx, "test", state, "stolen-code")
+	assert.ErrorIsf(t, err, ErrProviderError, "a code the provider did not issue should be rejected")
+
+	// a code issued for another login fails PKCE
+	authURL, _, err = f.BeginLogin(ctx, "test", "/")
+	assert.Nilf(t, err, "BeginLogin should not return an error: %v", err)
+	_, code := p.approve(t, authURL, "subject", "dave@test.com", true, nil)
+	_, otherState, err := f.BeginLogin(ctx, "test", "/")
+	assert.Nilf(t, err, "BeginLogin should not return an error: %v", err)
+	_, err = f.CompleteLogin(ctx, "test", otherState, code)
+	assert.ErrorIsf(t, err, ErrProviderError, "a code of another login should fail the code verifier")
+}
@@ -0,0 +1,446 @@
+package federation
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ooqls/go-auth/domain/v1/authentication"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/federatedidentities"
+	"github.com/ooqls/go-auth/records/v1/users"
+	"github.com/ooqls/go-cache/cache"
+	"github.com/ooqls/go-cache/factory"
+	"github.com/ooqls/go-cache/store"
+	"go.uber.org/zap"
+)
+
+// loginStateTTL is how long the user has to log in at the provider
+const loginStateTTL = 10 * time.Minute
+
+// maxUsernameLength bounds the usernames of provisioned users
+const maxUsernameLength = 64
+
+// loginState is what is remembered about a login while the user is at the provider
+type loginState struct {
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	ReturnTo     string
+	Used         bool
+}
+
+var _ Federator = &FederatorV1{}
+
+type FederatorV1 struct {
+	providers     map[string]*provider
+	order         []string
+	callbackURL   string
+	states        store.GenericInterface
+	reader        federatedidentities.Reader
+	writer        federatedidentities.Writer
+	userReader    users.Reader
+	userWriter    users.Writer
+	authenticator authentication.Authenticator
+}
+
+// NewFederatorV1 returns a federator for the providers. The provider redirects back to
+// {callbackURL}/{provider id}/callback, it must be registered as a redirect uri of the client at the provider
+func NewFederatorV1(
+	configs []ProviderConfig,
+	callbackURL string,
+	reader federatedidentities.Reader,
+	writer federatedidentities.Writer,
+	userReader users.Reader,
+	userWriter users.Writer,
+	authenticator authentication.Authenticator,
+	cacheFactory factory.CacheFactory,
+	client *http.Client) (*FederatorV1, error) {
+
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	f := &FederatorV1{
+		providers:     map[string]*provider{},
+		callbackURL:   strings.TrimSuffix(callbackURL, "/"),
+		states:        cacheFactory.NewStore("federation_states", loginStateTTL),
+		reader:        reader,
+		writer:        writer,
+		userReader:    userReader,
+		userWriter:    userWriter,
+		authenticator: authenticator,
+	}
+
+	for _, config := range configs {
+		p, err := newProvider(config, client)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := f.providers[config.ID]; ok {
+			return nil, fmt.Errorf("%w: duplicate id %s", ErrInvalidProvider, config.ID)
+		}
+
+		f.providers[config.ID] = p
+		f.order = append(f.order, config.ID)
+	}
+
+	return f, nil
+}
+
+// randomString returns a url safe random string of n bytes
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// validReturnTo returns true for paths on this service, anything else could redirect users to another site
+func validReturnTo(returnTo string) bool {
+	return strings.HasPrefix(returnTo, "/") && !strings.HasPrefix(returnTo, "//") && !strings.ContainsAny(returnTo, "\\\r\n")
+}
+
+func (f *FederatorV1) redirectURI(providerID string) string {
+	return f.callbackURL + "/" + providerID + "/callback"
+}
+
+func (f *FederatorV1) Providers() []Provider {
+	providers := make([]Provider, 0, len(f.order))
+	for _, id := range f.order {
+		providers = append(providers, Provider{
+			ID:   id,
+			Name: f.providers[id].config.Name,
+		})
+	}
+
+	return providers
+}
+
+func (f *FederatorV1) BeginLogin(ctx context.Context, providerID string, returnTo string) (string, string, error) {
+	l := l.With(zap.String("provider", providerID))
+
+	p, ok := f.providers[providerID]
+	if !ok {
+		return "", "", ErrProviderNotFound
+	}
+
+	if returnTo == "" {
+		returnTo = "/"
+	}
+
+	if !validReturnTo(returnTo) {
+		return "", "", ErrInvalidReturnTo
+	}
+
+	metadata, _, err := p.discover(ctx)
+	if err != nil {
+		l.Error("failed to discover identity provider", zap.Error(err))
+		return "", "", ErrProviderError
+	}
+
+	state, err := randomString(32)
+	if err != nil {
+		return "", "", ErrInternal
+	}
+
+	nonce, err := randomString(32)
+	if err != nil {
+		return "", "", ErrInternal
+	}
+
+	codeVerifier, err := randomString(32)
+	if err != nil {
+		return "", "", ErrInternal
+	}
+
+	challenge := sha256.Sum256([]byte(codeVerifier))
+	authURL, err := p.authorizationURL(metadata, f.redirectURI(providerID), state, nonce, base64.RawURLEncoding.EncodeToString(challenge[:]))
+	if err != nil {
+		l.Error("failed to build authorization url", zap.Error(err))
+		return "", "", ErrProviderError
+	}
+
+	err = f.states.Set(ctx, state, loginState{
+		Provider:     providerID,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ReturnTo:     returnTo,
+	})
+	if err != nil {
+		l.Error("failed to store login state", zap.Error(err))
+		return "", "", ErrInternal
+	}
+
+	return authURL, state, nil
+}
+
+func (f *FederatorV1) CompleteLogin(ctx context.Context, providerID string, state string, code string) (*LoginResult, error) {
+	l := l.With(zap.String("provider", providerID))
+
+	p, ok := f.providers[providerID]
+	if !ok {
+		return nil, ErrProviderNotFound
+	}
+
+	if state == "" || code == "" {
+		return nil, ErrInvalidState
+	}
+
+	// a state can only be used once, whether or not the login succeeds
+	var login loginState
+	err := f.states.Update(ctx, state, func(load func(target any) error) (any, error) {
+		if err := load(&login); err != nil {
+			return nil, err
+		}
+
+		if login.Used || login.Provider != providerID {
+			return nil, ErrInvalidState
+		}
+
+		login.Used = true
+		return login, nil
+	})
+	if err != nil {
+		if cache.IsCacheMissErr(err) || errors.Is(err, ErrInvalidState) {
+			l.Warn("invalid or used login state")
+			return nil, ErrInvalidState
+		}
+
+		l.Error("failed to get login state", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	metadata, keys, err := p.discover(ctx)
+	if err != nil {
+		l.Error("failed to discover identity provider", zap.Error(err))
+		return nil, ErrProviderError
+	}
+
+	idToken, err := p.exchange(ctx, metadata, f.redirectURI(providerID), code, login.CodeVerifier)
+	if err != nil {
+		l.Warn("failed to exchange authorization code", zap.Error(err))
+		return nil, ErrProviderError
+	}
+
+	identity, err := p.verifyIDToken(ctx, metadata, keys, idToken, login.Nonce)
+	if err != nil {
+		l.Warn("failed to verify id token", zap.Error(err))
+		return nil, ErrInvalidIDToken
+	}
+
+	user, provisioned, err := f.resolveUser(ctx, p.config, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := f.authenticator.AuthenticateNewUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	l.Info("user logged in with identity provider", zap.String("user_id", user.ID.String()), zap.Bool("provisioned", provisioned))
+	return &LoginResult{
+		User:        user,
+		Tokens:      tokens,
+		ReturnTo:    login.ReturnTo,
+		Provisioned: provisioned,
+	}, nil
+}
+
+// resolveUser returns the user linked to the identity, linking or provisioning one on its first login
+func (f *FederatorV1) resolveUser(ctx context.Context, config ProviderConfig, identity *Identity) (*users.User, bool, error) {
+	l := l.With(zap.String("provider", identity.Provider), zap.String("subject", identity.Subject))
+
+	linked, err := f.reader.GetIdentity(ctx, identity.Provider, identity.Subject)
+	if err != nil {
+		l.Error("failed to get federated identity", zap.Error(err))
+		return nil, false, ErrInternal
+	}
+
+	if linked != nil {
+		user, err := f.userReader.GetUser(ctx, linked.UserID)
+		if err != nil || user == nil {
+			l.Error("failed to get linked user", zap.String("user_id", linked.UserID.String()), zap.Error(err))
+			return nil, false, ErrInternal
+		}
+
+		err = f.writer.TouchIdentity(ctx, identity.Provider, identity.Subject, identity.Email)
+		if err != nil {
+			l.Warn("failed to record federated login", zap.Error(err))
+		}
+
+		return user, false, nil
+	}
+
+	if config.LinkByEmail && identity.EmailVerified && identity.Email != "" {
+		user, err := f.userByVerifiedEmail(ctx, identity.Email)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if user != nil {
+			err = f.link(ctx, identity, user.ID)
+			if err != nil {
+				return nil, false, err
+			}
+
+			l.Info("linked identity to existing user", zap.String("user_id", user.ID.String()))
+			return user, false, nil
+		}
+	}
+
+	if !config.Provision {
+		return nil, false, ErrAccountNotLinked
+	}
+
+	user, err := f.provision(ctx, identity)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = f.link(ctx, identity, user.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return user, true, nil
+}
+
+// userByVerifiedEmail returns the user who verified the email, or nil if there is none.
+// Users who did not verify it are never linked, anyone can sign up with an email they do not own
+func (f *FederatorV1) userByVerifiedEmail(ctx context.Context, email string) (*users.User, error) {
+	candidates, err := f.userReader.GetUsersByEmail(ctx, email)
+	if err != nil {
+		l.Error("failed to get users by email", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	var found *users.User
+	for i := range candidates {
+		if !candidates[i].EmailVerified {
+			continue
+		}
+
+		if found != nil {
+			return nil, ErrAmbiguousAccounts
+		}
+
+		found = &candidates[i]
+	}
+
+	return found, nil
+}
+
+func (f *FederatorV1) link(ctx context.Context, identity *Identity, userID records.UserId) error {
+	_, err := f.writer.CreateIdentity(ctx, federatedidentities.Identity{
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		UserID:   userID,
+		Email:    identity.Email,
+	})
+	if err != nil {
+		l.Error("failed to link federated identity", zap.String("user_id", userID.String()), zap.Error(err))
+		return ErrInternal
+	}
+
+	return nil
+}
+
+// provision creates a user for the identity, its email is verified when the provider verified it
+func (f *FederatorV1) provision(ctx context.Context, identity *Identity) (*users.User, error) {
+	l := l.With(zap.String("provider", identity.Provider), zap.String("subject", identity.Subject))
+
+	username, err := f.availableUsername(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	// the user has no credentials, the key only has to be something no challenge answer matches
+	key := make([]byte, 32)
+	salt := make([]byte, 16)
+	_, err = rand.Read(key)
+	if err == nil {
+		_, err = rand.Read(salt)
+	}
+	if err != nil {
+		return nil, ErrInternal
+	}
+
+	user := users.User{
+		ID:        records.NewUserID(),
+		Username:  username,
+		Email:     identity.Email,
+		Key:       key,
+		Salt:      salt,
+		Algorithm: FederatedAlgorithm,
+	}
+	err = f.userWriter.CreateUser(ctx, user)
+	if err != nil {
+		l.Error("failed to provision user", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	if identity.EmailVerified && identity.Email != "" {
+		verified, err := f.userWriter.VerifyEmail(ctx, user.ID, identity.Email)
+		if err != nil {
+			l.Error("failed to verify email of provisioned user", zap.String("user_id", user.ID.String()), zap.Error(err))
+			return nil, ErrInternal
+		}
+
+		user.EmailVerified = verified
+	}
+
+	l.Info("provisioned user", zap.String("user_id", user.ID.String()))
+	return &user, nil
+}
+
+// availableUsername returns a username for the identity that is not taken yet, it is based on
+// the preferred_username or email of the identity
+func (f *FederatorV1) availableUsername(ctx context.Context, identity *Identity) (string, error) {
+	base := identity.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(identity.Email, "@")
+	}
+	if base == "" {
+		base = identity.Provider + "-user"
+	}
+	// room is left for the suffix added when the username is taken
+	if runes := []rune(base); len(runes) > maxUsernameLength-7 {
+		base = string(runes[:maxUsernameLength-7])
+	}
+
+	username := base
+	for range 5 {
+		existing, err := f.userReader.GetUserByUsername(ctx, username)
+		if err != nil {
+			l.Error("failed to get user by username", zap.Error(err))
+			return "", ErrInternal
+		}
+
+		if existing == nil {
+			return username, nil
+		}
+
+		suffix := make([]byte, 3)
+		_, err = rand.Read(suffix)
+		if err != nil {
+			return "", ErrInternal
+		}
+		username = base + "-" + hex.EncodeToString(suffix)
+	}
+
+	l.Error("failed to find an available username", zap.String("username", base))
+	return "", ErrInternal
+}
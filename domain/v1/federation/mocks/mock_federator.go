@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: federation.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	federation "github.com/ooqls/go-auth/domain/v1/federation"
+)
+
+// MockFederator is a mock of Federator interface.
+type MockFederator struct {
+	ctrl     *gomock.Controller
+	recorder *MockFederatorMockRecorder
+}
+
+// MockFederatorMockRecorder is the mock recorder for MockFederator.
+type MockFederatorMockRecorder struct {
+	mock *MockFederator
+}
+
+// NewMockFederator creates a new mock instance.
+func NewMockFederator(ctrl *gomock.Controller) *MockFederator {
+	mock := &MockFederator{ctrl: ctrl}
+	mock.recorder = &MockFederatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFederator) EXPECT() *MockFederatorMockRecorder {
+	return m.recorder
+}
+
+// BeginLogin mocks base method.
+func (m *MockFederator) BeginLogin(ctx context.Context, providerID, returnTo string) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginLogin", ctx, providerID, returnTo)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// BeginLogin indicates an expected call of BeginLogin.
+func (mr *MockFederatorMockRecorder) BeginLogin(ctx, providerID, returnTo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginLogin", reflect.TypeOf((*MockFederator)(nil).BeginLogin), ctx, providerID, returnTo)
+}
+
+// CompleteLogin mocks base method.
+func (m *MockFederator) CompleteLogin(ctx context.Context, providerID, state, code string) (*federation.LoginResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteLogin", ctx, providerID, state, code)
+	ret0, _ := ret[0].(*federation.LoginResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteLogin indicates an expected call of CompleteLogin.
+func (mr *MockFederatorMockRecorder) CompleteLogin(ctx, providerID, state, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteLogin", reflect.TypeOf((*MockFederator)(nil).CompleteLogin), ctx, providerID, state, code)
+}
+
+// Providers mocks base method.
+func (m *MockFederator) Providers() []federation.Provider {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Providers")
+	ret0, _ := ret[0].([]federation.Provider)
+	return ret0
+}
+
+// Providers indicates an expected call of Providers.
+func (mr *MockFederatorMockRecorder) Providers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Providers", reflect.TypeOf((*MockFederator)(nil).Providers))
+}
@@ -0,0 +1,281 @@
+package federation
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"github.com/ooqls/go-auth/domain/v1/jwks"
+)
+
+// idTokenLeeway is the clock skew tolerated between us and the provider
+const idTokenLeeway = time.Minute
+
+// idTokenAlgorithms are the signing algorithms id tokens are accepted with, none and HMAC are never accepted
+var idTokenAlgorithms = []string{
+	jwtv5.SigningMethodRS256.Alg(),
+	jwtv5.SigningMethodRS384.Alg(),
+	jwtv5.SigningMethodRS512.Alg(),
+	jwtv5.SigningMethodPS256.Alg(),
+	jwtv5.SigningMethodES256.Alg(),
+	jwtv5.SigningMethodES384.Alg(),
+	jwtv5.SigningMethodEdDSA.Alg(),
+}
+
+// idTokenClaims are the claims of an upstream id token, OpenID Connect Core 2 and 5.1
+type idTokenClaims struct {
+	jwtv5.RegisteredClaims
+	Nonce             string       `json:"nonce"`
+	AuthorizedParty   string       `json:"azp,omitempty"`
+	Email             string       `json:"email,omitempty"`
+	EmailVerified     flexibleBool `json:"email_verified,omitempty"`
+	PreferredUsername string       `json:"preferred_username,omitempty"`
+}
+
+// flexibleBool is a boolean claim some providers send as the string "true"
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case "true", `"true"`:
+		*b = true
+	case "false", `"false"`, "null":
+		*b = false
+	default:
+		return fmt.Errorf("invalid boolean %s", data)
+	}
+
+	return nil
+}
+
+// providerMetadata are the endpoints of the discovery document the flow needs, OpenID Connect Discovery 3
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// provider is a configured identity provider, its endpoints are discovered on first use so the
+// service starts while a provider is unreachable
+type provider struct {
+	config   ProviderConfig
+	client   *http.Client
+	mu       sync.Mutex
+	metadata *providerMetadata
+	keys     *jwks.KeySet
+}
+
+func newProvider(config ProviderConfig, client *http.Client) (*provider, error) {
+	if config.ID == "" || config.Issuer == "" || config.ClientID == "" {
+		return nil, fmt.Errorf("%w: id, issuer and client_id are required", ErrInvalidProvider)
+	}
+
+	if strings.ContainsAny(config.ID, "/?#") {
+		return nil, fmt.Errorf("%w: id %q is not url safe", ErrInvalidProvider, config.ID)
+	}
+
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	if !slices.Contains(config.Scopes, "openid") {
+		config.Scopes = append([]string{"openid"}, config.Scopes...)
+	}
+
+	if config.Name == "" {
+		config.Name = config.ID
+	}
+
+	return &provider{
+		config: config,
+		client: client,
+	}, nil
+}
+
+// discover returns the endpoints of the provider, configured endpoints take precedence over discovered ones
+func (p *provider) discover(ctx context.Context) (*providerMetadata, *jwks.KeySet, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, p.keys, nil
+	}
+
+	metadata := &providerMetadata{
+		Issuer:                p.config.Issuer,
+		AuthorizationEndpoint: p.config.AuthorizationEndpoint,
+		TokenEndpoint:         p.config.TokenEndpoint,
+		JWKSURI:               p.config.JWKSURI,
+	}
+
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		discovered, err := p.fetchMetadata(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// OpenID Connect Discovery 4.3, the issuer of the document must be the one it was retrieved from
+		if discovered.Issuer != p.config.Issuer {
+			return nil, nil, fmt.Errorf("discovery document issuer %q does not match %q", discovered.Issuer, p.config.Issuer)
+		}
+
+		if metadata.AuthorizationEndpoint == "" {
+			metadata.AuthorizationEndpoint = discovered.AuthorizationEndpoint
+		}
+		if metadata.TokenEndpoint == "" {
+			metadata.TokenEndpoint = discovered.TokenEndpoint
+		}
+		if metadata.JWKSURI == "" {
+			metadata.JWKSURI = discovered.JWKSURI
+		}
+	}
+
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, nil, fmt.Errorf("provider %s has no authorization, token or jwks endpoint", p.config.ID)
+	}
+
+	p.metadata = metadata
+	p.keys = jwks.NewKeySet(p.client, metadata.JWKSURI, jwks.DefaultMinRefetchInterval)
+	return p.metadata, p.keys, nil
+}
+
+func (p *provider) fetchMetadata(ctx context.Context) (*providerMetadata, error) {
+	uri := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("discovery responded with %d", resp.StatusCode)
+	}
+
+	var metadata providerMetadata
+	err = json.NewDecoder(resp.Body).Decode(&metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return &metadata, nil
+}
+
+// authorizationURL returns the url of the authentication request, OpenID Connect Core 3.1.2.1
+func (p *provider) authorizationURL(metadata *providerMetadata, redirectURI string, state string, nonce string, codeChallenge string) (string, error) {
+	u, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// exchange redeems the authorization code for the provider's id token, OpenID Connect Core 3.1.3.1
+func (p *provider) exchange(ctx context.Context, metadata *providerMetadata, redirectURI string, code string, codeVerifier string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURI)
+	form.Set("code_verifier", codeVerifier)
+	form.Set("client_id", p.config.ClientID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		// client_secret_basic, the credentials are form encoded first, RFC 6749 2.3.1
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || body.Error != "" {
+		return "", fmt.Errorf("%w: %s %s", ErrProviderError, body.Error, body.ErrorDescription)
+	}
+
+	if body.IDToken == "" {
+		return "", fmt.Errorf("%w: no id_token in token response", ErrProviderError)
+	}
+
+	return body.IDToken, nil
+}
+
+// verifyIDToken checks the id token like OpenID Connect Core 3.1.3.7 describes and returns its identity
+func (p *provider) verifyIDToken(ctx context.Context, metadata *providerMetadata, keys *jwks.KeySet, idToken string, nonce string) (*Identity, error) {
+	var claims idTokenClaims
+	_, err := jwtv5.ParseWithClaims(idToken, &claims, func(token *jwtv5.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return keys.Key(ctx, kid)
+	},
+		jwtv5.WithValidMethods(idTokenAlgorithms),
+		jwtv5.WithIssuer(metadata.Issuer),
+		jwtv5.WithAudience(p.config.ClientID),
+		jwtv5.WithExpirationRequired(),
+		jwtv5.WithIssuedAt(),
+		jwtv5.WithLeeway(idTokenLeeway),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: no sub", ErrInvalidIDToken)
+	}
+
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.config.ClientID {
+		return nil, fmt.Errorf("%w: azp is not the client", ErrInvalidIDToken)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	return &Identity{
+		Provider:          p.config.ID,
+		Subject:           claims.Subject,
+		Email:             claims.Email,
+		EmailVerified:     bool(claims.EmailVerified),
+		PreferredUsername: claims.PreferredUsername,
+	}, nil
+}
@@ -0,0 +1,51 @@
+package introspection
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/domain/v1/serviceaccounts"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-log"
+	"go.uber.org/zap"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=introspection.go -destination=mocks/mock_introspector.go -package=mocks
+
+var l *zap.Logger
+
+func init() {
+	l = log.NewLogger("introspection")
+}
+
+var (
+	ErrInvalidCredentials error = errors.New("invalid caller credentials")
+	ErrInternal           error = errors.New("internal error")
+)
+
+// Introspection is what a resource server learns about a token, RFC 7662 2.2. Only Active is set for
+// tokens that are invalid, expired or revoked so callers can't tell why a token is not active
+type Introspection struct {
+	Active    bool
+	Subject   records.UserId
+	SessionID uuid.UUID
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	// ServiceAccount is set when Subject is a service account
+	ServiceAccount bool
+	// Roles are the ids of the roles the token is authorized with
+	Roles []records.RoleId
+	// RoleAggs are the roles with the permissions they grant, so services can authorize requests without reading roles
+	RoleAggs []records.RoleAgg
+	// Scopes are the ids of the permissions a personal access token is scoped to, it is empty for auth tokens
+	Scopes []records.PermissionId
+}
+
+// Introspector lets services verify auth tokens and personal access tokens without the signing keys, RFC 7662.
+// Callers authenticate as a service account like they do at the token endpoint
+type Introspector interface {
+	// Introspect returns the state of the token, ErrInvalidCredentials is returned if the caller failed to authenticate
+	Introspect(ctx context.Context, caller serviceaccounts.ClientCredentials, token string) (*Introspection, error)
+}
@@ -0,0 +1,103 @@
+package introspection
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	accesstokenmocks "github.com/ooqls/go-auth/domain/v1/accesstokens/mocks"
+	"github.com/ooqls/go-auth/domain/v1/authentication"
+	authmocks "github.com/ooqls/go-auth/domain/v1/authentication/mocks"
+	"github.com/ooqls/go-auth/domain/v1/authorization"
+	"github.com/ooqls/go-auth/domain/v1/serviceaccounts"
+	serviceaccountmocks "github.com/ooqls/go-auth/domain/v1/serviceaccounts/mocks"
+	"github.com/ooqls/go-auth/records"
+	serviceaccountrecords "github.com/ooqls/go-auth/records/v1/serviceaccounts"
+	"github.com/ooqls/go-auth/records/v1/users"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAccessToken = authentication.AccessTokenPrefix + "test"
+
+func TestIntrospector(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	caller := serviceaccountrecords.ServiceAccount{ID: uuid.New()}
+	user := &users.User{ID: uuid.New(), Username: "testuser", EmailVerified: true}
+	accessTokenID := uuid.New()
+	permission := records.Permission{ID: uuid.New()}
+	role := records.RoleAgg{RoleId: uuid.New(), Permissions: []records.Permission{permission, {ID: uuid.New()}}}
+
+	serviceAccounts := serviceaccountmocks.NewMockServiceAccountService(ctrl)
+	serviceAccounts.EXPECT().VerifyCredentials(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, credentials serviceaccounts.ClientCredentials) (*serviceaccountrecords.ServiceAccount, error) {
+			if credentials.ClientID != caller.ID.String() || credentials.ClientSecret != "secret" {
+				return nil, serviceaccounts.ErrInvalidCredentials
+			}
+			return &caller, nil
+		})
+
+	accessTokens := accesstokenmocks.NewMockAccessTokenService(ctrl)
+	accessTokens.EXPECT().VerifyAccessToken(gomock.Any(), testAccessToken).AnyTimes().Return(&authentication.UserClaims{
+		UserID:        user.ID,
+		AccessTokenID: accessTokenID,
+		ExpiresAt:     time.Now().Add(time.Hour),
+	}, nil)
+	accessTokens.EXPECT().AuthorizationContext(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, claims *authentication.UserClaims) (*authorization.Context, error) {
+			// a personal access token is scoped to one of the role's permissions
+			scoped := role
+			if claims.AccessTokenID != uuid.Nil {
+				scoped.Permissions = []records.Permission{permission}
+			}
+
+			authCtx := authorization.NewAuthorizationContext(records.UserAgg{UserId: claims.UserID, Roles: []records.RoleAgg{scoped}, EmailVerified: true})
+			return &authCtx, nil
+		})
+
+	authenticator := authmocks.NewTestAuthenticator(ctrl, accessTokens, nil)
+	introspector := NewIntrospectorV1(authenticator, accessTokens, serviceAccounts)
+	credentials := serviceaccounts.ClientCredentials{ClientID: caller.ID.String(), ClientSecret: "secret"}
+
+	tokens, err := authenticator.AuthenticateNewUser(ctx, user)
+	assert.Nilf(t, err, "should log in the user: %v", err)
+
+	_, err = introspector.Introspect(ctx, serviceaccounts.ClientCredentials{ClientID: caller.ID.String(), ClientSecret: "wrong"}, tokens.AuthToken)
+	assert.ErrorIsf(t, err, ErrInvalidCredentials, "should not introspect for callers who failed to authenticate")
+
+	introspection, err := introspector.Introspect(ctx, credentials, tokens.AuthToken)
+	assert.Nilf(t, err, "should introspect the auth token: %v", err)
+	assert.Truef(t, introspection.Active, "auth token should be active")
+	assert.Equalf(t, user.ID, introspection.Subject, "subject should be the user")
+	assert.NotEqualf(t, uuid.Nil, introspection.SessionID, "should return the session of the auth token")
+	assert.Truef(t, introspection.ExpiresAt.After(time.Now()), "should return when the auth token expires")
+	assert.Falsef(t, introspection.IssuedAt.IsZero(), "should return when the auth token was issued")
+	assert.Equalf(t, []records.RoleId{role.RoleId}, introspection.Roles, "should return the user's roles")
+	assert.Equalf(t, []records.RoleAgg{role}, introspection.RoleAggs, "should return the permissions of the user's roles")
+	assert.Emptyf(t, introspection.Scopes, "auth tokens are not scoped")
+
+	introspection, err = introspector.Introspect(ctx, credentials, testAccessToken)
+	assert.Nilf(t, err, "should introspect the personal access token: %v", err)
+	assert.Truef(t, introspection.Active, "personal access token should be active")
+	assert.Equalf(t, []records.PermissionId{permission.ID}, introspection.Scopes, "should return the permissions the token is scoped to")
+	assert.Equalf(t, []records.Permission{permission}, introspection.RoleAggs[0].Permissions, "roles should only grant the permissions in scope")
+
+	for _, token := range []string{"", "not a token", authentication.AccessTokenPrefix + "unknown"} {
+		accessTokens.EXPECT().VerifyAccessToken(gomock.Any(), token).AnyTimes().Return(nil, authentication.ErrInvalidToken)
+
+		introspection, err = introspector.Introspect(ctx, credentials, token)
+		assert.Nilf(t, err, "should not fail to introspect an invalid token: %v", err)
+		assert.Equalf(t, &Introspection{}, introspection, "invalid token %q should only be inactive", token)
+	}
+
+	err = authenticator.Revoke(ctx, tokens.AuthToken)
+	assert.Nilf(t, err, "should revoke the auth token: %v", err)
+
+	introspection, err = introspector.Introspect(ctx, credentials, tokens.AuthToken)
+	assert.Nilf(t, err, "should introspect the revoked token: %v", err)
+	assert.Falsef(t, introspection.Active, "revoked token should not be active")
+}
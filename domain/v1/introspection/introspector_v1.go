@@ -0,0 +1,131 @@
+package introspection
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/domain/v1/accesstokens"
+	"github.com/ooqls/go-auth/domain/v1/authentication"
+	"github.com/ooqls/go-auth/domain/v1/authorization"
+	"github.com/ooqls/go-auth/domain/v1/serviceaccounts"
+	"github.com/ooqls/go-auth/records"
+	"go.uber.org/zap"
+)
+
+// maxTokenLength is longer than any token we issue, longer tokens are inactive without being verified
+const maxTokenLength = 8192
+
+var _ Introspector = &IntrospectorV1{}
+
+type IntrospectorV1 struct {
+	authenticator   authentication.Authenticator
+	accessTokens    accesstokens.AccessTokenService
+	serviceAccounts serviceaccounts.ServiceAccountService
+}
+
+// NewIntrospectorV1 returns an Introspector verifying tokens with authenticator, the roles of users and personal
+// access tokens come from accessTokens and the roles of service accounts from serviceAccounts, which also authenticates callers
+func NewIntrospectorV1(
+	authenticator authentication.Authenticator,
+	accessTokens accesstokens.AccessTokenService,
+	serviceAccounts serviceaccounts.ServiceAccountService) *IntrospectorV1 {
+
+	return &IntrospectorV1{
+		authenticator:   authenticator,
+		accessTokens:    accessTokens,
+		serviceAccounts: serviceAccounts,
+	}
+}
+
+// Introspect authenticates the caller and checks the token like every request authenticated with it would be,
+// its signature, expiry and revocations or, for personal access tokens, that it is still stored
+func (i *IntrospectorV1) Introspect(ctx context.Context, caller serviceaccounts.ClientCredentials, token string) (*Introspection, error) {
+	account, err := i.serviceAccounts.VerifyCredentials(ctx, caller)
+	if err != nil {
+		if errors.Is(err, serviceaccounts.ErrInternal) {
+			return nil, ErrInternal
+		}
+
+		return nil, ErrInvalidCredentials
+	}
+
+	l := l.With(zap.String("caller_id", account.ID.String()))
+
+	if token == "" || len(token) > maxTokenLength {
+		return &Introspection{}, nil
+	}
+
+	claims, err := i.authenticator.IsAuthenticated(ctx, token)
+	if err != nil {
+		if errors.Is(err, authentication.ErrInternal) {
+			l.Error("failed to authenticate token", zap.Error(err))
+			return nil, ErrInternal
+		}
+
+		return &Introspection{}, nil
+	}
+
+	l = l.With(zap.String("user_id", claims.UserID.String()))
+
+	authCtx, err := i.authorizationContext(ctx, claims)
+	if err != nil {
+		// the token outlived its user
+		if errors.Is(err, accesstokens.ErrUserNotFound) {
+			return &Introspection{}, nil
+		}
+
+		l.Error("failed to get authorization context", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	introspection := &Introspection{
+		Active:         true,
+		Subject:        claims.UserID,
+		SessionID:      claims.SessionID,
+		IssuedAt:       claims.IssuedAt,
+		ExpiresAt:      claims.ExpiresAt,
+		ServiceAccount: claims.ServiceAccount,
+		Roles:          []records.RoleId{},
+		RoleAggs:       authCtx.Roles,
+		Scopes:         []records.PermissionId{},
+	}
+
+	for _, role := range authCtx.Roles {
+		introspection.Roles = append(introspection.Roles, role.RoleId)
+	}
+
+	// the roles of a personal access token only hold the permissions it is scoped to
+	if claims.AccessTokenID != uuid.Nil {
+		introspection.Scopes = permissionIDs(authCtx.Roles)
+	}
+
+	l.Debug("introspected token")
+	return introspection, nil
+}
+
+func (i *IntrospectorV1) authorizationContext(ctx context.Context, claims *authentication.UserClaims) (*authorization.Context, error) {
+	if claims.ServiceAccount {
+		return i.serviceAccounts.AuthorizationContext(ctx, claims.UserID)
+	}
+
+	return i.accessTokens.AuthorizationContext(ctx, claims)
+}
+
+// permissionIDs returns the ids of the permissions granted by the roles, without duplicates
+func permissionIDs(roleAggs []records.RoleAgg) []records.PermissionId {
+	ids := []records.PermissionId{}
+	seen := map[records.PermissionId]bool{}
+	for _, role := range roleAggs {
+		for _, permission := range role.Permissions {
+			if seen[permission.ID] {
+				continue
+			}
+
+			seen[permission.ID] = true
+			ids = append(ids, permission.ID)
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: introspection.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	introspection "github.com/ooqls/go-auth/domain/v1/introspection"
+	serviceaccounts "github.com/ooqls/go-auth/domain/v1/serviceaccounts"
+)
+
+// MockIntrospector is a mock of Introspector interface.
+type MockIntrospector struct {
+	ctrl     *gomock.Controller
+	recorder *MockIntrospectorMockRecorder
+}
+
+// MockIntrospectorMockRecorder is the mock recorder for MockIntrospector.
+type MockIntrospectorMockRecorder struct {
+	mock *MockIntrospector
+}
+
+// NewMockIntrospector creates a new mock instance.
+func NewMockIntrospector(ctrl *gomock.Controller) *MockIntrospector {
+	mock := &MockIntrospector{ctrl: ctrl}
+	mock.recorder = &MockIntrospectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIntrospector) EXPECT() *MockIntrospectorMockRecorder {
+	return m.recorder
+}
+
+// Introspect mocks base method.
+func (m *MockIntrospector) Introspect(ctx context.Context, caller serviceaccounts.ClientCredentials, token string) (*introspection.Introspection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Introspect", ctx, caller, token)
+	ret0, _ := ret[0].(*introspection.Introspection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Introspect indicates an expected call of Introspect.
+func (mr *MockIntrospectorMockRecorder) Introspect(ctx, caller, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Introspect", reflect.TypeOf((*MockIntrospector)(nil).Introspect), ctx, caller, token)
+}
@@ -0,0 +1,224 @@
+package jwks
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ooqls/go-log"
+	"go.uber.org/zap"
+)
+
+var l *zap.Logger
+
+func init() {
+	l = log.NewLogger("jwks")
+}
+
+// DefaultMinRefetchInterval limits how often the keys are fetched again for tokens with an unknown kid
+const DefaultMinRefetchInterval = time.Minute
+
+var (
+	ErrUnknownKey error = errors.New("unknown signing key")
+)
+
+// jwk is a public key of a JWK set, RFC 7517
+type jwk struct {
+	KeyType string `json:"kty"`
+	Use     string `json:"use,omitempty"`
+	KeyID   string `json:"kid,omitempty"`
+	N       string `json:"n,omitempty"`
+	E       string `json:"e,omitempty"`
+	Curve   string `json:"crv,omitempty"`
+	X       string `json:"x,omitempty"`
+	Y       string `json:"y,omitempty"`
+}
+
+// KeySet caches the signing keys of a jwks_uri. Keys are fetched again when a token
+// is signed with a key that is not known, providers publish new keys before signing with them
+type KeySet struct {
+	client             *http.Client
+	uri                string
+	minRefetchInterval time.Duration
+	mu                 sync.Mutex
+	keys               map[string]crypto.PublicKey
+	fetchedAt          time.Time
+}
+
+// NewKeySet returns the key set of uri, the keys are fetched again at most once per minRefetchInterval
+func NewKeySet(client *http.Client, uri string, minRefetchInterval time.Duration) *KeySet {
+	return &KeySet{
+		client:             client,
+		uri:                uri,
+		minRefetchInterval: minRefetchInterval,
+		keys:               map[string]crypto.PublicKey{},
+	}
+}
+
+// Key returns the key with the kid, a token without a kid can only be verified when the set has a single key.
+// ErrUnknownKey is returned if the jwks_uri does not publish the key
+func (s *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.lookup(kid)
+	if ok {
+		return key, nil
+	}
+
+	if !s.fetchedAt.IsZero() && time.Since(s.fetchedAt) < s.minRefetchInterval {
+		return nil, ErrUnknownKey
+	}
+
+	err := s.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok = s.lookup(kid)
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	return key, nil
+}
+
+func (s *KeySet) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" {
+		if len(s.keys) != 1 {
+			return nil, false
+		}
+
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+func (s *KeySet) fetch(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.uri, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("jwks_uri responded with %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&set)
+	if err != nil {
+		return err
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			// keys of unsupported types are skipped, the provider may sign with another one
+			l.Warn("skipping signing key", zap.String("jwks_uri", s.uri), zap.String("kid", k.KeyID), zap.Error(err))
+			continue
+		}
+
+		keys[k.KeyID] = key
+	}
+
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	return nil
+}
+
+// publicKey returns the RSA, EC or Ed25519 public key of the JWK
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		if key.N.BitLen() < 2048 || key.E < 3 {
+			return nil, fmt.Errorf("weak rsa key")
+		}
+
+		return key, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", k.Curve)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, fmt.Errorf("point is not on curve %s", k.Curve)
+		}
+
+		return key, nil
+	case "OKP":
+		if k.Curve != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %s", k.Curve)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid ed25519 key")
+		}
+
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %s", k.KeyType)
+}
@@ -0,0 +1,67 @@
+package keyring
+
+import (
+	"time"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/ooqls/go-crypto/jwt"
+)
+
+var _ jwt.TokenIssuer[any] = &tokenIssuer[any]{}
+
+// tokenIssuer issues the same tokens as the go-crypto issuer, but signs them with the active key of a ring
+// and verifies them with the key of their kid
+type tokenIssuer[C any] struct {
+	cfg    *jwt.TokenConfiguration
+	ring   KeyRing
+	parser *jwtv5.Parser
+}
+
+// NewTokenIssuer returns a token issuer for cfg that signs with the ring
+func NewTokenIssuer[C any](cfg *jwt.TokenConfiguration, ring KeyRing) jwt.TokenIssuer[C] {
+	var opts []jwtv5.ParserOption
+	for _, aud := range cfg.Audience {
+		opts = append(opts, jwtv5.WithAudience(aud))
+	}
+	opts = append(opts, jwtv5.WithIssuer(cfg.Issuer))
+	opts = append(opts, jwtv5.WithValidMethods([]string{jwtv5.SigningMethodRS256.Name}))
+
+	return &tokenIssuer[C]{
+		cfg:    cfg,
+		ring:   ring,
+		parser: jwtv5.NewParser(opts...),
+	}
+}
+
+func (i *tokenIssuer[C]) IssueToken(subject string, customClaim C) (string, *jwtv5.Token, error) {
+	if subject == "" {
+		return "", nil, jwt.ErrInvalidSubject
+	}
+
+	now := time.Now()
+	claims := jwt.ClaimsWrapper[C]{
+		RegisteredClaims: jwtv5.RegisteredClaims{
+			Issuer:    i.cfg.Issuer,
+			Subject:   subject,
+			Audience:  i.cfg.Audience,
+			ExpiresAt: jwtv5.NewNumericDate(now.Add(time.Second * time.Duration(i.cfg.ValidityDurationSeconds))),
+			NotBefore: jwtv5.NewNumericDate(now),
+			IssuedAt:  jwtv5.NewNumericDate(now),
+			ID:        uuid.NewString(),
+		},
+		CustomClaims: customClaim,
+	}
+
+	return i.ring.Sign(claims)
+}
+
+func (i *tokenIssuer[C]) Decrypt(token string) (*jwtv5.Token, C, error) {
+	claims := jwt.ClaimsWrapper[C]{}
+	jwtToken, err := i.parser.ParseWithClaims(token, &claims, i.ring.Keyfunc)
+	return jwtToken, claims.CustomClaims, err
+}
+
+func (i *tokenIssuer[C]) GetIssuer() string {
+	return i.cfg.Issuer
+}
@@ -0,0 +1,119 @@
+package keyring
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"github.com/ooqls/go-log"
+	"go.uber.org/zap"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=keyring.go -destination=mocks/mock_keyring.go -package=mocks
+
+var l *zap.Logger
+
+func init() {
+	l = log.NewLogger("keyring")
+}
+
+var (
+	ErrNoActiveKey        error = errors.New("key ring has no active key")
+	ErrUnknownKey         error = errors.New("unknown signing key")
+	ErrInvalidKey         error = errors.New("invalid signing key")
+	ErrInvalidSealKey     error = errors.New("seal key must be 32 bytes")
+	ErrUnsupportedKeyType error = errors.New("unsupported key type")
+)
+
+// PublicKey is the public half of a key in the ring, tokens it signed have KeyID in their kid header
+type PublicKey struct {
+	KeyID     string
+	Key       *rsa.PublicKey
+	CreatedAt time.Time
+	// ExpiresAt is when the key stops verifying, it is zero while the key is active
+	ExpiresAt time.Time
+}
+
+// KeyRing holds the keys tokens are signed with. The active key signs new tokens, the keys it replaced
+// keep verifying the tokens they signed until those expire, so rotating does not end any session
+type KeyRing interface {
+	// Sign signs the claims with the active key and sets the kid header
+	Sign(claims jwtv5.Claims) (string, *jwtv5.Token, error)
+	// Keyfunc returns the verification key of the token's kid, it is a jwt.Keyfunc
+	Keyfunc(token *jwtv5.Token) (any, error)
+	// PublicKeys returns the keys that verify tokens, to be published as a JWKS
+	PublicKeys() []PublicKey
+	ActiveKey() (*PublicKey, error)
+	// Rotate makes a new key the active key and retires the others
+	Rotate(ctx context.Context) error
+	// Reload picks up the keys other instances rotated in and drops expired keys
+	Reload(ctx context.Context) error
+}
+
+// Thumbprint returns the RFC 7638 thumbprint of an RSA public key, it is the kid of the key
+func Thumbprint(key *rsa.PublicKey) string {
+	n := base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+
+	// the members are in lexicographic order without whitespace
+	sum := sha256.Sum256([]byte(fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, e, n)))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// seal encrypts a private key with AES-GCM, the key id is authenticated so a sealed key can not be moved to another row
+func seal(sealKey []byte, keyID string, privateKey []byte) ([]byte, error) {
+	gcm, err := newGCM(sealKey)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, privateKey, []byte(keyID)), nil
+}
+
+// open decrypts a private key sealed with seal
+func open(sealKey []byte, keyID string, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(sealKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrInvalidKey
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	privateKey, err := gcm.Open(nil, nonce, ciphertext, []byte(keyID))
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+
+	return privateKey, nil
+}
+
+func newGCM(sealKey []byte) (cipher.AEAD, error) {
+	if len(sealKey) != 32 {
+		return nil, ErrInvalidSealKey
+	}
+
+	block, err := aes.NewCipher(sealKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,240 @@
+package keyring
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"testing"
+	"time"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/ooqls/go-auth/records/v1/signingkeys"
+	"github.com/ooqls/go-auth/records/v1/signingkeys/mocks"
+	"github.com/ooqls/go-crypto/jwt"
+	"github.com/ooqls/go-crypto/keys"
+	"github.com/stretchr/testify/assert"
+)
+
+type testClaims struct {
+	Name string `json:"name"`
+}
+
+var testTokenConfig = &jwt.TokenConfiguration{
+	Issuer:                  "test",
+	Audience:                []string{"auth"},
+	ValidityDurationSeconds: 60,
+}
+
+// newTestStore returns record mocks that keep the signing keys like the database would
+func newTestStore(ctrl *gomock.Controller) (*mocks.MockReader, *mocks.MockWriter) {
+	stored := []signingkeys.SigningKey{}
+
+	reader := mocks.NewMockReader(ctrl)
+	reader.EXPECT().ListKeys(gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context) ([]signingkeys.SigningKey, error) {
+			return append([]signingkeys.SigningKey{}, stored...), nil
+		})
+
+	writer := mocks.NewMockWriter(ctrl)
+	writer.EXPECT().CreateKey(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, key signingkeys.SigningKey) error {
+			key.CreatedAt = time.Now()
+			stored = append(stored, key)
+			return nil
+		})
+	writer.EXPECT().RetireKeys(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, activeID string, expiresAt time.Time) error {
+			for i := range stored {
+				if stored[i].ID != activeID && !stored[i].RetiredAt.Valid {
+					stored[i].RetiredAt = sql.NullTime{Time: time.Now(), Valid: true}
+					stored[i].ExpiresAt = sql.NullTime{Time: expiresAt, Valid: true}
+				}
+			}
+			return nil
+		})
+	writer.EXPECT().DeleteExpiredKeys(gomock.Any()).AnyTimes().Return(int64(0), nil)
+
+	return reader, writer
+}
+
+func newSealKey(t *testing.T) []byte {
+	sealKey := make([]byte, 32)
+	_, err := rand.Read(sealKey)
+	assert.Nilf(t, err, "failed to create seal key: %v", err)
+	return sealKey
+}
+
+func kid(t *testing.T, token string) string {
+	parsed, _, err := jwtv5.NewParser().ParseUnverified(token, &jwtv5.RegisteredClaims{})
+	assert.Nilf(t, err, "should parse the token: %v", err)
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+func TestKeyRing_Rotate(t *testing.T) {
+	ctx := context.Background()
+	ring, err := NewKeyRingV1(nil, nil, nil, time.Hour, nil)
+	assert.Nilf(t, err, "should create the key ring: %v", err)
+
+	issuer := NewTokenIssuer[testClaims](testTokenConfig, ring)
+	_, _, err = issuer.IssueToken("user", testClaims{Name: "user"})
+	assert.ErrorIsf(t, err, ErrNoActiveKey, "should not sign before the ring is loaded")
+
+	err = ring.Reload(ctx)
+	assert.Nilf(t, err, "should rotate in the first key: %v", err)
+	active, err := ring.ActiveKey()
+	assert.Nilf(t, err, "should have an active key: %v", err)
+
+	oldToken, _, err := issuer.IssueToken("user", testClaims{Name: "user"})
+	assert.Nilf(t, err, "should issue a token: %v", err)
+	assert.Equalf(t, active.KeyID, kid(t, oldToken), "should set the kid of the active key")
+
+	err = ring.Rotate(ctx)
+	assert.Nilf(t, err, "should rotate: %v", err)
+
+	newToken, _, err := issuer.IssueToken("user", testClaims{Name: "user"})
+	assert.Nilf(t, err, "should issue a token: %v", err)
+	assert.NotEqualf(t, kid(t, oldToken), kid(t, newToken), "should sign with the new key")
+
+	_, claims, err := issuer.Decrypt(oldToken)
+	assert.Nilf(t, err, "should verify tokens of the retired key: %v", err)
+	assert.Equalf(t, "user", claims.Name, "should return the custom claims")
+
+	_, _, err = issuer.Decrypt(newToken)
+	assert.Nilf(t, err, "should verify tokens of the new key: %v", err)
+	assert.Lenf(t, ring.PublicKeys(), 2, "should publish the retired key until it expires")
+
+	ring.keys[active.KeyID].ExpiresAt = time.Now().Add(-time.Second)
+	_, _, err = issuer.Decrypt(oldToken)
+	assert.ErrorIsf(t, err, ErrUnknownKey, "should not verify tokens of expired keys")
+	assert.Lenf(t, ring.PublicKeys(), 1, "should not publish expired keys")
+
+	otherIssuer := NewTokenIssuer[testClaims](&jwt.TokenConfiguration{
+		Issuer:                  "test",
+		Audience:                []string{"refresh"},
+		ValidityDurationSeconds: 60,
+	}, ring)
+	_, _, err = otherIssuer.Decrypt(newToken)
+	assert.NotNilf(t, err, "should check the audience")
+}
+
+func TestKeyRing_Persisted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	reader, writer := newTestStore(ctrl)
+	sealKey := newSealKey(t)
+
+	_, err := NewKeyRingV1(reader, writer, []byte("short"), time.Hour, nil)
+	assert.ErrorIsf(t, err, ErrInvalidSealKey, "should require a 32 byte seal key")
+
+	first, err := NewKeyRingV1(reader, writer, sealKey, time.Hour, nil)
+	assert.Nilf(t, err, "should create the key ring: %v", err)
+	err = first.Reload(ctx)
+	assert.Nilf(t, err, "should rotate in the first key: %v", err)
+
+	stored, _ := reader.ListKeys(ctx)
+	assert.Lenf(t, stored, 1, "should store the key")
+	assert.Equalf(t, "RS256", stored[0].Algorithm, "should store the algorithm")
+
+	second, err := NewKeyRingV1(reader, writer, sealKey, time.Hour, nil)
+	assert.Nilf(t, err, "should create the key ring: %v", err)
+	err = second.Reload(ctx)
+	assert.Nilf(t, err, "should load the stored key: %v", err)
+
+	firstActive, _ := first.ActiveKey()
+	secondActive, _ := second.ActiveKey()
+	assert.Equalf(t, firstActive.KeyID, secondActive.KeyID, "instances should share the active key")
+
+	firstIssuer := NewTokenIssuer[testClaims](testTokenConfig, first)
+	secondIssuer := NewTokenIssuer[testClaims](testTokenConfig, second)
+
+	err = first.Rotate(ctx)
+	assert.Nilf(t, err, "should rotate: %v", err)
+	token, _, err := firstIssuer.IssueToken("user", testClaims{Name: "user"})
+	assert.Nilf(t, err, "should issue a token: %v", err)
+
+	second.lastReload = time.Time{}
+	_, _, err = secondIssuer.Decrypt(token)
+	assert.Nilf(t, err, "should reload to verify tokens of keys rotated in by another instance: %v", err)
+
+	secondActive, _ = second.ActiveKey()
+	assert.Equalf(t, kid(t, token), secondActive.KeyID, "should make the newest key active")
+
+	wrongSealKey, err := NewKeyRingV1(reader, writer, newSealKey(t), time.Hour, nil)
+	assert.Nilf(t, err, "should create the key ring: %v", err)
+	err = wrongSealKey.Reload(ctx)
+	assert.ErrorIsf(t, err, ErrInvalidKey, "should not open keys sealed with another key")
+
+	stored, _ = reader.ListKeys(ctx)
+	assert.Lenf(t, stored, 2, "should not rotate when the stored keys can not be opened")
+}
+
+func TestKeyRing_Legacy(t *testing.T) {
+	rsaKey, err := keys.NewRSA()
+	assert.Nilf(t, err, "failed to create key: %v", err)
+	legacy := keys.NewJWTKey(*rsaKey)
+
+	legacyToken, _, err := jwt.NewJwtTokenIssuer[testClaims](testTokenConfig, legacy).IssueToken("user", testClaims{Name: "user"})
+	assert.Nilf(t, err, "should issue a legacy token: %v", err)
+
+	ring, err := NewKeyRingV1(nil, nil, nil, time.Hour, legacy)
+	assert.Nilf(t, err, "should create the key ring: %v", err)
+	err = ring.Reload(context.Background())
+	assert.Nilf(t, err, "should load the ring: %v", err)
+
+	_, claims, err := NewTokenIssuer[testClaims](testTokenConfig, ring).Decrypt(legacyToken)
+	assert.Nilf(t, err, "should verify tokens without a kid with the legacy key: %v", err)
+	assert.Equalf(t, "user", claims.Name, "should return the custom claims")
+	assert.Lenf(t, ring.PublicKeys(), 2, "should publish the legacy key")
+	assert.WithinDurationf(t, time.Now().Add(time.Hour), ring.legacy.ExpiresAt, time.Minute, "the legacy key should expire once its tokens have")
+
+	ring.legacy.ExpiresAt = time.Now().Add(-time.Second)
+	_, _, err = NewTokenIssuer[testClaims](testTokenConfig, ring).Decrypt(legacyToken)
+	assert.ErrorIsf(t, err, ErrUnknownKey, "should not verify tokens without a kid once the legacy key expired")
+	assert.Lenf(t, ring.PublicKeys(), 1, "should not publish the expired legacy key")
+
+	ring, err = NewKeyRingV1(nil, nil, nil, time.Hour, nil)
+	assert.Nilf(t, err, "should create the key ring: %v", err)
+	err = ring.Reload(context.Background())
+	assert.Nilf(t, err, "should load the ring: %v", err)
+
+	_, _, err = NewTokenIssuer[testClaims](testTokenConfig, ring).Decrypt(legacyToken)
+	assert.ErrorIsf(t, err, ErrUnknownKey, "should not verify tokens without a kid")
+}
+
+func TestRotator(t *testing.T) {
+	ctx := context.Background()
+	ring, err := NewKeyRingV1(nil, nil, nil, time.Hour, nil)
+	assert.Nilf(t, err, "should create the key ring: %v", err)
+
+	err = NewRotator(ring, time.Hour).RotateIfDue(ctx)
+	assert.Nilf(t, err, "should load the ring: %v", err)
+	active, _ := ring.ActiveKey()
+
+	err = NewRotator(ring, time.Hour).RotateIfDue(ctx)
+	assert.Nilf(t, err, "should not fail: %v", err)
+	notDue, _ := ring.ActiveKey()
+	assert.Equalf(t, active.KeyID, notDue.KeyID, "should not rotate before the period passed")
+
+	err = NewRotator(ring, 0).RotateIfDue(ctx)
+	assert.Nilf(t, err, "should rotate: %v", err)
+	due, _ := ring.ActiveKey()
+	assert.NotEqualf(t, active.KeyID, due.KeyID, "should rotate once the period passed")
+}
+
+func TestSeal(t *testing.T) {
+	sealKey := newSealKey(t)
+	sealed, err := seal(sealKey, "kid", []byte("private key"))
+	assert.Nilf(t, err, "should seal: %v", err)
+	assert.NotContainsf(t, string(sealed), "private key", "should encrypt the key")
+
+	opened, err := open(sealKey, "kid", sealed)
+	assert.Nilf(t, err, "should open: %v", err)
+	assert.Equalf(t, []byte("private key"), opened, "should round trip the key")
+
+	_, err = open(sealKey, "other", sealed)
+	assert.ErrorIsf(t, err, ErrInvalidKey, "should bind the sealed key to its kid")
+}
@@ -0,0 +1,379 @@
+package keyring
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"sync"
+	"time"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"github.com/ooqls/go-auth/records/v1/signingkeys"
+	"github.com/ooqls/go-crypto/keys"
+	"go.uber.org/zap"
+)
+
+const (
+	keySize = 2048
+	// ReloadInterval is how often instances should reload the ring to pick up keys rotated in by others,
+	// retired keys verify for that much longer than their tokens live
+	ReloadInterval = time.Minute
+	// minReloadInterval limits how often an unknown kid triggers a reload
+	minReloadInterval = 10 * time.Second
+)
+
+var _ KeyRing = &KeyRingV1{}
+
+type ringKey struct {
+	PublicKey
+	privateKey *rsa.PrivateKey
+}
+
+func (k *ringKey) expired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && !now.Before(k.ExpiresAt)
+}
+
+type KeyRingV1 struct {
+	reader    signingkeys.Reader
+	writer    signingkeys.Writer
+	sealKey   []byte
+	retention time.Duration
+	legacy    *PublicKey
+
+	// reloadMu serializes reloads and rotations, mu guards the keys
+	reloadMu   sync.Mutex
+	mu         sync.RWMutex
+	keys       map[string]*ringKey
+	active     *ringKey
+	lastReload time.Time
+}
+
+// NewKeyRingV1 returns a key ring persisted with reader and writer, private keys are stored sealed with sealKey.
+// The ring is kept in memory only when reader and writer are nil, keys are then lost on restart.
+// retention is the validity of the longest lived token the ring signs, retired keys verify for that long.
+// Tokens without a kid are verified with legacy, the key tokens were signed with before the ring, if it is not nil.
+// Like a retired key the legacy key stops verifying and is no longer published once retention has passed, so
+// whoever holds its private key can not sign tokens for longer than the tokens it signed live.
+// Reload has to be called before the ring can sign
+func NewKeyRingV1(
+	reader signingkeys.Reader,
+	writer signingkeys.Writer,
+	sealKey []byte,
+	retention time.Duration,
+	legacy keys.JwtSigningKey) (*KeyRingV1, error) {
+
+	if reader != nil && len(sealKey) != 32 {
+		return nil, ErrInvalidSealKey
+	}
+
+	ring := &KeyRingV1{
+		reader:    reader,
+		writer:    writer,
+		sealKey:   sealKey,
+		retention: retention,
+		keys:      map[string]*ringKey{},
+	}
+
+	if legacy != nil {
+		ring.legacy = &PublicKey{
+			KeyID:     Thumbprint(legacy.PublicKey()),
+			Key:       legacy.PublicKey(),
+			ExpiresAt: time.Now().Add(retention),
+		}
+	}
+
+	return ring, nil
+}
+
+func (r *KeyRingV1) Sign(claims jwtv5.Claims) (string, *jwtv5.Token, error) {
+	r.mu.RLock()
+	active := r.active
+	r.mu.RUnlock()
+
+	if active == nil {
+		return "", nil, ErrNoActiveKey
+	}
+
+	token := jwtv5.NewWithClaims(jwtv5.SigningMethodRS256, claims)
+	token.Header["kid"] = active.KeyID
+	signed, err := token.SignedString(active.privateKey)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return signed, token, nil
+}
+
+func (r *KeyRingV1) Keyfunc(token *jwtv5.Token) (any, error) {
+	if _, ok := token.Method.(*jwtv5.SigningMethodRSA); !ok {
+		return nil, ErrUnsupportedKeyType
+	}
+
+	kid, ok := token.Header["kid"].(string)
+	if !ok || kid == "" {
+		if !r.legacyValid(time.Now()) {
+			return nil, ErrUnknownKey
+		}
+
+		return r.legacy.Key, nil
+	}
+
+	key, ok := r.lookup(kid)
+	if !ok && r.reloadDue() {
+		// another instance may have rotated in a key since the last reload
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := r.Reload(ctx)
+		if err != nil {
+			l.Error("failed to reload key ring", zap.Error(err))
+		}
+
+		key, ok = r.lookup(kid)
+	}
+
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	return key, nil
+}
+
+func (r *KeyRingV1) lookup(kid string) (*rsa.PublicKey, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.keys[kid]
+	if !ok || key.expired(time.Now()) {
+		return nil, false
+	}
+
+	return key.Key, true
+}
+
+// legacyValid returns true if there is a legacy key and it has not expired
+func (r *KeyRingV1) legacyValid(now time.Time) bool {
+	return r.legacy != nil && now.Before(r.legacy.ExpiresAt)
+}
+
+func (r *KeyRingV1) reloadDue() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.reader != nil && time.Since(r.lastReload) >= minReloadInterval
+}
+
+func (r *KeyRingV1) PublicKeys() []PublicKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	publicKeys := []PublicKey{}
+	for _, key := range r.keys {
+		if !key.expired(now) {
+			publicKeys = append(publicKeys, key.PublicKey)
+		}
+	}
+
+	if r.legacyValid(now) {
+		if _, ok := r.keys[r.legacy.KeyID]; !ok {
+			publicKeys = append(publicKeys, *r.legacy)
+		}
+	}
+
+	return publicKeys
+}
+
+func (r *KeyRingV1) ActiveKey() (*PublicKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.active == nil {
+		return nil, ErrNoActiveKey
+	}
+
+	active := r.active.PublicKey
+	return &active, nil
+}
+
+// Rotate generates a new key, makes it the active key and retires the others.
+// Retired keys verify for the retention of the ring and the reload interval, since instances
+// that have not reloaded yet keep signing with the key they had
+func (r *KeyRingV1) Rotate(ctx context.Context) error {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, keySize)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	key := &ringKey{
+		PublicKey: PublicKey{
+			KeyID:     Thumbprint(&privateKey.PublicKey),
+			Key:       &privateKey.PublicKey,
+			CreatedAt: now,
+		},
+		privateKey: privateKey,
+	}
+	expiresAt := now.Add(r.retention + ReloadInterval)
+
+	if r.writer != nil {
+		sealed, err := seal(r.sealKey, key.KeyID, x509.MarshalPKCS1PrivateKey(privateKey))
+		if err != nil {
+			return err
+		}
+
+		err = r.writer.CreateKey(ctx, signingkeys.SigningKey{
+			ID:         key.KeyID,
+			Algorithm:  jwtv5.SigningMethodRS256.Alg(),
+			PrivateKey: sealed,
+		})
+		if err != nil {
+			l.Error("failed to store signing key", zap.Error(err))
+			return err
+		}
+
+		err = r.writer.RetireKeys(ctx, key.KeyID, expiresAt)
+		if err != nil {
+			l.Error("failed to retire signing keys", zap.Error(err))
+			return err
+		}
+
+		deleted, err := r.writer.DeleteExpiredKeys(ctx)
+		if err != nil {
+			l.Error("failed to delete expired signing keys", zap.Error(err))
+		} else if deleted > 0 {
+			l.Info("deleted expired signing keys", zap.Int64("count", deleted))
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, old := range r.keys {
+		if old.expired(now) {
+			delete(r.keys, id)
+			continue
+		}
+
+		if old.ExpiresAt.IsZero() {
+			old.ExpiresAt = expiresAt
+		}
+	}
+
+	r.keys[key.KeyID] = key
+	r.active = key
+	l.Info("rotated signing key", zap.String("kid", key.KeyID))
+	return nil
+}
+
+// Reload loads the keys from the store, the newest key that is not retired becomes the active key.
+// A key is rotated in when there is none, keys that can not be opened with the seal key are skipped
+func (r *KeyRingV1) Reload(ctx context.Context) error {
+	if r.reader == nil {
+		r.mu.Lock()
+		now := time.Now()
+		for id, key := range r.keys {
+			if key.expired(now) {
+				delete(r.keys, id)
+			}
+		}
+		hasActive := r.active != nil
+		r.mu.Unlock()
+
+		if !hasActive {
+			return r.Rotate(ctx)
+		}
+
+		return nil
+	}
+
+	r.reloadMu.Lock()
+	stored, err := r.reader.ListKeys(ctx)
+	if err != nil {
+		r.reloadMu.Unlock()
+		l.Error("failed to list signing keys", zap.Error(err))
+		return err
+	}
+
+	r.mu.RLock()
+	known := r.keys
+	r.mu.RUnlock()
+
+	loaded := map[string]*ringKey{}
+	var active *ringKey
+	var openErr error
+	for _, s := range stored {
+		key, ok := known[s.ID]
+		if !ok {
+			key, err = r.openKey(s)
+			if err != nil {
+				l.Error("failed to open signing key", zap.String("kid", s.ID), zap.Error(err))
+				openErr = err
+				continue
+			}
+		}
+
+		// copy so readers of the previous map are not affected
+		loadedKey := *key
+		loadedKey.CreatedAt = s.CreatedAt
+		loadedKey.ExpiresAt = time.Time{}
+		if s.ExpiresAt.Valid {
+			loadedKey.ExpiresAt = s.ExpiresAt.Time
+		}
+
+		loaded[s.ID] = &loadedKey
+		if !s.RetiredAt.Valid && (active == nil || !s.CreatedAt.Before(active.CreatedAt)) {
+			active = &loadedKey
+		}
+	}
+
+	r.mu.Lock()
+	r.keys = loaded
+	r.active = active
+	r.lastReload = time.Now()
+	r.mu.Unlock()
+	r.reloadMu.Unlock()
+
+	if active == nil {
+		if openErr != nil {
+			// rotating would retire the keys of the instances that can open them
+			return openErr
+		}
+
+		return r.Rotate(ctx)
+	}
+
+	return nil
+}
+
+func (r *KeyRingV1) openKey(s signingkeys.SigningKey) (*ringKey, error) {
+	if s.Algorithm != jwtv5.SigningMethodRS256.Alg() {
+		return nil, ErrUnsupportedKeyType
+	}
+
+	der, err := open(r.sealKey, s.ID, s.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := x509.ParsePKCS1PrivateKey(der)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+
+	if Thumbprint(&privateKey.PublicKey) != s.ID {
+		return nil, ErrInvalidKey
+	}
+
+	return &ringKey{
+		PublicKey: PublicKey{
+			KeyID: s.ID,
+			Key:   &privateKey.PublicKey,
+		},
+		privateKey: privateKey,
+	}, nil
+}
@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: keyring.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+	gomock "github.com/golang/mock/gomock"
+	keyring "github.com/ooqls/go-auth/domain/v1/keyring"
+)
+
+// MockKeyRing is a mock of KeyRing interface.
+type MockKeyRing struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyRingMockRecorder
+}
+
+// MockKeyRingMockRecorder is the mock recorder for MockKeyRing.
+type MockKeyRingMockRecorder struct {
+	mock *MockKeyRing
+}
+
+// NewMockKeyRing creates a new mock instance.
+func NewMockKeyRing(ctrl *gomock.Controller) *MockKeyRing {
+	mock := &MockKeyRing{ctrl: ctrl}
+	mock.recorder = &MockKeyRingMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyRing) EXPECT() *MockKeyRingMockRecorder {
+	return m.recorder
+}
+
+// ActiveKey mocks base method.
+func (m *MockKeyRing) ActiveKey() (*keyring.PublicKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActiveKey")
+	ret0, _ := ret[0].(*keyring.PublicKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActiveKey indicates an expected call of ActiveKey.
+func (mr *MockKeyRingMockRecorder) ActiveKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActiveKey", reflect.TypeOf((*MockKeyRing)(nil).ActiveKey))
+}
+
+// Keyfunc mocks base method.
+func (m *MockKeyRing) Keyfunc(token *jwt.Token) (any, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Keyfunc", token)
+	ret0, _ := ret[0].(any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Keyfunc indicates an expected call of Keyfunc.
+func (mr *MockKeyRingMockRecorder) Keyfunc(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keyfunc", reflect.TypeOf((*MockKeyRing)(nil).Keyfunc), token)
+}
+
+// PublicKeys mocks base method.
+func (m *MockKeyRing) PublicKeys() []keyring.PublicKey {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublicKeys")
+	ret0, _ := ret[0].([]keyring.PublicKey)
+	return ret0
+}
+
+// PublicKeys indicates an expected call of PublicKeys.
+func (mr *MockKeyRingMockRecorder) PublicKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublicKeys", reflect.TypeOf((*MockKeyRing)(nil).PublicKeys))
+}
+
+// Reload mocks base method.
+func (m *MockKeyRing) Reload(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reload", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reload indicates an expected call of Reload.
+func (mr *MockKeyRingMockRecorder) Reload(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reload", reflect.TypeOf((*MockKeyRing)(nil).Reload), ctx)
+}
+
+// Rotate mocks base method.
+func (m *MockKeyRing) Rotate(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rotate indicates an expected call of Rotate.
+func (mr *MockKeyRingMockRecorder) Rotate(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockKeyRing)(nil).Rotate), ctx)
+}
+
+// Sign mocks base method.
+func (m *MockKeyRing) Sign(claims jwt.Claims) (string, *jwt.Token, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sign", claims)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(*jwt.Token)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Sign indicates an expected call of Sign.
+func (mr *MockKeyRingMockRecorder) Sign(claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sign", reflect.TypeOf((*MockKeyRing)(nil).Sign), claims)
+}
@@ -0,0 +1,60 @@
+package keyring
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Rotator reloads a key ring every ReloadInterval and rotates its active key once it is older than the rotation period.
+// When several instances share the key ring they may rotate at the same time, the ring keeps the newest key active
+type Rotator struct {
+	ring   KeyRing
+	period time.Duration
+}
+
+func NewRotator(ring KeyRing, period time.Duration) *Rotator {
+	return &Rotator{
+		ring:   ring,
+		period: period,
+	}
+}
+
+// Run rotates the key ring until ctx is done
+func (r *Rotator) Run(ctx context.Context) {
+	ticker := time.NewTicker(ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		err := r.RotateIfDue(ctx)
+		if err != nil {
+			l.Error("failed to rotate key ring", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RotateIfDue reloads the key ring and rotates it if the active key is older than the rotation period
+func (r *Rotator) RotateIfDue(ctx context.Context) error {
+	err := r.ring.Reload(ctx)
+	if err != nil {
+		return err
+	}
+
+	active, err := r.ring.ActiveKey()
+	if err != nil {
+		return err
+	}
+
+	if time.Since(active.CreatedAt) < r.period {
+		return nil
+	}
+
+	return r.ring.Rotate(ctx)
+}
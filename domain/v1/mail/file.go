@@ -0,0 +1,29 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+var _ Mailer = &FileMailer{}
+
+// FileMailer writes emails to a file or log instead of sending them, it is meant for local development and tests
+type FileMailer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewFileMailer(w io.Writer) *FileMailer {
+	return &FileMailer{w: w}
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, err := fmt.Fprintf(m.w, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n", time.Now().Format(time.RFC1123Z), msg.To, msg.Subject, msg.Body)
+	return err
+}
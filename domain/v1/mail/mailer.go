@@ -0,0 +1,28 @@
+package mail
+
+import (
+	"context"
+
+	"github.com/ooqls/go-log"
+	"go.uber.org/zap"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=mailer.go -destination=mocks/mock_mailer.go -package=mocks
+
+var l *zap.Logger
+
+func init() {
+	l = log.NewLogger("mail")
+}
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers emails to users
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
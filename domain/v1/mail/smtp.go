@@ -0,0 +1,64 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+var _ Mailer = &SMTPMailer{}
+
+type SMTPConfig struct {
+	Host string
+	Port int
+	// Username and Password are used for PLAIN auth, no auth is done when Username is empty
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPMailer sends emails through an SMTP server, STARTTLS is used when the server supports it
+type SMTPMailer struct {
+	config SMTPConfig
+}
+
+func NewSMTPMailer(config SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{config: config}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if strings.ContainsAny(msg.To, "\r\n") || strings.ContainsAny(msg.Subject, "\r\n") {
+		return fmt.Errorf("invalid message header")
+	}
+
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	err := smtp.SendMail(addr, auth, m.config.From, []string{msg.To}, formatMessage(m.config.From, msg))
+	if err != nil {
+		l.Error("failed to send email", zap.String("subject", msg.Subject), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func formatMessage(from string, msg Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + msg.Subject + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,124 @@
+package oauth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"time"
+
+	"github.com/ooqls/go-auth/domain/v1/keyring"
+	"github.com/ooqls/go-auth/domain/v1/serivce/users"
+	"github.com/ooqls/go-auth/domain/v1/serviceaccounts"
+	"github.com/ooqls/go-auth/records/v1/oauthclients"
+	"github.com/ooqls/go-cache/factory"
+	"github.com/ooqls/go-cache/store"
+	"github.com/ooqls/go-crypto/jwt"
+)
+
+const (
+	// authorizationCodeTTL is how long a client has to exchange a code
+	authorizationCodeTTL = time.Minute
+	// consentRequestTTL is how long a user has to consent to a client
+	consentRequestTTL = 10 * time.Minute
+	// refreshTokenTTL is how long a refresh token can be used, every use issues a new one
+	refreshTokenTTL = 30 * 24 * time.Hour
+	// idTokenTTL is how long an id token is valid
+	idTokenTTL = time.Hour
+)
+
+var _ AuthorizationServer = &AuthorizationServerV1{}
+
+type AuthorizationServerV1 struct {
+	clientReader    oauthclients.Reader
+	clientWriter    oauthclients.Writer
+	userService     users.UserService
+	codes           store.GenericInterface
+	consentRequests store.GenericInterface
+	refreshTokens   store.GenericInterface
+	refreshFamilies store.GenericInterface
+	deviceCodes     store.GenericInterface
+	userCodes       store.GenericInterface
+	accessIssuer    jwt.TokenIssuer[AccessClaims]
+	keyRing         keyring.KeyRing
+	serviceAccounts serviceaccounts.ServiceAccountService
+}
+
+// NewAuthorizationServerV1 returns an authorization server issuing access tokens with accessIssuer,
+// whose audience should be the resource servers the clients call. Refresh tokens are opaque.
+// Id tokens are signed with keyRing, which must be the ring of accessIssuer, and the issuer of
+// accessIssuer must be the url the server is reachable at since OpenID Connect clients discover it from there.
+// The client credentials grant authenticates serviceAccounts, it is not supported if serviceAccounts is nil
+func NewAuthorizationServerV1(
+	clientReader oauthclients.Reader,
+	clientWriter oauthclients.Writer,
+	userService users.UserService,
+	cacheFactory factory.CacheFactory,
+	accessIssuer jwt.TokenIssuer[AccessClaims],
+	keyRing keyring.KeyRing,
+	serviceAccounts serviceaccounts.ServiceAccountService) AuthorizationServer {
+
+	return &AuthorizationServerV1{
+		clientReader:    clientReader,
+		clientWriter:    clientWriter,
+		userService:     userService,
+		codes:           cacheFactory.NewStore("oauth_codes", authorizationCodeTTL),
+		consentRequests: cacheFactory.NewStore("oauth_consent_requests", consentRequestTTL),
+		refreshTokens:   cacheFactory.NewStore("oauth_refresh_tokens", refreshTokenTTL),
+		refreshFamilies: cacheFactory.NewStore("oauth_refresh_families", refreshTokenTTL),
+		deviceCodes:     cacheFactory.NewStore("oauth_device_codes", deviceCodeTTL),
+		userCodes:       cacheFactory.NewStore("oauth_user_codes", deviceCodeTTL),
+		accessIssuer:    accessIssuer,
+		keyRing:         keyRing,
+		serviceAccounts: serviceAccounts,
+	}
+}
+
+// randomToken returns 32 random bytes encoded for use in urls
+func randomToken() (string, error) {
+	var b [32]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b[:]), nil
+}
+
+// tokenKey is the key a code or token is stored under, only its hash is stored
+func tokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// redirectWith adds the parameters to the client's redirect uri, keeping its own query
+func redirectWith(redirectURI string, params map[string]string) (string, error) {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	for k, v := range params {
+		if v != "" {
+			query.Set(k, v)
+		}
+	}
+
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// errorRedirect sends the error back to the client, RFC 6749 4.1.2.1
+func errorRedirect(redirectURI string, err error, state string) (*Authorization, error) {
+	redirect, parseErr := redirectWith(redirectURI, map[string]string{
+		"error": ErrorCode(err),
+		"state": state,
+	})
+	if parseErr != nil {
+		return nil, ErrInvalidRedirectURI
+	}
+
+	return &Authorization{RedirectURI: redirect}, nil
+}
@@ -0,0 +1,368 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/domain/v1/authentication"
+	"github.com/ooqls/go-auth/domain/v1/authorization"
+	"github.com/ooqls/go-auth/domain/v1/keyring"
+	usermocks "github.com/ooqls/go-auth/domain/v1/serivce/users/mocks"
+	"github.com/ooqls/go-auth/domain/v1/serviceaccounts"
+	accountmocks "github.com/ooqls/go-auth/domain/v1/serviceaccounts/mocks"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/oauthclients"
+	"github.com/ooqls/go-auth/records/v1/oauthclients/mocks"
+	"github.com/ooqls/go-auth/records/v1/users"
+	"github.com/ooqls/go-cache/factory"
+	"github.com/ooqls/go-crypto/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+const testRedirectURI = "https://app.example.com/callback"
+
+func newTestAuthorizationServer(t *testing.T, ctrl *gomock.Controller) (AuthorizationServer, jwt.TokenIssuer[AccessClaims]) {
+	return newTestAuthorizationServerWith(t, ctrl, nil)
+}
+
+// newTestAuthorizationServerWith returns a server whose record mocks keep clients and consents like the database would
+func newTestAuthorizationServerWith(t *testing.T, ctrl *gomock.Controller, serviceAccounts serviceaccounts.ServiceAccountService) (AuthorizationServer, jwt.TokenIssuer[AccessClaims]) {
+	clients := map[uuid.UUID]oauthclients.Client{}
+	consents := map[string]oauthclients.Consent{}
+	consentKey := func(userID records.UserId, clientID uuid.UUID) string {
+		return userID.String() + clientID.String()
+	}
+
+	reader := mocks.NewMockReader(ctrl)
+	reader.EXPECT().GetClient(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, id uuid.UUID) (*oauthclients.Client, error) {
+			client, ok := clients[id]
+			if !ok {
+				return nil, nil
+			}
+			return &client, nil
+		})
+	reader.EXPECT().GetConsent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, userID records.UserId, clientID uuid.UUID) (*oauthclients.Consent, error) {
+			consent, ok := consents[consentKey(userID, clientID)]
+			if !ok {
+				return nil, nil
+			}
+			return &consent, nil
+		})
+
+	writer := mocks.NewMockWriter(ctrl)
+	writer.EXPECT().CreateClient(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, client oauthclients.Client) (*oauthclients.Client, error) {
+			client.ID = uuid.New()
+			clients[client.ID] = client
+			return &client, nil
+		})
+	writer.EXPECT().UpsertConsent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, userID records.UserId, clientID uuid.UUID, scopes []string) (*oauthclients.Consent, error) {
+			consent := oauthclients.Consent{UserID: userID, ClientID: clientID, Scopes: scopes}
+			consents[consentKey(userID, clientID)] = consent
+			return &consent, nil
+		})
+	writer.EXPECT().DeleteConsent(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, userID records.UserId, clientID uuid.UUID) (bool, error) {
+			_, ok := consents[consentKey(userID, clientID)]
+			delete(consents, consentKey(userID, clientID))
+			return ok, nil
+		})
+
+	userService := usermocks.NewMockUserService(ctrl)
+	userService.EXPECT().GetUser(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ authorization.Context, id records.UserId) (*users.User, error) {
+			return &users.User{ID: id, Username: "user", Email: "user@example.com", EmailVerified: true}, nil
+		})
+
+	ring, err := keyring.NewKeyRingV1(nil, nil, nil, time.Hour, nil)
+	assert.Nilf(t, err, "failed to create key ring: %v", err)
+	err = ring.Reload(context.Background())
+	assert.Nilf(t, err, "failed to load key ring: %v", err)
+
+	issuer := keyring.NewTokenIssuer[AccessClaims](&jwt.TokenConfiguration{
+		Issuer:                  "https://auth.example.com",
+		Audience:                []string{"api"},
+		ValidityDurationSeconds: 300,
+	}, ring)
+
+	return NewAuthorizationServerV1(reader, writer, userService, &factory.MemCacheFactory{}, issuer, ring, serviceAccounts), issuer
+}
+
+func newPKCE() (verifier string, challenge string) {
+	verifier, _ = randomToken()
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func redirectParams(t *testing.T, authorization *Authorization) url.Values {
+	assert.NotNilf(t, authorization, "should return an authorization")
+	u, err := url.Parse(authorization.RedirectURI)
+	assert.Nilf(t, err, "should redirect to a valid uri: %v", err)
+	return u.Query()
+}
+
+func TestAuthorizationServer_RegisterClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	server, _ := newTestAuthorizationServer(t, ctrl)
+	owner := uuid.New()
+
+	_, _, err := server.RegisterClient(ctx, owner, "app", []string{"http://app.example.com/callback"}, []string{ScopeOpenID}, true)
+	assert.ErrorIsf(t, err, ErrInvalidRedirectURI, "should not allow plain http redirects to other hosts")
+
+	_, _, err = server.RegisterClient(ctx, owner, "app", []string{"https://app.example.com/callback#fragment"}, []string{ScopeOpenID}, true)
+	assert.ErrorIsf(t, err, ErrInvalidRedirectURI, "should not allow redirects with a fragment")
+
+	_, _, err = server.RegisterClient(ctx, owner, "app", []string{testRedirectURI}, []string{"admin"}, true)
+	assert.ErrorIsf(t, err, ErrInvalidScope, "should not register unsupported scopes")
+
+	client, secret, err := server.RegisterClient(ctx, owner, "native", []string{"http://127.0.0.1:8123/callback", "com.example.app:/callback"}, []string{ScopeOpenID}, false)
+	assert.Nilf(t, err, "should register a public native client: %v", err)
+	assert.Emptyf(t, secret, "public clients should not get a secret")
+	assert.Nilf(t, client.SecretHash, "public clients should not have a secret")
+
+	client, secret, err = server.RegisterClient(ctx, owner, "app", []string{testRedirectURI}, []string{ScopeEmail, ScopeOpenID}, true)
+	assert.Nilf(t, err, "should register a confidential client: %v", err)
+	assert.NotEmptyf(t, secret, "confidential clients should get a secret")
+	assert.NotEqualf(t, []byte(secret), client.SecretHash, "the secret should be stored hashed")
+
+	_, err = server.ValidateClient(ctx, uuid.NewString(), testRedirectURI)
+	assert.ErrorIsf(t, err, ErrInvalidClient, "should not validate an unknown client")
+
+	_, err = server.ValidateClient(ctx, client.ID.String(), "https://evil.example.com/callback")
+	assert.ErrorIsf(t, err, ErrInvalidRedirectURI, "should not validate an unregistered redirect uri")
+}
+
+func TestAuthorizationServer_AuthorizationCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	server, issuer := newTestAuthorizationServer(t, ctrl)
+	user := uuid.New()
+
+	client, secret, err := server.RegisterClient(ctx, uuid.New(), "app", []string{testRedirectURI}, []string{ScopeOpenID, ScopeEmail}, true)
+	assert.Nilf(t, err, "should register the client: %v", err)
+
+	verifier, challenge := newPKCE()
+	request := AuthorizeRequest{
+		ResponseType:        "code",
+		ClientID:            client.ID.String(),
+		RedirectURI:         testRedirectURI,
+		Scope:               "openid email",
+		State:               "xyz",
+		CodeChallenge:       challenge,
+		CodeChallengeMethod: codeChallengeMethodS256,
+	}
+
+	withoutPKCE := request
+	withoutPKCE.CodeChallengeMethod = "plain"
+	authorization, err := server.Authorize(ctx, user, withoutPKCE)
+	assert.Nilf(t, err, "should redirect the error to the client: %v", err)
+	params := redirectParams(t, authorization)
+	assert.Equalf(t, "invalid_request", params.Get("error"), "should require S256 PKCE")
+	assert.Equalf(t, "xyz", params.Get("state"), "should return the state with the error")
+
+	unknownScope := request
+	unknownScope.Scope = "openid profile"
+	authorization, err = server.Authorize(ctx, user, unknownScope)
+	assert.Nilf(t, err, "should redirect the error to the client: %v", err)
+	assert.Equalf(t, "invalid_scope", redirectParams(t, authorization).Get("error"), "should not grant scopes the client is not registered for")
+
+	authorization, err = server.Authorize(ctx, user, request)
+	assert.Nilf(t, err, "should not fail to authorize: %v", err)
+	assert.NotEmptyf(t, authorization.ConsentID, "should ask the user to consent the first time")
+
+	_, err = server.PendingConsent(ctx, uuid.New(), authorization.ConsentID)
+	assert.ErrorIsf(t, err, ErrConsentNotFound, "another user should not see the consent request")
+
+	consentRequest, err := server.PendingConsent(ctx, user, authorization.ConsentID)
+	assert.Nilf(t, err, "should get the consent request: %v", err)
+	assert.Equalf(t, "app", consentRequest.ClientName, "should show the client's name")
+	assert.Equalf(t, []string{ScopeOpenID, ScopeEmail}, consentRequest.Scopes, "should show the requested scopes")
+
+	consentID := authorization.ConsentID
+	authorization, err = server.Consent(ctx, user, consentID, true)
+	assert.Nilf(t, err, "should not fail to consent: %v", err)
+	params = redirectParams(t, authorization)
+	assert.NotEmptyf(t, params.Get("code"), "should redirect with a code")
+	assert.Equalf(t, "xyz", params.Get("state"), "should return the state")
+	code := params.Get("code")
+
+	_, err = server.Consent(ctx, user, consentID, true)
+	assert.ErrorIsf(t, err, ErrConsentNotFound, "should not answer a consent request twice")
+
+	tokenRequest := TokenRequest{
+		GrantType:    GrantTypeAuthorizationCode,
+		Code:         code,
+		RedirectURI:  testRedirectURI,
+		CodeVerifier: verifier,
+		ClientID:     client.ID.String(),
+		ClientSecret: secret,
+	}
+
+	wrongSecret := tokenRequest
+	wrongSecret.ClientSecret = "wrong"
+	_, err = server.Token(ctx, wrongSecret)
+	assert.ErrorIsf(t, err, ErrInvalidClient, "should authenticate the client")
+
+	tokens, err := server.Token(ctx, tokenRequest)
+	assert.Nilf(t, err, "should exchange the code: %v", err)
+	assert.Equalf(t, "Bearer", tokens.TokenType, "should issue bearer tokens")
+	assert.Equalf(t, "openid email", tokens.Scope, "should grant the consented scopes")
+	assert.Greaterf(t, tokens.ExpiresIn, 0, "should return when the access token expires")
+
+	_, claims, err := issuer.Decrypt(tokens.AccessToken)
+	assert.Nilf(t, err, "should issue a valid access token: %v", err)
+	assert.Equalf(t, user, claims.UserID, "access token should be for the user")
+	assert.Equalf(t, client.ID, claims.ClientID, "access token should be for the client")
+
+	_, err = server.Token(ctx, tokenRequest)
+	assert.ErrorIsf(t, err, ErrInvalidGrant, "should not exchange a code twice")
+
+	// the user is not asked to consent to the same scopes again
+	_, otherChallenge := newPKCE()
+	request.CodeChallenge = otherChallenge
+	authorization, err = server.Authorize(ctx, user, request)
+	assert.Nilf(t, err, "should not fail to authorize: %v", err)
+	assert.Emptyf(t, authorization.ConsentID, "should not ask for consent again")
+	tokenRequest.Code = redirectParams(t, authorization).Get("code")
+
+	_, err = server.Token(ctx, tokenRequest)
+	assert.ErrorIsf(t, err, ErrInvalidGrant, "should not exchange a code with the verifier of another request")
+
+	authorization, err = server.Authorize(ctx, uuid.New(), request)
+	assert.Nilf(t, err, "should not fail to authorize: %v", err)
+
+	_, err = server.Consent(ctx, uuid.Nil, authorization.ConsentID, false)
+	assert.ErrorIsf(t, err, ErrConsentNotFound, "should only let the user answer their consent request")
+}
+
+func TestAuthorizationServer_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	server, _ := newTestAuthorizationServer(t, ctrl)
+	user := uuid.New()
+
+	client, _, err := server.RegisterClient(ctx, uuid.New(), "app", []string{testRedirectURI}, []string{ScopeOpenID, ScopeEmail}, false)
+	assert.Nilf(t, err, "should register the client: %v", err)
+
+	login := func() *TokenResponse {
+		verifier, challenge := newPKCE()
+		authorization, err := server.Authorize(ctx, user, AuthorizeRequest{
+			ResponseType:        "code",
+			ClientID:            client.ID.String(),
+			RedirectURI:         testRedirectURI,
+			CodeChallenge:       challenge,
+			CodeChallengeMethod: codeChallengeMethodS256,
+		})
+		assert.Nilf(t, err, "should not fail to authorize: %v", err)
+
+		if authorization.ConsentID != "" {
+			authorization, err = server.Consent(ctx, user, authorization.ConsentID, true)
+			assert.Nilf(t, err, "should not fail to consent: %v", err)
+		}
+
+		tokens, err := server.Token(ctx, TokenRequest{
+			GrantType:    GrantTypeAuthorizationCode,
+			Code:         redirectParams(t, authorization).Get("code"),
+			RedirectURI:  testRedirectURI,
+			CodeVerifier: verifier,
+			ClientID:     client.ID.String(),
+		})
+		assert.Nilf(t, err, "should exchange the code: %v", err)
+		return tokens
+	}
+
+	refresh := func(refreshToken string, scope string) (*TokenResponse, error) {
+		return server.Token(ctx, TokenRequest{
+			GrantType:    GrantTypeRefreshToken,
+			RefreshToken: refreshToken,
+			Scope:        scope,
+			ClientID:     client.ID.String(),
+		})
+	}
+
+	tokens := login()
+
+	_, err = refresh(tokens.RefreshToken, "openid profile")
+	assert.ErrorIsf(t, err, ErrInvalidScope, "should not widen the scope")
+
+	// a failed refresh used the token
+	tokens = login()
+	refreshed, err := refresh(tokens.RefreshToken, "openid")
+	assert.Nilf(t, err, "should refresh: %v", err)
+	assert.Equalf(t, "openid", refreshed.Scope, "should narrow the scope")
+	assert.NotEqualf(t, tokens.RefreshToken, refreshed.RefreshToken, "should rotate the refresh token")
+
+	_, err = refresh(tokens.RefreshToken, "")
+	assert.ErrorIsf(t, err, ErrInvalidGrant, "should not accept a used refresh token")
+
+	_, err = refresh(refreshed.RefreshToken, "")
+	assert.ErrorIsf(t, err, ErrInvalidGrant, "reusing a refresh token should revoke its family")
+
+	tokens = login()
+	err = server.RevokeConsent(ctx, user, client.ID)
+	assert.Nilf(t, err, "should revoke the consent: %v", err)
+
+	_, err = refresh(tokens.RefreshToken, "")
+	assert.ErrorIsf(t, err, ErrInvalidGrant, "should not refresh after the user revoked their consent")
+
+	_, err = server.Token(ctx, TokenRequest{GrantType: "password", ClientID: client.ID.String()})
+	assert.ErrorIsf(t, err, ErrUnsupportedGrantType, "should only support the code and refresh grants")
+}
+
+func TestAuthorizationServer_ClientCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	accountID := uuid.New()
+	serviceAccounts := accountmocks.NewMockServiceAccountService(ctrl)
+	serviceAccounts.EXPECT().Authenticate(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, credentials serviceaccounts.ClientCredentials) (*authentication.TokenResponse, error) {
+			if credentials.ClientID != accountID.String() || credentials.ClientSecret != "secret" {
+				return nil, serviceaccounts.ErrInvalidCredentials
+			}
+			return &authentication.TokenResponse{AuthToken: "auth token", UserId: accountID, ExpiresIn: 300}, nil
+		})
+
+	server, _ := newTestAuthorizationServer(t, ctrl)
+	_, err := server.Token(ctx, TokenRequest{GrantType: GrantTypeClientCredentials, ClientID: accountID.String(), ClientSecret: "secret"})
+	assert.ErrorIsf(t, err, ErrUnsupportedGrantType, "should not support the grant without service accounts")
+	assert.NotContainsf(t, server.Discovery().GrantTypesSupported, GrantTypeClientCredentials, "should not advertise the grant without service accounts")
+
+	server, _ = newTestAuthorizationServerWith(t, ctrl, serviceAccounts)
+	assert.Containsf(t, server.Discovery().GrantTypesSupported, GrantTypeClientCredentials, "should advertise the grant")
+
+	response, err := server.Token(ctx, TokenRequest{GrantType: GrantTypeClientCredentials, ClientID: accountID.String(), ClientSecret: "secret"})
+	assert.Nilf(t, err, "should authenticate the service account: %v", err)
+	assert.Equalf(t, "auth token", response.AccessToken, "should return the service account's auth token")
+	assert.Equalf(t, "Bearer", response.TokenType, "should return a bearer token")
+	assert.Equalf(t, 300, response.ExpiresIn, "should return the token lifetime")
+	assert.Emptyf(t, response.RefreshToken, "should not issue a refresh token")
+
+	_, err = server.Token(ctx, TokenRequest{GrantType: GrantTypeClientCredentials, ClientID: accountID.String(), ClientSecret: "wrong"})
+	assert.ErrorIsf(t, err, ErrInvalidClient, "should not authenticate with wrong credentials")
+
+	_, err = server.Token(ctx, TokenRequest{GrantType: GrantTypeClientCredentials, ClientID: accountID.String(), ClientSecret: "secret", Scope: ScopeOpenID})
+	assert.ErrorIsf(t, err, ErrInvalidScope, "should not accept a scope")
+}
+
+func TestErrorCode(t *testing.T) {
+	assert.Equalf(t, "invalid_grant", ErrorCode(ErrInvalidGrant), "should return the protocol error code")
+	assert.Equalf(t, "server_error", ErrorCode(ErrInvalidRedirectURI), "should not leak other errors")
+}
@@ -0,0 +1,280 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/oauthclients"
+	"github.com/ooqls/go-cache/cache"
+	"go.uber.org/zap"
+)
+
+const codeChallengeMethodS256 = "S256"
+
+// pkceRegex matches code verifiers and S256 challenges, RFC 7636 4.1
+var pkceRegex = regexp.MustCompile(`^[A-Za-z0-9\-._~]{43,128}$`)
+
+// pendingAuthorization is an authorization request waiting for the user's consent
+type pendingAuthorization struct {
+	UserID        records.UserId
+	ClientID      uuid.UUID
+	RedirectURI   string
+	Scopes        []string
+	State         string
+	CodeChallenge string
+	Nonce         string
+	AuthTime      time.Time
+	Used          bool
+}
+
+// authorizationCode is what a code is exchanged for, it can only be exchanged once
+type authorizationCode struct {
+	UserID        records.UserId
+	ClientID      uuid.UUID
+	RedirectURI   string
+	Scopes        []string
+	CodeChallenge string
+	Nonce         string
+	AuthTime      time.Time
+	Used          bool
+}
+
+// verifyCodeChallenge checks the PKCE verifier against the S256 challenge of the authorization request
+func verifyCodeChallenge(challenge string, verifier string) bool {
+	if !pkceRegex.MatchString(verifier) {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+// Authorize handles an authorization request of the logged in user. The client and redirect uri are
+// checked first, errors after that are sent to the client's redirect uri
+func (s *AuthorizationServerV1) Authorize(ctx context.Context, userID records.UserId, req AuthorizeRequest) (*Authorization, error) {
+	l := l.With(zap.String("user_id", userID.String()), zap.String("client_id", req.ClientID))
+
+	client, err := s.ValidateClient(ctx, req.ClientID, req.RedirectURI)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ResponseType != "code" {
+		return errorRedirect(req.RedirectURI, ErrUnsupportedResponseType, req.State)
+	}
+
+	if req.CodeChallengeMethod != codeChallengeMethodS256 || !pkceRegex.MatchString(req.CodeChallenge) {
+		return errorRedirect(req.RedirectURI, ErrInvalidRequest, req.State)
+	}
+
+	if len(req.Nonce) > 1024 {
+		return errorRedirect(req.RedirectURI, ErrInvalidRequest, req.State)
+	}
+
+	scopes := ParseScope(req.Scope)
+	if len(scopes) == 0 {
+		scopes = client.Scopes
+	}
+
+	if !containsAll(client.Scopes, scopes) {
+		return errorRedirect(req.RedirectURI, ErrInvalidScope, req.State)
+	}
+
+	pending := pendingAuthorization{
+		UserID:        userID,
+		ClientID:      client.ID,
+		RedirectURI:   req.RedirectURI,
+		Scopes:        scopes,
+		State:         req.State,
+		CodeChallenge: req.CodeChallenge,
+		Nonce:         req.Nonce,
+		AuthTime:      req.AuthTime,
+	}
+
+	consent, err := s.clientReader.GetConsent(ctx, userID, client.ID)
+	if err != nil {
+		l.Error("failed to get consent", zap.Error(err))
+		return errorRedirect(req.RedirectURI, ErrServerError, req.State)
+	}
+
+	if consent != nil && containsAll(consent.Scopes, scopes) {
+		return s.issueCode(ctx, pending)
+	}
+
+	consentID, err := randomToken()
+	if err != nil {
+		return errorRedirect(req.RedirectURI, ErrServerError, req.State)
+	}
+
+	err = s.consentRequests.Set(ctx, tokenKey(consentID), pending)
+	if err != nil {
+		l.Error("failed to store consent request", zap.Error(err))
+		return errorRedirect(req.RedirectURI, ErrServerError, req.State)
+	}
+
+	return &Authorization{ConsentID: consentID}, nil
+}
+
+// getPendingConsent returns the user's pending authorization request, marking it used when consuming it
+func (s *AuthorizationServerV1) getPendingConsent(ctx context.Context, userID records.UserId, consentID string, consume bool) (*pendingAuthorization, error) {
+	if consentID == "" || len(consentID) > 1024 {
+		return nil, ErrConsentNotFound
+	}
+
+	var pending pendingAuthorization
+	err := s.consentRequests.Update(ctx, tokenKey(consentID), func(load func(target any) error) (any, error) {
+		if err := load(&pending); err != nil {
+			return nil, err
+		}
+
+		if pending.Used || pending.UserID != userID {
+			return nil, ErrConsentNotFound
+		}
+
+		pending.Used = consume
+		return pending, nil
+	})
+	if err != nil {
+		if cache.IsCacheMissErr(err) || errors.Is(err, ErrConsentNotFound) {
+			return nil, ErrConsentNotFound
+		}
+
+		l.Error("failed to get consent request", zap.Error(err))
+		return nil, ErrServerError
+	}
+
+	return &pending, nil
+}
+
+// PendingConsent returns the client and scopes of an authorization request the user has to consent to
+func (s *AuthorizationServerV1) PendingConsent(ctx context.Context, userID records.UserId, consentID string) (*ConsentRequest, error) {
+	pending, err := s.getPendingConsent(ctx, userID, consentID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := s.clientReader.GetClient(ctx, pending.ClientID)
+	if err != nil {
+		l.Error("failed to get client", zap.String("client_id", pending.ClientID.String()), zap.Error(err))
+		return nil, ErrServerError
+	}
+
+	if client == nil {
+		return nil, ErrConsentNotFound
+	}
+
+	return &ConsentRequest{
+		ClientID:   client.ID,
+		ClientName: client.Name,
+		Scopes:     pending.Scopes,
+	}, nil
+}
+
+// Consent answers a pending authorization request, approving it records the consent so the user isn't
+// asked again for the same scopes
+func (s *AuthorizationServerV1) Consent(ctx context.Context, userID records.UserId, consentID string, approved bool) (*Authorization, error) {
+	pending, err := s.getPendingConsent(ctx, userID, consentID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	l := l.With(zap.String("user_id", userID.String()), zap.String("client_id", pending.ClientID.String()))
+
+	if !approved {
+		l.Info("user denied consent")
+		return errorRedirect(pending.RedirectURI, ErrAccessDenied, pending.State)
+	}
+
+	err = s.recordConsent(ctx, userID, pending.ClientID, pending.Scopes)
+	if err != nil {
+		l.Error("failed to store consent", zap.Error(err))
+		return errorRedirect(pending.RedirectURI, ErrServerError, pending.State)
+	}
+
+	l.Info("user consented", zap.Strings("scopes", pending.Scopes))
+	return s.issueCode(ctx, *pending)
+}
+
+// recordConsent adds the scopes to the user's consent for the client
+func (s *AuthorizationServerV1) recordConsent(ctx context.Context, userID records.UserId, clientID uuid.UUID, scopes []string) error {
+	existing, err := s.clientReader.GetConsent(ctx, userID, clientID)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		scopes = slices.Concat(existing.Scopes, scopes)
+	}
+
+	_, err = s.clientWriter.UpsertConsent(ctx, userID, clientID, slices.Compact(slices.Sorted(slices.Values(scopes))))
+	return err
+}
+
+// issueCode redirects to the client with a new authorization code
+func (s *AuthorizationServerV1) issueCode(ctx context.Context, pending pendingAuthorization) (*Authorization, error) {
+	code, err := randomToken()
+	if err != nil {
+		return errorRedirect(pending.RedirectURI, ErrServerError, pending.State)
+	}
+
+	err = s.codes.Set(ctx, tokenKey(code), authorizationCode{
+		UserID:        pending.UserID,
+		ClientID:      pending.ClientID,
+		RedirectURI:   pending.RedirectURI,
+		Scopes:        pending.Scopes,
+		CodeChallenge: pending.CodeChallenge,
+		Nonce:         pending.Nonce,
+		AuthTime:      pending.AuthTime,
+	})
+	if err != nil {
+		l.Error("failed to store authorization code", zap.String("client_id", pending.ClientID.String()), zap.Error(err))
+		return errorRedirect(pending.RedirectURI, ErrServerError, pending.State)
+	}
+
+	redirect, err := redirectWith(pending.RedirectURI, map[string]string{
+		"code":  code,
+		"state": pending.State,
+	})
+	if err != nil {
+		return nil, ErrInvalidRedirectURI
+	}
+
+	return &Authorization{RedirectURI: redirect}, nil
+}
+
+func (s *AuthorizationServerV1) ListConsents(ctx context.Context, userID records.UserId) ([]oauthclients.Consent, error) {
+	consents, err := s.clientReader.ListConsentsForUser(ctx, userID)
+	if err != nil {
+		l.Error("failed to list consents", zap.String("user_id", userID.String()), zap.Error(err))
+		return nil, ErrServerError
+	}
+
+	return consents, nil
+}
+
+// RevokeConsent removes the user's consent for the client, the client's refresh tokens stop working with it
+func (s *AuthorizationServerV1) RevokeConsent(ctx context.Context, userID records.UserId, clientID uuid.UUID) error {
+	l := l.With(zap.String("user_id", userID.String()), zap.String("client_id", clientID.String()))
+
+	deleted, err := s.clientWriter.DeleteConsent(ctx, userID, clientID)
+	if err != nil {
+		l.Error("failed to delete consent", zap.Error(err))
+		return ErrServerError
+	}
+
+	if !deleted {
+		return ErrConsentNotFound
+	}
+
+	l.Info("user revoked consent")
+	return nil
+}
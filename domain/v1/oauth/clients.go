@@ -0,0 +1,186 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"net"
+	"net/url"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/oauthclients"
+	"go.uber.org/zap"
+)
+
+const maxRedirectURIs = 10
+
+func hashClientSecret(secret string) []byte {
+	sum := sha256.Sum256([]byte(secret))
+	return sum[:]
+}
+
+// validateRedirectURI requires an absolute uri without a fragment, plain http is only allowed for loopback
+// addresses used by native apps
+func validateRedirectURI(redirectURI string) error {
+	u, err := url.Parse(redirectURI)
+	if err != nil || !u.IsAbs() || u.Fragment != "" || u.Opaque != "" {
+		return ErrInvalidRedirectURI
+	}
+
+	switch u.Scheme {
+	case "https":
+	case "http":
+		host := u.Hostname()
+		ip := net.ParseIP(host)
+		if host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			return ErrInvalidRedirectURI
+		}
+	default:
+		// private-use schemes of native apps, RFC 8252 7.1
+		if u.Host != "" {
+			return ErrInvalidRedirectURI
+		}
+	}
+
+	return nil
+}
+
+// RegisterClient registers a client owned by the user and returns its secret, which is only stored hashed.
+// Public clients such as native and browser apps get no secret
+func (s *AuthorizationServerV1) RegisterClient(ctx context.Context, ownerID records.UserId, name string, redirectURIs []string, scopes []string, confidential bool) (*oauthclients.Client, string, error) {
+	l := l.With(zap.String("owner_id", ownerID.String()))
+
+	if name == "" || len(name) > 255 {
+		return nil, "", ErrInvalidRequest
+	}
+
+	if len(redirectURIs) == 0 || len(redirectURIs) > maxRedirectURIs {
+		return nil, "", ErrInvalidRedirectURI
+	}
+
+	for _, redirectURI := range redirectURIs {
+		err := validateRedirectURI(redirectURI)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	if len(scopes) == 0 || !containsAll(SupportedScopes, scopes) {
+		return nil, "", ErrInvalidScope
+	}
+
+	client := oauthclients.Client{
+		OwnerID:      ownerID,
+		Name:         name,
+		RedirectUris: redirectURIs,
+		Scopes:       slices.Compact(slices.Sorted(slices.Values(scopes))),
+	}
+
+	var secret string
+	if confidential {
+		var err error
+		secret, err = randomToken()
+		if err != nil {
+			return nil, "", ErrServerError
+		}
+
+		client.SecretHash = hashClientSecret(secret)
+	}
+
+	created, err := s.clientWriter.CreateClient(ctx, client)
+	if err != nil {
+		l.Error("failed to create client", zap.Error(err))
+		return nil, "", ErrServerError
+	}
+
+	l.Info("registered oauth client", zap.String("client_id", created.ID.String()), zap.Bool("confidential", confidential))
+	return created, secret, nil
+}
+
+func (s *AuthorizationServerV1) ListClients(ctx context.Context, ownerID records.UserId) ([]oauthclients.Client, error) {
+	clients, err := s.clientReader.ListClientsForOwner(ctx, ownerID)
+	if err != nil {
+		l.Error("failed to list clients", zap.String("owner_id", ownerID.String()), zap.Error(err))
+		return nil, ErrServerError
+	}
+
+	return clients, nil
+}
+
+// DeleteClient deletes a client of the user, the consents users gave it are deleted with it
+func (s *AuthorizationServerV1) DeleteClient(ctx context.Context, ownerID records.UserId, clientID uuid.UUID) error {
+	l := l.With(zap.String("owner_id", ownerID.String()), zap.String("client_id", clientID.String()))
+
+	deleted, err := s.clientWriter.DeleteClient(ctx, clientID, ownerID)
+	if err != nil {
+		l.Error("failed to delete client", zap.Error(err))
+		return ErrServerError
+	}
+
+	if !deleted {
+		return ErrClientNotFound
+	}
+
+	l.Info("deleted oauth client")
+	return nil
+}
+
+// getClient returns the client with the id, or ErrInvalidClient if there is none
+func (s *AuthorizationServerV1) getClient(ctx context.Context, clientID string) (*oauthclients.Client, error) {
+	id, err := uuid.Parse(clientID)
+	if err != nil {
+		return nil, ErrInvalidClient
+	}
+
+	client, err := s.clientReader.GetClient(ctx, id)
+	if err != nil {
+		l.Error("failed to get client", zap.String("client_id", clientID), zap.Error(err))
+		return nil, ErrServerError
+	}
+
+	if client == nil {
+		return nil, ErrInvalidClient
+	}
+
+	return client, nil
+}
+
+// authenticateClient checks the secret of confidential clients, public clients must not send one
+func (s *AuthorizationServerV1) authenticateClient(ctx context.Context, clientID string, secret string) (*oauthclients.Client, error) {
+	client, err := s.getClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.SecretHash == nil {
+		if secret != "" {
+			return nil, ErrInvalidClient
+		}
+
+		return client, nil
+	}
+
+	if secret == "" || subtle.ConstantTimeCompare(client.SecretHash, hashClientSecret(secret)) != 1 {
+		l.Warn("client failed to authenticate", zap.String("client_id", clientID))
+		return nil, ErrInvalidClient
+	}
+
+	return client, nil
+}
+
+// ValidateClient returns the client if redirectURI is one of its registered redirect uris.
+// Errors from ValidateClient must be shown to the user instead of redirecting to the client
+func (s *AuthorizationServerV1) ValidateClient(ctx context.Context, clientID string, redirectURI string) (*oauthclients.Client, error) {
+	client, err := s.getClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(client.RedirectUris, redirectURI) {
+		return nil, ErrInvalidRedirectURI
+	}
+
+	return client, nil
+}
@@ -0,0 +1,320 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/oauthclients"
+	"github.com/ooqls/go-cache/cache"
+	"go.uber.org/zap"
+)
+
+const GrantTypeDeviceCode = "urn:ietf:params:oauth:grant-type:device_code"
+
+const (
+	// deviceCodeTTL is how long the user has to approve a device
+	deviceCodeTTL = 10 * time.Minute
+	// devicePollInterval is how long a device waits between token requests, every slow_down adds devicePollSlowDown
+	devicePollInterval = 5 * time.Second
+	devicePollSlowDown = 5 * time.Second
+)
+
+// userCodeAlphabet has no vowels so user codes don't spell words and no characters that are easily confused, RFC 8628 6.1
+const userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+// userCodeLength is the number of characters in a user code, 20^8 codes is about 34 bits of entropy
+const userCodeLength = 8
+
+// deviceGrant is a device authorization request, it is stored under the hash of the device code.
+// The user answers it with the user code, the device polls the token endpoint until they do
+type deviceGrant struct {
+	ClientID   uuid.UUID
+	Scopes     []string
+	UserCode   string
+	ExpiresAt  time.Time
+	Interval   time.Duration
+	LastPolled time.Time
+	UserID     records.UserId
+	AuthTime   time.Time
+	Approved   bool
+	Denied     bool
+	Used       bool
+}
+
+// answered returns true if the user approved or denied the device
+func (d *deviceGrant) answered() bool {
+	return d.Approved || d.Denied
+}
+
+// userCodeEntry points a user code to the device grant it was issued with
+type userCodeEntry struct {
+	DeviceKey string
+}
+
+// newUserCode returns a random user code formatted as XXXX-XXXX
+func newUserCode() (string, error) {
+	var b strings.Builder
+	alphabetSize := big.NewInt(int64(len(userCodeAlphabet)))
+	for i := 0; i < userCodeLength; i++ {
+		if i == userCodeLength/2 {
+			b.WriteByte('-')
+		}
+
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+
+		b.WriteByte(userCodeAlphabet[n.Int64()])
+	}
+
+	return b.String(), nil
+}
+
+// normalizeUserCode makes user codes case insensitive and drops the dash and spaces users may type, RFC 8628 6.1
+func normalizeUserCode(userCode string) string {
+	userCode = strings.ToUpper(userCode)
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+
+		return r
+	}, userCode)
+}
+
+// AuthorizeDevice starts a device authorization, RFC 8628 3.1. The device shows the user code and
+// polls the token endpoint with the device code while the user approves it in a browser
+func (s *AuthorizationServerV1) AuthorizeDevice(ctx context.Context, req DeviceAuthorizationRequest) (*DeviceAuthorization, error) {
+	client, err := s.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	l := l.With(zap.String("client_id", client.ID.String()))
+
+	scopes := ParseScope(req.Scope)
+	if len(scopes) == 0 {
+		scopes = client.Scopes
+	}
+
+	if !containsAll(client.Scopes, scopes) {
+		return nil, ErrInvalidScope
+	}
+
+	deviceCode, err := randomToken()
+	if err != nil {
+		return nil, ErrServerError
+	}
+
+	userCode, err := newUserCode()
+	if err != nil {
+		return nil, ErrServerError
+	}
+
+	deviceKey := tokenKey(deviceCode)
+	err = s.deviceCodes.Set(ctx, deviceKey, deviceGrant{
+		ClientID:  client.ID,
+		Scopes:    scopes,
+		UserCode:  userCode,
+		ExpiresAt: time.Now().Add(deviceCodeTTL),
+		Interval:  devicePollInterval,
+	})
+	if err != nil {
+		l.Error("failed to store device code", zap.Error(err))
+		return nil, ErrServerError
+	}
+
+	err = s.userCodes.Set(ctx, normalizeUserCode(userCode), userCodeEntry{DeviceKey: deviceKey})
+	if err != nil {
+		l.Error("failed to store user code", zap.Error(err))
+		return nil, ErrServerError
+	}
+
+	l.Info("started device authorization", zap.Strings("scopes", scopes))
+	return &DeviceAuthorization{
+		DeviceCode: deviceCode,
+		UserCode:   userCode,
+		ExpiresIn:  int(deviceCodeTTL.Seconds()),
+		Interval:   int(devicePollInterval.Seconds()),
+	}, nil
+}
+
+// getPendingDevice returns the key and grant of a device the user has not answered yet
+func (s *AuthorizationServerV1) getPendingDevice(ctx context.Context, userCode string) (string, *deviceGrant, error) {
+	userCode = normalizeUserCode(userCode)
+	if userCode == "" || len(userCode) > 64 {
+		return "", nil, ErrDeviceNotFound
+	}
+
+	var entry userCodeEntry
+	err := s.userCodes.Get(ctx, userCode, &entry)
+	if err != nil {
+		if cache.IsCacheMissErr(err) {
+			return "", nil, ErrDeviceNotFound
+		}
+
+		l.Error("failed to get user code", zap.Error(err))
+		return "", nil, ErrServerError
+	}
+
+	var device deviceGrant
+	err = s.deviceCodes.Get(ctx, entry.DeviceKey, &device)
+	if err != nil {
+		if cache.IsCacheMissErr(err) {
+			return "", nil, ErrDeviceNotFound
+		}
+
+		l.Error("failed to get device code", zap.Error(err))
+		return "", nil, ErrServerError
+	}
+
+	if device.answered() || time.Now().After(device.ExpiresAt) {
+		return "", nil, ErrDeviceNotFound
+	}
+
+	return entry.DeviceKey, &device, nil
+}
+
+// PendingDevice returns the client and scopes of the device with the user code, for the user to approve
+func (s *AuthorizationServerV1) PendingDevice(ctx context.Context, userCode string) (*ConsentRequest, error) {
+	_, device, err := s.getPendingDevice(ctx, userCode)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := s.clientReader.GetClient(ctx, device.ClientID)
+	if err != nil {
+		l.Error("failed to get client", zap.String("client_id", device.ClientID.String()), zap.Error(err))
+		return nil, ErrServerError
+	}
+
+	if client == nil {
+		return nil, ErrDeviceNotFound
+	}
+
+	return &ConsentRequest{
+		ClientID:   client.ID,
+		ClientName: client.Name,
+		Scopes:     device.Scopes,
+	}, nil
+}
+
+// ApproveDevice answers the device with the user code for the logged in user. Approving it records the
+// consent like Consent, the device gets its tokens on its next poll. A user code can only be answered once
+func (s *AuthorizationServerV1) ApproveDevice(ctx context.Context, userID records.UserId, userCode string, approved bool, authTime time.Time) error {
+	deviceKey, device, err := s.getPendingDevice(ctx, userCode)
+	if err != nil {
+		return err
+	}
+
+	l := l.With(zap.String("user_id", userID.String()), zap.String("client_id", device.ClientID.String()))
+
+	if approved {
+		err = s.recordConsent(ctx, userID, device.ClientID, device.Scopes)
+		if err != nil {
+			l.Error("failed to store consent", zap.Error(err))
+			return ErrServerError
+		}
+	}
+
+	err = s.deviceCodes.Update(ctx, deviceKey, func(load func(target any) error) (any, error) {
+		if err := load(device); err != nil {
+			return nil, err
+		}
+
+		if device.answered() {
+			return nil, ErrDeviceNotFound
+		}
+
+		device.UserID = userID
+		device.AuthTime = authTime
+		device.Approved = approved
+		device.Denied = !approved
+		return *device, nil
+	})
+	if err != nil {
+		if cache.IsCacheMissErr(err) || errors.Is(err, ErrDeviceNotFound) {
+			return ErrDeviceNotFound
+		}
+
+		l.Error("failed to answer device authorization", zap.Error(err))
+		return ErrServerError
+	}
+
+	err = s.userCodes.Delete(ctx, normalizeUserCode(userCode))
+	if err != nil && !cache.IsCacheMissErr(err) {
+		l.Warn("failed to delete user code", zap.Error(err))
+	}
+
+	l.Info("user answered device authorization", zap.Bool("approved", approved))
+	return nil
+}
+
+// exchangeDeviceCode exchanges a device code for tokens once the user approved it, RFC 8628 3.4 and 3.5.
+// Devices polling faster than their interval are told to slow down and their interval grows
+func (s *AuthorizationServerV1) exchangeDeviceCode(ctx context.Context, client *oauthclients.Client, req TokenRequest) (*TokenResponse, error) {
+	l := l.With(zap.String("client_id", client.ID.String()))
+
+	if req.DeviceCode == "" || len(req.DeviceCode) > 1024 {
+		return nil, ErrInvalidRequest
+	}
+
+	now := time.Now()
+	var device deviceGrant
+	var pollErr error
+	err := s.deviceCodes.Update(ctx, tokenKey(req.DeviceCode), func(load func(target any) error) (any, error) {
+		if err := load(&device); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case device.ClientID != client.ID || device.Used:
+			return nil, ErrInvalidGrant
+		case now.After(device.ExpiresAt):
+			return nil, ErrExpiredToken
+		case device.Denied:
+			return nil, ErrAccessDenied
+		case device.Approved:
+			device.Used = true
+			return device, nil
+		case now.Sub(device.LastPolled) < device.Interval:
+			device.Interval += devicePollSlowDown
+			pollErr = ErrSlowDown
+		default:
+			pollErr = ErrAuthorizationPending
+		}
+
+		device.LastPolled = now
+		return device, nil
+	})
+	if err != nil {
+		switch {
+		case cache.IsCacheMissErr(err):
+			return nil, ErrInvalidGrant
+		case errors.Is(err, ErrInvalidGrant), errors.Is(err, ErrExpiredToken), errors.Is(err, ErrAccessDenied):
+			return nil, err
+		}
+
+		l.Error("failed to get device code", zap.Error(err))
+		return nil, ErrServerError
+	}
+
+	if pollErr != nil {
+		return nil, pollErr
+	}
+
+	return s.issueTokens(ctx, refreshGrant{
+		FamilyID: uuid.New(),
+		UserID:   device.UserID,
+		ClientID: client.ID,
+		Scopes:   device.Scopes,
+		AuthTime: device.AuthTime,
+	}, "")
+}
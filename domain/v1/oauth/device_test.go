@@ -0,0 +1,87 @@
+package oauth
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorizationServer_DeviceCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	server, issuer := newTestAuthorizationServer(t, ctrl)
+	user := uuid.New()
+
+	client, _, err := server.RegisterClient(ctx, uuid.New(), "cli", []string{"http://127.0.0.1/callback"}, []string{ScopeOpenID, ScopeProfile}, false)
+	assert.Nilf(t, err, "should register the client: %v", err)
+
+	_, err = server.AuthorizeDevice(ctx, DeviceAuthorizationRequest{ClientID: client.ID.String(), Scope: "openid email"})
+	assert.ErrorIsf(t, err, ErrInvalidScope, "should not authorize scopes the client is not registered for")
+
+	_, err = server.AuthorizeDevice(ctx, DeviceAuthorizationRequest{ClientID: client.ID.String(), ClientSecret: "secret"})
+	assert.ErrorIsf(t, err, ErrInvalidClient, "public clients should not send a secret")
+
+	device, err := server.AuthorizeDevice(ctx, DeviceAuthorizationRequest{ClientID: client.ID.String(), Scope: "openid"})
+	assert.Nilf(t, err, "should start the device authorization: %v", err)
+	assert.Regexpf(t, `^[BCDFGHJKLMNPQRSTVWXZ]{4}-[BCDFGHJKLMNPQRSTVWXZ]{4}$`, device.UserCode, "user code should be easy to type")
+	assert.Equalf(t, int(devicePollInterval.Seconds()), device.Interval, "should tell the device how often to poll")
+
+	poll := TokenRequest{GrantType: GrantTypeDeviceCode, DeviceCode: device.DeviceCode, ClientID: client.ID.String()}
+	_, err = server.Token(ctx, poll)
+	assert.ErrorIsf(t, err, ErrAuthorizationPending, "should wait for the user to approve the device")
+
+	_, err = server.Token(ctx, poll)
+	assert.ErrorIsf(t, err, ErrSlowDown, "should slow down a device polling faster than its interval")
+
+	other, _, err := server.RegisterClient(ctx, uuid.New(), "other", []string{"http://127.0.0.1/callback"}, []string{ScopeOpenID}, false)
+	assert.Nilf(t, err, "should register the other client: %v", err)
+	_, err = server.Token(ctx, TokenRequest{GrantType: GrantTypeDeviceCode, DeviceCode: device.DeviceCode, ClientID: other.ID.String()})
+	assert.ErrorIsf(t, err, ErrInvalidGrant, "should not give the device code's tokens to another client")
+
+	_, err = server.PendingDevice(ctx, "BCDF-GHJK")
+	assert.ErrorIsf(t, err, ErrDeviceNotFound, "should not find an unknown user code")
+
+	typed := strings.ToLower(strings.ReplaceAll(device.UserCode, "-", ""))
+	pending, err := server.PendingDevice(ctx, typed)
+	assert.Nilf(t, err, "should find the user code regardless of case and dash: %v", err)
+	assert.Equalf(t, "cli", pending.ClientName, "should show the client's name")
+	assert.Equalf(t, []string{ScopeOpenID}, pending.Scopes, "should show the requested scopes")
+
+	authTime := time.Now().Add(-time.Minute).Truncate(time.Second)
+	err = server.ApproveDevice(ctx, user, typed, true, authTime)
+	assert.Nilf(t, err, "should approve the device: %v", err)
+
+	err = server.ApproveDevice(ctx, uuid.New(), device.UserCode, true, authTime)
+	assert.ErrorIsf(t, err, ErrDeviceNotFound, "should not answer a user code twice")
+
+	tokens, err := server.Token(ctx, poll)
+	assert.Nilf(t, err, "should issue tokens once the device is approved: %v", err)
+	assert.NotEmptyf(t, tokens.RefreshToken, "should issue a refresh token")
+	assert.NotEmptyf(t, tokens.IDToken, "should issue an id token for the openid scope")
+
+	_, accessClaims, err := issuer.Decrypt(tokens.AccessToken)
+	assert.Nilf(t, err, "access token should be valid: %v", err)
+	assert.Equalf(t, user, accessClaims.UserID, "access token should be for the user who approved the device")
+
+	_, err = server.Token(ctx, poll)
+	assert.ErrorIsf(t, err, ErrInvalidGrant, "should not exchange a device code twice")
+
+	_, err = server.Token(ctx, TokenRequest{GrantType: GrantTypeRefreshToken, RefreshToken: tokens.RefreshToken, ClientID: client.ID.String()})
+	assert.Nilf(t, err, "approving the device should record the consent refreshing needs: %v", err)
+
+	denied, err := server.AuthorizeDevice(ctx, DeviceAuthorizationRequest{ClientID: client.ID.String()})
+	assert.Nilf(t, err, "should start another device authorization: %v", err)
+
+	err = server.ApproveDevice(ctx, user, denied.UserCode, false, authTime)
+	assert.Nilf(t, err, "should deny the device: %v", err)
+
+	_, err = server.Token(ctx, TokenRequest{GrantType: GrantTypeDeviceCode, DeviceCode: denied.DeviceCode, ClientID: client.ID.String()})
+	assert.ErrorIsf(t, err, ErrAccessDenied, "should tell the device the user denied it")
+}
@@ -0,0 +1,276 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: oauth.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	oauth "github.com/ooqls/go-auth/domain/v1/oauth"
+	records "github.com/ooqls/go-auth/records"
+	oauthclients "github.com/ooqls/go-auth/records/v1/oauthclients"
+)
+
+// MockAuthorizationServer is a mock of AuthorizationServer interface.
+type MockAuthorizationServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorizationServerMockRecorder
+}
+
+// MockAuthorizationServerMockRecorder is the mock recorder for MockAuthorizationServer.
+type MockAuthorizationServerMockRecorder struct {
+	mock *MockAuthorizationServer
+}
+
+// NewMockAuthorizationServer creates a new mock instance.
+func NewMockAuthorizationServer(ctrl *gomock.Controller) *MockAuthorizationServer {
+	mock := &MockAuthorizationServer{ctrl: ctrl}
+	mock.recorder = &MockAuthorizationServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthorizationServer) EXPECT() *MockAuthorizationServerMockRecorder {
+	return m.recorder
+}
+
+// ApproveDevice mocks base method.
+func (m *MockAuthorizationServer) ApproveDevice(ctx context.Context, userID records.UserId, userCode string, approved bool, authTime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveDevice", ctx, userID, userCode, approved, authTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApproveDevice indicates an expected call of ApproveDevice.
+func (mr *MockAuthorizationServerMockRecorder) ApproveDevice(ctx, userID, userCode, approved, authTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveDevice", reflect.TypeOf((*MockAuthorizationServer)(nil).ApproveDevice), ctx, userID, userCode, approved, authTime)
+}
+
+// Authorize mocks base method.
+func (m *MockAuthorizationServer) Authorize(ctx context.Context, userID records.UserId, req oauth.AuthorizeRequest) (*oauth.Authorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorize", ctx, userID, req)
+	ret0, _ := ret[0].(*oauth.Authorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authorize indicates an expected call of Authorize.
+func (mr *MockAuthorizationServerMockRecorder) Authorize(ctx, userID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockAuthorizationServer)(nil).Authorize), ctx, userID, req)
+}
+
+// AuthorizeDevice mocks base method.
+func (m *MockAuthorizationServer) AuthorizeDevice(ctx context.Context, req oauth.DeviceAuthorizationRequest) (*oauth.DeviceAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizeDevice", ctx, req)
+	ret0, _ := ret[0].(*oauth.DeviceAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthorizeDevice indicates an expected call of AuthorizeDevice.
+func (mr *MockAuthorizationServerMockRecorder) AuthorizeDevice(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizeDevice", reflect.TypeOf((*MockAuthorizationServer)(nil).AuthorizeDevice), ctx, req)
+}
+
+// Consent mocks base method.
+func (m *MockAuthorizationServer) Consent(ctx context.Context, userID records.UserId, consentID string, approved bool) (*oauth.Authorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consent", ctx, userID, consentID, approved)
+	ret0, _ := ret[0].(*oauth.Authorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consent indicates an expected call of Consent.
+func (mr *MockAuthorizationServerMockRecorder) Consent(ctx, userID, consentID, approved interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consent", reflect.TypeOf((*MockAuthorizationServer)(nil).Consent), ctx, userID, consentID, approved)
+}
+
+// DeleteClient mocks base method.
+func (m *MockAuthorizationServer) DeleteClient(ctx context.Context, ownerID records.UserId, clientID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteClient", ctx, ownerID, clientID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteClient indicates an expected call of DeleteClient.
+func (mr *MockAuthorizationServerMockRecorder) DeleteClient(ctx, ownerID, clientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClient", reflect.TypeOf((*MockAuthorizationServer)(nil).DeleteClient), ctx, ownerID, clientID)
+}
+
+// Discovery mocks base method.
+func (m *MockAuthorizationServer) Discovery() oauth.ProviderMetadata {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Discovery")
+	ret0, _ := ret[0].(oauth.ProviderMetadata)
+	return ret0
+}
+
+// Discovery indicates an expected call of Discovery.
+func (mr *MockAuthorizationServerMockRecorder) Discovery() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Discovery", reflect.TypeOf((*MockAuthorizationServer)(nil).Discovery))
+}
+
+// JWKS mocks base method.
+func (m *MockAuthorizationServer) JWKS() []oauth.JWK {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS")
+	ret0, _ := ret[0].([]oauth.JWK)
+	return ret0
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockAuthorizationServerMockRecorder) JWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockAuthorizationServer)(nil).JWKS))
+}
+
+// ListClients mocks base method.
+func (m *MockAuthorizationServer) ListClients(ctx context.Context, ownerID records.UserId) ([]oauthclients.Client, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClients", ctx, ownerID)
+	ret0, _ := ret[0].([]oauthclients.Client)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClients indicates an expected call of ListClients.
+func (mr *MockAuthorizationServerMockRecorder) ListClients(ctx, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClients", reflect.TypeOf((*MockAuthorizationServer)(nil).ListClients), ctx, ownerID)
+}
+
+// ListConsents mocks base method.
+func (m *MockAuthorizationServer) ListConsents(ctx context.Context, userID records.UserId) ([]oauthclients.Consent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListConsents", ctx, userID)
+	ret0, _ := ret[0].([]oauthclients.Consent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListConsents indicates an expected call of ListConsents.
+func (mr *MockAuthorizationServerMockRecorder) ListConsents(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConsents", reflect.TypeOf((*MockAuthorizationServer)(nil).ListConsents), ctx, userID)
+}
+
+// PendingConsent mocks base method.
+func (m *MockAuthorizationServer) PendingConsent(ctx context.Context, userID records.UserId, consentID string) (*oauth.ConsentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PendingConsent", ctx, userID, consentID)
+	ret0, _ := ret[0].(*oauth.ConsentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PendingConsent indicates an expected call of PendingConsent.
+func (mr *MockAuthorizationServerMockRecorder) PendingConsent(ctx, userID, consentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingConsent", reflect.TypeOf((*MockAuthorizationServer)(nil).PendingConsent), ctx, userID, consentID)
+}
+
+// PendingDevice mocks base method.
+func (m *MockAuthorizationServer) PendingDevice(ctx context.Context, userCode string) (*oauth.ConsentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PendingDevice", ctx, userCode)
+	ret0, _ := ret[0].(*oauth.ConsentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PendingDevice indicates an expected call of PendingDevice.
+func (mr *MockAuthorizationServerMockRecorder) PendingDevice(ctx, userCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingDevice", reflect.TypeOf((*MockAuthorizationServer)(nil).PendingDevice), ctx, userCode)
+}
+
+// RegisterClient mocks base method.
+func (m *MockAuthorizationServer) RegisterClient(ctx context.Context, ownerID records.UserId, name string, redirectURIs, scopes []string, confidential bool) (*oauthclients.Client, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterClient", ctx, ownerID, name, redirectURIs, scopes, confidential)
+	ret0, _ := ret[0].(*oauthclients.Client)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RegisterClient indicates an expected call of RegisterClient.
+func (mr *MockAuthorizationServerMockRecorder) RegisterClient(ctx, ownerID, name, redirectURIs, scopes, confidential interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterClient", reflect.TypeOf((*MockAuthorizationServer)(nil).RegisterClient), ctx, ownerID, name, redirectURIs, scopes, confidential)
+}
+
+// RevokeConsent mocks base method.
+func (m *MockAuthorizationServer) RevokeConsent(ctx context.Context, userID records.UserId, clientID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeConsent", ctx, userID, clientID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeConsent indicates an expected call of RevokeConsent.
+func (mr *MockAuthorizationServerMockRecorder) RevokeConsent(ctx, userID, clientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeConsent", reflect.TypeOf((*MockAuthorizationServer)(nil).RevokeConsent), ctx, userID, clientID)
+}
+
+// Token mocks base method.
+func (m *MockAuthorizationServer) Token(ctx context.Context, req oauth.TokenRequest) (*oauth.TokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token", ctx, req)
+	ret0, _ := ret[0].(*oauth.TokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockAuthorizationServerMockRecorder) Token(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockAuthorizationServer)(nil).Token), ctx, req)
+}
+
+// UserInfo mocks base method.
+func (m *MockAuthorizationServer) UserInfo(ctx context.Context, accessToken string) (*oauth.UserInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserInfo", ctx, accessToken)
+	ret0, _ := ret[0].(*oauth.UserInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserInfo indicates an expected call of UserInfo.
+func (mr *MockAuthorizationServerMockRecorder) UserInfo(ctx, accessToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserInfo", reflect.TypeOf((*MockAuthorizationServer)(nil).UserInfo), ctx, accessToken)
+}
+
+// ValidateClient mocks base method.
+func (m *MockAuthorizationServer) ValidateClient(ctx context.Context, clientID, redirectURI string) (*oauthclients.Client, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateClient", ctx, clientID, redirectURI)
+	ret0, _ := ret[0].(*oauthclients.Client)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateClient indicates an expected call of ValidateClient.
+func (mr *MockAuthorizationServerMockRecorder) ValidateClient(ctx, clientID, redirectURI interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateClient", reflect.TypeOf((*MockAuthorizationServer)(nil).ValidateClient), ctx, clientID, redirectURI)
+}
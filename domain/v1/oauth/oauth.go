@@ -0,0 +1,209 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/oauthclients"
+	"github.com/ooqls/go-log"
+	"go.uber.org/zap"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=oauth.go -destination=mocks/mock_authorization_server.go -package=mocks
+
+var l *zap.Logger
+
+func init() {
+	l = log.NewLogger("oauth")
+}
+
+// The errors named after RFC 6749 and RFC 6750 error codes are returned to clients as is, see ErrorCode
+var (
+	ErrInvalidRequest          error = errors.New("invalid_request")
+	ErrInvalidClient           error = errors.New("invalid_client")
+	ErrInvalidGrant            error = errors.New("invalid_grant")
+	ErrUnauthorizedClient      error = errors.New("unauthorized_client")
+	ErrUnsupportedGrantType    error = errors.New("unsupported_grant_type")
+	ErrUnsupportedResponseType error = errors.New("unsupported_response_type")
+	ErrInvalidScope            error = errors.New("invalid_scope")
+	ErrAccessDenied            error = errors.New("access_denied")
+	ErrServerError             error = errors.New("server_error")
+	ErrInvalidToken            error = errors.New("invalid_token")
+	ErrInsufficientScope       error = errors.New("insufficient_scope")
+	ErrAuthorizationPending    error = errors.New("authorization_pending")
+	ErrSlowDown                error = errors.New("slow_down")
+	ErrExpiredToken            error = errors.New("expired_token")
+
+	ErrInvalidRedirectURI error = errors.New("invalid redirect uri")
+	ErrClientNotFound     error = errors.New("client not found")
+	ErrConsentNotFound    error = errors.New("consent not found")
+	ErrDeviceNotFound     error = errors.New("device authorization not found")
+)
+
+var protocolErrors = []error{
+	ErrInvalidRequest,
+	ErrInvalidClient,
+	ErrInvalidGrant,
+	ErrUnauthorizedClient,
+	ErrUnsupportedGrantType,
+	ErrUnsupportedResponseType,
+	ErrInvalidScope,
+	ErrAccessDenied,
+	ErrInvalidToken,
+	ErrInsufficientScope,
+	ErrAuthorizationPending,
+	ErrSlowDown,
+	ErrExpiredToken,
+}
+
+// ErrorCode returns the RFC 6749 error code to send to the client for the error
+func ErrorCode(err error) string {
+	for _, protocolErr := range protocolErrors {
+		if errors.Is(err, protocolErr) {
+			return protocolErr.Error()
+		}
+	}
+
+	return ErrServerError.Error()
+}
+
+const (
+	ScopeOpenID  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+)
+
+// SupportedScopes are the scopes clients can be registered with
+var SupportedScopes = []string{ScopeOpenID, ScopeProfile, ScopeEmail}
+
+// ParseScope splits a space separated scope parameter, duplicates are removed
+func ParseScope(scope string) []string {
+	scopes := []string{}
+	for _, s := range strings.Fields(scope) {
+		if !slices.Contains(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+
+	return scopes
+}
+
+// FormatScope joins scopes into a scope parameter
+func FormatScope(scopes []string) string {
+	return strings.Join(scopes, " ")
+}
+
+// containsAll returns true if every scope of subset is in scopes
+func containsAll(scopes []string, subset []string) bool {
+	for _, s := range subset {
+		if !slices.Contains(scopes, s) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AuthorizeRequest are the parameters of an authorization request, PKCE is required for every client
+type AuthorizeRequest struct {
+	ResponseType        string
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Nonce               string
+	// AuthTime is when the user logged in, it is the auth_time claim of id tokens
+	AuthTime time.Time
+}
+
+// Authorization is the outcome of an authorization request. Either RedirectURI is the client's redirect
+// with a code or an error, or ConsentID is the pending request the user still has to consent to
+type Authorization struct {
+	RedirectURI string
+	ConsentID   string
+}
+
+// ConsentRequest is what the user is asked to consent to
+type ConsentRequest struct {
+	ClientID   uuid.UUID
+	ClientName string
+	Scopes     []string
+}
+
+// DeviceAuthorizationRequest are the parameters of a device authorization request, RFC 8628 3.1.
+// The client authenticates like it does at the token endpoint
+type DeviceAuthorizationRequest struct {
+	ClientID     string
+	ClientSecret string
+	Scope        string
+}
+
+// DeviceAuthorization is the response to a device authorization request, RFC 8628 3.2. The verification
+// uri is the page of the caller where users enter the user code
+type DeviceAuthorization struct {
+	DeviceCode string
+	UserCode   string
+	ExpiresIn  int
+	Interval   int
+}
+
+// TokenRequest are the parameters of a token request, the client authenticates with ClientID and ClientSecret.
+// Service accounts can authenticate with a jwt assertion instead of their secret, RFC 7523 2.2
+type TokenRequest struct {
+	GrantType           string
+	Code                string
+	RedirectURI         string
+	CodeVerifier        string
+	RefreshToken        string
+	DeviceCode          string
+	Scope               string
+	ClientID            string
+	ClientSecret        string
+	ClientAssertionType string
+	ClientAssertion     string
+}
+
+type TokenResponse struct {
+	AccessToken  string
+	TokenType    string
+	ExpiresIn    int
+	RefreshToken string
+	Scope        string
+	// IDToken is only issued for the openid scope
+	IDToken string
+}
+
+// AccessClaims are the custom claims of access tokens issued to clients
+type AccessClaims struct {
+	UserID   records.UserId `json:"user_id"`
+	ClientID uuid.UUID      `json:"client_id"`
+	Scope    string         `json:"scope"`
+}
+
+// AuthorizationServer is an OAuth 2.0 authorization server for the authorization code grant with PKCE,
+// the device authorization grant and the client credentials grant of service accounts, and an OpenID Connect provider. Users are logged in with the Authenticator before they are sent to Authorize
+type AuthorizationServer interface {
+	RegisterClient(ctx context.Context, ownerID records.UserId, name string, redirectURIs []string, scopes []string, confidential bool) (*oauthclients.Client, string, error)
+	ListClients(ctx context.Context, ownerID records.UserId) ([]oauthclients.Client, error)
+	DeleteClient(ctx context.Context, ownerID records.UserId, clientID uuid.UUID) error
+	ValidateClient(ctx context.Context, clientID string, redirectURI string) (*oauthclients.Client, error)
+	Authorize(ctx context.Context, userID records.UserId, req AuthorizeRequest) (*Authorization, error)
+	PendingConsent(ctx context.Context, userID records.UserId, consentID string) (*ConsentRequest, error)
+	Consent(ctx context.Context, userID records.UserId, consentID string, approved bool) (*Authorization, error)
+	ListConsents(ctx context.Context, userID records.UserId) ([]oauthclients.Consent, error)
+	RevokeConsent(ctx context.Context, userID records.UserId, clientID uuid.UUID) error
+	AuthorizeDevice(ctx context.Context, req DeviceAuthorizationRequest) (*DeviceAuthorization, error)
+	PendingDevice(ctx context.Context, userCode string) (*ConsentRequest, error)
+	ApproveDevice(ctx context.Context, userID records.UserId, userCode string, approved bool, authTime time.Time) error
+	Token(ctx context.Context, req TokenRequest) (*TokenResponse, error)
+	UserInfo(ctx context.Context, accessToken string) (*UserInfo, error)
+	Discovery() ProviderMetadata
+	JWKS() []JWK
+}
@@ -0,0 +1,212 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"github.com/ooqls/go-auth/domain/v1/authorization"
+	"github.com/ooqls/go-auth/domain/v1/keyring"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/users"
+	"go.uber.org/zap"
+)
+
+// IDClaims are the claims of id tokens, OpenID Connect Core 2. The email and profile claims
+// are only set when the client was granted their scope
+type IDClaims struct {
+	jwtv5.RegisteredClaims
+	Nonce             string `json:"nonce,omitempty"`
+	AuthTime          int64  `json:"auth_time,omitempty"`
+	Email             string `json:"email,omitempty"`
+	EmailVerified     *bool  `json:"email_verified,omitempty"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+}
+
+// UserInfo are the claims about the user returned by the userinfo endpoint, OpenID Connect Core 5.3.
+// Claims of scopes that were not granted are nil
+type UserInfo struct {
+	Subject           string
+	Email             *string
+	EmailVerified     *bool
+	PreferredUsername *string
+}
+
+// ProviderMetadata is the OpenID Connect discovery document, OpenID Connect Discovery 3
+type ProviderMetadata struct {
+	Issuer                            string
+	AuthorizationEndpoint             string
+	TokenEndpoint                     string
+	DeviceAuthorizationEndpoint       string
+	UserInfoEndpoint                  string
+	JWKSURI                           string
+	ScopesSupported                   []string
+	ResponseTypesSupported            []string
+	GrantTypesSupported               []string
+	SubjectTypesSupported             []string
+	IDTokenSigningAlgValuesSupported  []string
+	TokenEndpointAuthMethodsSupported []string
+	CodeChallengeMethodsSupported     []string
+	ClaimsSupported                   []string
+}
+
+// JWK is the public half of a signing key, RFC 7517
+type JWK struct {
+	KeyType   string
+	Use       string
+	Algorithm string
+	KeyID     string
+	Modulus   string
+	Exponent  string
+}
+
+// NewRSAJWK returns the JWK of an RSA public key used to sign RS256 tokens, its key id is the
+// RFC 7638 thumbprint like the kid of the key ring
+func NewRSAJWK(key *rsa.PublicKey) JWK {
+	return JWK{
+		KeyType:   "RSA",
+		Use:       "sig",
+		Algorithm: jwtv5.SigningMethodRS256.Alg(),
+		KeyID:     keyring.Thumbprint(key),
+		Modulus:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// userInfo returns the claims about the user the scopes grant access to
+func userInfo(user *users.User, scopes []string) *UserInfo {
+	info := &UserInfo{Subject: user.ID.String()}
+	if slices.Contains(scopes, ScopeEmail) {
+		info.Email = &user.Email
+		info.EmailVerified = &user.EmailVerified
+	}
+
+	if slices.Contains(scopes, ScopeProfile) {
+		info.PreferredUsername = &user.Username
+	}
+
+	return info
+}
+
+// getUser returns the user a grant was issued for, or nil if the user was deleted since
+func (s *AuthorizationServerV1) getUser(ctx context.Context, userID records.UserId) (*users.User, error) {
+	user, err := s.userService.GetUser(authorization.NewInternalOperationContext(ctx), userID)
+	if err != nil {
+		l.Error("failed to get user", zap.String("user_id", userID.String()), zap.Error(err))
+		return nil, ErrServerError
+	}
+
+	return user, nil
+}
+
+// issueIDToken issues an id token for the grant, OpenID Connect Core 3.1.3.6
+func (s *AuthorizationServerV1) issueIDToken(ctx context.Context, grant refreshGrant, nonce string) (string, error) {
+	user, err := s.getUser(ctx, grant.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	if user == nil {
+		return "", ErrInvalidGrant
+	}
+
+	info := userInfo(user, grant.Scopes)
+	now := time.Now()
+	claims := IDClaims{
+		RegisteredClaims: jwtv5.RegisteredClaims{
+			Issuer:    s.accessIssuer.GetIssuer(),
+			Subject:   info.Subject,
+			Audience:  jwtv5.ClaimStrings{grant.ClientID.String()},
+			ExpiresAt: jwtv5.NewNumericDate(now.Add(idTokenTTL)),
+			IssuedAt:  jwtv5.NewNumericDate(now),
+		},
+		Nonce:         nonce,
+		EmailVerified: info.EmailVerified,
+	}
+	if !grant.AuthTime.IsZero() {
+		claims.AuthTime = grant.AuthTime.Unix()
+	}
+	if info.Email != nil {
+		claims.Email = *info.Email
+	}
+	if info.PreferredUsername != nil {
+		claims.PreferredUsername = *info.PreferredUsername
+	}
+
+	idToken, _, err := s.keyRing.Sign(claims)
+	if err != nil {
+		l.Error("failed to sign id token", zap.String("user_id", grant.UserID.String()), zap.Error(err))
+		return "", ErrServerError
+	}
+
+	return idToken, nil
+}
+
+// UserInfo returns the claims about the user an access token was issued for, it must have been granted the openid scope
+func (s *AuthorizationServerV1) UserInfo(ctx context.Context, accessToken string) (*UserInfo, error) {
+	_, claims, err := s.accessIssuer.Decrypt(accessToken)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	scopes := ParseScope(claims.Scope)
+	if !slices.Contains(scopes, ScopeOpenID) {
+		return nil, ErrInsufficientScope
+	}
+
+	user, err := s.getUser(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, ErrInvalidToken
+	}
+
+	return userInfo(user, scopes), nil
+}
+
+// Discovery returns the OpenID Connect discovery document, the endpoints are relative to the issuer
+func (s *AuthorizationServerV1) Discovery() ProviderMetadata {
+	issuer := s.accessIssuer.GetIssuer()
+	base := strings.TrimSuffix(issuer, "/")
+
+	grantTypes := []string{GrantTypeAuthorizationCode, GrantTypeRefreshToken, GrantTypeDeviceCode}
+	authMethods := []string{"client_secret_basic", "client_secret_post", "none"}
+	if s.serviceAccounts != nil {
+		grantTypes = append(grantTypes, GrantTypeClientCredentials)
+		authMethods = append(authMethods, "private_key_jwt")
+	}
+
+	return ProviderMetadata{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             base + "/oauth/authorize",
+		TokenEndpoint:                     base + "/oauth/token",
+		DeviceAuthorizationEndpoint:       base + "/oauth/device_authorization",
+		UserInfoEndpoint:                  base + "/userinfo",
+		JWKSURI:                           base + "/jwks.json",
+		ScopesSupported:                   SupportedScopes,
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               grantTypes,
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{jwtv5.SigningMethodRS256.Alg()},
+		TokenEndpointAuthMethodsSupported: authMethods,
+		CodeChallengeMethodsSupported:     []string{codeChallengeMethodS256},
+		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "email", "email_verified", "preferred_username"},
+	}
+}
+
+// JWKS returns the public keys of the key ring, including retired keys that still verify tokens
+func (s *AuthorizationServerV1) JWKS() []JWK {
+	jwks := []JWK{}
+	for _, key := range s.keyRing.PublicKeys() {
+		jwks = append(jwks, NewRSAJWK(key.Key))
+	}
+
+	return jwks
+}
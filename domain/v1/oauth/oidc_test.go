@@ -0,0 +1,141 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"testing"
+	"time"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/records"
+	"github.com/stretchr/testify/assert"
+)
+
+// publicKey returns the RSA key a JWK describes, the way an OpenID Connect client would
+func publicKey(t *testing.T, jwk JWK) *rsa.PublicKey {
+	n, err := base64.RawURLEncoding.DecodeString(jwk.Modulus)
+	assert.Nilf(t, err, "modulus should be base64url encoded: %v", err)
+	e, err := base64.RawURLEncoding.DecodeString(jwk.Exponent)
+	assert.Nilf(t, err, "exponent should be base64url encoded: %v", err)
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}
+}
+
+// authorizeOpenID runs the authorization code grant for the user and returns the tokens
+func authorizeOpenID(t *testing.T, server AuthorizationServer, userID records.UserId, clientID uuid.UUID, scope string, nonce string, authTime time.Time) *TokenResponse {
+	ctx := context.Background()
+	verifier, challenge := newPKCE()
+	authorization, err := server.Authorize(ctx, userID, AuthorizeRequest{
+		ResponseType:        "code",
+		ClientID:            clientID.String(),
+		RedirectURI:         testRedirectURI,
+		Scope:               scope,
+		CodeChallenge:       challenge,
+		CodeChallengeMethod: codeChallengeMethodS256,
+		Nonce:               nonce,
+		AuthTime:            authTime,
+	})
+	assert.Nilf(t, err, "should not fail to authorize: %v", err)
+
+	if authorization.ConsentID != "" {
+		authorization, err = server.Consent(ctx, userID, authorization.ConsentID, true)
+		assert.Nilf(t, err, "should not fail to consent: %v", err)
+	}
+
+	tokens, err := server.Token(ctx, TokenRequest{
+		GrantType:    GrantTypeAuthorizationCode,
+		Code:         redirectParams(t, authorization).Get("code"),
+		RedirectURI:  testRedirectURI,
+		CodeVerifier: verifier,
+		ClientID:     clientID.String(),
+	})
+	assert.Nilf(t, err, "should exchange the code: %v", err)
+	return tokens
+}
+
+func TestAuthorizationServer_IDToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	server, _ := newTestAuthorizationServer(t, ctrl)
+	user := uuid.New()
+	authTime := time.Now().Add(-time.Minute).Truncate(time.Second)
+
+	client, _, err := server.RegisterClient(ctx, uuid.New(), "app", []string{testRedirectURI}, []string{ScopeOpenID, ScopeEmail, ScopeProfile}, false)
+	assert.Nilf(t, err, "should register the client: %v", err)
+
+	tokens := authorizeOpenID(t, server, user, client.ID, "email", "", authTime)
+	assert.Emptyf(t, tokens.IDToken, "should only issue id tokens for the openid scope")
+
+	tokens = authorizeOpenID(t, server, user, client.ID, "openid email", "n-0S6_WzA2Mj", authTime)
+	assert.NotEmptyf(t, tokens.IDToken, "should issue an id token for the openid scope")
+
+	jwks := server.JWKS()
+	assert.Lenf(t, jwks, 1, "should publish the signing key")
+
+	var claims IDClaims
+	_, err = jwtv5.ParseWithClaims(tokens.IDToken, &claims, func(token *jwtv5.Token) (any, error) {
+		return publicKey(t, jwks[0]), nil
+	}, jwtv5.WithValidMethods([]string{jwks[0].Algorithm}), jwtv5.WithAudience(client.ID.String()), jwtv5.WithIssuer(server.Discovery().Issuer))
+	assert.Nilf(t, err, "id token should verify with the published key: %v", err)
+	assert.Equalf(t, user.String(), claims.Subject, "subject should be the user")
+	assert.Equalf(t, "n-0S6_WzA2Mj", claims.Nonce, "should return the nonce of the authorization request")
+	assert.Equalf(t, authTime.Unix(), claims.AuthTime, "should set when the user logged in")
+	assert.Equalf(t, "user@example.com", claims.Email, "should set the email for the email scope")
+	assert.Emptyf(t, claims.PreferredUsername, "should not set profile claims without the profile scope")
+
+	refreshed, err := server.Token(ctx, TokenRequest{
+		GrantType:    GrantTypeRefreshToken,
+		RefreshToken: tokens.RefreshToken,
+		ClientID:     client.ID.String(),
+	})
+	assert.Nilf(t, err, "should refresh: %v", err)
+	assert.NotEmptyf(t, refreshed.IDToken, "should issue a new id token on refresh")
+}
+
+func TestAuthorizationServer_UserInfo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	server, _ := newTestAuthorizationServer(t, ctrl)
+	user := uuid.New()
+
+	client, _, err := server.RegisterClient(ctx, uuid.New(), "app", []string{testRedirectURI}, []string{ScopeOpenID, ScopeEmail, ScopeProfile}, false)
+	assert.Nilf(t, err, "should register the client: %v", err)
+
+	_, err = server.UserInfo(ctx, "not a token")
+	assert.ErrorIsf(t, err, ErrInvalidToken, "should not accept an invalid token")
+
+	tokens := authorizeOpenID(t, server, user, client.ID, "email", "", time.Time{})
+	_, err = server.UserInfo(ctx, tokens.AccessToken)
+	assert.ErrorIsf(t, err, ErrInsufficientScope, "should require the openid scope")
+
+	tokens = authorizeOpenID(t, server, user, client.ID, "openid profile", "", time.Time{})
+	info, err := server.UserInfo(ctx, tokens.AccessToken)
+	assert.Nilf(t, err, "should return the user info: %v", err)
+	assert.Equalf(t, user.String(), info.Subject, "subject should be the user")
+	assert.Nilf(t, info.Email, "should not return the email without the email scope")
+	if assert.NotNilf(t, info.PreferredUsername, "should return the username for the profile scope") {
+		assert.Equalf(t, "user", *info.PreferredUsername, "should return the username")
+	}
+}
+
+func TestNewRSAJWK(t *testing.T) {
+	// RFC 7638 3.1
+	n, _ := base64.RawURLEncoding.DecodeString("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}
+
+	jwk := NewRSAJWK(key)
+	assert.Equalf(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", jwk.KeyID, "key id should be the RFC 7638 thumbprint")
+	assert.Equalf(t, "AQAB", jwk.Exponent, "should encode the exponent")
+	assert.Equalf(t, key, publicKey(t, jwk), "should round trip the key")
+}
@@ -0,0 +1,279 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/domain/v1/serviceaccounts"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/oauthclients"
+	"github.com/ooqls/go-cache/cache"
+	"go.uber.org/zap"
+)
+
+const (
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeRefreshToken      = "refresh_token"
+	GrantTypeClientCredentials = "client_credentials"
+)
+
+// refreshGrant is what a refresh token is exchanged for. Every refresh token belongs to the family
+// started by the code exchange, presenting a used refresh token revokes the whole family
+type refreshGrant struct {
+	FamilyID uuid.UUID
+	UserID   records.UserId
+	ClientID uuid.UUID
+	Scopes   []string
+	AuthTime time.Time
+	Used     bool
+}
+
+type refreshFamily struct {
+	Revoked bool
+}
+
+// Token handles a token request, the client is authenticated before the grant is checked
+func (s *AuthorizationServerV1) Token(ctx context.Context, req TokenRequest) (*TokenResponse, error) {
+	if req.GrantType == GrantTypeClientCredentials {
+		return s.clientCredentials(ctx, req)
+	}
+
+	if req.ClientAssertion != "" {
+		// only service accounts authenticate with assertions
+		return nil, ErrInvalidClient
+	}
+
+	client, err := s.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.GrantType {
+	case GrantTypeAuthorizationCode:
+		return s.exchangeCode(ctx, client, req)
+	case GrantTypeRefreshToken:
+		return s.refresh(ctx, client, req)
+	case GrantTypeDeviceCode:
+		return s.exchangeDeviceCode(ctx, client, req)
+	}
+
+	return nil, ErrUnsupportedGrantType
+}
+
+// clientCredentials authenticates a service account, RFC 6749 4.4. The access token is an auth token of the
+// service account rather than a client access token, it is authorized by the account's roles so no scope can be requested
+func (s *AuthorizationServerV1) clientCredentials(ctx context.Context, req TokenRequest) (*TokenResponse, error) {
+	if s.serviceAccounts == nil {
+		return nil, ErrUnsupportedGrantType
+	}
+
+	if req.Scope != "" {
+		return nil, ErrInvalidScope
+	}
+
+	tokens, err := s.serviceAccounts.Authenticate(ctx, serviceaccounts.ClientCredentials{
+		ClientID:      req.ClientID,
+		ClientSecret:  req.ClientSecret,
+		AssertionType: req.ClientAssertionType,
+		Assertion:     req.ClientAssertion,
+	})
+	if err != nil {
+		if errors.Is(err, serviceaccounts.ErrInvalidCredentials) {
+			return nil, ErrInvalidClient
+		}
+
+		l.Error("failed to authenticate service account", zap.String("client_id", req.ClientID), zap.Error(err))
+		return nil, ErrServerError
+	}
+
+	return &TokenResponse{
+		AccessToken: tokens.AuthToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   tokens.ExpiresIn,
+	}, nil
+}
+
+// exchangeCode exchanges an authorization code for tokens, RFC 6749 4.1.3 and RFC 7636 4.6
+func (s *AuthorizationServerV1) exchangeCode(ctx context.Context, client *oauthclients.Client, req TokenRequest) (*TokenResponse, error) {
+	l := l.With(zap.String("client_id", client.ID.String()))
+
+	if req.Code == "" || len(req.Code) > 1024 {
+		return nil, ErrInvalidRequest
+	}
+
+	var code authorizationCode
+	err := s.codes.Update(ctx, tokenKey(req.Code), func(load func(target any) error) (any, error) {
+		if err := load(&code); err != nil {
+			return nil, err
+		}
+
+		if code.Used {
+			return nil, ErrInvalidGrant
+		}
+
+		code.Used = true
+		return code, nil
+	})
+	if err != nil {
+		if cache.IsCacheMissErr(err) || errors.Is(err, ErrInvalidGrant) {
+			l.Warn("invalid or used authorization code")
+			return nil, ErrInvalidGrant
+		}
+
+		l.Error("failed to get authorization code", zap.Error(err))
+		return nil, ErrServerError
+	}
+
+	if code.ClientID != client.ID || code.RedirectURI != req.RedirectURI {
+		l.Warn("authorization code was issued for another client or redirect uri")
+		return nil, ErrInvalidGrant
+	}
+
+	if !verifyCodeChallenge(code.CodeChallenge, req.CodeVerifier) {
+		l.Warn("invalid code verifier")
+		return nil, ErrInvalidGrant
+	}
+
+	return s.issueTokens(ctx, refreshGrant{
+		FamilyID: uuid.New(),
+		UserID:   code.UserID,
+		ClientID: client.ID,
+		Scopes:   code.Scopes,
+		AuthTime: code.AuthTime,
+	}, code.Nonce)
+}
+
+// refresh exchanges a refresh token for new tokens, the scope can only be narrowed and the user must
+// still consent to it
+func (s *AuthorizationServerV1) refresh(ctx context.Context, client *oauthclients.Client, req TokenRequest) (*TokenResponse, error) {
+	l := l.With(zap.String("client_id", client.ID.String()))
+
+	if req.RefreshToken == "" || len(req.RefreshToken) > 1024 {
+		return nil, ErrInvalidRequest
+	}
+
+	var grant refreshGrant
+	var reused bool
+	err := s.refreshTokens.Update(ctx, tokenKey(req.RefreshToken), func(load func(target any) error) (any, error) {
+		if err := load(&grant); err != nil {
+			return nil, err
+		}
+
+		reused = grant.Used
+		grant.Used = true
+		return grant, nil
+	})
+	if err != nil {
+		if cache.IsCacheMissErr(err) {
+			return nil, ErrInvalidGrant
+		}
+
+		l.Error("failed to get refresh token", zap.Error(err))
+		return nil, ErrServerError
+	}
+
+	l = l.With(zap.String("user_id", grant.UserID.String()), zap.String("family_id", grant.FamilyID.String()))
+
+	if grant.ClientID != client.ID {
+		l.Warn("refresh token was issued to another client")
+		return nil, ErrInvalidGrant
+	}
+
+	if reused {
+		l.Warn("refresh token reused, revoking its family")
+		err = s.refreshFamilies.Set(ctx, grant.FamilyID.String(), refreshFamily{Revoked: true})
+		if err != nil {
+			l.Error("failed to revoke refresh token family", zap.Error(err))
+		}
+
+		return nil, ErrInvalidGrant
+	}
+
+	var family refreshFamily
+	err = s.refreshFamilies.Get(ctx, grant.FamilyID.String(), &family)
+	if err != nil && !cache.IsCacheMissErr(err) {
+		l.Error("failed to get refresh token family", zap.Error(err))
+		return nil, ErrServerError
+	}
+
+	if family.Revoked {
+		return nil, ErrInvalidGrant
+	}
+
+	if req.Scope != "" {
+		scopes := ParseScope(req.Scope)
+		if !containsAll(grant.Scopes, scopes) {
+			return nil, ErrInvalidScope
+		}
+
+		grant.Scopes = scopes
+	}
+
+	consent, err := s.clientReader.GetConsent(ctx, grant.UserID, client.ID)
+	if err != nil {
+		l.Error("failed to get consent", zap.Error(err))
+		return nil, ErrServerError
+	}
+
+	if consent == nil || !containsAll(consent.Scopes, grant.Scopes) {
+		l.Info("user no longer consents to the refresh token's scopes")
+		return nil, ErrInvalidGrant
+	}
+
+	grant.Used = false
+	return s.issueTokens(ctx, grant, "")
+}
+
+// issueTokens issues an access token and a refresh token in the grant's family, and an id token for the openid scope
+func (s *AuthorizationServerV1) issueTokens(ctx context.Context, grant refreshGrant, nonce string) (*TokenResponse, error) {
+	l := l.With(zap.String("user_id", grant.UserID.String()), zap.String("client_id", grant.ClientID.String()))
+
+	scope := FormatScope(grant.Scopes)
+	accessToken, token, err := s.accessIssuer.IssueToken(grant.UserID.String(), AccessClaims{
+		UserID:   grant.UserID,
+		ClientID: grant.ClientID,
+		Scope:    scope,
+	})
+	if err != nil {
+		l.Error("failed to issue access token", zap.Error(err))
+		return nil, ErrServerError
+	}
+
+	expiresAt, err := token.Claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		l.Error("access token has no expiry", zap.Error(err))
+		return nil, ErrServerError
+	}
+
+	refreshToken, err := randomToken()
+	if err != nil {
+		return nil, ErrServerError
+	}
+
+	var idToken string
+	if slices.Contains(grant.Scopes, ScopeOpenID) {
+		idToken, err = s.issueIDToken(ctx, grant, nonce)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.refreshTokens.Set(ctx, tokenKey(refreshToken), grant)
+	if err != nil {
+		l.Error("failed to store refresh token", zap.Error(err))
+		return nil, ErrServerError
+	}
+
+	l.Info("issued oauth tokens", zap.String("scope", scope))
+	return &TokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(time.Until(expiresAt.Time).Seconds()),
+		RefreshToken: refreshToken,
+		Scope:        scope,
+		IDToken:      idToken,
+	}, nil
+}
@@ -0,0 +1,153 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: users.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	authentication "github.com/ooqls/go-auth/domain/v1/authentication"
+	authorization "github.com/ooqls/go-auth/domain/v1/authorization"
+	records "github.com/ooqls/go-auth/records"
+	users "github.com/ooqls/go-auth/records/v1/users"
+)
+
+// MockUserService is a mock of UserService interface.
+type MockUserService struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserServiceMockRecorder
+}
+
+// MockUserServiceMockRecorder is the mock recorder for MockUserService.
+type MockUserServiceMockRecorder struct {
+	mock *MockUserService
+}
+
+// NewMockUserService creates a new mock instance.
+func NewMockUserService(ctrl *gomock.Controller) *MockUserService {
+	mock := &MockUserService{ctrl: ctrl}
+	mock.recorder = &MockUserServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserService) EXPECT() *MockUserServiceMockRecorder {
+	return m.recorder
+}
+
+// ChangeCredentials mocks base method.
+func (m *MockUserService) ChangeCredentials(ctx authorization.Context, id records.UserId, oldKey, key, salt string, algorithm authentication.SupportedAlgorithm) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeCredentials", ctx, id, oldKey, key, salt, algorithm)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangeCredentials indicates an expected call of ChangeCredentials.
+func (mr *MockUserServiceMockRecorder) ChangeCredentials(ctx, id, oldKey, key, salt, algorithm interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeCredentials", reflect.TypeOf((*MockUserService)(nil).ChangeCredentials), ctx, id, oldKey, key, salt, algorithm)
+}
+
+// CreateUser mocks base method.
+func (m *MockUserService) CreateUser(ctx authorization.Context, email, key, salt, username string, algorithm authentication.SupportedAlgorithm) (*users.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, email, key, salt, username, algorithm)
+	ret0, _ := ret[0].(*users.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockUserServiceMockRecorder) CreateUser(ctx, email, key, salt, username, algorithm interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserService)(nil).CreateUser), ctx, email, key, salt, username, algorithm)
+}
+
+// DeleteUser mocks base method.
+func (m *MockUserService) DeleteUser(ctx authorization.Context, id records.UserId) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockUserServiceMockRecorder) DeleteUser(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserService)(nil).DeleteUser), ctx, id)
+}
+
+// GetUser mocks base method.
+func (m *MockUserService) GetUser(ctx authorization.Context, id records.UserId) (*users.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", ctx, id)
+	ret0, _ := ret[0].(*users.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockUserServiceMockRecorder) GetUser(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockUserService)(nil).GetUser), ctx, id)
+}
+
+// GetUserByUsername mocks base method.
+func (m *MockUserService) GetUserByUsername(ctx authorization.Context, username string) (*users.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByUsername", ctx, username)
+	ret0, _ := ret[0].(*users.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByUsername indicates an expected call of GetUserByUsername.
+func (mr *MockUserServiceMockRecorder) GetUserByUsername(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUsername", reflect.TypeOf((*MockUserService)(nil).GetUserByUsername), ctx, username)
+}
+
+// SetMagicLink mocks base method.
+func (m *MockUserService) SetMagicLink(ctx authorization.Context, id records.UserId, enabled bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMagicLink", ctx, id, enabled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMagicLink indicates an expected call of SetMagicLink.
+func (mr *MockUserServiceMockRecorder) SetMagicLink(ctx, id, enabled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMagicLink", reflect.TypeOf((*MockUserService)(nil).SetMagicLink), ctx, id, enabled)
+}
+
+// UpdateUser mocks base method.
+func (m *MockUserService) UpdateUser(ctx authorization.Context, id records.UserId, email, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", ctx, id, email, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUser indicates an expected call of UpdateUser.
+func (mr *MockUserServiceMockRecorder) UpdateUser(ctx, id, email, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserService)(nil).UpdateUser), ctx, id, email, username)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockUserService) VerifyEmail(ctx authorization.Context, id records.UserId, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx, id, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockUserServiceMockRecorder) VerifyEmail(ctx, id, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockUserService)(nil).VerifyEmail), ctx, id, email)
+}
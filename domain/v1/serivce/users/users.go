@@ -3,28 +3,36 @@ package users
 import (
 	"errors"
 
+	"github.com/ooqls/go-auth/domain/v1/authentication"
 	"github.com/ooqls/go-auth/domain/v1/authorization"
 	"github.com/ooqls/go-auth/records"
 	"github.com/ooqls/go-auth/records/v1/users"
-	"github.com/ooqls/go-crypto/crypto"
 	"github.com/ooqls/go-log"
 	"go.uber.org/zap"
 )
 
 var (
-	ErrInvalidEmail      error = errors.New("invalid email")
-	ErrInvalidKey        error = errors.New("invalid key")
-	ErrInvalidSalt       error = errors.New("invalid salt")
-	ErrInvalidUsername   error = errors.New("invalid username")
-	ErrUserAlreadyExists error = errors.New("user already exists")
-	ErrInternal          error = errors.New("internal error")
+	ErrInvalidEmail       error = errors.New("invalid email")
+	ErrInvalidKey         error = errors.New("invalid key")
+	ErrInvalidSalt        error = errors.New("invalid salt")
+	ErrInvalidUsername    error = errors.New("invalid username")
+	ErrInvalidAlgorithm   error = errors.New("invalid algorithm")
+	ErrUserAlreadyExists  error = errors.New("user already exists")
+	ErrCredentialsChanged error = errors.New("credentials changed concurrently")
+	ErrEmailChanged       error = errors.New("email changed")
+	ErrUserNotFound       error = errors.New("user not found")
+	ErrInternal           error = errors.New("internal error")
 )
 
+//go:generate go run github.com/golang/mock/mockgen -source=users.go -destination=mocks/mock_user_service.go -package=mocks
 type UserService interface {
-	CreateUser(ctx authorization.Context, email, key, salt, username string) (*users.User, error)
+	CreateUser(ctx authorization.Context, email, key, salt, username string, algorithm authentication.SupportedAlgorithm) (*users.User, error)
 	GetUser(ctx authorization.Context, id records.UserId) (*users.User, error)
 	GetUserByUsername(ctx authorization.Context, username string) (*users.User, error)
-	UpdateUser(ctx authorization.Context, id records.UserId, email, key, username string) error
+	UpdateUser(ctx authorization.Context, id records.UserId, email, username string) error
+	ChangeCredentials(ctx authorization.Context, id records.UserId, oldKey, key, salt string, algorithm authentication.SupportedAlgorithm) error
+	VerifyEmail(ctx authorization.Context, id records.UserId, email string) error
+	SetMagicLink(ctx authorization.Context, id records.UserId, enabled bool) error
 	DeleteUser(ctx authorization.Context, id records.UserId) error
 }
 
@@ -44,7 +52,8 @@ func NewUserServiceImpl(ua authorization.UserAuthorizer, userR users.Reader, use
 	}
 }
 
-func (u *UserServiceImpl) CreateUser(ctx authorization.Context, email, key, salt, username string) (*users.User, error) {
+// CreateUser creates a user with the given key, salt is only required for AESGCM keys since asymmetric keys are not derived
+func (u *UserServiceImpl) CreateUser(ctx authorization.Context, email, key, salt, username string, algorithm authentication.SupportedAlgorithm) (*users.User, error) {
 	if email == "" {
 		return nil, ErrInvalidEmail
 	}
@@ -53,7 +62,12 @@ func (u *UserServiceImpl) CreateUser(ctx authorization.Context, email, key, salt
 		return nil, ErrInvalidKey
 	}
 
-	if salt == "" {
+	algorithm, err := authentication.ParseAlgorithm(string(algorithm))
+	if err != nil {
+		return nil, ErrInvalidAlgorithm
+	}
+
+	if salt == "" && !algorithm.IsAsymmetric() {
 		return nil, ErrInvalidSalt
 	}
 
@@ -61,7 +75,7 @@ func (u *UserServiceImpl) CreateUser(ctx authorization.Context, email, key, salt
 		return nil, ErrInvalidUsername
 	}
 
-	err := crypto.VerifyGCMAESKey([]byte(key))
+	err = authentication.ValidateKey(algorithm, []byte(key))
 	if err != nil {
 		u.l.Error("failed to verify key", zap.Error(err))
 		return nil, ErrInvalidKey
@@ -78,11 +92,12 @@ func (u *UserServiceImpl) CreateUser(ctx authorization.Context, email, key, salt
 	}
 
 	user := users.User{
-		Email:    email,
-		Key:      []byte(key),
-		Username: username,
-		Salt:     []byte(salt),
-		ID:       records.NewUserID(),
+		Email:     email,
+		Key:       []byte(key),
+		Username:  username,
+		Salt:      []byte(salt),
+		ID:        records.NewUserID(),
+		Algorithm: string(algorithm),
 	}
 
 	// err = u.ua.IsAuthorizedToPerformUserAction(&ctx, authorization.CreateAction, user)
@@ -125,17 +140,127 @@ func (u *UserServiceImpl) GetUserByUsername(ctx authorization.Context, username
 	return user, nil
 }
 
-func (u *UserServiceImpl) UpdateUser(ctx authorization.Context, targetUserid records.UserId, email, key, username string) error {
+// UpdateUser updates the user's profile, the key can only be changed with ChangeCredentials
+func (u *UserServiceImpl) UpdateUser(ctx authorization.Context, targetUserid records.UserId, email, username string) error {
 	if err := u.ua.IsAuthorizedToPerformUserAction(&ctx, authorization.UpdateAction, users.User{ID: targetUserid}); err != nil {
 		return err
 	}
 
-	return u.userW.UpdateUser(ctx, users.User{
+	user := users.User{
 		ID:       targetUserid,
 		Email:    email,
-		Key:      []byte(key),
 		Username: username,
-	})
+	}
+
+	err := u.userW.UpdateUser(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	u.userR.Invalidate(ctx, &user)
+	return nil
+}
+
+// ChangeCredentials replaces the user's key, salt and algorithm. The caller must have verified that the
+// user proved the old key, the change fails with ErrCredentialsChanged if the key is no longer oldKey
+func (u *UserServiceImpl) ChangeCredentials(ctx authorization.Context, targetUserid records.UserId, oldKey, key, salt string, algorithm authentication.SupportedAlgorithm) error {
+	if err := u.ua.IsAuthorizedToPerformUserAction(&ctx, authorization.UpdateAction, users.User{ID: targetUserid}); err != nil {
+		return err
+	}
+
+	algorithm, err := authentication.ParseAlgorithm(string(algorithm))
+	if err != nil {
+		return ErrInvalidAlgorithm
+	}
+
+	if salt == "" && !algorithm.IsAsymmetric() {
+		return ErrInvalidSalt
+	}
+
+	err = authentication.ValidateKey(algorithm, []byte(key))
+	if err != nil {
+		u.l.Error("failed to verify key", zap.Error(err))
+		return ErrInvalidKey
+	}
+
+	user, err := u.userR.GetUser(ctx, targetUserid)
+	if err != nil {
+		u.l.Error("failed to get user", zap.Error(err))
+		return err
+	}
+
+	updated, err := u.userW.UpdateCredentials(ctx, targetUserid, []byte(oldKey), []byte(key), []byte(salt), string(algorithm))
+	if err != nil {
+		u.l.Error("failed to update credentials", zap.Error(err))
+		return err
+	}
+
+	u.userR.Invalidate(ctx, user)
+	if !updated {
+		return ErrCredentialsChanged
+	}
+
+	return nil
+}
+
+// VerifyEmail marks the user's email as verified, it fails with ErrEmailChanged if the user's email is no longer email
+func (u *UserServiceImpl) VerifyEmail(ctx authorization.Context, targetUserid records.UserId, email string) error {
+	if err := u.ua.IsAuthorizedToPerformUserAction(&ctx, authorization.UpdateAction, users.User{ID: targetUserid}); err != nil {
+		return err
+	}
+
+	user, err := u.userR.GetUser(ctx, targetUserid)
+	if err != nil {
+		u.l.Error("failed to get user", zap.Error(err))
+		return err
+	}
+
+	if user == nil {
+		return ErrEmailChanged
+	}
+
+	verified, err := u.userW.VerifyEmail(ctx, targetUserid, email)
+	if err != nil {
+		u.l.Error("failed to verify email", zap.Error(err))
+		return err
+	}
+
+	u.userR.Invalidate(ctx, user)
+	if !verified {
+		return ErrEmailChanged
+	}
+
+	return nil
+}
+
+// SetMagicLink turns logging in with emailed magic links on or off for the user
+func (u *UserServiceImpl) SetMagicLink(ctx authorization.Context, targetUserid records.UserId, enabled bool) error {
+	if err := u.ua.IsAuthorizedToPerformUserAction(&ctx, authorization.UpdateAction, users.User{ID: targetUserid}); err != nil {
+		return err
+	}
+
+	user, err := u.userR.GetUser(ctx, targetUserid)
+	if err != nil {
+		u.l.Error("failed to get user", zap.Error(err))
+		return err
+	}
+
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	updated, err := u.userW.SetMagicLink(ctx, targetUserid, enabled)
+	if err != nil {
+		u.l.Error("failed to set magic link", zap.Error(err))
+		return err
+	}
+
+	u.userR.Invalidate(ctx, user)
+	if !updated {
+		return ErrUserNotFound
+	}
+
+	return nil
 }
 
 func (u *UserServiceImpl) DeleteUser(ctx authorization.Context, id records.UserId) error {
@@ -0,0 +1,144 @@
+package serviceaccounts
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"time"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/records/v1/serviceaccounts"
+	"github.com/ooqls/go-cache/cache"
+	"go.uber.org/zap"
+)
+
+// maxAssertionLifetime is how far in the future an assertion may expire, it bounds how long used assertions are remembered
+const maxAssertionLifetime = 5 * time.Minute
+
+var assertionMethods = []string{
+	jwtv5.SigningMethodRS256.Alg(),
+	jwtv5.SigningMethodPS256.Alg(),
+	jwtv5.SigningMethodES256.Alg(),
+	jwtv5.SigningMethodEdDSA.Alg(),
+}
+
+// parsePublicKey parses a PEM encoded PKIX public key and returns its DER encoding,
+// RSA keys of at least 2048 bits, ECDSA and Ed25519 keys are accepted
+func parsePublicKey(publicKey []byte) ([]byte, error) {
+	block, _ := pem.Decode(publicKey)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, ErrInvalidPublicKey
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if k.N.BitLen() < 2048 {
+			return nil, ErrInvalidPublicKey
+		}
+	case *ecdsa.PublicKey, ed25519.PublicKey:
+	default:
+		return nil, ErrInvalidPublicKey
+	}
+
+	return block.Bytes, nil
+}
+
+// usedAssertion records the use of an assertion, Claim tells apart concurrent first uses of the same assertion
+type usedAssertion struct {
+	Claim uuid.UUID
+	Used  bool
+}
+
+// useAssertion marks the assertion as used, returning ErrInvalidCredentials if it already was. The store can not
+// create an entry atomically, so the first use writes an unused entry with its claim and redeems it with an update,
+// of concurrent first uses only the one whose entry was written last is accepted
+func (s *ServiceAccountServiceV1) useAssertion(ctx context.Context, key string) error {
+	var used usedAssertion
+	err := s.usedAssertions.Get(ctx, key, &used)
+	if err == nil {
+		return ErrInvalidCredentials
+	}
+
+	if !cache.IsCacheMissErr(err) {
+		return err
+	}
+
+	claim := uuid.New()
+	err = s.usedAssertions.Set(ctx, key, usedAssertion{Claim: claim})
+	if err != nil {
+		return err
+	}
+
+	return s.usedAssertions.Update(ctx, key, func(load func(target any) error) (any, error) {
+		if err := load(&used); err != nil {
+			return nil, err
+		}
+
+		if used.Used || used.Claim != claim {
+			return nil, ErrInvalidCredentials
+		}
+
+		used.Used = true
+		return used, nil
+	})
+}
+
+// verifyAssertion verifies a jwt assertion of the account, RFC 7523 3. The assertion must be issued by and
+// for the account, be addressed to the token endpoint and is only accepted once
+func (s *ServiceAccountServiceV1) verifyAssertion(ctx context.Context, account *serviceaccounts.ServiceAccount, assertion string) error {
+	l := l.With(zap.String("service_account_id", account.ID.String()))
+
+	if account.PublicKey == nil {
+		return ErrInvalidCredentials
+	}
+
+	key, err := x509.ParsePKIXPublicKey(account.PublicKey)
+	if err != nil {
+		l.Error("failed to parse public key", zap.Error(err))
+		return ErrInternal
+	}
+
+	claims := jwtv5.RegisteredClaims{}
+	parser := jwtv5.NewParser(
+		jwtv5.WithValidMethods(assertionMethods),
+		jwtv5.WithAudience(s.audience),
+		jwtv5.WithIssuer(account.ID.String()),
+		jwtv5.WithSubject(account.ID.String()),
+		jwtv5.WithExpirationRequired(),
+	)
+	_, err = parser.ParseWithClaims(assertion, &claims, func(*jwtv5.Token) (any, error) {
+		return key, nil
+	})
+	if err != nil {
+		l.Warn("invalid assertion", zap.Error(err))
+		return ErrInvalidCredentials
+	}
+
+	if claims.ID == "" || claims.ExpiresAt.After(time.Now().Add(maxAssertionLifetime)) {
+		l.Warn("assertion has no jti or expires too late")
+		return ErrInvalidCredentials
+	}
+
+	err = s.useAssertion(ctx, account.ID.String()+":"+claims.ID)
+	if err != nil {
+		if errors.Is(err, ErrInvalidCredentials) {
+			l.Warn("assertion reused", zap.String("jti", claims.ID))
+			return ErrInvalidCredentials
+		}
+
+		l.Error("failed to store used assertion", zap.Error(err))
+		return ErrInternal
+	}
+
+	return nil
+}
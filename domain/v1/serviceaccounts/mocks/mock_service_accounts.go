@@ -0,0 +1,189 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: serviceaccounts.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	authentication "github.com/ooqls/go-auth/domain/v1/authentication"
+	authorization "github.com/ooqls/go-auth/domain/v1/authorization"
+	serviceaccounts "github.com/ooqls/go-auth/domain/v1/serviceaccounts"
+	records "github.com/ooqls/go-auth/records"
+	serviceaccounts0 "github.com/ooqls/go-auth/records/v1/serviceaccounts"
+)
+
+// MockServiceAccountService is a mock of ServiceAccountService interface.
+type MockServiceAccountService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceAccountServiceMockRecorder
+}
+
+// MockServiceAccountServiceMockRecorder is the mock recorder for MockServiceAccountService.
+type MockServiceAccountServiceMockRecorder struct {
+	mock *MockServiceAccountService
+}
+
+// NewMockServiceAccountService creates a new mock instance.
+func NewMockServiceAccountService(ctrl *gomock.Controller) *MockServiceAccountService {
+	mock := &MockServiceAccountService{ctrl: ctrl}
+	mock.recorder = &MockServiceAccountServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockServiceAccountService) EXPECT() *MockServiceAccountServiceMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockServiceAccountService) Authenticate(ctx context.Context, credentials serviceaccounts.ClientCredentials) (*authentication.TokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, credentials)
+	ret0, _ := ret[0].(*authentication.TokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockServiceAccountServiceMockRecorder) Authenticate(ctx, credentials interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockServiceAccountService)(nil).Authenticate), ctx, credentials)
+}
+
+// AuthorizationContext mocks base method.
+func (m *MockServiceAccountService) AuthorizationContext(ctx context.Context, id uuid.UUID) (*authorization.Context, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizationContext", ctx, id)
+	ret0, _ := ret[0].(*authorization.Context)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthorizationContext indicates an expected call of AuthorizationContext.
+func (mr *MockServiceAccountServiceMockRecorder) AuthorizationContext(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizationContext", reflect.TypeOf((*MockServiceAccountService)(nil).AuthorizationContext), ctx, id)
+}
+
+// CreateServiceAccount mocks base method.
+func (m *MockServiceAccountService) CreateServiceAccount(ctx context.Context, ownerID records.UserId, name string, publicKey []byte) (*serviceaccounts0.ServiceAccount, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateServiceAccount", ctx, ownerID, name, publicKey)
+	ret0, _ := ret[0].(*serviceaccounts0.ServiceAccount)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateServiceAccount indicates an expected call of CreateServiceAccount.
+func (mr *MockServiceAccountServiceMockRecorder) CreateServiceAccount(ctx, ownerID, name, publicKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateServiceAccount", reflect.TypeOf((*MockServiceAccountService)(nil).CreateServiceAccount), ctx, ownerID, name, publicKey)
+}
+
+// DeleteServiceAccount mocks base method.
+func (m *MockServiceAccountService) DeleteServiceAccount(ctx context.Context, ownerID records.UserId, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteServiceAccount", ctx, ownerID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteServiceAccount indicates an expected call of DeleteServiceAccount.
+func (mr *MockServiceAccountServiceMockRecorder) DeleteServiceAccount(ctx, ownerID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServiceAccount", reflect.TypeOf((*MockServiceAccountService)(nil).DeleteServiceAccount), ctx, ownerID, id)
+}
+
+// GrantRole mocks base method.
+func (m *MockServiceAccountService) GrantRole(ctx context.Context, ownerID records.UserId, id uuid.UUID, roleID records.RoleId) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantRole", ctx, ownerID, id, roleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GrantRole indicates an expected call of GrantRole.
+func (mr *MockServiceAccountServiceMockRecorder) GrantRole(ctx, ownerID, id, roleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantRole", reflect.TypeOf((*MockServiceAccountService)(nil).GrantRole), ctx, ownerID, id, roleID)
+}
+
+// ListRoles mocks base method.
+func (m *MockServiceAccountService) ListRoles(ctx context.Context, ownerID records.UserId, id uuid.UUID) ([]records.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoles", ctx, ownerID, id)
+	ret0, _ := ret[0].([]records.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRoles indicates an expected call of ListRoles.
+func (mr *MockServiceAccountServiceMockRecorder) ListRoles(ctx, ownerID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoles", reflect.TypeOf((*MockServiceAccountService)(nil).ListRoles), ctx, ownerID, id)
+}
+
+// ListServiceAccounts mocks base method.
+func (m *MockServiceAccountService) ListServiceAccounts(ctx context.Context, ownerID records.UserId) ([]serviceaccounts0.ServiceAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListServiceAccounts", ctx, ownerID)
+	ret0, _ := ret[0].([]serviceaccounts0.ServiceAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServiceAccounts indicates an expected call of ListServiceAccounts.
+func (mr *MockServiceAccountServiceMockRecorder) ListServiceAccounts(ctx, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServiceAccounts", reflect.TypeOf((*MockServiceAccountService)(nil).ListServiceAccounts), ctx, ownerID)
+}
+
+// RevokeRole mocks base method.
+func (m *MockServiceAccountService) RevokeRole(ctx context.Context, ownerID records.UserId, id uuid.UUID, roleID records.RoleId) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRole", ctx, ownerID, id, roleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRole indicates an expected call of RevokeRole.
+func (mr *MockServiceAccountServiceMockRecorder) RevokeRole(ctx, ownerID, id, roleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRole", reflect.TypeOf((*MockServiceAccountService)(nil).RevokeRole), ctx, ownerID, id, roleID)
+}
+
+// RotateCredentials mocks base method.
+func (m *MockServiceAccountService) RotateCredentials(ctx context.Context, ownerID records.UserId, id uuid.UUID, publicKey []byte) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateCredentials", ctx, ownerID, id, publicKey)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateCredentials indicates an expected call of RotateCredentials.
+func (mr *MockServiceAccountServiceMockRecorder) RotateCredentials(ctx, ownerID, id, publicKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateCredentials", reflect.TypeOf((*MockServiceAccountService)(nil).RotateCredentials), ctx, ownerID, id, publicKey)
+}
+
+// VerifyCredentials mocks base method.
+func (m *MockServiceAccountService) VerifyCredentials(ctx context.Context, credentials serviceaccounts.ClientCredentials) (*serviceaccounts0.ServiceAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyCredentials", ctx, credentials)
+	ret0, _ := ret[0].(*serviceaccounts0.ServiceAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyCredentials indicates an expected call of VerifyCredentials.
+func (mr *MockServiceAccountServiceMockRecorder) VerifyCredentials(ctx, credentials interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyCredentials", reflect.TypeOf((*MockServiceAccountService)(nil).VerifyCredentials), ctx, credentials)
+}
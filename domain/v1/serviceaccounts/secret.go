@@ -0,0 +1,83 @@
+package serviceaccounts
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// argon2id parameters of new secret hashes, the parameters are stored with the hash so they can be raised later
+const (
+	argonTime    uint32 = 2
+	argonMemory  uint32 = 19 * 1024
+	argonThreads uint8  = 1
+	argonKeyLen  uint32 = 32
+	argonSaltLen        = 16
+)
+
+// newSecret returns a random client secret
+func newSecret() (string, error) {
+	var b [32]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b[:]), nil
+}
+
+// hashSecret hashes a secret with argon2id, the hash is in the PHC string format
+func hashSecret(secret string) (string, error) {
+	salt := make([]byte, argonSaltLen)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(secret), salt, argonTime, argonMemory, argonThreads, argonKeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		argonMemory,
+		argonTime,
+		argonThreads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// verifySecret returns true if the secret matches a hash of hashSecret
+func verifySecret(secret string, hash string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return false
+	}
+
+	var version int
+	_, err := fmt.Sscanf(parts[2], "v=%d", &version)
+	if err != nil || version != argon2.Version {
+		return false
+	}
+
+	var memory, time uint32
+	var threads uint8
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads)
+	if err != nil || threads == 0 {
+		return false
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return false
+	}
+
+	computed := argon2.IDKey([]byte(secret), salt, time, memory, threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, computed) == 1
+}
@@ -0,0 +1,322 @@
+package serviceaccounts
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/domain/v1/authentication"
+	"github.com/ooqls/go-auth/domain/v1/authorization"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/roles"
+	"github.com/ooqls/go-auth/records/v1/serviceaccounts"
+	"github.com/ooqls/go-cache/factory"
+	"github.com/ooqls/go-cache/store"
+	"go.uber.org/zap"
+)
+
+var _ ServiceAccountService = &ServiceAccountServiceV1{}
+
+type ServiceAccountServiceV1 struct {
+	reader         serviceaccounts.Reader
+	writer         serviceaccounts.Writer
+	roleReader     roles.Reader
+	authenticator  authentication.Authenticator
+	usedAssertions store.GenericInterface
+	audience       string
+}
+
+// NewServiceAccountServiceV1 returns a service account service issuing tokens with authenticator.
+// audience is the url of the token endpoint, jwt assertions must be addressed to it
+func NewServiceAccountServiceV1(
+	reader serviceaccounts.Reader,
+	writer serviceaccounts.Writer,
+	roleReader roles.Reader,
+	authenticator authentication.Authenticator,
+	cacheFactory factory.CacheFactory,
+	audience string) *ServiceAccountServiceV1 {
+
+	return &ServiceAccountServiceV1{
+		reader:         reader,
+		writer:         writer,
+		roleReader:     roleReader,
+		authenticator:  authenticator,
+		usedAssertions: cacheFactory.NewStore("service_account_assertions", maxAssertionLifetime),
+		audience:       audience,
+	}
+}
+
+// newCredentials returns the credentials to store for an account, a secret is generated when there is no public key
+func newCredentials(publicKey []byte) (sql.NullString, []byte, string, error) {
+	if len(publicKey) > 0 {
+		der, err := parsePublicKey(publicKey)
+		if err != nil {
+			return sql.NullString{}, nil, "", err
+		}
+
+		return sql.NullString{}, der, "", nil
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return sql.NullString{}, nil, "", ErrInternal
+	}
+
+	hash, err := hashSecret(secret)
+	if err != nil {
+		return sql.NullString{}, nil, "", ErrInternal
+	}
+
+	return sql.NullString{String: hash, Valid: true}, nil, secret, nil
+}
+
+func (s *ServiceAccountServiceV1) CreateServiceAccount(ctx context.Context, ownerID records.UserId, name string, publicKey []byte) (*serviceaccounts.ServiceAccount, string, error) {
+	l := l.With(zap.String("owner_id", ownerID.String()))
+
+	if name == "" || len(name) > 255 {
+		return nil, "", ErrInvalidName
+	}
+
+	secretHash, der, secret, err := newCredentials(publicKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	created, err := s.writer.CreateServiceAccount(ctx, serviceaccounts.ServiceAccount{
+		OwnerID:    ownerID,
+		Name:       name,
+		SecretHash: secretHash,
+		PublicKey:  der,
+	})
+	if err != nil {
+		l.Error("failed to create service account", zap.Error(err))
+		return nil, "", ErrInternal
+	}
+
+	l.Info("created service account", zap.String("service_account_id", created.ID.String()), zap.Bool("public_key", der != nil))
+	return created, secret, nil
+}
+
+func (s *ServiceAccountServiceV1) ListServiceAccounts(ctx context.Context, ownerID records.UserId) ([]serviceaccounts.ServiceAccount, error) {
+	accounts, err := s.reader.ListServiceAccountsForOwner(ctx, ownerID)
+	if err != nil {
+		l.Error("failed to list service accounts", zap.String("owner_id", ownerID.String()), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	return accounts, nil
+}
+
+// getOwnedAccount returns the account if it is owned by ownerID, or ErrServiceAccountNotFound
+func (s *ServiceAccountServiceV1) getOwnedAccount(ctx context.Context, ownerID records.UserId, id uuid.UUID) (*serviceaccounts.ServiceAccount, error) {
+	account, err := s.reader.GetServiceAccount(ctx, id)
+	if err != nil {
+		l.Error("failed to get service account", zap.String("service_account_id", id.String()), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	if account == nil || account.OwnerID != ownerID {
+		return nil, ErrServiceAccountNotFound
+	}
+
+	return account, nil
+}
+
+// DeleteServiceAccount deletes the account and revokes the tokens issued to it
+func (s *ServiceAccountServiceV1) DeleteServiceAccount(ctx context.Context, ownerID records.UserId, id uuid.UUID) error {
+	l := l.With(zap.String("owner_id", ownerID.String()), zap.String("service_account_id", id.String()))
+
+	deleted, err := s.writer.DeleteServiceAccount(ctx, id, ownerID)
+	if err != nil {
+		l.Error("failed to delete service account", zap.Error(err))
+		return ErrInternal
+	}
+
+	if !deleted {
+		return ErrServiceAccountNotFound
+	}
+
+	err = s.authenticator.RevokeAllForUser(ctx, id)
+	if err != nil {
+		l.Error("failed to revoke service account tokens", zap.Error(err))
+		return ErrInternal
+	}
+
+	l.Info("deleted service account")
+	return nil
+}
+
+func (s *ServiceAccountServiceV1) RotateCredentials(ctx context.Context, ownerID records.UserId, id uuid.UUID, publicKey []byte) (string, error) {
+	l := l.With(zap.String("owner_id", ownerID.String()), zap.String("service_account_id", id.String()))
+
+	secretHash, der, secret, err := newCredentials(publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	updated, err := s.writer.UpdateCredentials(ctx, serviceaccounts.ServiceAccount{
+		ID:         id,
+		OwnerID:    ownerID,
+		SecretHash: secretHash,
+		PublicKey:  der,
+	})
+	if err != nil {
+		l.Error("failed to update service account credentials", zap.Error(err))
+		return "", ErrInternal
+	}
+
+	if !updated {
+		return "", ErrServiceAccountNotFound
+	}
+
+	err = s.authenticator.RevokeAllForUser(ctx, id)
+	if err != nil {
+		l.Error("failed to revoke service account tokens", zap.Error(err))
+		return "", ErrInternal
+	}
+
+	l.Info("rotated service account credentials", zap.Bool("public_key", der != nil))
+	return secret, nil
+}
+
+func (s *ServiceAccountServiceV1) ListRoles(ctx context.Context, ownerID records.UserId, id uuid.UUID) ([]records.Role, error) {
+	_, err := s.getOwnedAccount(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	accountRoles, err := s.reader.GetRoles(ctx, id)
+	if err != nil {
+		l.Error("failed to get service account roles", zap.String("service_account_id", id.String()), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	return accountRoles, nil
+}
+
+// GrantRole grants a role the owner holds to the account, so owners can not hand out more than they have
+func (s *ServiceAccountServiceV1) GrantRole(ctx context.Context, ownerID records.UserId, id uuid.UUID, roleID records.RoleId) error {
+	l := l.With(zap.String("owner_id", ownerID.String()), zap.String("service_account_id", id.String()), zap.String("role_id", roleID.String()))
+
+	_, err := s.getOwnedAccount(ctx, ownerID, id)
+	if err != nil {
+		return err
+	}
+
+	ownerRoles, err := s.roleReader.GetRolesForUser(ctx, ownerID)
+	if err != nil {
+		l.Error("failed to get owner roles", zap.Error(err))
+		return ErrInternal
+	}
+
+	held := slices.ContainsFunc(ownerRoles, func(role roles.Role) bool {
+		return role.ID == roleID
+	})
+	if !held {
+		return ErrRoleNotHeld
+	}
+
+	err = s.writer.AddRole(ctx, id, roleID)
+	if err != nil {
+		l.Error("failed to grant role", zap.Error(err))
+		return ErrInternal
+	}
+
+	l.Info("granted role to service account")
+	return nil
+}
+
+func (s *ServiceAccountServiceV1) RevokeRole(ctx context.Context, ownerID records.UserId, id uuid.UUID, roleID records.RoleId) error {
+	l := l.With(zap.String("owner_id", ownerID.String()), zap.String("service_account_id", id.String()), zap.String("role_id", roleID.String()))
+
+	_, err := s.getOwnedAccount(ctx, ownerID, id)
+	if err != nil {
+		return err
+	}
+
+	removed, err := s.writer.RemoveRole(ctx, id, roleID)
+	if err != nil {
+		l.Error("failed to revoke role", zap.Error(err))
+		return ErrInternal
+	}
+
+	if !removed {
+		return ErrRoleNotGranted
+	}
+
+	l.Info("revoked role from service account")
+	return nil
+}
+
+// VerifyCredentials returns the service account the credentials belong to, the account's secret
+// or a jwt assertion is required, not both
+func (s *ServiceAccountServiceV1) VerifyCredentials(ctx context.Context, credentials ClientCredentials) (*serviceaccounts.ServiceAccount, error) {
+	id, err := uuid.Parse(credentials.ClientID)
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	l := l.With(zap.String("service_account_id", id.String()))
+
+	account, err := s.reader.GetServiceAccount(ctx, id)
+	if err != nil {
+		l.Error("failed to get service account", zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	if account == nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	switch {
+	case credentials.Assertion != "" && credentials.ClientSecret == "":
+		if credentials.AssertionType != ClientAssertionTypeJWTBearer {
+			return nil, ErrInvalidCredentials
+		}
+
+		err = s.verifyAssertion(ctx, account, credentials.Assertion)
+		if err != nil {
+			return nil, err
+		}
+	case credentials.ClientSecret != "" && credentials.Assertion == "":
+		if !account.SecretHash.Valid || !verifySecret(credentials.ClientSecret, account.SecretHash.String) {
+			l.Warn("service account failed to authenticate")
+			return nil, ErrInvalidCredentials
+		}
+	default:
+		return nil, ErrInvalidCredentials
+	}
+
+	return account, nil
+}
+
+// Authenticate authenticates a service account with the client credentials grant, RFC 6749 4.4
+func (s *ServiceAccountServiceV1) Authenticate(ctx context.Context, credentials ClientCredentials) (*authentication.TokenResponse, error) {
+	account, err := s.VerifyCredentials(ctx, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.authenticator.AuthenticateServiceAccount(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	l.Info("authenticated service account", zap.String("service_account_id", account.ID.String()))
+	return response, nil
+}
+
+func (s *ServiceAccountServiceV1) AuthorizationContext(ctx context.Context, id uuid.UUID) (*authorization.Context, error) {
+	accountRoles, err := s.reader.GetRoleAggs(ctx, id)
+	if err != nil {
+		l.Error("failed to get service account roles", zap.String("service_account_id", id.String()), zap.Error(err))
+		return nil, ErrInternal
+	}
+
+	authCtx := authorization.NewServiceAccountContext(ctx, records.UserAgg{
+		UserId: id,
+		Roles:  accountRoles,
+	})
+	return &authCtx, nil
+}
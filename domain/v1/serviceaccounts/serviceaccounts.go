@@ -0,0 +1,66 @@
+package serviceaccounts
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/domain/v1/authentication"
+	"github.com/ooqls/go-auth/domain/v1/authorization"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/serviceaccounts"
+	"github.com/ooqls/go-log"
+	"go.uber.org/zap"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=serviceaccounts.go -destination=mocks/mock_service_accounts.go -package=mocks
+
+var l *zap.Logger
+
+func init() {
+	l = log.NewLogger("service_accounts")
+}
+
+var (
+	ErrInvalidName            error = errors.New("invalid service account name")
+	ErrInvalidPublicKey       error = errors.New("invalid public key")
+	ErrInvalidCredentials     error = errors.New("invalid service account credentials")
+	ErrServiceAccountNotFound error = errors.New("service account not found")
+	ErrRoleNotHeld            error = errors.New("owner does not hold the role")
+	ErrRoleNotGranted         error = errors.New("role is not granted to the service account")
+	ErrInternal               error = errors.New("internal error")
+)
+
+// ClientAssertionTypeJWTBearer is the client_assertion_type of RFC 7523 jwt assertions
+const ClientAssertionTypeJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// ClientCredentials is how a service account authenticates, either with its secret or with a jwt assertion
+// signed by the private key of its public key, RFC 7523 2.2
+type ClientCredentials struct {
+	ClientID      string
+	ClientSecret  string
+	AssertionType string
+	Assertion     string
+}
+
+// ServiceAccountService manages the service accounts of users and authenticates them. Service accounts are
+// principals for machines, they are owned by the user who created them and can only be granted roles their owner holds
+type ServiceAccountService interface {
+	// CreateServiceAccount creates a service account, it authenticates with the returned secret or,
+	// when publicKey is a PEM encoded public key, with jwt assertions and no secret is returned
+	CreateServiceAccount(ctx context.Context, ownerID records.UserId, name string, publicKey []byte) (*serviceaccounts.ServiceAccount, string, error)
+	ListServiceAccounts(ctx context.Context, ownerID records.UserId) ([]serviceaccounts.ServiceAccount, error)
+	DeleteServiceAccount(ctx context.Context, ownerID records.UserId, id uuid.UUID) error
+	// RotateCredentials replaces the credentials of the account like CreateServiceAccount sets them,
+	// tokens issued with the old credentials are revoked
+	RotateCredentials(ctx context.Context, ownerID records.UserId, id uuid.UUID, publicKey []byte) (string, error)
+	ListRoles(ctx context.Context, ownerID records.UserId, id uuid.UUID) ([]records.Role, error)
+	GrantRole(ctx context.Context, ownerID records.UserId, id uuid.UUID, roleID records.RoleId) error
+	RevokeRole(ctx context.Context, ownerID records.UserId, id uuid.UUID, roleID records.RoleId) error
+	// VerifyCredentials checks the credentials without issuing a token, for services calling endpoints like token introspection
+	VerifyCredentials(ctx context.Context, credentials ClientCredentials) (*serviceaccounts.ServiceAccount, error)
+	// Authenticate checks the credentials and issues an auth token marked as a service account
+	Authenticate(ctx context.Context, credentials ClientCredentials) (*authentication.TokenResponse, error)
+	// AuthorizationContext returns the context a service account is authorized with, it holds the account's roles
+	AuthorizationContext(ctx context.Context, id uuid.UUID) (*authorization.Context, error)
+}
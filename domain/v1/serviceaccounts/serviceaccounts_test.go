@@ -0,0 +1,290 @@
+package serviceaccounts
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/domain/v1/authentication"
+	authmocks "github.com/ooqls/go-auth/domain/v1/authentication/mocks"
+	"github.com/ooqls/go-auth/domain/v1/authorization"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/roles"
+	rolemocks "github.com/ooqls/go-auth/records/v1/roles/mocks"
+	"github.com/ooqls/go-auth/records/v1/serviceaccounts"
+	"github.com/ooqls/go-auth/records/v1/serviceaccounts/mocks"
+	"github.com/ooqls/go-cache/factory"
+	"github.com/ooqls/go-cache/store"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAudience = "https://auth.test/oauth/token"
+
+// newTestStore returns record mocks that keep the service accounts and their roles like the database would
+func newTestStore(ctrl *gomock.Controller, accountRoles map[uuid.UUID][]records.RoleAgg) (*mocks.MockReader, *mocks.MockWriter) {
+	accounts := map[uuid.UUID]serviceaccounts.ServiceAccount{}
+
+	reader := mocks.NewMockReader(ctrl)
+	reader.EXPECT().GetServiceAccount(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, id uuid.UUID) (*serviceaccounts.ServiceAccount, error) {
+			account, ok := accounts[id]
+			if !ok {
+				return nil, nil
+			}
+			return &account, nil
+		})
+	reader.EXPECT().GetRoleAggs(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, id uuid.UUID) ([]records.RoleAgg, error) {
+			return accountRoles[id], nil
+		})
+
+	writer := mocks.NewMockWriter(ctrl)
+	writer.EXPECT().CreateServiceAccount(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, account serviceaccounts.ServiceAccount) (*serviceaccounts.ServiceAccount, error) {
+			account.ID = uuid.New()
+			accounts[account.ID] = account
+			return &account, nil
+		})
+	writer.EXPECT().UpdateCredentials(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, account serviceaccounts.ServiceAccount) (bool, error) {
+			stored, ok := accounts[account.ID]
+			if !ok || stored.OwnerID != account.OwnerID {
+				return false, nil
+			}
+			stored.SecretHash = account.SecretHash
+			stored.PublicKey = account.PublicKey
+			accounts[account.ID] = stored
+			return true, nil
+		})
+	writer.EXPECT().AddRole(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, id uuid.UUID, roleID records.RoleId) error {
+			accountRoles[id] = append(accountRoles[id], records.RoleAgg{RoleId: roleID})
+			return nil
+		})
+
+	return reader, writer
+}
+
+func newTestService(t *testing.T, ctrl *gomock.Controller, accountRoles map[uuid.UUID][]records.RoleAgg, roleReader roles.Reader) (*ServiceAccountServiceV1, authentication.Authenticator) {
+	reader, writer := newTestStore(ctrl, accountRoles)
+	authenticator := authmocks.NewTestAuthenticator(ctrl, nil, nil)
+	return NewServiceAccountServiceV1(reader, writer, roleReader, authenticator, &factory.MemCacheFactory{}, testAudience), authenticator
+}
+
+func TestServiceAccounts_Secret(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	owner := uuid.New()
+	service, authenticator := newTestService(t, ctrl, map[uuid.UUID][]records.RoleAgg{}, rolemocks.NewMockReader(ctrl))
+
+	_, _, err := service.CreateServiceAccount(ctx, owner, "", nil)
+	assert.ErrorIsf(t, err, ErrInvalidName, "should require a name")
+
+	account, secret, err := service.CreateServiceAccount(ctx, owner, "batch", nil)
+	assert.Nilf(t, err, "should create the service account: %v", err)
+	assert.NotEmptyf(t, secret, "should return a secret")
+	assert.NotContainsf(t, account.SecretHash.String, secret, "should only store the secret hashed")
+
+	_, err = service.Authenticate(ctx, ClientCredentials{ClientID: account.ID.String(), ClientSecret: "wrong"})
+	assert.ErrorIsf(t, err, ErrInvalidCredentials, "should not authenticate with a wrong secret")
+
+	_, err = service.Authenticate(ctx, ClientCredentials{ClientID: uuid.NewString(), ClientSecret: secret})
+	assert.ErrorIsf(t, err, ErrInvalidCredentials, "should not authenticate unknown accounts")
+
+	tokens, err := service.Authenticate(ctx, ClientCredentials{ClientID: account.ID.String(), ClientSecret: secret})
+	assert.Nilf(t, err, "should authenticate with the secret: %v", err)
+	assert.Emptyf(t, tokens.RefreshToken, "should not issue a refresh token")
+	assert.Greaterf(t, tokens.ExpiresIn, 0, "should return the token lifetime")
+
+	claims, err := authenticator.IsAuthenticated(ctx, tokens.AuthToken)
+	assert.Nilf(t, err, "should issue a valid auth token: %v", err)
+	assert.Equalf(t, account.ID, claims.UserID, "the token should belong to the service account")
+	assert.Truef(t, claims.ServiceAccount, "the token should be marked as a service account")
+
+	// issue times have a precision of one second
+	time.Sleep(time.Second)
+
+	newSecret, err := service.RotateCredentials(ctx, owner, account.ID, nil)
+	assert.Nilf(t, err, "should rotate the secret: %v", err)
+
+	_, err = authenticator.IsAuthenticated(ctx, tokens.AuthToken)
+	assert.ErrorIsf(t, err, authentication.ErrTokenRevoked, "should revoke tokens issued with the old secret")
+
+	_, err = service.Authenticate(ctx, ClientCredentials{ClientID: account.ID.String(), ClientSecret: secret})
+	assert.ErrorIsf(t, err, ErrInvalidCredentials, "should not authenticate with the old secret")
+
+	_, err = service.Authenticate(ctx, ClientCredentials{ClientID: account.ID.String(), ClientSecret: newSecret})
+	assert.Nilf(t, err, "should authenticate with the new secret: %v", err)
+
+	_, err = service.RotateCredentials(ctx, uuid.New(), account.ID, nil)
+	assert.ErrorIsf(t, err, ErrServiceAccountNotFound, "only the owner should rotate the secret")
+}
+
+func TestServiceAccounts_Assertion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	service, _ := newTestService(t, ctrl, map[uuid.UUID][]records.RoleAgg{}, rolemocks.NewMockReader(ctrl))
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.Nilf(t, err, "failed to create key: %v", err)
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	assert.Nilf(t, err, "failed to marshal key: %v", err)
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	_, _, err = service.CreateServiceAccount(ctx, uuid.New(), "batch", []byte("not a key"))
+	assert.ErrorIsf(t, err, ErrInvalidPublicKey, "should require a PEM public key")
+
+	account, secret, err := service.CreateServiceAccount(ctx, uuid.New(), "batch", publicPEM)
+	assert.Nilf(t, err, "should create the service account: %v", err)
+	assert.Emptyf(t, secret, "should not return a secret for accounts with a public key")
+
+	assertion := func(claims jwtv5.RegisteredClaims) string {
+		signed, err := jwtv5.NewWithClaims(jwtv5.SigningMethodEdDSA, claims).SignedString(privateKey)
+		assert.Nilf(t, err, "failed to sign assertion: %v", err)
+		return signed
+	}
+	validClaims := func() jwtv5.RegisteredClaims {
+		return jwtv5.RegisteredClaims{
+			Issuer:    account.ID.String(),
+			Subject:   account.ID.String(),
+			Audience:  []string{testAudience},
+			ExpiresAt: jwtv5.NewNumericDate(time.Now().Add(time.Minute)),
+			ID:        uuid.NewString(),
+		}
+	}
+	credentials := func(assertion string) ClientCredentials {
+		return ClientCredentials{
+			ClientID:      account.ID.String(),
+			AssertionType: ClientAssertionTypeJWTBearer,
+			Assertion:     assertion,
+		}
+	}
+
+	valid := assertion(validClaims())
+	_, err = service.Authenticate(ctx, credentials(valid))
+	assert.Nilf(t, err, "should authenticate with a valid assertion: %v", err)
+
+	_, err = service.Authenticate(ctx, credentials(valid))
+	assert.ErrorIsf(t, err, ErrInvalidCredentials, "should not accept an assertion twice")
+
+	wrongAudience := validClaims()
+	wrongAudience.Audience = []string{"https://other.test/token"}
+	_, err = service.Authenticate(ctx, credentials(assertion(wrongAudience)))
+	assert.ErrorIsf(t, err, ErrInvalidCredentials, "should check the audience")
+
+	wrongSubject := validClaims()
+	wrongSubject.Subject = uuid.NewString()
+	_, err = service.Authenticate(ctx, credentials(assertion(wrongSubject)))
+	assert.ErrorIsf(t, err, ErrInvalidCredentials, "should check the subject")
+
+	longLived := validClaims()
+	longLived.ExpiresAt = jwtv5.NewNumericDate(time.Now().Add(time.Hour))
+	_, err = service.Authenticate(ctx, credentials(assertion(longLived)))
+	assert.ErrorIsf(t, err, ErrInvalidCredentials, "should not accept long lived assertions")
+
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.Nilf(t, err, "failed to create key: %v", err)
+	forged, err := jwtv5.NewWithClaims(jwtv5.SigningMethodEdDSA, validClaims()).SignedString(otherKey)
+	assert.Nilf(t, err, "failed to sign assertion: %v", err)
+	_, err = service.Authenticate(ctx, credentials(forged))
+	assert.ErrorIsf(t, err, ErrInvalidCredentials, "should check the signature")
+
+	withType := credentials(assertion(validClaims()))
+	withType.AssertionType = "other"
+	_, err = service.Authenticate(ctx, withType)
+	assert.ErrorIsf(t, err, ErrInvalidCredentials, "should require the jwt-bearer assertion type")
+
+	_, err = service.Authenticate(ctx, ClientCredentials{ClientID: account.ID.String(), ClientSecret: "secret"})
+	assert.ErrorIsf(t, err, ErrInvalidCredentials, "should not authenticate accounts with a public key by secret")
+}
+
+// racingStore writes the entry of a concurrent first use of the assertion right after each Set
+type racingStore struct {
+	store.GenericInterface
+}
+
+func (s racingStore) Set(ctx context.Context, key string, value any) error {
+	err := s.GenericInterface.Set(ctx, key, value)
+	if err != nil {
+		return err
+	}
+
+	return s.GenericInterface.Set(ctx, key, usedAssertion{Claim: uuid.New()})
+}
+
+func TestServiceAccounts_AssertionRace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	service, _ := newTestService(t, ctrl, map[uuid.UUID][]records.RoleAgg{}, rolemocks.NewMockReader(ctrl))
+
+	err := service.useAssertion(ctx, "account:jti")
+	assert.Nilf(t, err, "should accept the first use of an assertion: %v", err)
+
+	err = service.useAssertion(ctx, "account:jti")
+	assert.ErrorIsf(t, err, ErrInvalidCredentials, "should not accept an assertion twice")
+
+	service.usedAssertions = racingStore{service.usedAssertions}
+	err = service.useAssertion(ctx, "account:other")
+	assert.ErrorIsf(t, err, ErrInvalidCredentials, "should not accept a use whose entry was overwritten by a concurrent use")
+
+	err = service.useAssertion(ctx, "account:other")
+	assert.ErrorIsf(t, err, ErrInvalidCredentials, "should not accept an assertion another use claimed")
+}
+
+func TestServiceAccounts_Roles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	owner := uuid.New()
+	heldRole := records.Role{ID: uuid.New(), RoleName: "reader"}
+
+	roleReader := rolemocks.NewMockReader(ctrl)
+	roleReader.EXPECT().GetRolesForUser(gomock.Any(), owner).AnyTimes().Return([]records.Role{heldRole}, nil)
+
+	accountRoles := map[uuid.UUID][]records.RoleAgg{}
+	service, _ := newTestService(t, ctrl, accountRoles, roleReader)
+
+	account, _, err := service.CreateServiceAccount(ctx, owner, "batch", nil)
+	assert.Nilf(t, err, "should create the service account: %v", err)
+
+	err = service.GrantRole(ctx, owner, account.ID, uuid.New())
+	assert.ErrorIsf(t, err, ErrRoleNotHeld, "should not grant roles the owner does not hold")
+
+	err = service.GrantRole(ctx, uuid.New(), account.ID, heldRole.ID)
+	assert.ErrorIsf(t, err, ErrServiceAccountNotFound, "only the owner should grant roles")
+
+	err = service.GrantRole(ctx, owner, account.ID, heldRole.ID)
+	assert.Nilf(t, err, "should grant roles the owner holds: %v", err)
+
+	// the role's permissions are loaded from the role aggregate
+	accountRoles[account.ID][0].Permissions = []records.Permission{{
+		ResourceGroup: authorization.CoreResourceGroup,
+		ResourceKind:  "user",
+		ResourceName:  "*",
+		Actions:       string(authorization.ReadAction),
+	}}
+
+	authCtx, err := service.AuthorizationContext(ctx, account.ID)
+	assert.Nilf(t, err, "should return the authorization context: %v", err)
+	assert.Truef(t, authCtx.IsServiceAccount(), "the context should be marked as a service account")
+	assert.Equalf(t, account.ID, authCtx.GetUserID(), "the context should belong to the service account")
+
+	target := records.User{ID: uuid.New()}
+	checkpoint := authorization.UserHasResourcePermission()
+	assert.Truef(t, checkpoint.IsAuthorized(authCtx, authorization.ReadAction, authorization.NewUserResource(target)), "should hold the permissions of its roles")
+	assert.Falsef(t, checkpoint.IsAuthorized(authCtx, authorization.DeleteAction, authorization.NewUserResource(target)), "should not hold other permissions")
+}
@@ -17,8 +17,9 @@ type RoleAgg struct {
 }
 
 type UserAgg struct {
-	UserId UserId
-	Roles  []RoleAgg
+	UserId        UserId
+	Roles         []RoleAgg
+	EmailVerified bool
 }
 
 //go:generate sqlc generate --file v1/sqlc/sqlc.yaml
@@ -0,0 +1,54 @@
+package accesstokens
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+var _ Reader = &SQLReader{}
+
+//go:generate go run github.com/golang/mock/mockgen -source=accesstokens_reader.go -destination=mocks/mock_accesstokens_reader.go -package=mocks
+type Reader interface {
+	GetAccessTokenByHash(ctx context.Context, tokenHash []byte) (*AccessToken, error)
+	ListAccessTokensForUser(ctx context.Context, userID records.UserId) ([]AccessToken, error)
+	GetPermissionIDs(ctx context.Context, id uuid.UUID) ([]records.PermissionId, error)
+}
+
+type SQLReader struct {
+	q *gen.Queries
+}
+
+func NewSQLReader(db *sqlx.DB) *SQLReader {
+	return &SQLReader{
+		q: gen.New(db),
+	}
+}
+
+// GetAccessTokenByHash returns the token with the given hash, or nil if there is none
+func (r *SQLReader) GetAccessTokenByHash(ctx context.Context, tokenHash []byte) (*AccessToken, error) {
+	token, err := r.q.GetPersonalAccessTokenByHash(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (r *SQLReader) ListAccessTokensForUser(ctx context.Context, userID records.UserId) ([]AccessToken, error) {
+	return r.q.ListPersonalAccessTokensForUser(ctx, userID)
+}
+
+// GetPermissionIDs returns the ids of the permissions the token is scoped to
+func (r *SQLReader) GetPermissionIDs(ctx context.Context, id uuid.UUID) ([]records.PermissionId, error) {
+	return r.q.GetPersonalAccessTokenPermissionIDs(ctx, id)
+}
@@ -0,0 +1,91 @@
+package accesstokens
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+var _ Writer = &SQLWriter{}
+
+//go:generate go run github.com/golang/mock/mockgen -source=accesstokens_writer.go -destination=mocks/mock_accesstokens_writer.go -package=mocks
+type Writer interface {
+	CreateAccessToken(ctx context.Context, token AccessToken, permissionIDs []records.PermissionId) (*AccessToken, error)
+	TouchAccessToken(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+	DeleteAccessToken(ctx context.Context, id uuid.UUID, userID records.UserId) (bool, error)
+}
+
+type SQLWriter struct {
+	db *sqlx.DB
+	q  *gen.Queries
+}
+
+func NewSQLWriter(db *sqlx.DB) *SQLWriter {
+	return &SQLWriter{
+		db: db,
+		q:  gen.New(db),
+	}
+}
+
+// CreateAccessToken stores the token together with the permissions it is scoped to
+func (w *SQLWriter) CreateAccessToken(ctx context.Context, token AccessToken, permissionIDs []records.PermissionId) (*AccessToken, error) {
+	tx, err := w.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := w.q.WithTx(tx.Tx)
+	created, err := q.CreatePersonalAccessToken(ctx, gen.CreatePersonalAccessTokenParams{
+		UserID:    token.UserID,
+		Name:      token.Name,
+		TokenHash: token.TokenHash,
+		ExpiresAt: token.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, permissionID := range permissionIDs {
+		err = q.AddPermissionToPersonalAccessToken(ctx, gen.AddPermissionToPersonalAccessTokenParams{
+			TokenID:      created.ID,
+			PermissionID: permissionID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// TouchAccessToken records when the token was last used
+func (w *SQLWriter) TouchAccessToken(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	return w.q.TouchPersonalAccessToken(ctx, gen.TouchPersonalAccessTokenParams{
+		ID:         id,
+		LastUsedAt: sql.NullTime{Time: usedAt, Valid: true},
+	})
+}
+
+// DeleteAccessToken deletes the token if it belongs to userID, returns false if it does not
+func (w *SQLWriter) DeleteAccessToken(ctx context.Context, id uuid.UUID, userID records.UserId) (bool, error) {
+	rows, err := w.q.DeletePersonalAccessToken(ctx, gen.DeletePersonalAccessTokenParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
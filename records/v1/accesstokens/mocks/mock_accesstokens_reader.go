@@ -0,0 +1,83 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: accesstokens_reader.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	records "github.com/ooqls/go-auth/records"
+	accesstokens "github.com/ooqls/go-auth/records/v1/accesstokens"
+)
+
+// MockReader is a mock of Reader interface.
+type MockReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockReaderMockRecorder
+}
+
+// MockReaderMockRecorder is the mock recorder for MockReader.
+type MockReaderMockRecorder struct {
+	mock *MockReader
+}
+
+// NewMockReader creates a new mock instance.
+func NewMockReader(ctrl *gomock.Controller) *MockReader {
+	mock := &MockReader{ctrl: ctrl}
+	mock.recorder = &MockReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReader) EXPECT() *MockReaderMockRecorder {
+	return m.recorder
+}
+
+// GetAccessTokenByHash mocks base method.
+func (m *MockReader) GetAccessTokenByHash(ctx context.Context, tokenHash []byte) (*accesstokens.AccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccessTokenByHash", ctx, tokenHash)
+	ret0, _ := ret[0].(*accesstokens.AccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccessTokenByHash indicates an expected call of GetAccessTokenByHash.
+func (mr *MockReaderMockRecorder) GetAccessTokenByHash(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccessTokenByHash", reflect.TypeOf((*MockReader)(nil).GetAccessTokenByHash), ctx, tokenHash)
+}
+
+// GetPermissionIDs mocks base method.
+func (m *MockReader) GetPermissionIDs(ctx context.Context, id uuid.UUID) ([]records.PermissionId, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPermissionIDs", ctx, id)
+	ret0, _ := ret[0].([]records.PermissionId)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPermissionIDs indicates an expected call of GetPermissionIDs.
+func (mr *MockReaderMockRecorder) GetPermissionIDs(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPermissionIDs", reflect.TypeOf((*MockReader)(nil).GetPermissionIDs), ctx, id)
+}
+
+// ListAccessTokensForUser mocks base method.
+func (m *MockReader) ListAccessTokensForUser(ctx context.Context, userID records.UserId) ([]accesstokens.AccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccessTokensForUser", ctx, userID)
+	ret0, _ := ret[0].([]accesstokens.AccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccessTokensForUser indicates an expected call of ListAccessTokensForUser.
+func (mr *MockReaderMockRecorder) ListAccessTokensForUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccessTokensForUser", reflect.TypeOf((*MockReader)(nil).ListAccessTokensForUser), ctx, userID)
+}
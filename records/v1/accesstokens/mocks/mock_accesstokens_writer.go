@@ -0,0 +1,83 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: accesstokens_writer.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	records "github.com/ooqls/go-auth/records"
+	accesstokens "github.com/ooqls/go-auth/records/v1/accesstokens"
+)
+
+// MockWriter is a mock of Writer interface.
+type MockWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockWriterMockRecorder
+}
+
+// MockWriterMockRecorder is the mock recorder for MockWriter.
+type MockWriterMockRecorder struct {
+	mock *MockWriter
+}
+
+// NewMockWriter creates a new mock instance.
+func NewMockWriter(ctrl *gomock.Controller) *MockWriter {
+	mock := &MockWriter{ctrl: ctrl}
+	mock.recorder = &MockWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWriter) EXPECT() *MockWriterMockRecorder {
+	return m.recorder
+}
+
+// CreateAccessToken mocks base method.
+func (m *MockWriter) CreateAccessToken(ctx context.Context, token accesstokens.AccessToken, permissionIDs []records.PermissionId) (*accesstokens.AccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccessToken", ctx, token, permissionIDs)
+	ret0, _ := ret[0].(*accesstokens.AccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccessToken indicates an expected call of CreateAccessToken.
+func (mr *MockWriterMockRecorder) CreateAccessToken(ctx, token, permissionIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccessToken", reflect.TypeOf((*MockWriter)(nil).CreateAccessToken), ctx, token, permissionIDs)
+}
+
+// DeleteAccessToken mocks base method.
+func (m *MockWriter) DeleteAccessToken(ctx context.Context, id uuid.UUID, userID records.UserId) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccessToken", ctx, id, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAccessToken indicates an expected call of DeleteAccessToken.
+func (mr *MockWriterMockRecorder) DeleteAccessToken(ctx, id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccessToken", reflect.TypeOf((*MockWriter)(nil).DeleteAccessToken), ctx, id, userID)
+}
+
+// TouchAccessToken mocks base method.
+func (m *MockWriter) TouchAccessToken(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchAccessToken", ctx, id, usedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchAccessToken indicates an expected call of TouchAccessToken.
+func (mr *MockWriterMockRecorder) TouchAccessToken(ctx, id, usedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchAccessToken", reflect.TypeOf((*MockWriter)(nil).TouchAccessToken), ctx, id, usedAt)
+}
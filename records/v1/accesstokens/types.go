@@ -0,0 +1,7 @@
+package accesstokens
+
+import (
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+type AccessToken = gen.Authv1PersonalAccessToken
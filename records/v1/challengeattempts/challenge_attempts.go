@@ -12,14 +12,18 @@ import (
 	"go.uber.org/zap"
 )
 
+var _ Reader = &SQLReader{}
+var _ Writer = &SQLWriter{}
+
 //go:generate go run github.com/golang/mock/mockgen -source=challenge_attempts.go -destination=mocks/mock_challenge_attempts.go -package=mocks -mock_names=ChallengeAttemptReader=MockReader -mock_names=ChallengeAttemptWriter=MockWriter
 type Reader interface {
-	GetChallengeAttempts(ctx context.Context, userID string) ([]ChallengeAttempt, error)
-	GetFailedAttempts(ctx context.Context, userID string, minutes int) ([]ChallengeAttempt, error)
+	GetChallengeAttempts(ctx context.Context, userID uuid.UUID) ([]ChallengeAttempt, error)
+	// GetFailedAttempts returns the failed attempts of the last given minutes made since the user's last successful attempt, most recent first
+	GetFailedAttempts(ctx context.Context, userID uuid.UUID, minutes int) ([]ChallengeAttempt, error)
 }
 
 type Writer interface {
-	CreateChallengeAttempt(ctx context.Context, challengeAttempt ChallengeAttempt) error
+	CreateChallengeAttempt(ctx context.Context, userID uuid.UUID, challengeID uuid.UUID, success bool) error
 }
 
 type SQLReader struct {
@@ -29,10 +33,14 @@ type SQLReader struct {
 }
 
 func NewSQLReader(q *gen.Queries, cache *cache.Cache[[]ChallengeAttempt]) *SQLReader {
-	return &SQLReader{q: q, cache: cache}
+	return &SQLReader{q: q, cache: cache, l: log.NewLogger("challenge_attempts_reader")}
 }
 
 func (r *SQLReader) GetChallengeAttempts(ctx context.Context, userID uuid.UUID) ([]ChallengeAttempt, error) {
+	if r.cache == nil {
+		return r.q.GetChallengeAttempts(ctx, userID)
+	}
+
 	cachedAttempts, err := r.cache.Get(ctx, userID.String())
 	if err == nil {
 		return *cachedAttempts, nil
@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
 	challengeattempts "github.com/ooqls/go-auth/records/v1/challengeattempts"
 )
 
@@ -36,7 +37,7 @@ func (m *MockReader) EXPECT() *MockReaderMockRecorder {
 }
 
 // GetChallengeAttempts mocks base method.
-func (m *MockReader) GetChallengeAttempts(ctx context.Context, userID string) ([]challengeattempts.ChallengeAttempt, error) {
+func (m *MockReader) GetChallengeAttempts(ctx context.Context, userID uuid.UUID) ([]challengeattempts.ChallengeAttempt, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetChallengeAttempts", ctx, userID)
 	ret0, _ := ret[0].([]challengeattempts.ChallengeAttempt)
@@ -51,7 +52,7 @@ func (mr *MockReaderMockRecorder) GetChallengeAttempts(ctx, userID interface{})
 }
 
 // GetFailedAttempts mocks base method.
-func (m *MockReader) GetFailedAttempts(ctx context.Context, userID string, minutes int) ([]challengeattempts.ChallengeAttempt, error) {
+func (m *MockReader) GetFailedAttempts(ctx context.Context, userID uuid.UUID, minutes int) ([]challengeattempts.ChallengeAttempt, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetFailedAttempts", ctx, userID, minutes)
 	ret0, _ := ret[0].([]challengeattempts.ChallengeAttempt)
@@ -89,15 +90,15 @@ func (m *MockWriter) EXPECT() *MockWriterMockRecorder {
 }
 
 // CreateChallengeAttempt mocks base method.
-func (m *MockWriter) CreateChallengeAttempt(ctx context.Context, challengeAttempt challengeattempts.ChallengeAttempt) error {
+func (m *MockWriter) CreateChallengeAttempt(ctx context.Context, userID, challengeID uuid.UUID, success bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateChallengeAttempt", ctx, challengeAttempt)
+	ret := m.ctrl.Call(m, "CreateChallengeAttempt", ctx, userID, challengeID, success)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateChallengeAttempt indicates an expected call of CreateChallengeAttempt.
-func (mr *MockWriterMockRecorder) CreateChallengeAttempt(ctx, challengeAttempt interface{}) *gomock.Call {
+func (mr *MockWriterMockRecorder) CreateChallengeAttempt(ctx, userID, challengeID, success interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChallengeAttempt", reflect.TypeOf((*MockWriter)(nil).CreateChallengeAttempt), ctx, challengeAttempt)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChallengeAttempt", reflect.TypeOf((*MockWriter)(nil).CreateChallengeAttempt), ctx, userID, challengeID, success)
 }
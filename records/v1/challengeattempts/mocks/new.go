@@ -4,6 +4,11 @@ import "github.com/golang/mock/gomock"
 
 func NoopWriter(ctrl *gomock.Controller) *MockWriter {
 	mock := NewMockWriter(ctrl)
-	mock.EXPECT().CreateChallengeAttempt(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+	mock.EXPECT().CreateChallengeAttempt(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
 	return mock
-}
\ No newline at end of file
+}
+func NoFailedAttempts(ctrl *gomock.Controller) *MockReader {
+	mock := NewMockReader(ctrl)
+	mock.EXPECT().GetFailedAttempts(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
+	return mock
+}
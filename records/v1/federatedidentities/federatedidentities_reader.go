@@ -0,0 +1,50 @@
+package federatedidentities
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+var _ Reader = &SQLReader{}
+
+//go:generate go run github.com/golang/mock/mockgen -source=federatedidentities_reader.go -destination=mocks/mock_federatedidentities_reader.go -package=mocks
+type Reader interface {
+	GetIdentity(ctx context.Context, provider string, subject string) (*Identity, error)
+	ListIdentitiesForUser(ctx context.Context, userID records.UserId) ([]Identity, error)
+}
+
+type SQLReader struct {
+	q *gen.Queries
+}
+
+func NewSQLReader(db *sqlx.DB) *SQLReader {
+	return &SQLReader{
+		q: gen.New(db),
+	}
+}
+
+// GetIdentity returns the identity of the provider's subject, or nil if it is not linked to a user
+func (r *SQLReader) GetIdentity(ctx context.Context, provider string, subject string) (*Identity, error) {
+	identity, err := r.q.GetFederatedIdentity(ctx, gen.GetFederatedIdentityParams{
+		Provider: provider,
+		Subject:  subject,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &identity, nil
+}
+
+func (r *SQLReader) ListIdentitiesForUser(ctx context.Context, userID records.UserId) ([]Identity, error) {
+	return r.q.ListFederatedIdentitiesForUser(ctx, userID)
+}
@@ -0,0 +1,50 @@
+package federatedidentities
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+var _ Writer = &SQLWriter{}
+
+//go:generate go run github.com/golang/mock/mockgen -source=federatedidentities_writer.go -destination=mocks/mock_federatedidentities_writer.go -package=mocks
+type Writer interface {
+	CreateIdentity(ctx context.Context, identity Identity) (*Identity, error)
+	TouchIdentity(ctx context.Context, provider string, subject string, email string) error
+}
+
+type SQLWriter struct {
+	q *gen.Queries
+}
+
+func NewSQLWriter(db *sqlx.DB) *SQLWriter {
+	return &SQLWriter{
+		q: gen.New(db),
+	}
+}
+
+// CreateIdentity links the provider's subject to identity.UserID
+func (w *SQLWriter) CreateIdentity(ctx context.Context, identity Identity) (*Identity, error) {
+	created, err := w.q.CreateFederatedIdentity(ctx, gen.CreateFederatedIdentityParams{
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		UserID:   identity.UserID,
+		Email:    identity.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// TouchIdentity records a login of the identity and the email the provider currently has for it
+func (w *SQLWriter) TouchIdentity(ctx context.Context, provider string, subject string, email string) error {
+	return w.q.TouchFederatedIdentity(ctx, gen.TouchFederatedIdentityParams{
+		Provider: provider,
+		Subject:  subject,
+		Email:    email,
+	})
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: federatedidentities_reader.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	records "github.com/ooqls/go-auth/records"
+	federatedidentities "github.com/ooqls/go-auth/records/v1/federatedidentities"
+)
+
+// MockReader is a mock of Reader interface.
+type MockReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockReaderMockRecorder
+}
+
+// MockReaderMockRecorder is the mock recorder for MockReader.
+type MockReaderMockRecorder struct {
+	mock *MockReader
+}
+
+// NewMockReader creates a new mock instance.
+func NewMockReader(ctrl *gomock.Controller) *MockReader {
+	mock := &MockReader{ctrl: ctrl}
+	mock.recorder = &MockReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReader) EXPECT() *MockReaderMockRecorder {
+	return m.recorder
+}
+
+// GetIdentity mocks base method.
+func (m *MockReader) GetIdentity(ctx context.Context, provider, subject string) (*federatedidentities.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdentity", ctx, provider, subject)
+	ret0, _ := ret[0].(*federatedidentities.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdentity indicates an expected call of GetIdentity.
+func (mr *MockReaderMockRecorder) GetIdentity(ctx, provider, subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdentity", reflect.TypeOf((*MockReader)(nil).GetIdentity), ctx, provider, subject)
+}
+
+// ListIdentitiesForUser mocks base method.
+func (m *MockReader) ListIdentitiesForUser(ctx context.Context, userID records.UserId) ([]federatedidentities.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIdentitiesForUser", ctx, userID)
+	ret0, _ := ret[0].([]federatedidentities.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIdentitiesForUser indicates an expected call of ListIdentitiesForUser.
+func (mr *MockReaderMockRecorder) ListIdentitiesForUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIdentitiesForUser", reflect.TypeOf((*MockReader)(nil).ListIdentitiesForUser), ctx, userID)
+}
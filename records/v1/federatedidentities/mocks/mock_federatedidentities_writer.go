@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: federatedidentities_writer.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	federatedidentities "github.com/ooqls/go-auth/records/v1/federatedidentities"
+)
+
+// MockWriter is a mock of Writer interface.
+type MockWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockWriterMockRecorder
+}
+
+// MockWriterMockRecorder is the mock recorder for MockWriter.
+type MockWriterMockRecorder struct {
+	mock *MockWriter
+}
+
+// NewMockWriter creates a new mock instance.
+func NewMockWriter(ctrl *gomock.Controller) *MockWriter {
+	mock := &MockWriter{ctrl: ctrl}
+	mock.recorder = &MockWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWriter) EXPECT() *MockWriterMockRecorder {
+	return m.recorder
+}
+
+// CreateIdentity mocks base method.
+func (m *MockWriter) CreateIdentity(ctx context.Context, identity federatedidentities.Identity) (*federatedidentities.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdentity", ctx, identity)
+	ret0, _ := ret[0].(*federatedidentities.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIdentity indicates an expected call of CreateIdentity.
+func (mr *MockWriterMockRecorder) CreateIdentity(ctx, identity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdentity", reflect.TypeOf((*MockWriter)(nil).CreateIdentity), ctx, identity)
+}
+
+// TouchIdentity mocks base method.
+func (m *MockWriter) TouchIdentity(ctx context.Context, provider, subject, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchIdentity", ctx, provider, subject, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchIdentity indicates an expected call of TouchIdentity.
+func (mr *MockWriterMockRecorder) TouchIdentity(ctx, provider, subject, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchIdentity", reflect.TypeOf((*MockWriter)(nil).TouchIdentity), ctx, provider, subject, email)
+}
@@ -0,0 +1,7 @@
+package federatedidentities
+
+import (
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+type Identity = gen.Authv1FederatedIdentity
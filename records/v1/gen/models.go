@@ -28,6 +28,50 @@ type Authv1ChallengeAttempt struct {
 	CreatedAt   time.Time
 }
 
+type Authv1FederatedIdentity struct {
+	Provider    string
+	Subject     string
+	UserID      uuid.UUID
+	Email       string
+	CreatedAt   time.Time
+	LastLoginAt time.Time
+}
+
+type Authv1Mfa struct {
+	UserID       uuid.UUID
+	Secret       []byte
+	Confirmed    bool
+	LastUsedStep int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type Authv1MfaRecoveryCode struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	CodeHash  string
+	UsedAt    sql.NullTime
+	CreatedAt time.Time
+}
+
+type Authv1OauthClient struct {
+	ID           uuid.UUID
+	OwnerID      uuid.UUID
+	Name         string
+	SecretHash   []byte
+	RedirectUris []string
+	Scopes       []string
+	CreatedAt    time.Time
+}
+
+type Authv1OauthConsent struct {
+	UserID    uuid.UUID
+	ClientID  uuid.UUID
+	Scopes    []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
 type Authv1Permission struct {
 	ID            uuid.UUID
 	ResourceKind  string
@@ -38,6 +82,22 @@ type Authv1Permission struct {
 	Actions       string
 }
 
+type Authv1PersonalAccessToken struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	TokenHash  []byte
+	ExpiresAt  time.Time
+	LastUsedAt sql.NullTime
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type Authv1PersonalAccessTokenPermission struct {
+	TokenID      uuid.UUID
+	PermissionID uuid.UUID
+}
+
 type Authv1Resource struct {
 	ResourceGroup string
 	ResourceKind  string
@@ -64,22 +124,54 @@ type Authv1RolePermission struct {
 	UpdatedAt    time.Time
 }
 
+type Authv1ServiceAccount struct {
+	ID         uuid.UUID
+	OwnerID    uuid.UUID
+	Name       string
+	SecretHash sql.NullString
+	PublicKey  []byte
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type Authv1ServiceAccountRole struct {
+	ServiceAccountID uuid.UUID
+	RoleID           uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
 type Authv1Session struct {
-	ID        uuid.UUID
-	UserID    uuid.UUID
-	Token     string
-	CreatedAt time.Time
-	ExpiresAt time.Time
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Token      string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	UserAgent  string
+	IpAddress  string
+	LastSeenAt time.Time
+}
+
+type Authv1SigningKey struct {
+	ID         string
+	Algorithm  string
+	PrivateKey []byte
+	CreatedAt  time.Time
+	RetiredAt  sql.NullTime
+	ExpiresAt  sql.NullTime
 }
 
 type Authv1User struct {
-	ID        uuid.UUID
-	Username  string
-	Email     string
-	Key       []byte
-	Salt      []byte
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID               uuid.UUID
+	Username         string
+	Email            string
+	Key              []byte
+	Salt             []byte
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Algorithm        string
+	EmailVerified    bool
+	MagicLinkEnabled bool
 }
 
 type Authv1UserRole struct {
@@ -88,3 +180,18 @@ type Authv1UserRole struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
+
+type Authv1WebauthnCredential struct {
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	CredentialID    []byte
+	PublicKey       []byte
+	AttestationType string
+	Aaguid          []byte
+	SignCount       int64
+	Transports      []string
+	BackupEligible  bool
+	BackupState     bool
+	CreatedAt       time.Time
+	LastUsedAt      sql.NullTime
+}
@@ -14,24 +14,40 @@ import (
 
 const createSession = `-- name: CreateSession :one
 INSERT INTO authv1_sessions (
+  id,
   user_id,
   token,
+  user_agent,
+  ip_address,
   expires_at
 ) VALUES (
   $1,
   $2,
-  $3
-) RETURNING id, user_id, token, created_at, expires_at
+  $3,
+  $4,
+  $5,
+  $6
+) RETURNING id, user_id, token, created_at, expires_at, user_agent, ip_address, last_seen_at
 `
 
 type CreateSessionParams struct {
+	ID        uuid.UUID
 	UserID    uuid.UUID
 	Token     string
+	UserAgent string
+	IpAddress string
 	ExpiresAt time.Time
 }
 
 func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Authv1Session, error) {
-	row := q.db.QueryRowContext(ctx, createSession, arg.UserID, arg.Token, arg.ExpiresAt)
+	row := q.db.QueryRowContext(ctx, createSession,
+		arg.ID,
+		arg.UserID,
+		arg.Token,
+		arg.UserAgent,
+		arg.IpAddress,
+		arg.ExpiresAt,
+	)
 	var i Authv1Session
 	err := row.Scan(
 		&i.ID,
@@ -39,10 +55,45 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (A
 		&i.Token,
 		&i.CreatedAt,
 		&i.ExpiresAt,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.LastSeenAt,
 	)
 	return i, err
 }
 
+const deleteOtherSessionsForUser = `-- name: DeleteOtherSessionsForUser :many
+DELETE FROM authv1_sessions WHERE user_id = $1 AND id <> $2 RETURNING id
+`
+
+type DeleteOtherSessionsForUserParams struct {
+	UserID uuid.UUID
+	ID     uuid.UUID
+}
+
+func (q *Queries) DeleteOtherSessionsForUser(ctx context.Context, arg DeleteOtherSessionsForUserParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, deleteOtherSessionsForUser, arg.UserID, arg.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const deleteSession = `-- name: DeleteSession :exec
 DELETE FROM authv1_sessions WHERE id = $1
 `
@@ -52,8 +103,25 @@ func (q *Queries) DeleteSession(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const deleteSessionForUser = `-- name: DeleteSessionForUser :execrows
+DELETE FROM authv1_sessions WHERE id = $1 AND user_id = $2
+`
+
+type DeleteSessionForUserParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) DeleteSessionForUser(ctx context.Context, arg DeleteSessionForUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteSessionForUser, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getSession = `-- name: GetSession :one
-SELECT id, user_id, token, created_at, expires_at FROM authv1_sessions WHERE id = $1
+SELECT id, user_id, token, created_at, expires_at, user_agent, ip_address, last_seen_at FROM authv1_sessions WHERE id = $1
 `
 
 func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (Authv1Session, error) {
@@ -65,12 +133,15 @@ func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (Authv1Session,
 		&i.Token,
 		&i.CreatedAt,
 		&i.ExpiresAt,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.LastSeenAt,
 	)
 	return i, err
 }
 
 const listSessions = `-- name: ListSessions :many
-SELECT id, user_id, token, created_at, expires_at FROM authv1_sessions ORDER BY created_at LIMIT $1 OFFSET $2
+SELECT id, user_id, token, created_at, expires_at, user_agent, ip_address, last_seen_at FROM authv1_sessions ORDER BY created_at LIMIT $1 OFFSET $2
 `
 
 type ListSessionsParams struct {
@@ -93,6 +164,45 @@ func (q *Queries) ListSessions(ctx context.Context, arg ListSessionsParams) ([]A
 			&i.Token,
 			&i.CreatedAt,
 			&i.ExpiresAt,
+			&i.UserAgent,
+			&i.IpAddress,
+			&i.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSessionsForUser = `-- name: ListSessionsForUser :many
+SELECT id, user_id, token, created_at, expires_at, user_agent, ip_address, last_seen_at FROM authv1_sessions WHERE user_id = $1 AND expires_at > now () ORDER BY last_seen_at DESC
+`
+
+func (q *Queries) ListSessionsForUser(ctx context.Context, userID uuid.UUID) ([]Authv1Session, error) {
+	rows, err := q.db.QueryContext(ctx, listSessionsForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Authv1Session
+	for rows.Next() {
+		var i Authv1Session
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Token,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.UserAgent,
+			&i.IpAddress,
+			&i.LastSeenAt,
 		); err != nil {
 			return nil, err
 		}
@@ -107,6 +217,25 @@ func (q *Queries) ListSessions(ctx context.Context, arg ListSessionsParams) ([]A
 	return items, nil
 }
 
+const touchSession = `-- name: TouchSession :exec
+UPDATE authv1_sessions SET
+  token = $2,
+  expires_at = $3,
+  last_seen_at = now ()
+WHERE id = $1
+`
+
+type TouchSessionParams struct {
+	ID        uuid.UUID
+	Token     string
+	ExpiresAt time.Time
+}
+
+func (q *Queries) TouchSession(ctx context.Context, arg TouchSessionParams) error {
+	_, err := q.db.ExecContext(ctx, touchSession, arg.ID, arg.Token, arg.ExpiresAt)
+	return err
+}
+
 const updateSession = `-- name: UpdateSession :exec
 UPDATE authv1_sessions SET
   user_id = $1,
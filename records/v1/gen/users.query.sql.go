@@ -17,22 +17,25 @@ INSERT INTO authv1_users (
   username,
   email,
   salt,
-  key
+  key,
+  algorithm
 ) VALUES (
   $1,
   $2,
   $3,
   $4,
-  $5
-) RETURNING id, username, email, key, salt, created_at, updated_at
+  $5,
+  $6
+) RETURNING id, username, email, key, salt, created_at, updated_at, algorithm, email_verified, magic_link_enabled
 `
 
 type CreateUserParams struct {
-	ID       uuid.UUID
-	Username string
-	Email    string
-	Salt     []byte
-	Key      []byte
+	ID        uuid.UUID
+	Username  string
+	Email     string
+	Salt      []byte
+	Key       []byte
+	Algorithm string
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (Authv1User, error) {
@@ -42,6 +45,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (Authv1U
 		arg.Email,
 		arg.Salt,
 		arg.Key,
+		arg.Algorithm,
 	)
 	var i Authv1User
 	err := row.Scan(
@@ -52,6 +56,9 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (Authv1U
 		&i.Salt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Algorithm,
+		&i.EmailVerified,
+		&i.MagicLinkEnabled,
 	)
 	return i, err
 }
@@ -66,7 +73,7 @@ func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) error {
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, username, email, key, salt, created_at, updated_at FROM authv1_users WHERE id = $1
+SELECT id, username, email, key, salt, created_at, updated_at, algorithm, email_verified, magic_link_enabled FROM authv1_users WHERE id = $1
 `
 
 func (q *Queries) GetUser(ctx context.Context, id uuid.UUID) (Authv1User, error) {
@@ -80,12 +87,15 @@ func (q *Queries) GetUser(ctx context.Context, id uuid.UUID) (Authv1User, error)
 		&i.Salt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Algorithm,
+		&i.EmailVerified,
+		&i.MagicLinkEnabled,
 	)
 	return i, err
 }
 
 const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, username, email, key, salt, created_at, updated_at FROM authv1_users WHERE username = $1
+SELECT id, username, email, key, salt, created_at, updated_at, algorithm, email_verified, magic_link_enabled FROM authv1_users WHERE username = $1
 `
 
 func (q *Queries) GetUserByUsername(ctx context.Context, username string) (Authv1User, error) {
@@ -99,12 +109,53 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (Authv
 		&i.Salt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Algorithm,
+		&i.EmailVerified,
+		&i.MagicLinkEnabled,
 	)
 	return i, err
 }
 
+const getUsersByEmail = `-- name: GetUsersByEmail :many
+SELECT id, username, email, key, salt, created_at, updated_at, algorithm, email_verified, magic_link_enabled FROM authv1_users WHERE email = $1 ORDER BY created_at
+`
+
+func (q *Queries) GetUsersByEmail(ctx context.Context, email string) ([]Authv1User, error) {
+	rows, err := q.db.QueryContext(ctx, getUsersByEmail, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Authv1User
+	for rows.Next() {
+		var i Authv1User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Email,
+			&i.Key,
+			&i.Salt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Algorithm,
+			&i.EmailVerified,
+			&i.MagicLinkEnabled,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUsers = `-- name: ListUsers :many
-SELECT id, username, email, key, salt, created_at, updated_at FROM authv1_users ORDER BY username LIMIT $1 OFFSET $2
+SELECT id, username, email, key, salt, created_at, updated_at, algorithm, email_verified, magic_link_enabled FROM authv1_users ORDER BY username LIMIT $1 OFFSET $2
 `
 
 type ListUsersParams struct {
@@ -129,6 +180,9 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]Authv1U
 			&i.Salt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Algorithm,
+			&i.EmailVerified,
+			&i.MagicLinkEnabled,
 		); err != nil {
 			return nil, err
 		}
@@ -144,7 +198,7 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]Authv1U
 }
 
 const searchUsers = `-- name: SearchUsers :many
-SELECT id, username, email, key, salt, created_at, updated_at FROM authv1_users WHERE username ILIKE $1 ORDER BY username LIMIT $2 OFFSET $3
+SELECT id, username, email, key, salt, created_at, updated_at, algorithm, email_verified, magic_link_enabled FROM authv1_users WHERE username ILIKE $1 ORDER BY username LIMIT $2 OFFSET $3
 `
 
 type SearchUsersParams struct {
@@ -170,6 +224,9 @@ func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]Aut
 			&i.Salt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Algorithm,
+			&i.EmailVerified,
+			&i.MagicLinkEnabled,
 		); err != nil {
 			return nil, err
 		}
@@ -184,28 +241,93 @@ func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]Aut
 	return items, nil
 }
 
+const setUserMagicLink = `-- name: SetUserMagicLink :execrows
+UPDATE authv1_users SET
+  magic_link_enabled = $2,
+  updated_at = now()
+WHERE id = $1
+`
+
+type SetUserMagicLinkParams struct {
+	ID               uuid.UUID
+	MagicLinkEnabled bool
+}
+
+func (q *Queries) SetUserMagicLink(ctx context.Context, arg SetUserMagicLinkParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setUserMagicLink, arg.ID, arg.MagicLinkEnabled)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateUser = `-- name: UpdateUser :exec
 UPDATE authv1_users SET
   username = $1,
   email = $2,
-  key = $3,
+  email_verified = email_verified AND email = $2,
   updated_at = now()
-WHERE id = $4
+WHERE id = $3
 `
 
 type UpdateUserParams struct {
 	Username string
 	Email    string
-	Key      []byte
 	ID       uuid.UUID
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) error {
-	_, err := q.db.ExecContext(ctx, updateUser,
-		arg.Username,
-		arg.Email,
+	_, err := q.db.ExecContext(ctx, updateUser, arg.Username, arg.Email, arg.ID)
+	return err
+}
+
+const updateUserCredentials = `-- name: UpdateUserCredentials :execrows
+UPDATE authv1_users SET
+  key = $1,
+  salt = $2,
+  algorithm = $3,
+  updated_at = now()
+WHERE id = $4 AND key = $5
+`
+
+type UpdateUserCredentialsParams struct {
+	Key       []byte
+	Salt      []byte
+	Algorithm string
+	ID        uuid.UUID
+	OldKey    []byte
+}
+
+func (q *Queries) UpdateUserCredentials(ctx context.Context, arg UpdateUserCredentialsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateUserCredentials,
 		arg.Key,
+		arg.Salt,
+		arg.Algorithm,
 		arg.ID,
+		arg.OldKey,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const verifyUserEmail = `-- name: VerifyUserEmail :execrows
+UPDATE authv1_users SET
+  email_verified = TRUE,
+  updated_at = now()
+WHERE id = $1 AND email = $2
+`
+
+type VerifyUserEmailParams struct {
+	ID    uuid.UUID
+	Email string
+}
+
+func (q *Queries) VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, verifyUserEmail, arg.ID, arg.Email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
@@ -0,0 +1,47 @@
+package mfa
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+var _ Reader = &SQLReader{}
+
+//go:generate go run github.com/golang/mock/mockgen -source=mfa_reader.go -destination=mocks/mock_mfa_reader.go -package=mocks
+type Reader interface {
+	GetMFA(ctx context.Context, userID records.UserId) (*MFA, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID records.UserId) (int64, error)
+}
+
+type SQLReader struct {
+	q *gen.Queries
+}
+
+func NewSQLReader(db *sqlx.DB) *SQLReader {
+	return &SQLReader{
+		q: gen.New(db),
+	}
+}
+
+// GetMFA returns the user's MFA enrollment, or nil if the user never enrolled
+func (r *SQLReader) GetMFA(ctx context.Context, userID records.UserId) (*MFA, error) {
+	mfa, err := r.q.GetMFA(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &mfa, nil
+}
+
+func (r *SQLReader) CountUnusedRecoveryCodes(ctx context.Context, userID records.UserId) (int64, error) {
+	return r.q.CountUnusedRecoveryCodes(ctx, userID)
+}
@@ -0,0 +1,102 @@
+package mfa
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+var _ Writer = &SQLWriter{}
+
+//go:generate go run github.com/golang/mock/mockgen -source=mfa_writer.go -destination=mocks/mock_mfa_writer.go -package=mocks
+type Writer interface {
+	EnrollMFA(ctx context.Context, userID records.UserId, secret []byte) (bool, error)
+	ConfirmMFA(ctx context.Context, userID records.UserId, recoveryCodeHashes []string) error
+	UseStep(ctx context.Context, userID records.UserId, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userID records.UserId, codeHash string) (bool, error)
+}
+
+type SQLWriter struct {
+	db *sqlx.DB
+	q  *gen.Queries
+}
+
+func NewSQLWriter(db *sqlx.DB) *SQLWriter {
+	return &SQLWriter{
+		db: db,
+		q:  gen.New(db),
+	}
+}
+
+// EnrollMFA stores a new unconfirmed secret for the user, returns false if the user already has confirmed MFA
+func (w *SQLWriter) EnrollMFA(ctx context.Context, userID records.UserId, secret []byte) (bool, error) {
+	rows, err := w.q.EnrollMFA(ctx, gen.EnrollMFAParams{
+		UserID: userID,
+		Secret: secret,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// ConfirmMFA enables MFA for the user and replaces their recovery codes
+func (w *SQLWriter) ConfirmMFA(ctx context.Context, userID records.UserId, recoveryCodeHashes []string) error {
+	tx, err := w.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := w.q.WithTx(tx.Tx)
+	err = q.ConfirmMFA(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	err = q.DeleteRecoveryCodes(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, codeHash := range recoveryCodeHashes {
+		err = q.CreateRecoveryCode(ctx, gen.CreateRecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: codeHash,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UseStep marks the time step of a TOTP code as used, returns false if the step or a later one was already used
+func (w *SQLWriter) UseStep(ctx context.Context, userID records.UserId, step int64) (bool, error) {
+	rows, err := w.q.UseMFAStep(ctx, gen.UseMFAStepParams{
+		UserID:       userID,
+		LastUsedStep: step,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// UseRecoveryCode marks the recovery code as used, returns false if there is no such unused code
+func (w *SQLWriter) UseRecoveryCode(ctx context.Context, userID records.UserId, codeHash string) (bool, error) {
+	rows, err := w.q.UseRecoveryCode(ctx, gen.UseRecoveryCodeParams{
+		UserID:   userID,
+		CodeHash: codeHash,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
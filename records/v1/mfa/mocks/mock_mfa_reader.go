@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: mfa_reader.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	records "github.com/ooqls/go-auth/records"
+	mfa "github.com/ooqls/go-auth/records/v1/mfa"
+)
+
+// MockReader is a mock of Reader interface.
+type MockReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockReaderMockRecorder
+}
+
+// MockReaderMockRecorder is the mock recorder for MockReader.
+type MockReaderMockRecorder struct {
+	mock *MockReader
+}
+
+// NewMockReader creates a new mock instance.
+func NewMockReader(ctrl *gomock.Controller) *MockReader {
+	mock := &MockReader{ctrl: ctrl}
+	mock.recorder = &MockReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReader) EXPECT() *MockReaderMockRecorder {
+	return m.recorder
+}
+
+// CountUnusedRecoveryCodes mocks base method.
+func (m *MockReader) CountUnusedRecoveryCodes(ctx context.Context, userID records.UserId) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnusedRecoveryCodes", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnusedRecoveryCodes indicates an expected call of CountUnusedRecoveryCodes.
+func (mr *MockReaderMockRecorder) CountUnusedRecoveryCodes(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnusedRecoveryCodes", reflect.TypeOf((*MockReader)(nil).CountUnusedRecoveryCodes), ctx, userID)
+}
+
+// GetMFA mocks base method.
+func (m *MockReader) GetMFA(ctx context.Context, userID records.UserId) (*mfa.MFA, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMFA", ctx, userID)
+	ret0, _ := ret[0].(*mfa.MFA)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMFA indicates an expected call of GetMFA.
+func (mr *MockReaderMockRecorder) GetMFA(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMFA", reflect.TypeOf((*MockReader)(nil).GetMFA), ctx, userID)
+}
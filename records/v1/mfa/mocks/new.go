@@ -0,0 +1,12 @@
+package mocks
+
+import (
+	"github.com/golang/mock/gomock"
+)
+
+// NotEnrolled returns a reader for users that never enrolled in MFA
+func NotEnrolled(ctrl *gomock.Controller) *MockReader {
+	mock := NewMockReader(ctrl)
+	mock.EXPECT().GetMFA(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
+	return mock
+}
@@ -0,0 +1,6 @@
+package mfa
+
+import "github.com/ooqls/go-auth/records/v1/gen"
+
+type MFA = gen.Authv1Mfa
+type RecoveryCode = gen.Authv1MfaRecoveryCode
@@ -0,0 +1,98 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: oauthclients_reader.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	records "github.com/ooqls/go-auth/records"
+	oauthclients "github.com/ooqls/go-auth/records/v1/oauthclients"
+)
+
+// MockReader is a mock of Reader interface.
+type MockReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockReaderMockRecorder
+}
+
+// MockReaderMockRecorder is the mock recorder for MockReader.
+type MockReaderMockRecorder struct {
+	mock *MockReader
+}
+
+// NewMockReader creates a new mock instance.
+func NewMockReader(ctrl *gomock.Controller) *MockReader {
+	mock := &MockReader{ctrl: ctrl}
+	mock.recorder = &MockReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReader) EXPECT() *MockReaderMockRecorder {
+	return m.recorder
+}
+
+// GetClient mocks base method.
+func (m *MockReader) GetClient(ctx context.Context, id uuid.UUID) (*oauthclients.Client, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClient", ctx, id)
+	ret0, _ := ret[0].(*oauthclients.Client)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClient indicates an expected call of GetClient.
+func (mr *MockReaderMockRecorder) GetClient(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClient", reflect.TypeOf((*MockReader)(nil).GetClient), ctx, id)
+}
+
+// GetConsent mocks base method.
+func (m *MockReader) GetConsent(ctx context.Context, userID records.UserId, clientID uuid.UUID) (*oauthclients.Consent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConsent", ctx, userID, clientID)
+	ret0, _ := ret[0].(*oauthclients.Consent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConsent indicates an expected call of GetConsent.
+func (mr *MockReaderMockRecorder) GetConsent(ctx, userID, clientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConsent", reflect.TypeOf((*MockReader)(nil).GetConsent), ctx, userID, clientID)
+}
+
+// ListClientsForOwner mocks base method.
+func (m *MockReader) ListClientsForOwner(ctx context.Context, ownerID records.UserId) ([]oauthclients.Client, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClientsForOwner", ctx, ownerID)
+	ret0, _ := ret[0].([]oauthclients.Client)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClientsForOwner indicates an expected call of ListClientsForOwner.
+func (mr *MockReaderMockRecorder) ListClientsForOwner(ctx, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClientsForOwner", reflect.TypeOf((*MockReader)(nil).ListClientsForOwner), ctx, ownerID)
+}
+
+// ListConsentsForUser mocks base method.
+func (m *MockReader) ListConsentsForUser(ctx context.Context, userID records.UserId) ([]oauthclients.Consent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListConsentsForUser", ctx, userID)
+	ret0, _ := ret[0].([]oauthclients.Consent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListConsentsForUser indicates an expected call of ListConsentsForUser.
+func (mr *MockReaderMockRecorder) ListConsentsForUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConsentsForUser", reflect.TypeOf((*MockReader)(nil).ListConsentsForUser), ctx, userID)
+}
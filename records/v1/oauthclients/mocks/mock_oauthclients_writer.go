@@ -0,0 +1,98 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: oauthclients_writer.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	records "github.com/ooqls/go-auth/records"
+	oauthclients "github.com/ooqls/go-auth/records/v1/oauthclients"
+)
+
+// MockWriter is a mock of Writer interface.
+type MockWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockWriterMockRecorder
+}
+
+// MockWriterMockRecorder is the mock recorder for MockWriter.
+type MockWriterMockRecorder struct {
+	mock *MockWriter
+}
+
+// NewMockWriter creates a new mock instance.
+func NewMockWriter(ctrl *gomock.Controller) *MockWriter {
+	mock := &MockWriter{ctrl: ctrl}
+	mock.recorder = &MockWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWriter) EXPECT() *MockWriterMockRecorder {
+	return m.recorder
+}
+
+// CreateClient mocks base method.
+func (m *MockWriter) CreateClient(ctx context.Context, client oauthclients.Client) (*oauthclients.Client, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClient", ctx, client)
+	ret0, _ := ret[0].(*oauthclients.Client)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateClient indicates an expected call of CreateClient.
+func (mr *MockWriterMockRecorder) CreateClient(ctx, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClient", reflect.TypeOf((*MockWriter)(nil).CreateClient), ctx, client)
+}
+
+// DeleteClient mocks base method.
+func (m *MockWriter) DeleteClient(ctx context.Context, id uuid.UUID, ownerID records.UserId) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteClient", ctx, id, ownerID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteClient indicates an expected call of DeleteClient.
+func (mr *MockWriterMockRecorder) DeleteClient(ctx, id, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClient", reflect.TypeOf((*MockWriter)(nil).DeleteClient), ctx, id, ownerID)
+}
+
+// DeleteConsent mocks base method.
+func (m *MockWriter) DeleteConsent(ctx context.Context, userID records.UserId, clientID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConsent", ctx, userID, clientID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteConsent indicates an expected call of DeleteConsent.
+func (mr *MockWriterMockRecorder) DeleteConsent(ctx, userID, clientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConsent", reflect.TypeOf((*MockWriter)(nil).DeleteConsent), ctx, userID, clientID)
+}
+
+// UpsertConsent mocks base method.
+func (m *MockWriter) UpsertConsent(ctx context.Context, userID records.UserId, clientID uuid.UUID, scopes []string) (*oauthclients.Consent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertConsent", ctx, userID, clientID, scopes)
+	ret0, _ := ret[0].(*oauthclients.Consent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertConsent indicates an expected call of UpsertConsent.
+func (mr *MockWriterMockRecorder) UpsertConsent(ctx, userID, clientID, scopes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertConsent", reflect.TypeOf((*MockWriter)(nil).UpsertConsent), ctx, userID, clientID, scopes)
+}
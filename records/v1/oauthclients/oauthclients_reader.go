@@ -0,0 +1,71 @@
+package oauthclients
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+var _ Reader = &SQLReader{}
+
+//go:generate go run github.com/golang/mock/mockgen -source=oauthclients_reader.go -destination=mocks/mock_oauthclients_reader.go -package=mocks
+type Reader interface {
+	GetClient(ctx context.Context, id uuid.UUID) (*Client, error)
+	ListClientsForOwner(ctx context.Context, ownerID records.UserId) ([]Client, error)
+	GetConsent(ctx context.Context, userID records.UserId, clientID uuid.UUID) (*Consent, error)
+	ListConsentsForUser(ctx context.Context, userID records.UserId) ([]Consent, error)
+}
+
+type SQLReader struct {
+	q *gen.Queries
+}
+
+func NewSQLReader(db *sqlx.DB) *SQLReader {
+	return &SQLReader{
+		q: gen.New(db),
+	}
+}
+
+// GetClient returns the client, or nil if there is none
+func (r *SQLReader) GetClient(ctx context.Context, id uuid.UUID) (*Client, error) {
+	client, err := r.q.GetOAuthClient(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &client, nil
+}
+
+func (r *SQLReader) ListClientsForOwner(ctx context.Context, ownerID records.UserId) ([]Client, error) {
+	return r.q.ListOAuthClientsForOwner(ctx, ownerID)
+}
+
+// GetConsent returns the scopes the user granted the client, or nil if the user never consented
+func (r *SQLReader) GetConsent(ctx context.Context, userID records.UserId, clientID uuid.UUID) (*Consent, error) {
+	consent, err := r.q.GetOAuthConsent(ctx, gen.GetOAuthConsentParams{
+		UserID:   userID,
+		ClientID: clientID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &consent, nil
+}
+
+func (r *SQLReader) ListConsentsForUser(ctx context.Context, userID records.UserId) ([]Consent, error) {
+	return r.q.ListOAuthConsentsForUser(ctx, userID)
+}
@@ -0,0 +1,85 @@
+package oauthclients
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+var _ Writer = &SQLWriter{}
+
+//go:generate go run github.com/golang/mock/mockgen -source=oauthclients_writer.go -destination=mocks/mock_oauthclients_writer.go -package=mocks
+type Writer interface {
+	CreateClient(ctx context.Context, client Client) (*Client, error)
+	DeleteClient(ctx context.Context, id uuid.UUID, ownerID records.UserId) (bool, error)
+	UpsertConsent(ctx context.Context, userID records.UserId, clientID uuid.UUID, scopes []string) (*Consent, error)
+	DeleteConsent(ctx context.Context, userID records.UserId, clientID uuid.UUID) (bool, error)
+}
+
+type SQLWriter struct {
+	q *gen.Queries
+}
+
+func NewSQLWriter(db *sqlx.DB) *SQLWriter {
+	return &SQLWriter{
+		q: gen.New(db),
+	}
+}
+
+func (w *SQLWriter) CreateClient(ctx context.Context, client Client) (*Client, error) {
+	created, err := w.q.CreateOAuthClient(ctx, gen.CreateOAuthClientParams{
+		OwnerID:      client.OwnerID,
+		Name:         client.Name,
+		SecretHash:   client.SecretHash,
+		RedirectUris: client.RedirectUris,
+		Scopes:       client.Scopes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// DeleteClient deletes the client if it is owned by ownerID, returns false if it is not
+func (w *SQLWriter) DeleteClient(ctx context.Context, id uuid.UUID, ownerID records.UserId) (bool, error) {
+	rows, err := w.q.DeleteOAuthClient(ctx, gen.DeleteOAuthClientParams{
+		ID:      id,
+		OwnerID: ownerID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// UpsertConsent records the scopes the user granted the client, replacing what was granted before
+func (w *SQLWriter) UpsertConsent(ctx context.Context, userID records.UserId, clientID uuid.UUID, scopes []string) (*Consent, error) {
+	consent, err := w.q.UpsertOAuthConsent(ctx, gen.UpsertOAuthConsentParams{
+		UserID:   userID,
+		ClientID: clientID,
+		Scopes:   scopes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &consent, nil
+}
+
+// DeleteConsent revokes the user's consent for the client, returns false if there was none
+func (w *SQLWriter) DeleteConsent(ctx context.Context, userID records.UserId, clientID uuid.UUID) (bool, error) {
+	rows, err := w.q.DeleteOAuthConsent(ctx, gen.DeleteOAuthConsentParams{
+		UserID:   userID,
+		ClientID: clientID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
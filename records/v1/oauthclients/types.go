@@ -0,0 +1,8 @@
+package oauthclients
+
+import (
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+type Client = gen.Authv1OauthClient
+type Consent = gen.Authv1OauthConsent
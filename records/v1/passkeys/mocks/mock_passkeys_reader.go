@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: passkeys_reader.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	records "github.com/ooqls/go-auth/records"
+	passkeys "github.com/ooqls/go-auth/records/v1/passkeys"
+)
+
+// MockReader is a mock of Reader interface.
+type MockReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockReaderMockRecorder
+}
+
+// MockReaderMockRecorder is the mock recorder for MockReader.
+type MockReaderMockRecorder struct {
+	mock *MockReader
+}
+
+// NewMockReader creates a new mock instance.
+func NewMockReader(ctrl *gomock.Controller) *MockReader {
+	mock := &MockReader{ctrl: ctrl}
+	mock.recorder = &MockReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReader) EXPECT() *MockReaderMockRecorder {
+	return m.recorder
+}
+
+// GetCredential mocks base method.
+func (m *MockReader) GetCredential(ctx context.Context, credentialID []byte) (*passkeys.Credential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCredential", ctx, credentialID)
+	ret0, _ := ret[0].(*passkeys.Credential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCredential indicates an expected call of GetCredential.
+func (mr *MockReaderMockRecorder) GetCredential(ctx, credentialID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCredential", reflect.TypeOf((*MockReader)(nil).GetCredential), ctx, credentialID)
+}
+
+// ListCredentialsForUser mocks base method.
+func (m *MockReader) ListCredentialsForUser(ctx context.Context, userID records.UserId) ([]passkeys.Credential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCredentialsForUser", ctx, userID)
+	ret0, _ := ret[0].([]passkeys.Credential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCredentialsForUser indicates an expected call of ListCredentialsForUser.
+func (mr *MockReaderMockRecorder) ListCredentialsForUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCredentialsForUser", reflect.TypeOf((*MockReader)(nil).ListCredentialsForUser), ctx, userID)
+}
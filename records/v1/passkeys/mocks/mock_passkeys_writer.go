@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: passkeys_writer.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	passkeys "github.com/ooqls/go-auth/records/v1/passkeys"
+)
+
+// MockWriter is a mock of Writer interface.
+type MockWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockWriterMockRecorder
+}
+
+// MockWriterMockRecorder is the mock recorder for MockWriter.
+type MockWriterMockRecorder struct {
+	mock *MockWriter
+}
+
+// NewMockWriter creates a new mock instance.
+func NewMockWriter(ctrl *gomock.Controller) *MockWriter {
+	mock := &MockWriter{ctrl: ctrl}
+	mock.recorder = &MockWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWriter) EXPECT() *MockWriterMockRecorder {
+	return m.recorder
+}
+
+// CreateCredential mocks base method.
+func (m *MockWriter) CreateCredential(ctx context.Context, cred passkeys.Credential) (*passkeys.Credential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCredential", ctx, cred)
+	ret0, _ := ret[0].(*passkeys.Credential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCredential indicates an expected call of CreateCredential.
+func (mr *MockWriterMockRecorder) CreateCredential(ctx, cred interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCredential", reflect.TypeOf((*MockWriter)(nil).CreateCredential), ctx, cred)
+}
+
+// UpdateSignCount mocks base method.
+func (m *MockWriter) UpdateSignCount(ctx context.Context, credentialID []byte, signCount uint32, backupState bool) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSignCount", ctx, credentialID, signCount, backupState)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSignCount indicates an expected call of UpdateSignCount.
+func (mr *MockWriterMockRecorder) UpdateSignCount(ctx, credentialID, signCount, backupState interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSignCount", reflect.TypeOf((*MockWriter)(nil).UpdateSignCount), ctx, credentialID, signCount, backupState)
+}
@@ -0,0 +1,47 @@
+package passkeys
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+var _ Reader = &SQLReader{}
+
+//go:generate go run github.com/golang/mock/mockgen -source=passkeys_reader.go -destination=mocks/mock_passkeys_reader.go -package=mocks
+type Reader interface {
+	GetCredential(ctx context.Context, credentialID []byte) (*Credential, error)
+	ListCredentialsForUser(ctx context.Context, userID records.UserId) ([]Credential, error)
+}
+
+type SQLReader struct {
+	q *gen.Queries
+}
+
+func NewSQLReader(db *sqlx.DB) *SQLReader {
+	return &SQLReader{
+		q: gen.New(db),
+	}
+}
+
+// GetCredential returns the credential with the authenticator assigned credential id, or nil if there is none
+func (r *SQLReader) GetCredential(ctx context.Context, credentialID []byte) (*Credential, error) {
+	cred, err := r.q.GetWebAuthnCredential(ctx, credentialID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &cred, nil
+}
+
+func (r *SQLReader) ListCredentialsForUser(ctx context.Context, userID records.UserId) ([]Credential, error) {
+	return r.q.ListWebAuthnCredentialsForUser(ctx, userID)
+}
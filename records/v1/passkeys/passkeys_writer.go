@@ -0,0 +1,60 @@
+package passkeys
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+var _ Writer = &SQLWriter{}
+
+//go:generate go run github.com/golang/mock/mockgen -source=passkeys_writer.go -destination=mocks/mock_passkeys_writer.go -package=mocks
+type Writer interface {
+	CreateCredential(ctx context.Context, cred Credential) (*Credential, error)
+	UpdateSignCount(ctx context.Context, credentialID []byte, signCount uint32, backupState bool) (bool, error)
+}
+
+type SQLWriter struct {
+	q *gen.Queries
+}
+
+func NewSQLWriter(db *sqlx.DB) *SQLWriter {
+	return &SQLWriter{
+		q: gen.New(db),
+	}
+}
+
+func (w *SQLWriter) CreateCredential(ctx context.Context, cred Credential) (*Credential, error) {
+	created, err := w.q.CreateWebAuthnCredential(ctx, gen.CreateWebAuthnCredentialParams{
+		UserID:          cred.UserID,
+		CredentialID:    cred.CredentialID,
+		PublicKey:       cred.PublicKey,
+		AttestationType: cred.AttestationType,
+		Aaguid:          cred.Aaguid,
+		SignCount:       cred.SignCount,
+		Transports:      cred.Transports,
+		BackupEligible:  cred.BackupEligible,
+		BackupState:     cred.BackupState,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// UpdateSignCount stores the sign count reported by the authenticator, returns false if the
+// stored count is not lower than the new one. Authenticators that do not count always report 0
+func (w *SQLWriter) UpdateSignCount(ctx context.Context, credentialID []byte, signCount uint32, backupState bool) (bool, error) {
+	rows, err := w.q.UpdateWebAuthnSignCount(ctx, gen.UpdateWebAuthnSignCountParams{
+		CredentialID: credentialID,
+		SignCount:    int64(signCount),
+		BackupState:  backupState,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
@@ -0,0 +1,7 @@
+package passkeys
+
+import (
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+type Credential = gen.Authv1WebauthnCredential
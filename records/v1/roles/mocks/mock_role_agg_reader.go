@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: role_agg_reader.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	roles "github.com/ooqls/go-auth/records/v1/roles"
+)
+
+// MockAggRoleReader is a mock of AggRoleReader interface.
+type MockAggRoleReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockAggRoleReaderMockRecorder
+}
+
+// MockAggRoleReaderMockRecorder is the mock recorder for MockAggRoleReader.
+type MockAggRoleReaderMockRecorder struct {
+	mock *MockAggRoleReader
+}
+
+// NewMockAggRoleReader creates a new mock instance.
+func NewMockAggRoleReader(ctrl *gomock.Controller) *MockAggRoleReader {
+	mock := &MockAggRoleReader{ctrl: ctrl}
+	mock.recorder = &MockAggRoleReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAggRoleReader) EXPECT() *MockAggRoleReaderMockRecorder {
+	return m.recorder
+}
+
+// GetRoleAggForUser mocks base method.
+func (m *MockAggRoleReader) GetRoleAggForUser(ctx context.Context, id roles.UserId) ([]roles.RoleAgg, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoleAggForUser", ctx, id)
+	ret0, _ := ret[0].([]roles.RoleAgg)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoleAggForUser indicates an expected call of GetRoleAggForUser.
+func (mr *MockAggRoleReaderMockRecorder) GetRoleAggForUser(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoleAggForUser", reflect.TypeOf((*MockAggRoleReader)(nil).GetRoleAggForUser), ctx, id)
+}
@@ -14,8 +14,11 @@ type Role = authv1.Role
 type Permission = authv1.Permission
 type UserId = authv1.UserId
 
+var _ AggRoleReader = &AggRoleReaderImpl{}
+
+//go:generate go run github.com/golang/mock/mockgen -source=role_agg_reader.go -destination=mocks/mock_role_agg_reader.go -package=mocks
 type AggRoleReader interface {
-	GetRoleAggForUser(ctx context.Context, id UserId) (*RoleAgg, error)
+	GetRoleAggForUser(ctx context.Context, id UserId) ([]RoleAgg, error)
 }
 
 type AggRoleReaderImpl struct {
@@ -30,46 +33,42 @@ func NewAggRoleReaderImpl(l *zap.Logger, q *gen.Queries) *AggRoleReaderImpl {
 	}
 }
 
+// GetRoleAggForUser returns the roles of the user with their permissions
 func (r *AggRoleReaderImpl) GetRoleAggForUser(ctx context.Context, id UserId) ([]RoleAgg, error) {
 	roleAggs, err := r.q.GetRoleAggregate(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	roleMap := map[string]*RoleAgg{}
+	indexes := map[RoleId]int{}
 	roles := make([]RoleAgg, 0)
 
 	for _, r := range roleAggs {
-		aggR, ok := roleMap[r.RoleID.UUID.String()]
+		i, ok := indexes[r.RoleID.UUID]
 		if !ok {
-			newAggRole := &RoleAgg{
+			i = len(roles)
+			indexes[r.RoleID.UUID] = i
+			roles = append(roles, RoleAgg{
 				RoleId:        RoleId(r.RoleID.UUID),
 				RoleHierarchy: int32(r.RoleHierarchy),
-				Permissions: []Permission{
-					{
-						ID:            r.ID.UUID,
-						ResourceKind:  r.ResourceKind.String,
-						ResourceGroup: r.ResourceGroup.String,
-						ResourceName:  r.ResourceName.String,
-						Actions:       r.Actions.String,
-						CreatedAt:     r.CreatedAt.Time,
-						UpdatedAt:     r.UpdatedAt.Time,
-					},
-				},
-			}
-			roleMap[r.RoleID.UUID.String()] = newAggRole
-			roles = append(roles, *newAggRole)
-		} else {
-			aggR.Permissions = append(aggR.Permissions, Permission{
-				ID:            r.ID.UUID,
-				ResourceKind:  r.ResourceKind.String,
-				ResourceGroup: r.ResourceGroup.String,
-				ResourceName:  r.ResourceName.String,
-				Actions:       r.Actions.String,
-				CreatedAt:     r.CreatedAt.Time,
-				UpdatedAt:     r.UpdatedAt.Time,
+				Permissions:   []Permission{},
 			})
 		}
+
+		// roles without permissions have a single row without a permission
+		if !r.ID.Valid {
+			continue
+		}
+
+		roles[i].Permissions = append(roles[i].Permissions, Permission{
+			ID:            r.ID.UUID,
+			ResourceKind:  r.ResourceKind.String,
+			ResourceGroup: r.ResourceGroup.String,
+			ResourceName:  r.ResourceName.String,
+			Actions:       r.Actions.String,
+			CreatedAt:     r.CreatedAt.Time,
+			UpdatedAt:     r.UpdatedAt.Time,
+		})
 	}
 	return roles, nil
 }
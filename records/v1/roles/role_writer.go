@@ -18,6 +18,12 @@ type Writer interface {
 	RemoveRoleFromUser(ctx context.Context, userId uuid.UUID, roleId uuid.UUID) error
 }
 
+func NewSQLRoleWriter(q *gen.Queries) *SQLWriter {
+	return &SQLWriter{
+		q: *q,
+	}
+}
+
 type SQLWriter struct {
 	q gen.Queries
 }
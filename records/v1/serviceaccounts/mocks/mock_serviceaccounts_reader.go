@@ -0,0 +1,98 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: serviceaccounts_reader.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	records "github.com/ooqls/go-auth/records"
+	serviceaccounts "github.com/ooqls/go-auth/records/v1/serviceaccounts"
+)
+
+// MockReader is a mock of Reader interface.
+type MockReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockReaderMockRecorder
+}
+
+// MockReaderMockRecorder is the mock recorder for MockReader.
+type MockReaderMockRecorder struct {
+	mock *MockReader
+}
+
+// NewMockReader creates a new mock instance.
+func NewMockReader(ctrl *gomock.Controller) *MockReader {
+	mock := &MockReader{ctrl: ctrl}
+	mock.recorder = &MockReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReader) EXPECT() *MockReaderMockRecorder {
+	return m.recorder
+}
+
+// GetRoleAggs mocks base method.
+func (m *MockReader) GetRoleAggs(ctx context.Context, id uuid.UUID) ([]records.RoleAgg, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoleAggs", ctx, id)
+	ret0, _ := ret[0].([]records.RoleAgg)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoleAggs indicates an expected call of GetRoleAggs.
+func (mr *MockReaderMockRecorder) GetRoleAggs(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoleAggs", reflect.TypeOf((*MockReader)(nil).GetRoleAggs), ctx, id)
+}
+
+// GetRoles mocks base method.
+func (m *MockReader) GetRoles(ctx context.Context, id uuid.UUID) ([]records.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoles", ctx, id)
+	ret0, _ := ret[0].([]records.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoles indicates an expected call of GetRoles.
+func (mr *MockReaderMockRecorder) GetRoles(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoles", reflect.TypeOf((*MockReader)(nil).GetRoles), ctx, id)
+}
+
+// GetServiceAccount mocks base method.
+func (m *MockReader) GetServiceAccount(ctx context.Context, id uuid.UUID) (*serviceaccounts.ServiceAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceAccount", ctx, id)
+	ret0, _ := ret[0].(*serviceaccounts.ServiceAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceAccount indicates an expected call of GetServiceAccount.
+func (mr *MockReaderMockRecorder) GetServiceAccount(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceAccount", reflect.TypeOf((*MockReader)(nil).GetServiceAccount), ctx, id)
+}
+
+// ListServiceAccountsForOwner mocks base method.
+func (m *MockReader) ListServiceAccountsForOwner(ctx context.Context, ownerID records.UserId) ([]serviceaccounts.ServiceAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListServiceAccountsForOwner", ctx, ownerID)
+	ret0, _ := ret[0].([]serviceaccounts.ServiceAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServiceAccountsForOwner indicates an expected call of ListServiceAccountsForOwner.
+func (mr *MockReaderMockRecorder) ListServiceAccountsForOwner(ctx, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServiceAccountsForOwner", reflect.TypeOf((*MockReader)(nil).ListServiceAccountsForOwner), ctx, ownerID)
+}
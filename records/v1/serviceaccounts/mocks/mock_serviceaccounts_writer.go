@@ -0,0 +1,112 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: serviceaccounts_writer.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	records "github.com/ooqls/go-auth/records"
+	serviceaccounts "github.com/ooqls/go-auth/records/v1/serviceaccounts"
+)
+
+// MockWriter is a mock of Writer interface.
+type MockWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockWriterMockRecorder
+}
+
+// MockWriterMockRecorder is the mock recorder for MockWriter.
+type MockWriterMockRecorder struct {
+	mock *MockWriter
+}
+
+// NewMockWriter creates a new mock instance.
+func NewMockWriter(ctrl *gomock.Controller) *MockWriter {
+	mock := &MockWriter{ctrl: ctrl}
+	mock.recorder = &MockWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWriter) EXPECT() *MockWriterMockRecorder {
+	return m.recorder
+}
+
+// AddRole mocks base method.
+func (m *MockWriter) AddRole(ctx context.Context, id uuid.UUID, roleID records.RoleId) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRole", ctx, id, roleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRole indicates an expected call of AddRole.
+func (mr *MockWriterMockRecorder) AddRole(ctx, id, roleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRole", reflect.TypeOf((*MockWriter)(nil).AddRole), ctx, id, roleID)
+}
+
+// CreateServiceAccount mocks base method.
+func (m *MockWriter) CreateServiceAccount(ctx context.Context, account serviceaccounts.ServiceAccount) (*serviceaccounts.ServiceAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateServiceAccount", ctx, account)
+	ret0, _ := ret[0].(*serviceaccounts.ServiceAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateServiceAccount indicates an expected call of CreateServiceAccount.
+func (mr *MockWriterMockRecorder) CreateServiceAccount(ctx, account interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateServiceAccount", reflect.TypeOf((*MockWriter)(nil).CreateServiceAccount), ctx, account)
+}
+
+// DeleteServiceAccount mocks base method.
+func (m *MockWriter) DeleteServiceAccount(ctx context.Context, id uuid.UUID, ownerID records.UserId) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteServiceAccount", ctx, id, ownerID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteServiceAccount indicates an expected call of DeleteServiceAccount.
+func (mr *MockWriterMockRecorder) DeleteServiceAccount(ctx, id, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteServiceAccount", reflect.TypeOf((*MockWriter)(nil).DeleteServiceAccount), ctx, id, ownerID)
+}
+
+// RemoveRole mocks base method.
+func (m *MockWriter) RemoveRole(ctx context.Context, id uuid.UUID, roleID records.RoleId) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveRole", ctx, id, roleID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveRole indicates an expected call of RemoveRole.
+func (mr *MockWriterMockRecorder) RemoveRole(ctx, id, roleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveRole", reflect.TypeOf((*MockWriter)(nil).RemoveRole), ctx, id, roleID)
+}
+
+// UpdateCredentials mocks base method.
+func (m *MockWriter) UpdateCredentials(ctx context.Context, account serviceaccounts.ServiceAccount) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCredentials", ctx, account)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCredentials indicates an expected call of UpdateCredentials.
+func (mr *MockWriterMockRecorder) UpdateCredentials(ctx, account interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCredentials", reflect.TypeOf((*MockWriter)(nil).UpdateCredentials), ctx, account)
+}
@@ -0,0 +1,94 @@
+package serviceaccounts
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+var _ Reader = &SQLReader{}
+
+//go:generate go run github.com/golang/mock/mockgen -source=serviceaccounts_reader.go -destination=mocks/mock_serviceaccounts_reader.go -package=mocks
+type Reader interface {
+	GetServiceAccount(ctx context.Context, id uuid.UUID) (*ServiceAccount, error)
+	ListServiceAccountsForOwner(ctx context.Context, ownerID records.UserId) ([]ServiceAccount, error)
+	GetRoles(ctx context.Context, id uuid.UUID) ([]records.Role, error)
+	GetRoleAggs(ctx context.Context, id uuid.UUID) ([]records.RoleAgg, error)
+}
+
+type SQLReader struct {
+	q *gen.Queries
+}
+
+func NewSQLReader(db *sqlx.DB) *SQLReader {
+	return &SQLReader{
+		q: gen.New(db),
+	}
+}
+
+// GetServiceAccount returns the service account, or nil if there is none
+func (r *SQLReader) GetServiceAccount(ctx context.Context, id uuid.UUID) (*ServiceAccount, error) {
+	account, err := r.q.GetServiceAccount(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func (r *SQLReader) ListServiceAccountsForOwner(ctx context.Context, ownerID records.UserId) ([]ServiceAccount, error) {
+	return r.q.ListServiceAccountsForOwner(ctx, ownerID)
+}
+
+func (r *SQLReader) GetRoles(ctx context.Context, id uuid.UUID) ([]records.Role, error) {
+	return r.q.GetRolesForServiceAccount(ctx, id)
+}
+
+// GetRoleAggs returns the roles of the service account with their permissions
+func (r *SQLReader) GetRoleAggs(ctx context.Context, id uuid.UUID) ([]records.RoleAgg, error) {
+	rows, err := r.q.GetServiceAccountRoleAggregate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := map[uuid.UUID]int{}
+	roles := []records.RoleAgg{}
+	for _, row := range rows {
+		i, ok := indexes[row.RoleID]
+		if !ok {
+			i = len(roles)
+			indexes[row.RoleID] = i
+			roles = append(roles, records.RoleAgg{
+				RoleId:        row.RoleID,
+				RoleHierarchy: row.RoleHierarchy,
+				Permissions:   []records.Permission{},
+			})
+		}
+
+		// roles without permissions have a single row without a permission
+		if !row.ID.Valid {
+			continue
+		}
+
+		roles[i].Permissions = append(roles[i].Permissions, records.Permission{
+			ID:            row.ID.UUID,
+			ResourceKind:  row.ResourceKind.String,
+			ResourceGroup: row.ResourceGroup.String,
+			ResourceName:  row.ResourceName.String,
+			Actions:       row.Actions.String,
+			CreatedAt:     row.CreatedAt.Time,
+			UpdatedAt:     row.UpdatedAt.Time,
+		})
+	}
+
+	return roles, nil
+}
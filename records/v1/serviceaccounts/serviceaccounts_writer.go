@@ -0,0 +1,95 @@
+package serviceaccounts
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+var _ Writer = &SQLWriter{}
+
+//go:generate go run github.com/golang/mock/mockgen -source=serviceaccounts_writer.go -destination=mocks/mock_serviceaccounts_writer.go -package=mocks
+type Writer interface {
+	CreateServiceAccount(ctx context.Context, account ServiceAccount) (*ServiceAccount, error)
+	UpdateCredentials(ctx context.Context, account ServiceAccount) (bool, error)
+	DeleteServiceAccount(ctx context.Context, id uuid.UUID, ownerID records.UserId) (bool, error)
+	AddRole(ctx context.Context, id uuid.UUID, roleID records.RoleId) error
+	RemoveRole(ctx context.Context, id uuid.UUID, roleID records.RoleId) (bool, error)
+}
+
+type SQLWriter struct {
+	q *gen.Queries
+}
+
+func NewSQLWriter(db *sqlx.DB) *SQLWriter {
+	return &SQLWriter{
+		q: gen.New(db),
+	}
+}
+
+func (w *SQLWriter) CreateServiceAccount(ctx context.Context, account ServiceAccount) (*ServiceAccount, error) {
+	created, err := w.q.CreateServiceAccount(ctx, gen.CreateServiceAccountParams{
+		OwnerID:    account.OwnerID,
+		Name:       account.Name,
+		SecretHash: account.SecretHash,
+		PublicKey:  account.PublicKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// UpdateCredentials replaces the secret hash and public key of the account if it is owned by account.OwnerID,
+// returns false if it is not
+func (w *SQLWriter) UpdateCredentials(ctx context.Context, account ServiceAccount) (bool, error) {
+	rows, err := w.q.UpdateServiceAccountCredentials(ctx, gen.UpdateServiceAccountCredentialsParams{
+		ID:         account.ID,
+		OwnerID:    account.OwnerID,
+		SecretHash: account.SecretHash,
+		PublicKey:  account.PublicKey,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// DeleteServiceAccount deletes the account if it is owned by ownerID, returns false if it is not
+func (w *SQLWriter) DeleteServiceAccount(ctx context.Context, id uuid.UUID, ownerID records.UserId) (bool, error) {
+	rows, err := w.q.DeleteServiceAccount(ctx, gen.DeleteServiceAccountParams{
+		ID:      id,
+		OwnerID: ownerID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// AddRole grants the role to the account, granting a role it already holds does nothing
+func (w *SQLWriter) AddRole(ctx context.Context, id uuid.UUID, roleID records.RoleId) error {
+	return w.q.AddRoleToServiceAccount(ctx, gen.AddRoleToServiceAccountParams{
+		ServiceAccountID: id,
+		RoleID:           roleID,
+	})
+}
+
+// RemoveRole takes the role from the account, returns false if it did not hold it
+func (w *SQLWriter) RemoveRole(ctx context.Context, id uuid.UUID, roleID records.RoleId) (bool, error) {
+	rows, err := w.q.RemoveRoleFromServiceAccount(ctx, gen.RemoveRoleFromServiceAccountParams{
+		ServiceAccountID: id,
+		RoleID:           roleID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
@@ -0,0 +1,7 @@
+package serviceaccounts
+
+import (
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+type ServiceAccount = gen.Authv1ServiceAccount
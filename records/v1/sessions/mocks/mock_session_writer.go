@@ -0,0 +1,97 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: session_writer.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	records "github.com/ooqls/go-auth/records"
+	sessions "github.com/ooqls/go-auth/records/v1/sessions"
+)
+
+// MockWriter is a mock of Writer interface.
+type MockWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockWriterMockRecorder
+}
+
+// MockWriterMockRecorder is the mock recorder for MockWriter.
+type MockWriterMockRecorder struct {
+	mock *MockWriter
+}
+
+// NewMockWriter creates a new mock instance.
+func NewMockWriter(ctrl *gomock.Controller) *MockWriter {
+	mock := &MockWriter{ctrl: ctrl}
+	mock.recorder = &MockWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWriter) EXPECT() *MockWriterMockRecorder {
+	return m.recorder
+}
+
+// CreateSession mocks base method.
+func (m *MockWriter) CreateSession(ctx context.Context, session sessions.Session) (*sessions.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", ctx, session)
+	ret0, _ := ret[0].(*sessions.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockWriterMockRecorder) CreateSession(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockWriter)(nil).CreateSession), ctx, session)
+}
+
+// DeleteOtherSessions mocks base method.
+func (m *MockWriter) DeleteOtherSessions(ctx context.Context, userID records.UserId, keep sessions.SessionId) ([]sessions.SessionId, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOtherSessions", ctx, userID, keep)
+	ret0, _ := ret[0].([]sessions.SessionId)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOtherSessions indicates an expected call of DeleteOtherSessions.
+func (mr *MockWriterMockRecorder) DeleteOtherSessions(ctx, userID, keep interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOtherSessions", reflect.TypeOf((*MockWriter)(nil).DeleteOtherSessions), ctx, userID, keep)
+}
+
+// DeleteSession mocks base method.
+func (m *MockWriter) DeleteSession(ctx context.Context, userID records.UserId, id sessions.SessionId) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSession", ctx, userID, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSession indicates an expected call of DeleteSession.
+func (mr *MockWriterMockRecorder) DeleteSession(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockWriter)(nil).DeleteSession), ctx, userID, id)
+}
+
+// TouchSession mocks base method.
+func (m *MockWriter) TouchSession(ctx context.Context, id sessions.SessionId, token string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchSession", ctx, id, token, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchSession indicates an expected call of TouchSession.
+func (mr *MockWriterMockRecorder) TouchSession(ctx, id, token, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchSession", reflect.TypeOf((*MockWriter)(nil).TouchSession), ctx, id, token, expiresAt)
+}
@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/ooqls/go-auth/records/v1/sessions"
+)
+
+// AcceptSessions returns a writer that accepts every write and reports sessions as found
+func AcceptSessions(ctrl *gomock.Controller) *MockWriter {
+	mock := NewMockWriter(ctrl)
+	mock.EXPECT().CreateSession(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ any, session sessions.Session) (*sessions.Session, error) {
+			return &session, nil
+		})
+	mock.EXPECT().TouchSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+	mock.EXPECT().DeleteSession(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(true, nil)
+	mock.EXPECT().DeleteOtherSessions(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
+	return mock
+}
+
+func ReturnSessions(ctrl *gomock.Controller, userSessions ...sessions.Session) *MockReader {
+	mock := NewMockReader(ctrl)
+	mock.EXPECT().ListSessionsForUser(gomock.Any(), gomock.Any()).AnyTimes().Return(userSessions, nil)
+	return mock
+}
@@ -0,0 +1,48 @@
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+var _ Reader = &SQLReader{}
+
+//go:generate go run github.com/golang/mock/mockgen -source=session_reader.go -destination=mocks/mock_session_reader.go -package=mocks
+type Reader interface {
+	GetSession(ctx context.Context, id SessionId) (*Session, error)
+	ListSessionsForUser(ctx context.Context, userID records.UserId) ([]Session, error)
+}
+
+type SQLReader struct {
+	q *gen.Queries
+}
+
+func NewSQLReader(db *sqlx.DB) *SQLReader {
+	return &SQLReader{
+		q: gen.New(db),
+	}
+}
+
+// GetSession returns the session with the given id, or nil if it does not exist
+func (r *SQLReader) GetSession(ctx context.Context, id SessionId) (*Session, error) {
+	session, err := r.q.GetSession(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// ListSessionsForUser returns the user's unexpired sessions, most recently seen first
+func (r *SQLReader) ListSessionsForUser(ctx context.Context, userID records.UserId) ([]Session, error) {
+	return r.q.ListSessionsForUser(ctx, userID)
+}
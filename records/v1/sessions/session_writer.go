@@ -0,0 +1,76 @@
+package sessions
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/ooqls/go-auth/records"
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+var _ Writer = &SQLWriter{}
+
+//go:generate go run github.com/golang/mock/mockgen -source=session_writer.go -destination=mocks/mock_session_writer.go -package=mocks
+type Writer interface {
+	CreateSession(ctx context.Context, session Session) (*Session, error)
+	TouchSession(ctx context.Context, id SessionId, token string, expiresAt time.Time) error
+	DeleteSession(ctx context.Context, userID records.UserId, id SessionId) (bool, error)
+	DeleteOtherSessions(ctx context.Context, userID records.UserId, keep SessionId) ([]SessionId, error)
+}
+
+type SQLWriter struct {
+	q *gen.Queries
+}
+
+func NewSQLWriter(db *sqlx.DB) *SQLWriter {
+	return &SQLWriter{
+		q: gen.New(db),
+	}
+}
+
+func (w *SQLWriter) CreateSession(ctx context.Context, session Session) (*Session, error) {
+	created, err := w.q.CreateSession(ctx, gen.CreateSessionParams{
+		ID:        session.ID,
+		UserID:    session.UserID,
+		Token:     session.Token,
+		UserAgent: session.UserAgent,
+		IpAddress: session.IpAddress,
+		ExpiresAt: session.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// TouchSession records activity on the session and replaces its current token
+func (w *SQLWriter) TouchSession(ctx context.Context, id SessionId, token string, expiresAt time.Time) error {
+	return w.q.TouchSession(ctx, gen.TouchSessionParams{
+		ID:        id,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// DeleteSession deletes the user's session, returns false if the user has no such session
+func (w *SQLWriter) DeleteSession(ctx context.Context, userID records.UserId, id SessionId) (bool, error) {
+	rows, err := w.q.DeleteSessionForUser(ctx, gen.DeleteSessionForUserParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// DeleteOtherSessions deletes every session of the user except keep and returns the deleted session ids
+func (w *SQLWriter) DeleteOtherSessions(ctx context.Context, userID records.UserId, keep SessionId) ([]SessionId, error) {
+	return w.q.DeleteOtherSessionsForUser(ctx, gen.DeleteOtherSessionsForUserParams{
+		UserID: userID,
+		ID:     keep,
+	})
+}
@@ -0,0 +1,9 @@
+package sessions
+
+import (
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+type Session = gen.Authv1Session
+type SessionId = uuid.UUID
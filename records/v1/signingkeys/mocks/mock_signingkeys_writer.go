@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: signingkeys_writer.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	signingkeys "github.com/ooqls/go-auth/records/v1/signingkeys"
+)
+
+// MockWriter is a mock of Writer interface.
+type MockWriter struct {
+	ctrl     *gomock.Controller
+	recorder *MockWriterMockRecorder
+}
+
+// MockWriterMockRecorder is the mock recorder for MockWriter.
+type MockWriterMockRecorder struct {
+	mock *MockWriter
+}
+
+// NewMockWriter creates a new mock instance.
+func NewMockWriter(ctrl *gomock.Controller) *MockWriter {
+	mock := &MockWriter{ctrl: ctrl}
+	mock.recorder = &MockWriterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWriter) EXPECT() *MockWriterMockRecorder {
+	return m.recorder
+}
+
+// CreateKey mocks base method.
+func (m *MockWriter) CreateKey(ctx context.Context, key signingkeys.SigningKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateKey indicates an expected call of CreateKey.
+func (mr *MockWriterMockRecorder) CreateKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKey", reflect.TypeOf((*MockWriter)(nil).CreateKey), ctx, key)
+}
+
+// DeleteExpiredKeys mocks base method.
+func (m *MockWriter) DeleteExpiredKeys(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredKeys", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredKeys indicates an expected call of DeleteExpiredKeys.
+func (mr *MockWriterMockRecorder) DeleteExpiredKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredKeys", reflect.TypeOf((*MockWriter)(nil).DeleteExpiredKeys), ctx)
+}
+
+// RetireKeys mocks base method.
+func (m *MockWriter) RetireKeys(ctx context.Context, activeID string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetireKeys", ctx, activeID, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetireKeys indicates an expected call of RetireKeys.
+func (mr *MockWriterMockRecorder) RetireKeys(ctx, activeID, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetireKeys", reflect.TypeOf((*MockWriter)(nil).RetireKeys), ctx, activeID, expiresAt)
+}
@@ -0,0 +1,30 @@
+package signingkeys
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+var _ Reader = &SQLReader{}
+
+//go:generate go run github.com/golang/mock/mockgen -source=signingkeys_reader.go -destination=mocks/mock_signingkeys_reader.go -package=mocks
+type Reader interface {
+	ListKeys(ctx context.Context) ([]SigningKey, error)
+}
+
+type SQLReader struct {
+	q *gen.Queries
+}
+
+func NewSQLReader(db *sqlx.DB) *SQLReader {
+	return &SQLReader{
+		q: gen.New(db),
+	}
+}
+
+// ListKeys returns the keys that have not expired, oldest first
+func (r *SQLReader) ListKeys(ctx context.Context) ([]SigningKey, error) {
+	return r.q.ListSigningKeys(ctx)
+}
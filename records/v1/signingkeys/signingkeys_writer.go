@@ -0,0 +1,49 @@
+package signingkeys
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+var _ Writer = &SQLWriter{}
+
+//go:generate go run github.com/golang/mock/mockgen -source=signingkeys_writer.go -destination=mocks/mock_signingkeys_writer.go -package=mocks
+type Writer interface {
+	CreateKey(ctx context.Context, key SigningKey) error
+	RetireKeys(ctx context.Context, activeID string, expiresAt time.Time) error
+	DeleteExpiredKeys(ctx context.Context) (int64, error)
+}
+
+type SQLWriter struct {
+	q *gen.Queries
+}
+
+func NewSQLWriter(db *sqlx.DB) *SQLWriter {
+	return &SQLWriter{
+		q: gen.New(db),
+	}
+}
+
+func (w *SQLWriter) CreateKey(ctx context.Context, key SigningKey) error {
+	return w.q.CreateSigningKey(ctx, gen.CreateSigningKeyParams{
+		ID:         key.ID,
+		Algorithm:  key.Algorithm,
+		PrivateKey: key.PrivateKey,
+	})
+}
+
+// RetireKeys retires every key but the active one, retired keys are kept for verification until expiresAt
+func (w *SQLWriter) RetireKeys(ctx context.Context, activeID string, expiresAt time.Time) error {
+	return w.q.RetireSigningKeys(ctx, gen.RetireSigningKeysParams{
+		ActiveID:  activeID,
+		ExpiresAt: sql.NullTime{Time: expiresAt, Valid: true},
+	})
+}
+
+func (w *SQLWriter) DeleteExpiredKeys(ctx context.Context) (int64, error) {
+	return w.q.DeleteExpiredSigningKeys(ctx)
+}
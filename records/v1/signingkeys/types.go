@@ -0,0 +1,7 @@
+package signingkeys
+
+import (
+	"github.com/ooqls/go-auth/records/v1/gen"
+)
+
+type SigningKey = gen.Authv1SigningKey
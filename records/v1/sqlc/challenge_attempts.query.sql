@@ -8,7 +8,11 @@ ORDER BY created_at DESC;
 INSERT INTO authv1_challenge_attempts (challenge_id, user_id, success) VALUES ($1, $2, $3);
 
 -- name: GetFailedAttempts :many
-SELECT id, challenge_id, user_id, success, created_at
-FROM authv1_challenge_attempts
-WHERE user_id = $1 AND success = FALSE AND created_at >= NOW() - (sqlc.arg(minutes) || ' minutes')::interval
-ORDER BY created_at DESC;
\ No newline at end of file
+SELECT a.id, a.challenge_id, a.user_id, a.success, a.created_at
+FROM authv1_challenge_attempts a
+WHERE a.user_id = $1 AND a.success = FALSE AND a.created_at >= NOW() - (sqlc.arg(minutes) || ' minutes')::interval
+  AND a.created_at > COALESCE((
+    SELECT MAX(s.created_at) FROM authv1_challenge_attempts s
+    WHERE s.user_id = $1 AND s.success = TRUE
+  ), '-infinity'::timestamptz)
+ORDER BY a.created_at DESC;
\ No newline at end of file
@@ -0,0 +1,21 @@
+-- name: GetFederatedIdentity :one
+SELECT * FROM authv1_federated_identities WHERE provider = $1 AND subject = $2;
+
+-- name: ListFederatedIdentitiesForUser :many
+SELECT * FROM authv1_federated_identities WHERE user_id = $1 ORDER BY provider;
+
+-- name: CreateFederatedIdentity :one
+INSERT INTO authv1_federated_identities (
+  provider,
+  subject,
+  user_id,
+  email
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: TouchFederatedIdentity :exec
+UPDATE authv1_federated_identities SET
+  email = $3,
+  last_login_at = now()
+WHERE provider = $1 AND subject = $2;
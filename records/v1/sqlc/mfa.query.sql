@@ -0,0 +1,44 @@
+-- name: GetMFA :one
+SELECT * FROM authv1_mfa WHERE user_id = $1;
+
+-- name: EnrollMFA :execrows
+INSERT INTO authv1_mfa (
+  user_id,
+  secret
+) VALUES (
+  $1,
+  $2
+) ON CONFLICT (user_id) DO UPDATE SET
+  secret = EXCLUDED.secret,
+  last_used_step = 0,
+  updated_at = now()
+WHERE authv1_mfa.confirmed = FALSE;
+
+-- name: ConfirmMFA :exec
+UPDATE authv1_mfa SET
+  confirmed = TRUE,
+  updated_at = now()
+WHERE user_id = $1;
+
+-- name: UseMFAStep :execrows
+UPDATE authv1_mfa SET
+  last_used_step = $2,
+  updated_at = now()
+WHERE user_id = $1 AND last_used_step < $2;
+
+-- name: DeleteMFA :exec
+DELETE FROM authv1_mfa WHERE user_id = $1;
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO authv1_mfa_recovery_codes (user_id, code_hash) VALUES ($1, $2);
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM authv1_mfa_recovery_codes WHERE user_id = $1;
+
+-- name: UseRecoveryCode :execrows
+UPDATE authv1_mfa_recovery_codes SET
+  used_at = now()
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+
+-- name: CountUnusedRecoveryCodes :one
+SELECT COUNT(*) FROM authv1_mfa_recovery_codes WHERE user_id = $1 AND used_at IS NULL;
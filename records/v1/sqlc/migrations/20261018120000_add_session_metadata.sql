@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+START TRANSACTION;
+
+ALTER TABLE authv1_sessions ADD COLUMN IF NOT EXISTS user_agent TEXT NOT NULL DEFAULT '';
+ALTER TABLE authv1_sessions ADD COLUMN IF NOT EXISTS ip_address TEXT NOT NULL DEFAULT '';
+ALTER TABLE authv1_sessions ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMPTZ NOT NULL DEFAULT now ();
+
+CREATE INDEX IF NOT EXISTS authv1_sessions_user_id_idx ON authv1_sessions (user_id);
+
+COMMIT;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+START TRANSACTION;
+
+DROP INDEX IF EXISTS authv1_sessions_user_id_idx;
+
+ALTER TABLE authv1_sessions DROP COLUMN IF EXISTS last_seen_at;
+ALTER TABLE authv1_sessions DROP COLUMN IF EXISTS ip_address;
+ALTER TABLE authv1_sessions DROP COLUMN IF EXISTS user_agent;
+
+COMMIT;
+
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+START TRANSACTION;
+
+-- challenges are kept in the cache store, attempts reference challenge ids that never reach authv1_challenges
+ALTER TABLE authv1_challenge_attempts DROP CONSTRAINT IF EXISTS authv1_challenge_attempts_challenge_id_fkey;
+
+CREATE INDEX IF NOT EXISTS authv1_challenge_attempts_user_id_created_at_idx ON authv1_challenge_attempts (user_id, created_at);
+
+COMMIT;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+START TRANSACTION;
+
+DROP INDEX IF EXISTS authv1_challenge_attempts_user_id_created_at_idx;
+
+COMMIT;
+
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+START TRANSACTION;
+
+ALTER TABLE authv1_users ADD COLUMN IF NOT EXISTS algorithm TEXT NOT NULL DEFAULT 'AESGCM';
+
+COMMIT;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+START TRANSACTION;
+
+ALTER TABLE authv1_users DROP COLUMN IF EXISTS algorithm;
+
+COMMIT;
+
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+START TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS authv1_mfa (
+  user_id uuid PRIMARY KEY REFERENCES authv1_users (id) ON DELETE CASCADE,
+  secret BYTEA NOT NULL,
+  confirmed BOOLEAN NOT NULL DEFAULT FALSE,
+  last_used_step BIGINT NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now ()
+);
+
+CREATE TABLE IF NOT EXISTS authv1_mfa_recovery_codes (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid (),
+  user_id uuid NOT NULL REFERENCES authv1_users (id) ON DELETE CASCADE,
+  code_hash TEXT NOT NULL,
+  used_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+  UNIQUE (user_id, code_hash)
+);
+
+COMMIT;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+START TRANSACTION;
+
+DROP TABLE IF EXISTS authv1_mfa_recovery_codes;
+DROP TABLE IF EXISTS authv1_mfa;
+
+COMMIT;
+
+-- +goose StatementEnd
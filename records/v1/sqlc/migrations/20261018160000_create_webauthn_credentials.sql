@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+START TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS authv1_webauthn_credentials (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid (),
+  user_id uuid NOT NULL REFERENCES authv1_users (id) ON DELETE CASCADE,
+  credential_id BYTEA NOT NULL UNIQUE,
+  public_key BYTEA NOT NULL,
+  attestation_type TEXT NOT NULL DEFAULT '',
+  aaguid BYTEA NOT NULL DEFAULT '',
+  sign_count BIGINT NOT NULL DEFAULT 0,
+  transports TEXT[] NOT NULL DEFAULT '{}',
+  backup_eligible BOOLEAN NOT NULL DEFAULT FALSE,
+  backup_state BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+  last_used_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS authv1_webauthn_credentials_user_id_idx ON authv1_webauthn_credentials (user_id);
+
+COMMIT;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+START TRANSACTION;
+
+DROP TABLE IF EXISTS authv1_webauthn_credentials;
+
+COMMIT;
+
+-- +goose StatementEnd
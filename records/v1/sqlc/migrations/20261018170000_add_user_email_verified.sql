@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+START TRANSACTION;
+
+ALTER TABLE authv1_users ADD COLUMN IF NOT EXISTS email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- users registered before email verification existed keep their access
+UPDATE authv1_users SET email_verified = TRUE;
+
+COMMIT;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+START TRANSACTION;
+
+ALTER TABLE authv1_users DROP COLUMN IF EXISTS email_verified;
+
+COMMIT;
+
+-- +goose StatementEnd
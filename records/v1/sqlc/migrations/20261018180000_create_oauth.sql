@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+START TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS authv1_oauth_clients (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid (),
+  owner_id uuid NOT NULL REFERENCES authv1_users (id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  -- public clients have no secret and rely on PKCE alone
+  secret_hash BYTEA,
+  redirect_uris TEXT[] NOT NULL,
+  scopes TEXT[] NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now ()
+);
+
+CREATE INDEX IF NOT EXISTS authv1_oauth_clients_owner_id_idx ON authv1_oauth_clients (owner_id);
+
+CREATE TABLE IF NOT EXISTS authv1_oauth_consents (
+  user_id uuid NOT NULL REFERENCES authv1_users (id) ON DELETE CASCADE,
+  client_id uuid NOT NULL REFERENCES authv1_oauth_clients (id) ON DELETE CASCADE,
+  scopes TEXT[] NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+  PRIMARY KEY (user_id, client_id)
+);
+
+COMMIT;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+START TRANSACTION;
+
+DROP TABLE IF EXISTS authv1_oauth_consents;
+DROP TABLE IF EXISTS authv1_oauth_clients;
+
+COMMIT;
+
+-- +goose StatementEnd
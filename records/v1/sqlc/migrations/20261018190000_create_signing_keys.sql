@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+START TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS authv1_signing_keys (
+  -- the RFC 7638 thumbprint of the public key, it is the kid header of the tokens the key signs
+  id TEXT PRIMARY KEY,
+  algorithm TEXT NOT NULL,
+  -- the PKCS #1 private key, sealed with the key ring's encryption key
+  private_key BYTEA NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+  -- retired keys no longer sign, they verify until expires_at when the last token they signed expired
+  retired_at TIMESTAMPTZ,
+  expires_at TIMESTAMPTZ
+);
+
+COMMIT;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+START TRANSACTION;
+
+DROP TABLE IF EXISTS authv1_signing_keys;
+
+COMMIT;
+
+-- +goose StatementEnd
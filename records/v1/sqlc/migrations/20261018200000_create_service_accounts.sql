@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+START TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS authv1_service_accounts (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid (),
+  owner_id uuid NOT NULL REFERENCES authv1_users (id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  -- argon2id hash of the client secret, null for accounts that authenticate with a jwt assertion
+  secret_hash TEXT,
+  -- PKIX public key the jwt assertions of the account are verified with
+  public_key BYTEA,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+  CHECK (secret_hash IS NOT NULL OR public_key IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS authv1_service_accounts_owner_id_idx ON authv1_service_accounts (owner_id);
+
+CREATE TABLE IF NOT EXISTS authv1_service_account_roles (
+  service_account_id uuid NOT NULL REFERENCES authv1_service_accounts (id) ON DELETE CASCADE,
+  role_id uuid NOT NULL REFERENCES authv1_roles (id) ON DELETE CASCADE,
+  PRIMARY KEY (service_account_id, role_id),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now ()
+);
+
+COMMIT;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+START TRANSACTION;
+
+DROP TABLE IF EXISTS authv1_service_account_roles;
+DROP TABLE IF EXISTS authv1_service_accounts;
+
+COMMIT;
+
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+START TRANSACTION;
+
+CREATE TABLE IF NOT EXISTS authv1_personal_access_tokens (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid (),
+  user_id uuid NOT NULL REFERENCES authv1_users (id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  -- sha256 of the token, the token itself is only shown to the user when it is created
+  token_hash BYTEA NOT NULL UNIQUE,
+  expires_at TIMESTAMPTZ NOT NULL,
+  last_used_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now ()
+);
+
+CREATE INDEX IF NOT EXISTS authv1_personal_access_tokens_user_id_idx ON authv1_personal_access_tokens (user_id);
+
+-- the permissions the token is scoped to, only those the user also holds through their roles are granted
+CREATE TABLE IF NOT EXISTS authv1_personal_access_token_permissions (
+  token_id uuid NOT NULL REFERENCES authv1_personal_access_tokens (id) ON DELETE CASCADE,
+  permission_id uuid NOT NULL REFERENCES authv1_permissions (id) ON DELETE CASCADE,
+  PRIMARY KEY (token_id, permission_id)
+);
+
+COMMIT;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+START TRANSACTION;
+
+DROP TABLE IF EXISTS authv1_personal_access_token_permissions;
+DROP TABLE IF EXISTS authv1_personal_access_tokens;
+
+COMMIT;
+
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+START TRANSACTION;
+
+-- links the accounts of upstream identity providers to users, subject is the provider's sub claim
+CREATE TABLE IF NOT EXISTS authv1_federated_identities (
+  provider TEXT NOT NULL,
+  subject TEXT NOT NULL,
+  user_id uuid NOT NULL REFERENCES authv1_users (id) ON DELETE CASCADE,
+  email TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+  last_login_at TIMESTAMPTZ NOT NULL DEFAULT now (),
+  PRIMARY KEY (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS authv1_federated_identities_user_id_idx ON authv1_federated_identities (user_id);
+CREATE INDEX IF NOT EXISTS authv1_users_email_idx ON authv1_users (email);
+
+COMMIT;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+START TRANSACTION;
+
+DROP INDEX IF EXISTS authv1_users_email_idx;
+DROP TABLE IF EXISTS authv1_federated_identities;
+
+COMMIT;
+
+-- +goose StatementEnd
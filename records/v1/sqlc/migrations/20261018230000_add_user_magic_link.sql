@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+START TRANSACTION;
+
+-- users opt in to logging in with emailed links, unless the magic link policy lets every user
+ALTER TABLE authv1_users ADD COLUMN IF NOT EXISTS magic_link_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+START TRANSACTION;
+
+ALTER TABLE authv1_users DROP COLUMN IF EXISTS magic_link_enabled;
+
+COMMIT;
+
+-- +goose StatementEnd
@@ -0,0 +1,46 @@
+-- name: CreateOAuthClient :one
+INSERT INTO authv1_oauth_clients (
+  owner_id,
+  name,
+  secret_hash,
+  redirect_uris,
+  scopes
+) VALUES (
+  $1,
+  $2,
+  $3,
+  $4,
+  $5
+) RETURNING *;
+
+-- name: GetOAuthClient :one
+SELECT * FROM authv1_oauth_clients WHERE id = $1;
+
+-- name: ListOAuthClientsForOwner :many
+SELECT * FROM authv1_oauth_clients WHERE owner_id = $1 ORDER BY created_at;
+
+-- name: DeleteOAuthClient :execrows
+DELETE FROM authv1_oauth_clients WHERE id = $1 AND owner_id = $2;
+
+-- name: GetOAuthConsent :one
+SELECT * FROM authv1_oauth_consents WHERE user_id = $1 AND client_id = $2;
+
+-- name: ListOAuthConsentsForUser :many
+SELECT * FROM authv1_oauth_consents WHERE user_id = $1 ORDER BY updated_at DESC;
+
+-- name: UpsertOAuthConsent :one
+INSERT INTO authv1_oauth_consents (
+  user_id,
+  client_id,
+  scopes
+) VALUES (
+  $1,
+  $2,
+  $3
+) ON CONFLICT (user_id, client_id) DO UPDATE SET
+  scopes = EXCLUDED.scopes,
+  updated_at = now()
+RETURNING *;
+
+-- name: DeleteOAuthConsent :execrows
+DELETE FROM authv1_oauth_consents WHERE user_id = $1 AND client_id = $2;
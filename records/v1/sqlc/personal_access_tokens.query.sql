@@ -0,0 +1,27 @@
+-- name: CreatePersonalAccessToken :one
+INSERT INTO authv1_personal_access_tokens (
+  user_id,
+  name,
+  token_hash,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: AddPermissionToPersonalAccessToken :exec
+INSERT INTO authv1_personal_access_token_permissions (token_id, permission_id) VALUES ($1, $2);
+
+-- name: GetPersonalAccessTokenByHash :one
+SELECT * FROM authv1_personal_access_tokens WHERE token_hash = $1;
+
+-- name: ListPersonalAccessTokensForUser :many
+SELECT * FROM authv1_personal_access_tokens WHERE user_id = $1 ORDER BY created_at;
+
+-- name: GetPersonalAccessTokenPermissionIDs :many
+SELECT permission_id FROM authv1_personal_access_token_permissions WHERE token_id = $1;
+
+-- name: TouchPersonalAccessToken :exec
+UPDATE authv1_personal_access_tokens SET last_used_at = $2 WHERE id = $1;
+
+-- name: DeletePersonalAccessToken :execrows
+DELETE FROM authv1_personal_access_tokens WHERE id = $1 AND user_id = $2;
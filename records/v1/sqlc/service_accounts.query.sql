@@ -0,0 +1,44 @@
+-- name: CreateServiceAccount :one
+INSERT INTO authv1_service_accounts (
+  owner_id,
+  name,
+  secret_hash,
+  public_key
+) VALUES (
+  $1,
+  $2,
+  $3,
+  $4
+) RETURNING *;
+
+-- name: GetServiceAccount :one
+SELECT * FROM authv1_service_accounts WHERE id = $1;
+
+-- name: ListServiceAccountsForOwner :many
+SELECT * FROM authv1_service_accounts WHERE owner_id = $1 ORDER BY created_at;
+
+-- name: UpdateServiceAccountCredentials :execrows
+UPDATE authv1_service_accounts SET
+  secret_hash = $3,
+  public_key = $4,
+  updated_at = now()
+WHERE id = $1 AND owner_id = $2;
+
+-- name: DeleteServiceAccount :execrows
+DELETE FROM authv1_service_accounts WHERE id = $1 AND owner_id = $2;
+
+-- name: GetRolesForServiceAccount :many
+SELECT r.* FROM authv1_roles r JOIN authv1_service_account_roles s ON r.id = s.role_id WHERE s.service_account_id = $1;
+
+-- name: GetServiceAccountRoleAggregate :many
+SELECT roles.role_name, roles.role_hierarchy, perm.*, account_roles.role_id FROM authv1_roles roles
+  JOIN authv1_service_account_roles account_roles ON roles.id = account_roles.role_id
+  LEFT JOIN authv1_role_permissions role_perm ON roles.id = role_perm.role_id
+  LEFT JOIN authv1_permissions perm ON role_perm.permission_id = perm.id
+WHERE account_roles.service_account_id = $1;
+
+-- name: AddRoleToServiceAccount :exec
+INSERT INTO authv1_service_account_roles (service_account_id, role_id) VALUES ($1, $2) ON CONFLICT DO NOTHING;
+
+-- name: RemoveRoleFromServiceAccount :execrows
+DELETE FROM authv1_service_account_roles WHERE service_account_id = $1 AND role_id = $2;
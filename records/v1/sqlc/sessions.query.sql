@@ -4,15 +4,24 @@ SELECT * FROM authv1_sessions WHERE id = $1;
 -- name: ListSessions :many
 SELECT * FROM authv1_sessions ORDER BY created_at LIMIT $1 OFFSET $2;
 
+-- name: ListSessionsForUser :many
+SELECT * FROM authv1_sessions WHERE user_id = $1 AND expires_at > now () ORDER BY last_seen_at DESC;
+
 -- name: CreateSession :one
 INSERT INTO authv1_sessions (
+  id,
   user_id,
   token,
+  user_agent,
+  ip_address,
   expires_at
 ) VALUES (
   $1,
   $2,
-  $3
+  $3,
+  $4,
+  $5,
+  $6
 ) RETURNING *;
 
 -- name: UpdateSession :exec
@@ -22,5 +31,18 @@ UPDATE authv1_sessions SET
   expires_at = $3
 WHERE id = $4;
 
+-- name: TouchSession :exec
+UPDATE authv1_sessions SET
+  token = $2,
+  expires_at = $3,
+  last_seen_at = now ()
+WHERE id = $1;
+
 -- name: DeleteSession :exec
-DELETE FROM authv1_sessions WHERE id = $1;
\ No newline at end of file
+DELETE FROM authv1_sessions WHERE id = $1;
+
+-- name: DeleteSessionForUser :execrows
+DELETE FROM authv1_sessions WHERE id = $1 AND user_id = $2;
+
+-- name: DeleteOtherSessionsForUser :many
+DELETE FROM authv1_sessions WHERE user_id = $1 AND id <> $2 RETURNING id;
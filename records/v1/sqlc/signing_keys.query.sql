@@ -0,0 +1,21 @@
+-- name: CreateSigningKey :exec
+INSERT INTO authv1_signing_keys (
+  id,
+  algorithm,
+  private_key
+) VALUES (
+  $1,
+  $2,
+  $3
+);
+
+-- name: ListSigningKeys :many
+SELECT * FROM authv1_signing_keys WHERE expires_at IS NULL OR expires_at > now () ORDER BY created_at;
+
+-- name: RetireSigningKeys :exec
+UPDATE authv1_signing_keys
+SET retired_at = now (), expires_at = sqlc.arg(expires_at)
+WHERE retired_at IS NULL AND id <> sqlc.arg(active_id);
+
+-- name: DeleteExpiredSigningKeys :execrows
+DELETE FROM authv1_signing_keys WHERE expires_at <= now ();
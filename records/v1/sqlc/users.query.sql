@@ -4,6 +4,9 @@ SELECT * FROM authv1_users WHERE id = $1;
 -- name: GetUserByUsername :one
 SELECT * FROM authv1_users WHERE username = $1;
 
+-- name: GetUsersByEmail :many
+SELECT * FROM authv1_users WHERE email = $1 ORDER BY created_at;
+
 -- name: ListUsers :many
 SELECT * FROM authv1_users ORDER BY username LIMIT $1 OFFSET $2;
 
@@ -16,22 +19,44 @@ INSERT INTO authv1_users (
   username,
   email,
   salt,
-  key
+  key,
+  algorithm
 ) VALUES (
   $1,
   $2,
   $3,
   $4,
-  $5
+  $5,
+  $6
 ) RETURNING *;
 
 -- name: UpdateUser :exec
 UPDATE authv1_users SET
   username = $1,
   email = $2,
-  key = $3,
+  email_verified = email_verified AND email = $2,
+  updated_at = now()
+WHERE id = $3;
+
+-- name: VerifyUserEmail :execrows
+UPDATE authv1_users SET
+  email_verified = TRUE,
+  updated_at = now()
+WHERE id = $1 AND email = $2;
+
+-- name: SetUserMagicLink :execrows
+UPDATE authv1_users SET
+  magic_link_enabled = $2,
+  updated_at = now()
+WHERE id = $1;
+
+-- name: UpdateUserCredentials :execrows
+UPDATE authv1_users SET
+  key = sqlc.arg(key),
+  salt = sqlc.arg(salt),
+  algorithm = sqlc.arg(algorithm),
   updated_at = now()
-WHERE id = $4;
+WHERE id = sqlc.arg(id) AND key = sqlc.arg(old_key);
 
 -- name: DeleteUser :exec
 DELETE FROM authv1_users WHERE id = $1;
@@ -0,0 +1,36 @@
+-- name: CreateWebAuthnCredential :one
+INSERT INTO authv1_webauthn_credentials (
+  user_id,
+  credential_id,
+  public_key,
+  attestation_type,
+  aaguid,
+  sign_count,
+  transports,
+  backup_eligible,
+  backup_state
+) VALUES (
+  $1,
+  $2,
+  $3,
+  $4,
+  $5,
+  $6,
+  $7,
+  $8,
+  $9
+) RETURNING *;
+
+-- name: GetWebAuthnCredential :one
+SELECT * FROM authv1_webauthn_credentials WHERE credential_id = $1;
+
+-- name: ListWebAuthnCredentialsForUser :many
+SELECT * FROM authv1_webauthn_credentials WHERE user_id = $1 ORDER BY created_at;
+
+-- name: UpdateWebAuthnSignCount :execrows
+UPDATE authv1_webauthn_credentials SET
+  sign_count = $2,
+  backup_state = $3,
+  last_used_at = now()
+WHERE credential_id = $1 AND (sign_count < $2 OR (sign_count = 0 AND $2 = 0));
+
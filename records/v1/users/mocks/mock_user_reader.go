@@ -79,3 +79,30 @@ func (mr *MockReaderMockRecorder) GetUsers(ctx, offset, limit interface{}) *gomo
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockReader)(nil).GetUsers), ctx, offset, limit)
 }
+
+// GetUsersByEmail mocks base method.
+func (m *MockReader) GetUsersByEmail(ctx context.Context, email string) ([]users.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersByEmail", ctx, email)
+	ret0, _ := ret[0].([]users.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersByEmail indicates an expected call of GetUsersByEmail.
+func (mr *MockReaderMockRecorder) GetUsersByEmail(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByEmail", reflect.TypeOf((*MockReader)(nil).GetUsersByEmail), ctx, email)
+}
+
+// Invalidate mocks base method.
+func (m *MockReader) Invalidate(ctx context.Context, user *users.User) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Invalidate", ctx, user)
+}
+
+// Invalidate indicates an expected call of Invalidate.
+func (mr *MockReaderMockRecorder) Invalidate(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invalidate", reflect.TypeOf((*MockReader)(nil).Invalidate), ctx, user)
+}
@@ -64,6 +64,36 @@ func (mr *MockWriterMockRecorder) DeleteUser(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockWriter)(nil).DeleteUser), ctx, id)
 }
 
+// SetMagicLink mocks base method.
+func (m *MockWriter) SetMagicLink(ctx context.Context, id users.UserId, enabled bool) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMagicLink", ctx, id, enabled)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetMagicLink indicates an expected call of SetMagicLink.
+func (mr *MockWriterMockRecorder) SetMagicLink(ctx, id, enabled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMagicLink", reflect.TypeOf((*MockWriter)(nil).SetMagicLink), ctx, id, enabled)
+}
+
+// UpdateCredentials mocks base method.
+func (m *MockWriter) UpdateCredentials(ctx context.Context, id users.UserId, oldKey, key, salt []byte, algorithm string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCredentials", ctx, id, oldKey, key, salt, algorithm)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCredentials indicates an expected call of UpdateCredentials.
+func (mr *MockWriterMockRecorder) UpdateCredentials(ctx, id, oldKey, key, salt, algorithm interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCredentials", reflect.TypeOf((*MockWriter)(nil).UpdateCredentials), ctx, id, oldKey, key, salt, algorithm)
+}
+
 // UpdateUser mocks base method.
 func (m *MockWriter) UpdateUser(ctx context.Context, user gen.Authv1User) error {
 	m.ctrl.T.Helper()
@@ -77,3 +107,18 @@ func (mr *MockWriterMockRecorder) UpdateUser(ctx, user interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockWriter)(nil).UpdateUser), ctx, user)
 }
+
+// VerifyEmail mocks base method.
+func (m *MockWriter) VerifyEmail(ctx context.Context, id users.UserId, email string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx, id, email)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockWriterMockRecorder) VerifyEmail(ctx, id, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockWriter)(nil).VerifyEmail), ctx, id, email)
+}
@@ -19,7 +19,9 @@ var _ Reader = &SQLReader{}
 type Reader interface {
 	GetUser(ctx context.Context, id UserId) (*User, error)
 	GetUserByUsername(ctx context.Context, username string) (*User, error)
+	GetUsersByEmail(ctx context.Context, email string) ([]User, error)
 	GetUsers(ctx context.Context, offset, limit int32) ([]User, error)
+	Invalidate(ctx context.Context, user *User)
 }
 
 type SQLReader struct {
@@ -118,6 +120,12 @@ func (r *SQLReader) GetUser(ctx context.Context, id UserId) (*User, error) {
 	return &user, nil
 }
 
+// GetUsersByEmail returns the users with the email, emails are not unique. It is not cached since the
+// result decides whether the email is trusted
+func (r *SQLReader) GetUsersByEmail(ctx context.Context, email string) ([]User, error) {
+	return r.q.GetUsersByEmail(ctx, email)
+}
+
 func (r *SQLReader) GetUsers(ctx context.Context, offset, limit int32) ([]User, error) {
 	cacheKey := fmt.Sprintf("users:%d:%d", offset, limit)
 
@@ -150,3 +158,17 @@ func (r *SQLReader) GetUsers(ctx context.Context, offset, limit int32) ([]User,
 
 	return users, nil
 }
+
+// Invalidate drops the cached copies of the user, it is called after the user is updated
+func (r *SQLReader) Invalidate(ctx context.Context, user *User) {
+	if r.cache == nil {
+		return
+	}
+
+	for _, key := range []string{user.Username, user.ID.String()} {
+		err := r.cache.Delete(ctx, key)
+		if err != nil && !cache.IsCacheMissErr(err) {
+			r.l.Error("failed to delete cache", zap.Error(err))
+		}
+	}
+}
@@ -14,6 +14,9 @@ type Writer interface {
 	CreateUser(ctx context.Context, user gen.Authv1User) error
 	DeleteUser(ctx context.Context, id UserId) error
 	UpdateUser(ctx context.Context, user gen.Authv1User) error
+	UpdateCredentials(ctx context.Context, id UserId, oldKey []byte, key []byte, salt []byte, algorithm string) (bool, error)
+	VerifyEmail(ctx context.Context, id UserId, email string) (bool, error)
+	SetMagicLink(ctx context.Context, id UserId, enabled bool) (bool, error)
 }
 
 func NewSQLWriter(db *sqlx.DB) *SQLWriter {
@@ -28,11 +31,12 @@ type SQLWriter struct {
 
 func (w *SQLWriter) CreateUser(ctx context.Context, user gen.Authv1User) error {
 	_, err := w.query.CreateUser(ctx, gen.CreateUserParams{
-		ID:       user.ID,
-		Username: user.Username,
-		Email:    user.Email,
-		Salt:     user.Salt,
-		Key:      user.Key,
+		ID:        user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		Salt:      user.Salt,
+		Key:       user.Key,
+		Algorithm: user.Algorithm,
 	})
 	return err
 }
@@ -46,7 +50,51 @@ func (w *SQLWriter) UpdateUser(ctx context.Context, user gen.Authv1User) error {
 		ID:       user.ID,
 		Username: user.Username,
 		Email:    user.Email,
-		Key:      user.Key,
 	})
 	return err
 }
+
+// UpdateCredentials replaces the user's key, salt and algorithm in one statement.
+// Returns false if the user's key is no longer oldKey, which means it was changed concurrently
+func (w *SQLWriter) UpdateCredentials(ctx context.Context, id UserId, oldKey []byte, key []byte, salt []byte, algorithm string) (bool, error) {
+	rows, err := w.query.UpdateUserCredentials(ctx, gen.UpdateUserCredentialsParams{
+		ID:        id,
+		Key:       key,
+		Salt:      salt,
+		Algorithm: algorithm,
+		OldKey:    oldKey,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// VerifyEmail marks the user's email as verified.
+// Returns false if the user's email is no longer the one that was verified
+func (w *SQLWriter) VerifyEmail(ctx context.Context, id UserId, email string) (bool, error) {
+	rows, err := w.query.VerifyUserEmail(ctx, gen.VerifyUserEmailParams{
+		ID:    id,
+		Email: email,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// SetMagicLink opts the user in or out of logging in with emailed links.
+// Returns false if the user does not exist
+func (w *SQLWriter) SetMagicLink(ctx context.Context, id UserId, enabled bool) (bool, error) {
+	rows, err := w.query.SetUserMagicLink(ctx, gen.SetUserMagicLinkParams{
+		ID:               id,
+		MagicLinkEnabled: enabled,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
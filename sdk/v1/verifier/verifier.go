@@ -0,0 +1,131 @@
+package verifier
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/domain/v1/authentication"
+	"github.com/ooqls/go-auth/domain/v1/jwks"
+	"github.com/ooqls/go-crypto/jwt"
+	"github.com/ooqls/go-log"
+	"go.uber.org/zap"
+)
+
+var l *zap.Logger
+
+func init() {
+	l = log.NewLogger("verifier")
+}
+
+const (
+	// DefaultAudience is the audience of the auth tokens the auth service issues
+	DefaultAudience = "auth"
+	// DefaultLeeway is the clock skew tolerated between a service and the auth service
+	DefaultLeeway = time.Minute
+)
+
+var (
+	ErrInvalidConfig   error = errors.New("invalid verifier config")
+	ErrInvalidToken    error = errors.New("token invalid")
+	ErrKeysUnavailable error = errors.New("signing keys unavailable")
+)
+
+type Config struct {
+	// JWKSURI is the jwks_uri of the auth service, /jwks.json
+	JWKSURI string
+	// Issuer is the issuer of the auth service's auth tokens
+	Issuer string
+	// Audience defaults to DefaultAudience, tokens of other audiences like refresh tokens are refused
+	Audience string
+	// Leeway defaults to DefaultLeeway, it applies to exp, nbf and iat
+	Leeway time.Duration
+	// MinRefetchInterval limits how often tokens with an unknown kid fetch the keys again, it defaults to
+	// jwks.DefaultMinRefetchInterval. Tokens signed right after a rotation are refused until the keys are fetched again
+	MinRefetchInterval time.Duration
+	// HTTPClient fetches the signing keys, it defaults to a client with a 10 second timeout
+	HTTPClient *http.Client
+}
+
+// Verifier verifies auth tokens with the public keys of the auth service, services verify tokens without a request
+// to the auth service or its private keys. The keys are fetched again when a token is signed with an unknown key so
+// rotated keys are picked up. Revoked sessions and personal access tokens can only be checked by the auth service,
+// use its introspection endpoint for them
+type Verifier struct {
+	keys   *jwks.KeySet
+	parser *jwtv5.Parser
+}
+
+// NewVerifier returns a Verifier for cfg, the keys are fetched when the first token is verified
+func NewVerifier(cfg Config) (*Verifier, error) {
+	if cfg.JWKSURI == "" || cfg.Issuer == "" {
+		return nil, ErrInvalidConfig
+	}
+
+	if cfg.Audience == "" {
+		cfg.Audience = DefaultAudience
+	}
+
+	if cfg.Leeway == 0 {
+		cfg.Leeway = DefaultLeeway
+	}
+
+	if cfg.MinRefetchInterval == 0 {
+		cfg.MinRefetchInterval = jwks.DefaultMinRefetchInterval
+	}
+
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &Verifier{
+		keys: jwks.NewKeySet(cfg.HTTPClient, cfg.JWKSURI, cfg.MinRefetchInterval),
+		parser: jwtv5.NewParser(
+			jwtv5.WithValidMethods([]string{jwtv5.SigningMethodRS256.Name}),
+			jwtv5.WithIssuer(cfg.Issuer),
+			jwtv5.WithAudience(cfg.Audience),
+			jwtv5.WithExpirationRequired(),
+			jwtv5.WithIssuedAt(),
+			jwtv5.WithLeeway(cfg.Leeway),
+		),
+	}, nil
+}
+
+// Verify returns the claims of the auth token with IssuedAt and ExpiresAt set like Authenticator.IsAuthenticated.
+// ErrInvalidToken is returned for tokens that are malformed, expired or not signed by the auth service and
+// ErrKeysUnavailable if the signing keys could not be fetched
+func (v *Verifier) Verify(ctx context.Context, token string) (*authentication.UserClaims, error) {
+	var keyErr error
+	claims := jwt.ClaimsWrapper[authentication.UserClaims]{}
+	_, err := v.parser.ParseWithClaims(token, &claims, func(t *jwtv5.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := v.keys.Key(ctx, kid)
+		if err != nil && !errors.Is(err, jwks.ErrUnknownKey) {
+			keyErr = err
+		}
+
+		return key, err
+	})
+	if keyErr != nil {
+		l.Error("failed to fetch signing keys", zap.Error(keyErr))
+		return nil, ErrKeysUnavailable
+	}
+
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	userClaims := claims.CustomClaims
+	if userClaims.UserID == uuid.Nil {
+		return nil, ErrInvalidToken
+	}
+
+	if claims.IssuedAt != nil {
+		userClaims.IssuedAt = claims.IssuedAt.Time
+	}
+	userClaims.ExpiresAt = claims.ExpiresAt.Time
+	return &userClaims, nil
+}
@@ -0,0 +1,133 @@
+package verifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ooqls/go-auth/api/v1/gen/gen_oauth"
+	"github.com/ooqls/go-auth/domain/v1/authentication"
+	"github.com/ooqls/go-auth/domain/v1/keyring"
+	"github.com/ooqls/go-auth/domain/v1/oauth"
+	"github.com/ooqls/go-crypto/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestAuthService returns a server publishing the keys of the ring like the auth service's /jwks.json
+func newTestAuthService(ring keyring.KeyRing, fetches *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		response := gen_oauth.JWKS{Keys: []gen_oauth.JWK{}}
+		for _, key := range ring.PublicKeys() {
+			jwk := oauth.NewRSAJWK(key.Key)
+			response.Keys = append(response.Keys, gen_oauth.JWK{
+				Kty: jwk.KeyType,
+				Use: jwk.Use,
+				Alg: jwk.Algorithm,
+				Kid: jwk.KeyID,
+				N:   jwk.Modulus,
+				E:   jwk.Exponent,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+}
+
+func newTestIssuer(ring keyring.KeyRing, issuer string, audience string, validity float64) jwt.TokenIssuer[authentication.UserClaims] {
+	return keyring.NewTokenIssuer[authentication.UserClaims](&jwt.TokenConfiguration{
+		Issuer:                  issuer,
+		Audience:                []string{audience},
+		ValidityDurationSeconds: validity,
+	}, ring)
+}
+
+func TestVerifier(t *testing.T) {
+	ctx := context.Background()
+	ring, err := keyring.NewKeyRingV1(nil, nil, nil, time.Hour, nil)
+	assert.Nilf(t, err, "should create the key ring: %v", err)
+	err = ring.Reload(ctx)
+	assert.Nilf(t, err, "should load the key ring: %v", err)
+
+	var fetches atomic.Int32
+	server := newTestAuthService(ring, &fetches)
+	defer server.Close()
+
+	_, err = NewVerifier(Config{JWKSURI: server.URL})
+	assert.ErrorIsf(t, err, ErrInvalidConfig, "should require the issuer")
+
+	verifier, err := NewVerifier(Config{JWKSURI: server.URL, Issuer: "auth-service", MinRefetchInterval: time.Nanosecond})
+	assert.Nilf(t, err, "should create the verifier: %v", err)
+
+	userID := uuid.New()
+	sessionID := uuid.New()
+	authIssuer := newTestIssuer(ring, "auth-service", DefaultAudience, 300)
+	token, _, err := authIssuer.IssueToken(userID.String(), authentication.UserClaims{UserID: userID, SessionID: sessionID, AMR: []string{authentication.AuthMethodPassword}})
+	assert.Nilf(t, err, "should issue the token: %v", err)
+
+	claims, err := verifier.Verify(ctx, token)
+	assert.Nilf(t, err, "should verify the token: %v", err)
+	assert.Equalf(t, userID, claims.UserID, "should return the user of the token")
+	assert.Equalf(t, sessionID, claims.SessionID, "should return the session of the token")
+	assert.Equalf(t, []string{authentication.AuthMethodPassword}, claims.AMR, "should return the custom claims of the token")
+	assert.WithinDurationf(t, time.Now().Add(300*time.Second), claims.ExpiresAt, 2*time.Second, "should return when the token expires")
+	assert.WithinDurationf(t, time.Now(), claims.IssuedAt, 2*time.Second, "should return when the token was issued")
+
+	_, err = verifier.Verify(ctx, token)
+	assert.Nilf(t, err, "should verify the token again: %v", err)
+	assert.Equalf(t, int32(1), fetches.Load(), "the keys should be cached")
+
+	err = ring.Rotate(ctx)
+	assert.Nilf(t, err, "should rotate the keys: %v", err)
+	rotated, _, err := authIssuer.IssueToken(userID.String(), authentication.UserClaims{UserID: userID})
+	assert.Nilf(t, err, "should issue the token: %v", err)
+
+	_, err = verifier.Verify(ctx, rotated)
+	assert.Nilf(t, err, "should verify tokens signed with a rotated in key: %v", err)
+	assert.Equalf(t, int32(2), fetches.Load(), "the keys should be fetched again for an unknown kid")
+
+	_, err = verifier.Verify(ctx, token)
+	assert.Nilf(t, err, "retired keys should keep verifying their tokens: %v", err)
+
+	skewed, _, err := newTestIssuer(ring, "auth-service", DefaultAudience, -30).IssueToken(userID.String(), authentication.UserClaims{UserID: userID})
+	assert.Nilf(t, err, "should issue the token: %v", err)
+	_, err = verifier.Verify(ctx, skewed)
+	assert.Nilf(t, err, "tokens that expired within the leeway should be verified: %v", err)
+
+	otherRing, err := keyring.NewKeyRingV1(nil, nil, nil, time.Hour, nil)
+	assert.Nilf(t, err, "should create the key ring: %v", err)
+	err = otherRing.Reload(ctx)
+	assert.Nilf(t, err, "should load the key ring: %v", err)
+
+	invalid := map[string]jwt.TokenIssuer[authentication.UserClaims]{
+		"expired":        newTestIssuer(ring, "auth-service", DefaultAudience, -120),
+		"refresh token":  newTestIssuer(ring, "refresh", "refresh", 300),
+		"other audience": newTestIssuer(ring, "auth-service", "email_verification", 300),
+		"other issuer":   newTestIssuer(ring, "other", DefaultAudience, 300),
+		"other key":      newTestIssuer(otherRing, "auth-service", DefaultAudience, 300),
+	}
+	for name, issuer := range invalid {
+		token, _, err := issuer.IssueToken(userID.String(), authentication.UserClaims{UserID: userID})
+		assert.Nilf(t, err, "should issue the token: %v", err)
+
+		_, err = verifier.Verify(ctx, token)
+		assert.ErrorIsf(t, err, ErrInvalidToken, "%s should not be verified", name)
+	}
+
+	for _, token := range []string{"", "not a token", authentication.AccessTokenPrefix + "test"} {
+		_, err = verifier.Verify(ctx, token)
+		assert.ErrorIsf(t, err, ErrInvalidToken, "%q should not be verified", token)
+	}
+
+	server.Close()
+	unavailable, err := NewVerifier(Config{JWKSURI: server.URL, Issuer: "auth-service"})
+	assert.Nilf(t, err, "should create the verifier: %v", err)
+	_, err = unavailable.Verify(ctx, token)
+	assert.ErrorIsf(t, err, ErrKeysUnavailable, "should fail when the keys can not be fetched")
+}